@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"strings"
+)
+
+// overrideFlags — флаги командной строки, переопределяющие параметры конфигурации.
+//
+//	Имя параметра получается из имени флага: http-port — HTTP_PORT. Флаги
+//	имеют приоритет над переменными окружения и файлом конфигурации.
+var overrideFlags = []struct {
+	name  string
+	usage string
+}{
+	{"app-env", "Application environment: dev or production"},
+	{"log-level", "Log level: debug, info, warn or error"},
+	{"http-port", "HTTP server port"},
+	{"metrics-port", "Metrics server port"},
+	{"db-host", "Database host"},
+	{"db-port", "Database port"},
+	{"db-name", "Database name"},
+	{"db-user", "Database user"},
+	{"kafka-brokers", "Comma-separated list of Kafka brokers"},
+	{"kafka-topic", "Kafka topic with orders"},
+	{"kafka-group-id", "Kafka consumer group"},
+}
+
+// registerOverrideFlags регистрирует флаги overrideFlags.
+//
+//	Параметры:
+//	- fs: набор флагов.
+//	Возвращает:
+//	- func() map[string]string: после fs.Parse возвращает явно заданные флаги
+//	  по именам переменных окружения.
+func registerOverrideFlags(fs *flag.FlagSet) func() map[string]string {
+	keys := make(map[string]string, len(overrideFlags))
+	for _, f := range overrideFlags {
+		key := strings.ToUpper(strings.ReplaceAll(f.name, "-", "_"))
+		keys[f.name] = key
+		fs.String(f.name, "", f.usage+" (overrides "+key+")")
+	}
+	return func() map[string]string {
+		overrides := make(map[string]string)
+		fs.Visit(func(f *flag.Flag) {
+			if key, ok := keys[f.Name]; ok {
+				overrides[key] = f.Value.String()
+			}
+		})
+		return overrides
+	}
+}
@@ -3,25 +3,46 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"io/fs"
 	"l0_wb/internal/metrics"
+	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"sync"
 	"syscall"
 
 	"go.uber.org/zap"
+	"l0_wb/internal/breaker"
+	"l0_wb/internal/buildinfo"
 	"l0_wb/internal/cache"
+	"l0_wb/internal/cdc"
 	"l0_wb/internal/config"
 	"l0_wb/internal/db"
+	"l0_wb/internal/errtrack"
+	"l0_wb/internal/events"
+	"l0_wb/internal/feed"
+	"l0_wb/internal/fieldcrypt"
 	"l0_wb/internal/kafka"
 	"l0_wb/internal/repository"
 	"l0_wb/internal/server"
 	"l0_wb/internal/service"
+	"l0_wb/internal/sli"
+	"l0_wb/internal/startup"
+	"l0_wb/internal/tracing"
 	"l0_wb/internal/util"
+	"l0_wb/internal/watchdog"
+	"l0_wb/web"
 )
 
 // main инициализирует приложение, настраивает зависимости, запускает Kafka-консьюмер и HTTP-сервер.
 func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "Path to YAML config file (environment variables override its values)")
+	overrides := registerOverrideFlags(flag.CommandLine)
+	flag.Parse()
+
 	// Инициализация логгера
 	if err := util.InitLogger(); err != nil {
 		panic("failed to initialize logger: " + err.Error())
@@ -43,61 +64,313 @@ func main() {
 	}()
 
 	// Загружаем конфигурацию
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfigWithOverrides(*configPath, overrides())
 	if err != nil {
+		// Сообщаем обо всех некорректных параметрах сразу
+		var cfgErr *config.ValidationError
+		if errors.As(err, &cfgErr) {
+			logger.Fatal("Invalid configuration", zap.Strings("problems", cfgErr.Problems))
+		}
 		logger.Fatal("failed to load config: %v", zap.Error(err))
 	}
+	if err := util.SetLogLevel(cfg.LogLevel); err != nil {
+		logger.Fatal("Invalid log level", zap.Error(err))
+	}
+	// Формат и вывод журнала известны только после загрузки конфигурации
+	if err := util.ConfigureLogger(util.LoggerOptions{
+		Format:     cfg.LogFormat,
+		Output:     cfg.LogOutput,
+		MaxSizeMB:  cfg.LogMaxSizeMB,
+		MaxBackups: cfg.LogMaxBackups,
+		MaxAgeDays: cfg.LogMaxAgeDays,
+		Compress:   cfg.LogCompress,
+	}); err != nil {
+		logger.Fatal("Failed to configure logger", zap.Error(err))
+	}
+	logger = util.GetLogger()
+	util.SetLogRawPayloads(cfg.LogRawPayloads)
+	if cfg.LogRawPayloads {
+		logger.Warn("Raw message payloads are logged without masking personal data")
+	}
+
+	build := buildinfo.Get()
+	logger.Info("Starting l0_wb",
+		zap.String("version", build.Version),
+		zap.String("commit", build.Commit),
+		zap.String("build_date", build.BuildDate),
+	)
+
+	// Границы корзин гистограмм задаются до первых наблюдений (миграции БД уже пишут метрики)
+	metrics.SetBuckets(metrics.Buckets{
+		OrderProcessing: cfg.MetricsOrderProcessingBuckets,
+		HTTP:            cfg.MetricsHTTPBuckets,
+		DB:              cfg.MetricsDBBuckets,
+	})
+
+	// Распределённая трассировка (настраивается переменными OTEL_*)
+	shutdownTracing, err := tracing.Init(ctx)
+	if err != nil {
+		logger.Fatal("failed to initialize tracing", zap.Error(err))
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.Warn("failed to flush traces", zap.Error(err))
+		}
+	}()
+	if tracing.Enabled() {
+		logger.Info("Tracing enabled")
+	}
+
+	// Отправка паник и сбоев в Sentry (при заданном SENTRY_DSN)
+	flushErrors, err := errtrack.Init(errtrack.Options{
+		DSN:         cfg.SentryDSN,
+		Environment: cfg.SentryEnvironment,
+		Release:     build.Version,
+		SampleRate:  cfg.SentrySampleRate,
+	})
+	if err != nil {
+		logger.Fatal("failed to initialize error tracking", zap.Error(err))
+	}
+	defer flushErrors(cfg.ShutdownTimeout)
+	if errtrack.Enabled() {
+		logger.Info("Error tracking enabled", zap.String("environment", cfg.SentryEnvironment))
+	}
+
+	// Окно SLI, общее для проверки готовности, сброса нагрузки и автоматов защиты
+	sli.Configure(cfg.SLIWindow)
 
 	// Инициализация БД
-	database, err := db.InitDB(cfg)
+	database, err := db.InitDB(ctx, cfg)
 	if err != nil {
 		logger.Fatal("failed to initialize database: %v", zap.Error(err))
 	}
 
+	// Ожидание Kafka: брокеры могут запускаться дольше сервиса
+	err = startup.Wait(ctx, "kafka", startup.Policy{
+		MaxAttempts: cfg.StartupRetryMaxAttempts,
+		BaseDelay:   cfg.StartupRetryBaseDelay,
+		MaxDelay:    cfg.StartupRetryMaxDelay,
+	}, func(ctx context.Context) error {
+		return kafka.Ping(ctx, cfg)
+	})
+	if err != nil {
+		logger.Fatal("failed to connect to kafka", zap.Error(err))
+	}
+
+	// Крайний срок каждого запроса репозиториев: зависший запрос не удерживает соединение пула
+	repository.SetQueryTimeout(cfg.DBQueryTimeout)
+
+	// Автомат защиты БД: при её недоступности запросы сразу получают отказ и не копят таймауты в пуле.
+	// Размыкается по доле ошибок БД в окне SLI с теми же порогами, что и проверка готовности
+	if cfg.DBBreakerEnabled {
+		repository.SetCircuitBreaker(breaker.New("db", breaker.Settings{
+			MaxErrorRate: cfg.SLIMaxErrorRate,
+			MinEvents:    uint64(cfg.SLIMinEvents),
+			OpenTimeout:  cfg.DBBreakerOpenTimeout,
+			IsFailure:    repository.IsDBFailure,
+			Snapshot:     func() sli.Snapshot { return sli.Get(sli.SourceDB) },
+		}))
+	}
+
+	// Реплики для чтения (DB_REPLICA_DSNS)
+	replicas, err := db.InitReplicas(ctx, cfg)
+	if err != nil {
+		logger.Fatal("failed to initialize database replicas", zap.Error(err))
+	}
+	for _, replica := range replicas {
+		defer replica.Close()
+	}
+	readDB := repository.NewReadDB(database, replicas...)
+
+	// Метрики пулов соединений (database_pool_*)
+	go db.MonitorPool(ctx, "primary", database)
+	for i, replica := range replicas {
+		go db.MonitorPool(ctx, "replica-"+strconv.Itoa(i), replica)
+	}
+
+	// Шифрование персональных данных получателя
+	fieldCipher, err := fieldcrypt.NewFromBase64(cfg.PIIEncryptionKey, cfg.PIIEncryptionPreviousKeys)
+	if err != nil {
+		logger.Fatal("invalid PII encryption key", zap.Error(err))
+	}
+	if fieldCipher == nil {
+		logger.Warn("PII_ENCRYPTION_KEY is not set, delivery personal data is stored unencrypted")
+	}
+	repository.SetFieldCipher(fieldCipher)
+
 	// Создание репозиториев
-	ordersRepo := repository.NewOrdersRepository(database)
-	deliveriesRepo := repository.NewDeliveriesRepository(database)
-	paymentsRepo := repository.NewPaymentsRepository(database)
-	itemsRepo := repository.NewItemsRepository(database)
+	repos := repository.NewRepositoriesWithReads(database, readDB)
+	apiKeysRepo := repository.NewAPIKeysRepository(database)
+	statsRepo := repository.NewStatsRepository(readDB)
 
 	// Инициализация кэша и загрузка данных из БД
-	orderCache := cache.NewOrderCache()
-	if err := orderCache.LoadFromDB(ctx, ordersRepo, deliveriesRepo, paymentsRepo, itemsRepo, database); err != nil {
+	orderCache := cache.NewOrderCache(
+		cache.WithMaxEntries(cfg.CacheMaxEntries),
+		cache.WithTTL(cfg.CacheTTL),
+		cache.WithPreloadWindow(cfg.CachePreloadWindow),
+	)
+	if err := orderCache.LoadFromDB(ctx, repos.Orders); err != nil {
 		logger.Warn("failed to load cache from DB: %v", zap.Error(err))
 	}
 
+	// Публикатор событий об изменении заказов
+	var publisher events.Publisher
+	if eventPublisher := kafka.NewEventPublisher(cfg); eventPublisher != nil {
+		defer eventPublisher.Close()
+		publisher = eventPublisher
+	}
+
 	// Инициализация сервисов
-	orderService := service.NewOrderService(database, ordersRepo, deliveriesRepo, paymentsRepo, itemsRepo)
+	serviceOpts := []service.Option{
+		service.WithAmountTolerance(cfg.OrderAmountTolerance),
+		service.WithCache(orderCache),
+		service.WithRetryPolicy(service.RetryPolicy{
+			MaxAttempts: cfg.DBRetryMaxAttempts,
+			BaseDelay:   cfg.DBRetryBaseDelay,
+			MaxDelay:    cfg.DBRetryMaxDelay,
+		}),
+		service.WithTxOptions(repository.TxOptions{IsoLevel: repository.TxIsoLevel(cfg.DBTxIsolation)}),
+	}
+	var dedup *service.Deduplicator
+	if cfg.OrderDedupWindow > 0 {
+		dedup = service.NewDeduplicator(repository.NewOrderDedupRepository(database), cfg.OrderDedupWindow)
+		serviceOpts = append(serviceOpts, service.WithDeduplicator(dedup))
+	}
+	orderService := service.NewOrderService(repository.NewTxManager(database), repos, publisher, serviceOpts...)
+
+	// Поток обработанных заказов для WebSocket-клиентов
+	orderFeed := feed.NewHub(64)
 
 	// Запуск Kafka-консьюмера для получения новых заказов
-	consumer := kafka.NewConsumer(cfg.KafkaBrokers, cfg.KafkaTopic, cfg.KafkaGroupID, orderService, orderCache)
+	consumer := kafka.NewConsumer(cfg, orderService, orderFeed)
 
 	// Инициализация метрик Prometheus
-	metrics.Init()
+	metrics.Init(cfg.MetricsDiskPaths)
 
 	// Используем sync.WaitGroup для управления запущенными горутинами
 	var wg sync.WaitGroup
 
-	// Запуск сервера метрик на порту 9100
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		metrics.StartMetricsServer("9100", &wg)
-	}()
+	// Запуск отдельного сервера метрик, если /metrics не отдаётся основным HTTP-сервером
+	if !cfg.MetricsOnMainServer {
+		metricsAddr := net.JoinHostPort(cfg.MetricsAddr, cfg.MetricsPort)
+		logger.Info("Starting metrics server", zap.String("addr", metricsAddr))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := metrics.StartMetricsServer(ctx, metricsAddr, cfg.ShutdownTimeout); err != nil {
+				logger.Error("metrics server stopped with error", zap.Error(err))
+			}
+		}()
+	}
 
-	// Запуск Kafka-консьюмера для получения новых заказов
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := consumer.Run(ctx); err != nil {
-			logger.Fatal("kafka consumer stopped with error", zap.Error(err))
-			cancel()
-		}
-	}()
+	// Очистка записей дедупликации, вышедших за окно
+	if dedup != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dedup.Run(ctx, cfg.OrderDedupWindow)
+		}()
+	}
+
+	// Наблюдение за доступностью БД и Kafka
+	if cfg.WatchdogEnabled {
+		w := watchdog.New(
+			repository.NewIncidentsRepository(database),
+			watchdog.Settings{
+				Interval:          cfg.WatchdogInterval,
+				FailureThreshold:  cfg.WatchdogFailureThreshold,
+				RecoveryThreshold: cfg.WatchdogRecoveryThreshold,
+			},
+			watchdog.Dependency{Name: "db", Probe: database.Ping},
+			watchdog.Dependency{Name: "kafka", Probe: func(ctx context.Context) error {
+				return kafka.Ping(ctx, cfg)
+			}},
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Run(ctx)
+		}()
+	}
+
+	// Обновление кэша при изменении заказов в БД в обход сервиса
+	if cfg.CacheCDCEnabled {
+		listener := cdc.New(database, repository.NewOrdersRepository(database), orderCache,
+			cdc.WithDebounce(cfg.CacheCDCDebounce), cdc.WithOrigin(db.ApplicationName))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			listener.Run(ctx)
+		}()
+	}
+
+	if cfg.ReplayEnabled {
+		// Режим воспроизведения: вместо чтения Kafka прогоняем заказы из БД и завершаем работу
+		replayer := newReplayer(ctx, cfg, repos.Orders, orderService)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer cancel()
+			if _, err := replayer.Run(ctx); err != nil {
+				logger.Error("replay stopped with error", zap.Error(err))
+			}
+		}()
+	} else {
+		// Запуск Kafka-консьюмера для получения новых заказов
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := consumer.Run(ctx); err != nil {
+				logger.Fatal("kafka consumer stopped with error", zap.Error(err))
+				cancel()
+			}
+		}()
+	}
 
 	// Запуск HTTP-сервера
-	// Раздача статических файлов из директории "web".
-	srv := server.NewServer(cfg.HTTPPort, orderCache, "web")
+	// Статические файлы встроены в бинарный файл; STATIC_DIR позволяет править их без пересборки.
+	var static fs.FS = web.FS
+	if cfg.StaticDir != "" {
+		logger.Info("Serving static files from directory", zap.String("dir", cfg.StaticDir))
+		static = os.DirFS(cfg.StaticDir)
+	}
+	srv := server.NewServer(cfg, orderCache, orderService, orderFeed, static, apiKeysRepo, statsRepo)
+	srv.SetAuditRepository(repository.NewAuditRepository(database))
+	if !cfg.ReplayEnabled {
+		srv.SetConsumerControl(consumer)
+	}
+	srv.AddReadinessCheck("database", database.Ping)
+	for i, replica := range replicas {
+		srv.AddReadinessCheck("database_replica_"+strconv.Itoa(i), replica.Ping)
+	}
+	srv.AddReadinessCheck("cache", func(context.Context) error {
+		if !orderCache.Warmed() {
+			return errors.New("cache is not warmed up")
+		}
+		return nil
+	})
+	if !cfg.ReplayEnabled {
+		srv.AddReadinessCheck("kafka_consumer", func(context.Context) error {
+			if !consumer.Running() {
+				return errors.New("kafka consumer is not running")
+			}
+			return nil
+		})
+	}
+
+	// Перезагрузка изменяемых на лету параметров по SIGHUP и через /api/admin/config/reload
+	reloader := config.NewReloader(*configPath, overrides(), cfg)
+	reloader.OnReload(func(c *config.Config) {
+		if err := util.SetLogLevel(c.LogLevel); err != nil {
+			logger.Error("Failed to set log level", zap.Error(err))
+		}
+		srv.Reconfigure(c)
+		consumer.SetBatchSize(c.KafkaBatchSize)
+	})
+	srv.SetConfigReloader(reloader)
+	go reloadOnSignal(ctx, reloader)
 
 	wg.Add(1)
 	go func() {
@@ -111,3 +384,54 @@ func main() {
 	wg.Wait()
 	logger.Info("Application stopped")
 }
+
+// reloadOnSignal перезагружает конфигурацию при получении SIGHUP до отмены контекста.
+//
+//	Параметры:
+//	- ctx: контекст работы приложения.
+//	- reloader: перезагрузка конфигурации.
+func reloadOnSignal(ctx context.Context, reloader *config.Reloader) {
+	logger := util.GetLogger()
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			changed, err := reloader.Reload()
+			var cfgErr *config.ValidationError
+			switch {
+			case errors.As(err, &cfgErr):
+				logger.Error("Configuration not reloaded: invalid configuration", zap.Strings("problems", cfgErr.Problems))
+			case err != nil:
+				logger.Error("Failed to reload configuration", zap.Error(err))
+			default:
+				logger.Info("Configuration reloaded", zap.Strings("changed", changed))
+			}
+		}
+	}
+}
+
+// newReplayer создаёт Replayer в соответствии с REPLAY_TARGET.
+//
+//	Для цели scratch заказы сохраняются в отдельную схему через собственный набор
+//	репозиториев и сервис; для dry-run заказы только декодируются и валидируются.
+func newReplayer(ctx context.Context, cfg *config.Config, ordersRepo repository.OrdersRepository, source service.OrderService) *kafka.Replayer {
+	logger := util.GetLogger()
+
+	var sink service.OrderService
+	if cfg.ReplayTarget == config.ReplayTargetScratch {
+		scratchDB, err := db.InitScratchDB(ctx, cfg, cfg.ReplaySchema)
+		if err != nil {
+			logger.Fatal("failed to initialize scratch schema", zap.Error(err))
+		}
+		sink = service.NewOrderService(repository.NewTxManager(scratchDB), repository.NewRepositories(scratchDB), nil,
+			service.WithAmountTolerance(cfg.OrderAmountTolerance))
+	}
+
+	logger.Info("Replay mode enabled", zap.String("target", cfg.ReplayTarget), zap.Int("rate", cfg.ReplayRate))
+	return kafka.NewReplayer(ordersRepo, source, sink, cfg.ReplayRate)
+}
@@ -0,0 +1,190 @@
+// main provides a command-line tool for managing database schema migrations.
+//
+// Usage:
+//
+//	migrate [--config=config.yaml] up
+//	migrate [--config=config.yaml] down [N]
+//	migrate [--config=config.yaml] status
+//	migrate [--config=config.yaml] force VERSION
+//	migrate [--dir=internal/db/migrations] create NAME
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+	"l0_wb/internal/db"
+	"l0_wb/internal/util"
+)
+
+// main разбирает команду и выполняет её; при ошибке завершает процесс с кодом 1.
+func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "Path to YAML config file (environment variables override its values)")
+	dir := flag.String("dir", "internal/db/migrations", "Migrations directory for the create command")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	// Инициализация логгера
+	if err := util.InitLogger(); err != nil {
+		panic("failed to initialize logger: " + err.Error())
+	}
+	defer util.SyncLogger()
+
+	if err := run(*configPath, *dir, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		util.SyncLogger()
+		os.Exit(1)
+	}
+}
+
+// usage выводит справку по командам.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "Usage: migrate [flags] <command> [args]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+	fmt.Fprintln(out, "  up             apply all pending migrations")
+	fmt.Fprintln(out, "  down [N]       roll back the last N migrations (default 1)")
+	fmt.Fprintln(out, "  status         list migrations and the current schema version")
+	fmt.Fprintln(out, "  force VERSION  set the schema version and clear the dirty flag without running migrations")
+	fmt.Fprintln(out, "  create NAME    create empty up/down files for the next migration")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Flags:")
+	flag.PrintDefaults()
+}
+
+// run выполняет команду.
+//
+//	Параметры:
+//	- configPath: путь к YAML-файлу конфигурации.
+//	- dir: каталог миграций для команды create.
+//	- args: команда и её аргументы.
+//	Возвращает:
+//	- error: ошибка выполнения команды.
+func run(configPath, dir string, args []string) error {
+	cmd, args := args[0], args[1:]
+
+	// create не требует подключения к БД.
+	if cmd == "create" {
+		if len(args) != 1 {
+			return errors.New("usage: migrate create NAME")
+		}
+		paths, err := db.CreateMigration(dir, args[0])
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			fmt.Println(path)
+		}
+		return nil
+	}
+
+	var steps int
+	var version uint64
+	switch cmd {
+	case "up", "status":
+		if len(args) != 0 {
+			return fmt.Errorf("usage: migrate %s", cmd)
+		}
+	case "down":
+		steps = 1
+		if len(args) > 1 {
+			return errors.New("usage: migrate down [N]")
+		}
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of migrations %q", args[0])
+			}
+			steps = n
+		}
+	case "force":
+		if len(args) != 1 {
+			return errors.New("usage: migrate force VERSION")
+		}
+		v, err := strconv.ParseUint(args[0], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[0])
+		}
+		version = v
+	default:
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+
+	cfg, err := config.LoadConfigWithOverrides(configPath, nil)
+	if err != nil {
+		var cfgErr *config.ValidationError
+		if errors.As(err, &cfgErr) {
+			util.GetLogger().Error("Invalid configuration", zap.Strings("problems", cfgErr.Problems))
+		}
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := util.SetLogLevel(cfg.LogLevel); err != nil {
+		return err
+	}
+
+	pool, err := db.Connect(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	mg, err := db.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	defer mg.Close()
+
+	switch cmd {
+	case "up":
+		err = mg.Up()
+	case "down":
+		err = mg.Down(steps)
+	case "force":
+		err = mg.Force(uint(version))
+	}
+	if err != nil {
+		return err
+	}
+	return printStatus(mg)
+}
+
+// printStatus выводит список миграций и текущую версию схемы.
+//
+//	Параметры:
+//	- mg: Migrator подключённой БД.
+//	Возвращает:
+//	- error: ошибка чтения состояния миграций.
+func printStatus(mg *db.Migrator) error {
+	migrations, current, dirty, err := mg.Status()
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		state := "pending"
+		switch {
+		case m.Applied:
+			state = "applied"
+		case dirty && m.Version == current:
+			state = "dirty"
+		}
+		fmt.Printf("%04d  %-8s %s\n", m.Version, state, m.Name)
+	}
+	if dirty {
+		fmt.Printf("version: %d (dirty)\n", current)
+	} else {
+		fmt.Printf("version: %d\n", current)
+	}
+	return nil
+}
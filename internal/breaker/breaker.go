@@ -0,0 +1,203 @@
+// Package breaker implements a circuit breaker that stops calls to a failing dependency.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/metrics"
+	"l0_wb/internal/sli"
+	"l0_wb/internal/util"
+)
+
+// ErrOpen возвращается вместо вызова, пока автомат разомкнут.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State — состояние автомата защиты.
+type State int
+
+// Состояния автомата защиты.
+const (
+	StateClosed   State = iota // Вызовы выполняются, сбои подсчитываются
+	StateHalfOpen              // Таймаут истёк, результат следующего вызова решает, замкнуть ли автомат
+	StateOpen                  // Вызовы отклоняются с ErrOpen
+)
+
+// String возвращает название состояния для журналов.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// Settings содержит пороги срабатывания автомата.
+type Settings struct {
+	MaxErrorRate float64             // Допустимая доля ошибок в окне SLI (0..1)
+	MinEvents    uint64              // Минимум событий в окне для размыкания
+	OpenTimeout  time.Duration       // Время в разомкнутом состоянии до пробного вызова
+	IsFailure    func(error) bool    // Считать ли ошибку сбоем зависимости (nil — любая ошибка)
+	Snapshot     func() sli.Snapshot // Окно SLI зависимости (nil — источник name общего калькулятора SLI)
+}
+
+// Breaker — автомат защиты зависимости.
+//
+//	Автомат не ведёт собственного счёта сбоев, а опирается на то же окно
+//	SLI, что и проверка готовности: при сбое зависимости он размыкается,
+//	если доля ошибок в окне превышает MaxErrorRate при не менее чем
+//	MinEvents событиях. Разомкнутый автомат на время OpenTimeout
+//	отклоняет вызовы с ErrOpen, не обращаясь к зависимости.
+//	Затем он переходит в полуоткрытое состояние: успешный вызов замыкает
+//	автомат, сбой снова размыкает его на OpenTimeout. Вызовы в полуоткрытом
+//	состоянии не ограничиваются одним пробным, так как операции с БД бывают
+//	вложенными (запросы внутри транзакции) и пробная транзакция не должна
+//	отклонять собственные запросы.
+type Breaker struct {
+	name     string
+	settings Settings
+
+	mu       sync.Mutex
+	state    State
+	openedAt time.Time
+
+	now    func() time.Time
+	logger *zap.Logger
+}
+
+// New создаёт замкнутый автомат защиты.
+//
+//	Параметры:
+//	- name: имя зависимости для метрик и журналов.
+//	- settings: пороги срабатывания.
+//	Возвращает:
+//	- *Breaker: экземпляр автомата.
+func New(name string, settings Settings) *Breaker {
+	if settings.IsFailure == nil {
+		settings.IsFailure = func(err error) bool { return err != nil }
+	}
+	if settings.Snapshot == nil {
+		settings.Snapshot = func() sli.Snapshot { return sli.Get(name) }
+	}
+	metrics.SetCircuitBreakerState(name, int(StateClosed))
+	return &Breaker{
+		name:     name,
+		settings: settings,
+		now:      time.Now,
+		logger:   util.GetLogger(),
+	}
+}
+
+// Execute выполняет fn, если автомат не разомкнут.
+//
+//	Параметры:
+//	- fn: вызов зависимости.
+//	Возвращает:
+//	- error: ErrOpen, если вызов отклонён, иначе ошибку fn.
+func (b *Breaker) Execute(fn func() error) error {
+	if !b.allow() {
+		metrics.CircuitBreakerRejected.WithLabelValues(b.name).Inc()
+		return ErrOpen
+	}
+	err := fn()
+	b.observe(err)
+	return err
+}
+
+// State возвращает текущее состояние автомата.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.settings.OpenTimeout {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// RetryAfter возвращает время до перехода разомкнутого автомата в полуоткрытое состояние.
+func (b *Breaker) RetryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state != StateOpen {
+		return 0
+	}
+	if left := b.settings.OpenTimeout - b.now().Sub(b.openedAt); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// allow сообщает, можно ли выполнить вызов, и переводит автомат в полуоткрытое состояние по таймауту.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state != StateOpen {
+		return true
+	}
+	if b.now().Sub(b.openedAt) < b.settings.OpenTimeout {
+		return false
+	}
+	b.setState(StateHalfOpen)
+	return true
+}
+
+// observe учитывает результат вызова.
+//
+//	Отклонённый вложенный вызов и отменённый вызывающей стороной ничего не
+//	говорят о доступности зависимости и не меняют состояния. Сбой
+//	пробного вызова размыкает автомат сразу, сбой в замкнутом состоянии —
+//	только при превышении порога доли ошибок в окне SLI.
+func (b *Breaker) observe(err error) {
+	if errors.Is(err, ErrOpen) || errors.Is(err, context.Canceled) {
+		return
+	}
+	if !b.settings.IsFailure(err) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.state != StateClosed {
+			b.setState(StateClosed)
+		}
+		return
+	}
+
+	// Окно читается без блокировки автомата: у него собственная синхронизация
+	window := b.settings.Snapshot()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case b.state == StateHalfOpen:
+		b.trip(window, err)
+	case b.state == StateClosed && window.Exceeds(b.settings.MaxErrorRate, b.settings.MinEvents):
+		b.trip(window, err)
+	}
+}
+
+// trip размыкает автомат.
+func (b *Breaker) trip(window sli.Snapshot, err error) {
+	b.openedAt = b.now()
+	b.setState(StateOpen)
+	b.logger.Warn("Circuit breaker opened",
+		zap.String("dependency", b.name),
+		zap.Uint64("window_events", window.Total),
+		zap.Float64("window_error_rate", window.ErrorRate),
+		zap.Duration("open_timeout", b.settings.OpenTimeout),
+		zap.Error(err),
+	)
+}
+
+// setState меняет состояние автомата и обновляет метрику.
+func (b *Breaker) setState(state State) {
+	if state == StateClosed && b.state != StateClosed {
+		b.logger.Info("Circuit breaker closed", zap.String("dependency", b.name))
+	}
+	b.state = state
+	metrics.SetCircuitBreakerState(b.name, int(state))
+}
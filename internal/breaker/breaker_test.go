@@ -0,0 +1,110 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"l0_wb/internal/sli"
+	"l0_wb/internal/util"
+)
+
+// TestBreakerTransitions проверяет размыкание по доле ошибок в окне SLI, отклонение вызовов и восстановление.
+func TestBreakerTransitions(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	now := time.Unix(0, 0)
+	// Окно заполняется результатами вызовов, как это делает обёртка репозиториев для sli.SourceDB
+	window := sli.NewWindow(time.Minute, 6)
+	b := New("test", Settings{MaxErrorRate: 0.5, MinEvents: 4, OpenTimeout: 10 * time.Second, Snapshot: window.Snapshot})
+	b.now = func() time.Time { return now }
+
+	failure := errors.New("connection refused")
+	fail := func() error { return failure }
+	calls := 0
+	ok := func() error { calls++; return nil }
+	execute := func(fn func() error) error {
+		err := b.Execute(fn)
+		if !errors.Is(err, ErrOpen) {
+			window.Record(err == nil)
+		}
+		return err
+	}
+
+	// Пока событий в окне меньше MinEvents, сбои не размыкают автомат.
+	_ = execute(fail)
+	_ = execute(fail)
+	_ = execute(fail)
+	if b.State() != StateClosed {
+		t.Fatalf("expected closed breaker below the volume threshold, got %s", b.State())
+	}
+
+	// Доля ошибок не выше порога не размыкает автомат.
+	for range 3 {
+		_ = execute(ok)
+	}
+	_ = execute(fail) // До вызова в окне 3 сбоя из 6 событий: доля 0.5 не превышает порог
+	if b.State() != StateClosed {
+		t.Fatalf("expected closed breaker at the error rate threshold, got %s", b.State())
+	}
+
+	// Сбой при доле ошибок выше порога размыкает автомат.
+	_ = execute(fail)
+	if b.State() != StateOpen {
+		t.Fatalf("expected open breaker, got %s", b.State())
+	}
+	calls = 0
+	if err := b.Execute(ok); !errors.Is(err, ErrOpen) || calls != 0 {
+		t.Fatalf("expected call to be rejected, got err=%v calls=%d", err, calls)
+	}
+	if got := b.RetryAfter(); got != 10*time.Second {
+		t.Fatalf("expected retry after 10s, got %s", got)
+	}
+
+	// Отмена вызывающей стороной и отклонённые вложенные вызовы не меняют состояния.
+	now = now.Add(10 * time.Second)
+	_ = b.Execute(func() error { return context.Canceled })
+	_ = b.Execute(func() error { return ErrOpen })
+	if b.State() != StateHalfOpen {
+		t.Fatalf("expected half-open breaker, got %s", b.State())
+	}
+
+	// Сбой пробного вызова снова размыкает автомат.
+	if err := b.Execute(fail); !errors.Is(err, failure) {
+		t.Fatalf("expected trial call to run, got %v", err)
+	}
+	if b.State() != StateOpen {
+		t.Fatalf("expected open breaker after failed trial, got %s", b.State())
+	}
+
+	// Успешный пробный вызов замыкает автомат.
+	now = now.Add(10 * time.Second)
+	if err := b.Execute(ok); err != nil {
+		t.Fatalf("expected trial call to succeed, got %v", err)
+	}
+	if b.State() != StateClosed {
+		t.Fatalf("expected closed breaker, got %s", b.State())
+	}
+}
+
+// TestBreakerIsFailure проверяет, что ошибки, не признанные сбоем, не размыкают автомат.
+func TestBreakerIsFailure(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	notFound := errors.New("not found")
+	b := New("test", Settings{
+		OpenTimeout: time.Minute,
+		IsFailure:   func(err error) bool { return err != nil && !errors.Is(err, notFound) },
+		Snapshot:    func() sli.Snapshot { return sli.Snapshot{Total: 10, Errors: 10, ErrorRate: 1} },
+	})
+
+	if err := b.Execute(func() error { return notFound }); !errors.Is(err, notFound) {
+		t.Fatalf("expected original error, got %v", err)
+	}
+	if b.State() != StateClosed {
+		t.Fatalf("expected closed breaker, got %s", b.State())
+	}
+}
@@ -0,0 +1,82 @@
+// Package buildinfo exposes the version, commit and build date of the running binary.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Сведения о сборке, задаваемые при компиляции:
+//
+//	go build -ldflags "-X l0_wb/internal/buildinfo.Version=v1.2.3 \
+//	  -X l0_wb/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X l0_wb/internal/buildinfo.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	// Version — версия приложения.
+	Version = "dev"
+	// Commit — хеш коммита, из которого собран бинарный файл.
+	Commit = ""
+	// BuildDate — время сборки в формате RFC 3339.
+	BuildDate = ""
+)
+
+// unknown подставляется вместо сведений, которые не удалось определить.
+const unknown = "unknown"
+
+// Info — сведения о сборке запущенного бинарного файла.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get возвращает сведения о сборке.
+//
+//	Коммит и время сборки, не заданные через -ldflags, берутся из сведений
+//	системы контроля версий, которые go build встраивает в бинарный файл
+//	(vcs.revision и vcs.time); изменённое рабочее дерево отмечается суффиксом "-dirty".
+//	Возвращает:
+//	- Info: сведения о сборке; неизвестные поля имеют значение "unknown".
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		var revision, modified, vcsTime string
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				revision = s.Value
+			case "vcs.modified":
+				modified = s.Value
+			case "vcs.time":
+				vcsTime = s.Value
+			}
+		}
+		if info.Commit == "" && revision != "" {
+			info.Commit = revision
+			if modified == "true" {
+				info.Commit += "-dirty"
+			}
+		}
+		if info.BuildDate == "" {
+			info.BuildDate = vcsTime
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = unknown
+	}
+	if info.Commit == "" {
+		info.Commit = unknown
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = unknown
+	}
+	return info
+}
@@ -2,9 +2,11 @@
 package cache
 
 import (
+	"container/list"
 	"context"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 	"l0_wb/internal/model"
@@ -12,85 +14,177 @@ import (
 	"l0_wb/internal/util"
 )
 
+// entry — элемент списка вытеснения кэша.
+type entry struct {
+	order   *model.Order
+	expires time.Time // Момент устаревания записи (нулевое значение — не устаревает)
+}
+
 // OrderCache представляет собой кэш для хранения заказов в памяти.
+//
+//	По умолчанию кэш не ограничен. При заданном максимальном числе записей
+//	вытесняются давно не запрошенные заказы (LRU), при заданном TTL заказ
+//	перестаёт отдаваться по истечении этого времени с момента записи.
 type OrderCache struct {
-	mu     sync.RWMutex            // Мьютекс для синхронизации доступа к кэшу
-	cache  map[string]*model.Order // Словарь, где ключ — order_uid, значение — объект заказа
-	logger *zap.Logger
+	mu       sync.Mutex               // Мьютекс для синхронизации доступа к кэшу
+	cache    map[string]*list.Element // Словарь, где ключ — order_uid, значение — элемент списка lru
+	lru      *list.List               // Записи *entry от недавно запрошенных к давно не запрошенным
+	warmed   atomic.Bool              // Признак завершённой загрузки заказов из БД
+	complete atomic.Bool              // Признак того, что кэш содержит все заказы БД (сбрасывается при очистке и вытеснении)
+	logger   *zap.Logger
+
+	maxEntries    int           // Максимальное число заказов (0 — без ограничения)
+	ttl           time.Duration // Время жизни записи (0 — без ограничения)
+	preloadWindow time.Duration // Глубина загрузки из БД по дате создания (0 — все заказы)
+	now           func() time.Time
+}
+
+// Option настраивает OrderCache.
+type Option func(*OrderCache)
+
+// WithMaxEntries ограничивает число заказов в кэше; при переполнении вытесняются давно не запрошенные.
+//
+//	Параметры:
+//	- n: максимальное число заказов (0 — без ограничения).
+func WithMaxEntries(n int) Option {
+	return func(c *OrderCache) {
+		c.maxEntries = max(n, 0)
+	}
+}
+
+// WithTTL задаёт время жизни записи кэша.
+//
+//	Параметры:
+//	- ttl: время с момента записи, после которого заказ не отдаётся из кэша (0 — без ограничения).
+func WithTTL(ttl time.Duration) Option {
+	return func(c *OrderCache) {
+		c.ttl = max(ttl, 0)
+	}
+}
+
+// WithPreloadWindow ограничивает загрузку из БД при старте заказами, созданными за указанный период.
+//
+//	Параметры:
+//	- window: глубина загрузки по дате создания (0 — все заказы).
+func WithPreloadWindow(window time.Duration) Option {
+	return func(c *OrderCache) {
+		c.preloadWindow = max(window, 0)
+	}
 }
 
 // NewOrderCache создает новый пустой кэш заказов.
 //
+//	Параметры:
+//	- opts: опции кэша (ограничение размера, TTL, глубина загрузки).
 //	Возвращает:
 //	- *OrderCache: экземпляр кэша.
-func NewOrderCache() *OrderCache {
-	return &OrderCache{
-		cache:  make(map[string]*model.Order),
+func NewOrderCache(opts ...Option) *OrderCache {
+	c := &OrderCache{
+		cache:  make(map[string]*list.Element),
+		lru:    list.New(),
 		logger: util.GetLogger(),
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-// LoadFromDB загружает все заказы из базы данных в кэш.
+// LoadFromDB загружает заказы из базы данных в кэш.
 //
 //	Этот метод рекомендуется вызывать при старте приложения после инициализации БД.
+//	Загружаются заказы за период WithPreloadWindow (по умолчанию все); если
+//	их больше WithMaxEntries, загружаются самые новые.
 //	Параметры:
 //	- ctx: контекст выполнения.
 //	- ordersRepo: репозиторий для работы с таблицей orders.
-//	- deliveriesRepo: репозиторий для работы с таблицей deliveries.
-//	- paymentsRepo: репозиторий для работы с таблицей payments.
-//	- itemsRepo: репозиторий для работы с таблицей items.
-//	- db: подключение к базе данных.
 //	Возвращает:
 //	- error: ошибку, если произошел сбой при загрузке данных из БД.
-func (c *OrderCache) LoadFromDB(
-	ctx context.Context,
-	ordersRepo repository.OrdersRepository,
-	deliveriesRepo repository.DeliveriesRepository,
-	paymentsRepo repository.PaymentsRepository,
-	itemsRepo repository.ItemsRepository,
-	db *pgxpool.Pool,
-) error {
+func (c *OrderCache) LoadFromDB(ctx context.Context, ordersRepo repository.OrdersRepository) error {
 	c.logger.Info("Starting to load orders into cache")
-	// TODO если нет возможности получить все order_uid из БД, реализовать метод GetAllOrderIDs() из ordersRepo
-
-	// Получаем список всех order_uid из БД
-	orderUIDs, err := getAllOrderUIDs(ctx, db)
+	// Получаем список order_uid из БД в порядке создания
+	var orderUIDs []string
+	var err error
+	if c.preloadWindow > 0 {
+		orderUIDs, err = ordersRepo.GetOrderIDsSince(ctx, c.now().Add(-c.preloadWindow))
+	} else {
+		orderUIDs, err = ordersRepo.GetAllOrderIDs(ctx)
+	}
 	if err != nil {
 		c.logger.Error("Failed to fetch order UIDs from database", zap.Error(err))
 		return err
 	}
 	c.logger.Info("Fetched order UIDs", zap.Int("count", len(orderUIDs)))
 
+	// Кэш полон, только если загружены все заказы и ни один не устареет
+	complete := c.preloadWindow == 0 && c.ttl == 0
+	if c.maxEntries > 0 && len(orderUIDs) > c.maxEntries {
+		orderUIDs = orderUIDs[len(orderUIDs)-c.maxEntries:]
+		complete = false
+	}
+
 	// Загружаем полный заказ для каждого order_uid и сохраняем в кэш
 	for _, uid := range orderUIDs {
-		o, err := loadFullOrder(ctx, uid, ordersRepo, deliveriesRepo, paymentsRepo, itemsRepo)
+		o, err := ordersRepo.GetFullByID(ctx, uid)
 		if err != nil {
 			c.logger.Warn("Failed to load order", zap.String("order_uid", uid), zap.Error(err))
 			continue
 		}
 		c.mu.Lock()
-		c.cache[uid] = o
+		c.set(o)
 		c.mu.Unlock()
 	}
 
-	c.logger.Info("Finished loading orders into cache", zap.Int("cached_orders", len(c.cache)))
+	c.logger.Info("Finished loading orders into cache", zap.Int("cached_orders", c.Len()))
+	c.warmed.Store(true)
+	c.complete.Store(complete)
 	return nil
 }
 
+// Warmed сообщает, завершена ли загрузка заказов из БД.
+func (c *OrderCache) Warmed() bool {
+	return c.warmed.Load()
+}
+
+// Complete сообщает, содержит ли кэш все заказы БД.
+//
+//	Кэш полон после загрузки из БД и пополняется при сохранении заказов;
+//	после Clear он содержит только заказы, сохранённые с момента очистки.
+//	Кэш с TTL или глубиной загрузки не бывает полным, ограниченный по
+//	размеру — перестаёт быть полным при первом вытеснении.
+func (c *OrderCache) Complete() bool {
+	return c.complete.Load()
+}
+
 // Get возвращает заказ из кэша по его order_uid.
 //
 //	Параметры:
 //	- orderUID: уникальный идентификатор заказа.
 //	Возвращает:
-//	- *model.Order: объект заказа (nil, если не найден).
+//	- *model.Order: объект заказа (nil, если не найден или устарел).
 func (c *OrderCache) Get(orderUID string) *model.Order {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	order := c.cache[orderUID]
-	if order == nil {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.lookup(orderUID)
+	if e == nil {
 		c.logger.Warn("Order not found in cache", zap.String("order_uid", orderUID))
+		return nil
 	}
-	return order
+	c.lru.MoveToFront(c.cache[orderUID])
+	return e.order
+}
+
+// Contains сообщает, есть ли заказ в кэше.
+//
+//	Параметры:
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- bool: true, если заказ есть в кэше.
+func (c *OrderCache) Contains(orderUID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lookup(orderUID) != nil
 }
 
 // Set добавляет или обновляет заказ в кэше.
@@ -100,93 +194,102 @@ func (c *OrderCache) Get(orderUID string) *model.Order {
 func (c *OrderCache) Set(order *model.Order) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.cache[order.OrderUID] = order
+	c.set(order)
 	c.logger.Info("Order added to cache", zap.String("order_uid", order.OrderUID))
 }
 
-// loadFullOrder загружает полный заказ из базы данных, включая связанные данные (доставка, оплата, товары).
+// Delete удаляет заказ из кэша.
 //
 //	Параметры:
-//	- ctx: контекст выполнения.
 //	- orderUID: уникальный идентификатор заказа.
-//	- ordersRepo: репозиторий для работы с таблицей orders.
-//	- deliveriesRepo: репозиторий для работы с таблицей deliveries.
-//	- paymentsRepo: репозиторий для работы с таблицей payments.
-//	- itemsRepo: репозиторий для работы с таблицей items.
 //	Возвращает:
-//	- *model.Order: заполненный объект заказа.
-//	- error: ошибку, если не удалось загрузить данные.
-func loadFullOrder(
-	ctx context.Context,
-	orderUID string,
-	ordersRepo repository.OrdersRepository,
-	deliveriesRepo repository.DeliveriesRepository,
-	paymentsRepo repository.PaymentsRepository,
-	itemsRepo repository.ItemsRepository,
-) (*model.Order, error) {
-	o, err := ordersRepo.GetByID(ctx, orderUID)
-	if err != nil {
-		return nil, err
+//	- bool: true, если заказ был в кэше.
+func (c *OrderCache) Delete(orderUID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.cache[orderUID]
+	if ok {
+		c.remove(el)
 	}
+	return ok
+}
 
-	d, err := deliveriesRepo.GetByOrderID(ctx, orderUID)
-	if err != nil {
-		return nil, err
-	}
-	p, err := paymentsRepo.GetByOrderID(ctx, orderUID)
-	if err != nil {
-		return nil, err
-	}
-	it, err := itemsRepo.GetByOrderID(ctx, orderUID)
-	if err != nil {
-		return nil, err
-	}
+// Len возвращает число заказов в кэше.
+func (c *OrderCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.cache)
+}
 
-	o.Delivery = *d
-	o.Payment = *p
-	o.Items = it
-	return o, nil
+// Clear удаляет все заказы из кэша.
+//
+//	Возвращает:
+//	- int: число удалённых заказов.
+func (c *OrderCache) Clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(c.cache)
+	c.cache = make(map[string]*list.Element)
+	c.lru.Init()
+	c.complete.Store(false)
+	c.logger.Info("Order cache cleared", zap.Int("removed", n))
+	return n
 }
 
 // GetAll возвращает список всех заказов, хранящихся в кэше.
 //
+//	Устаревшие заказы удаляются и в список не попадают.
 //	Возвращает:
 //	- []model.Order: список всех заказов.
 func (c *OrderCache) GetAll() []*model.Order {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	orders := make([]*model.Order, 0, len(c.cache))
-	for _, order := range c.cache {
-		orders = append(orders, order)
+	for uid := range c.cache {
+		if e := c.lookup(uid); e != nil {
+			orders = append(orders, e.order)
+		}
 	}
 
 	c.logger.Info("Fetched all orders from cache", zap.Int("count", len(orders)))
 	return orders
 }
 
-// getAllOrderUIDs возвращает список всех order_uid из таблицы orders.
-//
-//	Параметры:
-//	- ctx: контекст выполнения.
-//	- db: подключение к базе данных.
-//	Возвращает:
-//	- []string: список order_uid.
-//	- error: ошибку, если не удалось выполнить запрос.
-func getAllOrderUIDs(ctx context.Context, db *pgxpool.Pool) ([]string, error) {
-	rows, err := db.Query(ctx, `SELECT order_uid FROM orders`)
-	if err != nil {
-		return nil, err
+// lookup возвращает запись заказа, удаляя её, если она устарела. Вызывается под c.mu.
+func (c *OrderCache) lookup(orderUID string) *entry {
+	el, ok := c.cache[orderUID]
+	if !ok {
+		return nil
+	}
+	e := el.Value.(*entry)
+	if !e.expires.IsZero() && !c.now().Before(e.expires) {
+		c.remove(el)
+		return nil
 	}
-	defer rows.Close()
+	return e
+}
 
-	var uids []string
-	for rows.Next() {
-		var uid string
-		if err := rows.Scan(&uid); err != nil {
-			return nil, err
-		}
-		uids = append(uids, uid)
+// set добавляет или обновляет запись и вытесняет давно не запрошенные заказы сверх лимита. Вызывается под c.mu.
+func (c *OrderCache) set(order *model.Order) {
+	e := &entry{order: order}
+	if c.ttl > 0 {
+		e.expires = c.now().Add(c.ttl)
 	}
-	return uids, rows.Err()
+	if el, ok := c.cache[order.OrderUID]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.cache[order.OrderUID] = c.lru.PushFront(e)
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+		c.complete.Store(false)
+	}
+}
+
+// remove удаляет элемент из кэша. Вызывается под c.mu.
+func (c *OrderCache) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.cache, el.Value.(*entry).order.OrderUID)
 }
@@ -1,9 +1,12 @@
 package cache
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"l0_wb/internal/model"
+	"l0_wb/internal/repository/mocks"
 	"l0_wb/internal/util"
 )
 
@@ -47,3 +50,43 @@ func TestOrderCache(t *testing.T) {
 		t.Errorf("expected updated TrackNumber updated_track, got %s", updatedGot.TrackNumber)
 	}
 }
+
+// TestOrderCacheLimits проверяет вытеснение по размеру, TTL и загрузку за период.
+func TestOrderCacheLimits(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewOrderCache(WithMaxEntries(2), WithTTL(time.Minute), WithPreloadWindow(24*time.Hour))
+	cache.now = func() time.Time { return now }
+
+	var since time.Time
+	repo := &mocks.OrdersRepositoryMock{
+		GetOrderIDsSinceFunc: func(_ context.Context, t time.Time) ([]string, error) {
+			since = t
+			return []string{"a", "b", "c"}, nil
+		},
+		GetFullByIDFunc: func(_ context.Context, uid string) (*model.Order, error) {
+			return &model.Order{OrderUID: uid}, nil
+		},
+	}
+	if err := cache.LoadFromDB(context.Background(), repo); err != nil {
+		t.Fatalf("LoadFromDB: %v", err)
+	}
+	if !since.Equal(now.Add(-24 * time.Hour)) {
+		t.Errorf("expected preload since %v, got %v", now.Add(-24*time.Hour), since)
+	}
+	// Загружаются самые новые заказы, кэш не считается полным
+	if cache.Len() != 2 || cache.Contains("a") || cache.Complete() {
+		t.Fatalf("expected newest 2 orders in incomplete cache, got len=%d complete=%v", cache.Len(), cache.Complete())
+	}
+
+	// Запрошенный заказ остаётся, вытесняется давно не запрошенный
+	cache.Get("b")
+	cache.Set(&model.Order{OrderUID: "d"})
+	if !cache.Contains("b") || cache.Contains("c") {
+		t.Error("expected least recently used order to be evicted")
+	}
+
+	now = now.Add(time.Minute)
+	if cache.Get("b") != nil || cache.Len() != 1 {
+		t.Errorf("expected expired order to be dropped, len=%d", cache.Len())
+	}
+}
@@ -0,0 +1,241 @@
+// Package cdc propagates order changes made directly in the database into the in-memory cache.
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/metrics"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/util"
+)
+
+// Channel — канал LISTEN/NOTIFY, в который триггеры таблиц заказа пишут order_uid
+// и application_name записавшего соединения (миграции 0013 и 0014).
+const Channel = "order_changes"
+
+// Итоги обновления кэша, метка result метрики cache_cdc_events_total.
+const (
+	resultRefreshed = "refreshed"
+	resultEvicted   = "evicted"
+	resultSkipped   = "skipped"
+	resultFailed    = "failed"
+	resultOwn       = "own"
+)
+
+// Параметры по умолчанию.
+const (
+	defaultDebounce       = 200 * time.Millisecond
+	defaultReconnectDelay = 5 * time.Second
+	// refreshTimeout ограничивает время обновления одного заказа.
+	refreshTimeout = 5 * time.Second
+)
+
+// Listener подписывается на уведомления об изменении заказов и обновляет кэш.
+//
+//	Уведомления отправляют триггеры таблиц orders, deliveries, payments и
+//	items при любой записи, в том числе из psql, скриптов исправления и
+//	других экземпляров сервиса. order_uid из уведомлений копятся в течение
+//	debounce, после чего каждый заказ перечитывается из БД один раз.
+//	Обновляются только заказы, которые уже есть в кэше, а если кэш содержит
+//	все заказы БД, — ещё и новые: массовая загрузка старых заказов не
+//	вытесняет из кэша рабочие. Уведомления о записях самого экземпляра
+//	(WithOrigin) пропускаются: сервис обновляет кэш при сохранении сам.
+type Listener struct {
+	pool           *pgxpool.Pool
+	orders         repository.OrdersRepository
+	cache          *cache.OrderCache
+	origin         string
+	debounce       time.Duration
+	reconnectDelay time.Duration
+	logger         *zap.Logger
+}
+
+// Option настраивает Listener.
+type Option func(*Listener)
+
+// WithDebounce задаёт время накопления уведомлений перед обновлением кэша.
+func WithDebounce(d time.Duration) Option {
+	return func(l *Listener) {
+		l.debounce = d
+	}
+}
+
+// WithOrigin задаёт application_name соединений сервиса: уведомления о его
+// собственных записях не вызывают повторного чтения заказа.
+func WithOrigin(applicationName string) Option {
+	return func(l *Listener) {
+		l.origin = applicationName
+	}
+}
+
+// WithReconnectDelay задаёт паузу перед повторной подпиской после обрыва соединения.
+func WithReconnectDelay(d time.Duration) Option {
+	return func(l *Listener) {
+		l.reconnectDelay = d
+	}
+}
+
+// New создаёт Listener.
+//
+//	Параметры:
+//	- pool: пул соединений к основной БД; на время работы Listener занимает одно соединение.
+//	- orders: репозиторий, из которого перечитываются изменённые заказы
+//	  (основная БД, чтобы не получить устаревшие данные реплики).
+//	- orderCache: обновляемый кэш заказов.
+//	- opts: дополнительные настройки.
+//	Возвращает:
+//	- *Listener: экземпляр Listener.
+func New(pool *pgxpool.Pool, orders repository.OrdersRepository, orderCache *cache.OrderCache, opts ...Option) *Listener {
+	l := &Listener{
+		pool:           pool,
+		orders:         orders,
+		cache:          orderCache,
+		debounce:       defaultDebounce,
+		reconnectDelay: defaultReconnectDelay,
+		logger:         util.GetLogger().With(zap.String("component", "cdc")),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Run получает уведомления до отмены контекста.
+//
+//	После обрыва соединения подписка возобновляется через reconnectDelay.
+//	Уведомления, отправленные, пока подписки не было, теряются: такие
+//	изменения попадут в кэш при следующем сохранении заказа или по TTL.
+//	Параметры:
+//	- ctx: контекст выполнения для остановки подписки.
+func (l *Listener) Run(ctx context.Context) {
+	l.logger.Info("Order change listener started", zap.String("channel", Channel), zap.Duration("debounce", l.debounce))
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			l.logger.Info("Order change listener stopped")
+			return
+		}
+		l.logger.Warn("Order change subscription lost, notifications may be missed until it is restored",
+			zap.Duration("retry_in", l.reconnectDelay), zap.Error(err))
+		metrics.RecordError("cdc", "listen")
+
+		timer := time.NewTimer(l.reconnectDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.logger.Info("Order change listener stopped")
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// listen подписывается на Channel и обрабатывает уведомления до ошибки соединения или отмены контекста.
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// Соединение с активной подпиской не возвращается в пул
+	defer func() {
+		_ = conn.Conn().Close(context.Background())
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{Channel}.Sanitize()); err != nil {
+		return err
+	}
+
+	pending := make(map[string]struct{})
+	var flushAt time.Time
+	for {
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if len(pending) > 0 {
+			waitCtx, cancel = context.WithDeadline(ctx, flushAt)
+		}
+		n, err := conn.Conn().WaitForNotification(waitCtx)
+		cancel()
+
+		switch {
+		case err == nil:
+			orderUID, own := l.parse(n.Payload)
+			if own {
+				metrics.RecordCacheCDCEvent(resultOwn)
+				continue
+			}
+			if len(pending) == 0 {
+				flushAt = time.Now().Add(l.debounce)
+			}
+			pending[orderUID] = struct{}{}
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(err, context.DeadlineExceeded):
+			for orderUID := range pending {
+				l.refresh(ctx, orderUID)
+			}
+			clear(pending)
+		default:
+			return err
+		}
+	}
+}
+
+// change — уведомление триггера об изменении заказа.
+type change struct {
+	OrderUID string `json:"order_uid"`
+	Origin   string `json:"origin"` // application_name записавшего соединения
+}
+
+// parse разбирает уведомление и сообщает, вызвано ли оно записью самого экземпляра.
+//
+//	Триггеры миграции 0013 передают только order_uid, без JSON: такие
+//	уведомления считаются чужими.
+//	Параметры:
+//	- payload: текст уведомления.
+//	Возвращает:
+//	- string: order_uid изменённого заказа.
+//	- bool: true, если заказ изменён соединением с application_name экземпляра.
+func (l *Listener) parse(payload string) (string, bool) {
+	var c change
+	if err := json.Unmarshal([]byte(payload), &c); err != nil || c.OrderUID == "" {
+		return payload, false
+	}
+	return c.OrderUID, l.origin != "" && c.Origin == l.origin
+}
+
+// refresh перечитывает заказ из БД и обновляет или удаляет его в кэше.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- orderUID: идентификатор изменённого заказа.
+func (l *Listener) refresh(ctx context.Context, orderUID string) {
+	if !l.cache.Contains(orderUID) && !l.cache.Complete() {
+		metrics.RecordCacheCDCEvent(resultSkipped)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+	order, err := l.orders.GetFullByID(ctx, orderUID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows) || (err == nil && order.DeletedAt != nil):
+		// Удалённые заказы в кэше не хранятся
+		l.cache.Delete(orderUID)
+		metrics.RecordCacheCDCEvent(resultEvicted)
+	case err != nil:
+		// Устаревший заказ лучше убрать: следующий запрос перечитает его из БД
+		l.cache.Delete(orderUID)
+		metrics.RecordCacheCDCEvent(resultFailed)
+		l.logger.Warn("Failed to refresh changed order, evicted from cache", zap.String("order_uid", orderUID), zap.Error(err))
+	default:
+		l.cache.Set(order)
+		metrics.RecordCacheCDCEvent(resultRefreshed)
+	}
+}
@@ -0,0 +1,106 @@
+package cdc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository/mocks"
+	"l0_wb/internal/util"
+)
+
+// TestListenerRefresh проверяет обновление, удаление и пропуск заказов в кэше по уведомлениям.
+func TestListenerRefresh(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	defer util.SyncLogger()
+
+	deletedAt := time.Now()
+	repo := &mocks.OrdersRepositoryMock{
+		GetFullByIDFunc: func(_ context.Context, uid string) (*model.Order, error) {
+			switch uid {
+			case "deleted":
+				return &model.Order{OrderUID: uid, DeletedAt: &deletedAt}, nil
+			case "missing":
+				return nil, pgx.ErrNoRows
+			case "broken":
+				return nil, errors.New("connection reset")
+			}
+			return &model.Order{OrderUID: uid, TrackNumber: "updated"}, nil
+		},
+	}
+
+	// Кэш не загружен из БД и не считается полным: новые заказы не добавляются
+	orderCache := cache.NewOrderCache()
+	for _, uid := range []string{"changed", "deleted", "missing", "broken"} {
+		orderCache.Set(&model.Order{OrderUID: uid})
+	}
+	l := New(nil, repo, orderCache)
+	for _, uid := range []string{"changed", "deleted", "missing", "broken", "new"} {
+		l.refresh(context.Background(), uid)
+	}
+
+	if got := orderCache.Get("changed"); got == nil || got.TrackNumber != "updated" {
+		t.Errorf("expected changed order to be refreshed, got %+v", got)
+	}
+	for _, uid := range []string{"deleted", "missing", "broken"} {
+		if orderCache.Contains(uid) {
+			t.Errorf("expected order %q to be evicted", uid)
+		}
+	}
+	if orderCache.Contains("new") {
+		t.Error("orders absent from an incomplete cache must not be added")
+	}
+	if calls := len(repo.GetFullByIDCalls()); calls != 4 {
+		t.Errorf("expected 4 reads, got %d", calls)
+	}
+
+	// Полный кэш пополняется новыми заказами
+	complete := cache.NewOrderCache()
+	if err := complete.LoadFromDB(context.Background(), &mocks.OrdersRepositoryMock{
+		GetAllOrderIDsFunc: func(context.Context) ([]string, error) { return nil, nil },
+	}); err != nil {
+		t.Fatalf("LoadFromDB: %v", err)
+	}
+	New(nil, repo, complete).refresh(context.Background(), "new")
+	if !complete.Contains("new") {
+		t.Error("expected new order to be added to a complete cache")
+	}
+}
+
+// TestListenerParse проверяет разбор уведомлений и пропуск собственных записей экземпляра.
+func TestListenerParse(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	defer util.SyncLogger()
+
+	l := New(nil, nil, cache.NewOrderCache(), WithOrigin("l0_wb-self"))
+	tests := []struct {
+		payload string
+		uid     string
+		own     bool
+	}{
+		{`{"order_uid":"a","origin":"l0_wb-self"}`, "a", true},
+		{`{"order_uid":"b","origin":"l0_wb-other"}`, "b", false},
+		{`{"order_uid":"c","origin":"psql"}`, "c", false},
+		// Уведомление триггера миграции 0013
+		{"d", "d", false},
+	}
+	for _, tt := range tests {
+		uid, own := l.parse(tt.payload)
+		if uid != tt.uid || own != tt.own {
+			t.Errorf("parse(%q) = %q, %v; want %q, %v", tt.payload, uid, own, tt.uid, tt.own)
+		}
+	}
+
+	// Без WithOrigin собственные записи не распознаются
+	if _, own := New(nil, nil, cache.NewOrderCache()).parse(`{"order_uid":"a","origin":""}`); own {
+		t.Error("notifications must not be treated as own without an origin")
+	}
+}
@@ -3,82 +3,982 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Допустимые значения REPLAY_TARGET.
+const (
+	ReplayTargetDryRun  = "dry-run" // Только декодирование и валидация, без сохранения
+	ReplayTargetScratch = "scratch" // Сохранение в отдельную схему БД
+)
+
+// Допустимые значения CACHE_BACKEND.
+const (
+	CacheBackendMemory = "memory" // Кэш заказов в памяти процесса
+	CacheBackendRedis  = "redis"  // Общий кэш в Redis (в этой сборке не поддерживается)
+)
+
+// Допустимые значения ORDER_EVENTS_MODE.
+const (
+	OrderEventsModeFull = "full" // Событие содержит заказ целиком
+	OrderEventsModeDiff = "diff" // Событие содержит только изменённые поля
+)
+
+// Допустимые значения LOG_FORMAT.
+const (
+	LogFormatJSON    = "json"    // Записи в формате JSON для сборщиков журналов
+	LogFormatConsole = "console" // Читаемые записи для локальной разработки
+)
+
+// Окружения приложения (APP_ENV).
+const (
+	AppEnvDev        = "dev"
+	AppEnvProduction = "production"
 )
 
+// APIKeyConfig описывает ключ API, заданный через конфигурацию.
+type APIKeyConfig struct {
+	Name      string // Имя клиента
+	Key       string // Значение ключа
+	RateLimit int    // Лимит запросов в секунду (0 — лимит по умолчанию)
+}
+
 // Config содержит все необходимые параметры конфигурации приложения.
 type Config struct {
+	AppEnv   string // Окружение приложения: dev или production
+	LogLevel string // Уровень журналирования: debug, info, warn или error
+
+	// Параметры вывода журнала приложения
+	LogFormat      string // Формат записей: json или console
+	LogOutput      string // Путь к файлу журнала или stdout/stderr
+	LogMaxSizeMB   int    // Размер файла журнала, после которого он ротируется, МБ
+	LogMaxBackups  int    // Сколько ротированных файлов хранить (0 — все)
+	LogMaxAgeDays  int    // Сколько дней хранить ротированные файлы (0 — не удалять по возрасту)
+	LogCompress    bool   // Сжимать ротированные файлы gzip
+	LogRawPayloads bool   // Писать содержимое сообщений в журнал без маскирования персональных данных
+
+	SecretsRefreshInterval time.Duration     // Период перечитывания секретов для ротации учётных данных (0 — не перечитывать)
+	secrets                *secretRefresher  // Источники секретов для Secret (nil — ротация выключена)
+	configFile             string            // Путь к файлу конфигурации (пусто — не задан)
+	sources                map[string]string // Источник значения каждого параметра (см. Sources)
+
 	// Параметры подключения к базе данных
-	DBHost     string // Хост базы данных
-	DBPort     int    // Порт базы данных
+	DBHost     string // Хост базы данных; несколько хостов через запятую (host или host:port) для переключения на резервный
+	DBPort     int    // Порт базы данных для хостов DB_HOST без порта
 	DBUser     string // Имя пользователя базы данных
 	DBPassword string // Пароль пользователя базы данных
 	DBName     string // Имя базы данных
 
+	DBTargetSessionAttrs string // Требование к серверу из DB_HOST: read-write, primary, standby, prefer-standby, read-only или any
+
+	// Параметры TLS-подключения к базе данных
+	DBSSLMode     string // Режим sslmode: disable, allow, prefer, require, verify-ca или verify-full
+	DBSSLRootCert string // Путь к сертификату центра сертификации для verify-ca и verify-full
+	DBSSLCert     string // Путь к клиентскому сертификату
+	DBSSLKey      string // Путь к закрытому ключу клиентского сертификата
+
+	DBDSN string // Строка подключения целиком; заменяет параметры DB_HOST..DB_SSLKEY (пусто — собирается из них)
+
+	DBReplicaDSNs []string // Строки подключения к репликам для чтения (пусто — чтение идёт с основной БД)
+
+	DBSlowQueryThreshold time.Duration // Длительность запроса, начиная с которой он пишется в журнал (0 — не писать)
+
+	DBStatementTimeout time.Duration // statement_timeout соединений пула на сервере (0 — без ограничения)
+	DBQueryTimeout     time.Duration // Крайний срок одной операции репозитория или транзакции (0 — без ограничения)
+
+	DBAutoMigrate bool // Применять миграции схемы при запуске (false — только через cmd/migrate)
+
+	// Параметры пула соединений (основная БД и каждая реплика)
+	DBPoolMaxConns          int           // Максимальное число соединений
+	DBPoolMinConns          int           // Число соединений, поддерживаемых открытыми
+	DBPoolHealthCheckPeriod time.Duration // Период проверки простаивающих соединений
+	DBPoolMaxConnLifetime   time.Duration // Максимальное время жизни соединения
+	DBPoolMaxConnIdleTime   time.Duration // Максимальное время простоя соединения
+
 	// Параметры Kafka
-	KafkaBrokers []string // Адреса брокеров Kafka
+	KafkaBrokers []string // Адреса брокеров Kafka в формате host:port
 	KafkaTopic   string   // Топик Kafka для обработки заказов
 	KafkaGroupID string   // Группа потребителей Kafka
 
+	KafkaSASLUser     string // Имя пользователя SASL/PLAIN (пусто — без аутентификации)
+	KafkaSASLPassword string // Пароль SASL/PLAIN
+
+	KafkaWriteQueueSize int           // Ёмкость очереди заказов между чтением Kafka и сохранением в БД
+	KafkaWriters        int           // Число обработчиков, сохраняющих заказы из очереди
+	KafkaBatchSize      int           // Максимальный размер батча одного SaveBatch
+	KafkaBatchTimeout   time.Duration // Максимальное ожидание заполнения батча
+	KafkaDrainTimeout   time.Duration // Время на сохранение заказов из очереди при остановке
+
+	OrderEventsTopic string // Топик событий об изменении заказов (пусто — события не публикуются)
+	OrderEventsMode  string // Формат событий: full или diff
+
+	OrderAmountTolerance int           // Допустимое расхождение payment.amount с суммой товаров и доставки
+	OrderDedupWindow     time.Duration // Окно, в котором повтор неизменённого заказа пропускается без транзакции (0 — выключено)
+
 	// Параметры HTTP-сервера
-	HTTPPort string // Порт, на котором работает HTTP-сервер
+	HTTPPort             string // Порт, на котором работает HTTP-сервер
+	OrderStreamThreshold int    // Количество товаров, начиная с которого заказ отдаётся потоково
+
+	SSEHeartbeatInterval time.Duration // Интервал heartbeat-комментариев в потоке SSE
+
+	StaticDir string // Каталог статических файлов вместо встроенных в бинарный файл (для локальной разработки)
+
+	// Параметры экспорта метрик Prometheus
+	MetricsAddr         string   // Адрес, на котором слушает сервер метрик (пусто — все интерфейсы)
+	MetricsPort         string   // Порт сервера метрик
+	MetricsOnMainServer bool     // Отдавать /metrics основным HTTP-сервером вместо отдельного
+	MetricsDiskPaths    []string // Каталоги, заполнение дисков которых публикуется в метриках
+
+	// Границы корзин гистограмм задержек, в секундах
+	MetricsOrderProcessingBuckets []float64 // order_processing_duration_seconds
+	MetricsHTTPBuckets            []float64 // http_response_time_seconds
+	MetricsDBBuckets              []float64 // database_query_duration_seconds и database_statement_duration_seconds
+
+	// Параметры TLS
+	TLSCertFile         string   // Путь к сертификату (PEM)
+	TLSKeyFile          string   // Путь к закрытому ключу (PEM)
+	TLSAutocertDomains  []string // Домены для автоматического получения сертификатов Let's Encrypt
+	TLSAutocertCacheDir string   // Каталог для хранения полученных сертификатов
+	TLSAutocertEmail    string   // Контактный email для Let's Encrypt
+	HTTPRedirectPort    string   // Порт HTTP, с которого запросы перенаправляются на HTTPS (пусто — не слушать)
 
 	ShutdownTimeout time.Duration // Таймаут на завершение работы приложения
+
+	// Ограничения запросов
+	RequestTimeout      time.Duration // Максимальное время обработки запроса API (0 — без ограничения)
+	MaxRequestBodyBytes int64         // Максимальный размер тела запроса в байтах (0 — без ограничения)
+	BulkMaxBodyBytes    int64         // Максимальный размер тела массовой загрузки заказов в байтах (0 — без ограничения)
+	BulkMaxOrders       int           // Максимальное число заказов в одной массовой загрузке (0 — без ограничения)
+
+	// Параметры сжатия ответов
+	CompressionEnabled bool // Сжимать ответы gzip по Accept-Encoding
+	CompressionMinSize int  // Минимальный размер ответа в байтах для сжатия
+	CompressionLevel   int  // Уровень сжатия gzip (-1 — по умолчанию, 1..9)
+
+	StatsCacheTTL time.Duration // Время кэширования статистики заказов (0 — без кэша)
+
+	// Параметры кэша заказов
+	CacheBackend       string        // Хранилище кэша: memory
+	CacheMaxEntries    int           // Максимальное число заказов в кэше (0 — без ограничения)
+	CacheTTL           time.Duration // Время жизни заказа в кэше (0 — без ограничения)
+	CachePreloadWindow time.Duration // Глубина загрузки заказов в кэш при старте по дате создания (0 — все заказы)
+	CacheCDCEnabled    bool          // Обновлять кэш по уведомлениям БД об изменении заказов (LISTEN/NOTIFY)
+	CacheCDCDebounce   time.Duration // Время накопления уведомлений перед обновлением кэша
+
+	// Параметры журнала доступа
+	AccessLogEnabled     bool   // Писать журнал доступа HTTP
+	AccessLogOutput      string // Путь к файлу журнала или stdout/stderr
+	AccessLogSampleEvery int    // Записывать каждый N-й успешный запрос (ошибки пишутся всегда)
+
+	// Параметры пагинации
+	CursorSecret string        // Секрет для подписи курсоров пагинации (пусто — случайный при старте)
+	CursorTTL    time.Duration // Срок действия курсора
+
+	// Параметры CORS
+	CORSAllowedOrigins   []string      // Разрешённые источники ("*" — любой)
+	CORSAllowedMethods   []string      // Разрешённые методы
+	CORSAllowedHeaders   []string      // Разрешённые заголовки запроса
+	CORSMaxAge           time.Duration // Время кэширования ответа на preflight-запрос
+	CORSAllowCredentials bool          // Разрешать запросы с cookie и заголовком Authorization
+
+	// Параметры аутентификации по API-ключам
+	APIKeys                []APIKeyConfig // Статически заданные ключи
+	APIKeysFromDB          bool           // Искать ключи в таблице api_keys
+	APIKeyDefaultRateLimit int            // Лимит запросов в секунду по умолчанию (0 — без ограничения)
+
+	// Параметры административных маршрутов /api/admin
+	AdminToken    string // Токен администратора (Authorization: Bearer)
+	AdminUser     string // Имя пользователя для Basic-аутентификации
+	AdminPassword string // Пароль для Basic-аутентификации
+
+	// Параметры шифрования персональных данных получателя
+	PIIEncryptionKey          string   // Основной ключ AES-256 в base64 (пусто — новые значения не шифруются)
+	PIIEncryptionPreviousKeys []string // Прежние ключи в base64, только для расшифровки до перешифрования
+
+	// Параметры отправки ошибок в Sentry
+	SentryDSN         string  // DSN проекта Sentry (пусто — ошибки не отправляются)
+	SentryEnvironment string  // Окружение в событиях (по умолчанию APP_ENV)
+	SentrySampleRate  float64 // Доля отправляемых событий (0..1)
+
+	// Параметры режима воспроизведения заказов из БД
+	ReplayEnabled bool   // Вместо чтения Kafka прогнать заказы из БД через конвейер обработки
+	ReplayRate    int    // Скорость воспроизведения, заказов в секунду
+	ReplayTarget  string // Куда сохранять результат: dry-run (без сохранения) или scratch (отдельная схема)
+	ReplaySchema  string // Имя схемы для ReplayTarget=scratch
+
+	// Параметры SLI и защиты от перегрузки
+	SLIWindow           time.Duration // Длительность скользящего окна для расчёта доли ошибок
+	SLIMaxErrorRate     float64       // Допустимая доля ошибок (0..1)
+	SLIMinEvents        int           // Минимум событий в окне для принятия решений
+	LoadSheddingEnabled bool          // Отбрасывать часть запросов при превышении доли ошибок HTTP
+
+	// Параметры наблюдения за зависимостями
+	WatchdogEnabled           bool          // Отслеживать доступность БД и Kafka и фиксировать инциденты
+	WatchdogInterval          time.Duration // Интервал проверки зависимостей
+	WatchdogFailureThreshold  int           // Число неудачных проверок подряд для открытия инцидента
+	WatchdogRecoveryThreshold int           // Число успешных проверок подряд для закрытия инцидента
+
+	// Параметры автомата защиты БД
+	DBBreakerEnabled     bool          // Прекращать обращения к БД при сбоях соединения и высокой доле ошибок в окне SLI
+	DBBreakerOpenTimeout time.Duration // Время до пробного обращения к БД после размыкания
+
+	// Параметры повтора транзакций при временных ошибках БД
+	DBRetryMaxAttempts int           // Максимум попыток сохранения, включая первую (1 — без повторов)
+	DBRetryBaseDelay   time.Duration // Задержка перед первым повтором; удваивается с каждой попыткой
+	DBRetryMaxDelay    time.Duration // Верхняя граница задержки между попытками
+	DBTxIsolation      string        // Уровень изоляции транзакций сохранения заказов: read_committed, repeatable_read или serializable
+
+	// Параметры ожидания БД и Kafka при запуске
+	StartupRetryMaxAttempts int           // Максимум попыток подключения, включая первую (1 — без повторов)
+	StartupRetryBaseDelay   time.Duration // Задержка перед первым повтором; удваивается с каждой попыткой
+	StartupRetryMaxDelay    time.Duration // Верхняя граница задержки между попытками
+
+	// Параметры профилирования
+	ProfilingEnabled     bool // Включает сбор профиля аллокаций и задержек по эндпоинтам
+	ProfilingSampleEvery int  // Замер аллокаций выполняется для каждого N-го запроса
 }
 
 // LoadConfig загружает конфигурацию из переменных окружения или использует значения по умолчанию.
 //
+//	Если задана переменная CONFIG_FILE, параметры дополнительно читаются из
+//	файла YAML по этому пути (см. LoadConfigFile).
 //	Возвращает:
 //	- *Config: указатель на объект конфигурации.
 //	- error: ошибку, если какие-либо из параметров не удалось обработать.
 func LoadConfig() (*Config, error) {
+	return LoadConfigFile(os.Getenv("CONFIG_FILE"))
+}
+
+// LoadConfigFile загружает конфигурацию из файла YAML и переменных окружения.
+//
+//	Переменные окружения переопределяют параметры файла, отсутствующие в
+//	обоих источниках параметры получают значения по умолчанию. Имена
+//	параметров файла соответствуют переменным окружения (см. source), а
+//	неизвестные параметры считаются ошибкой.
+//	Параметры:
+//	- path: путь к файлу конфигурации (пусто — только переменные окружения).
+//	Возвращает:
+//	- *Config: указатель на объект конфигурации.
+//	- error: ошибку чтения файла или *ValidationError со всеми некорректными параметрами.
+func LoadConfigFile(path string) (*Config, error) {
+	return LoadConfigWithOverrides(path, nil)
+}
+
+// LoadConfigWithOverrides загружает конфигурацию, как LoadConfigFile, и применяет поверх неё
+// значения, заданные в командной строке.
+//
+//	Параметры:
+//	- path: путь к файлу конфигурации (пусто — только переменные окружения).
+//	- overrides: значения по именам переменных окружения (например, HTTP_PORT);
+//	  переопределяют и переменные окружения, и файл.
+//	Возвращает:
+//	- *Config: указатель на объект конфигурации.
+//	- error: ошибку чтения файла или *ValidationError со всеми некорректными параметрами.
+func LoadConfigWithOverrides(path string, overrides map[string]string) (*Config, error) {
 	cfg := &Config{}
 
+	src, err := loadSource(path)
+	if err != nil {
+		return nil, err
+	}
+	src.overrides = overrides
+
+	// Секреты могут задаваться напрямую, через файлы *_FILE, Vault или зашифрованный файл SECRETS_FILE
+	secrets, err := loadSecrets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load secrets: %w", err)
+	}
+	secrets.file = src
+
+	var errs problems
+
+	secretsRefresh, err := time.ParseDuration(src.get("SECRETS_REFRESH_INTERVAL", "1m"))
+	if err != nil || secretsRefresh < 0 {
+		errs.addf("invalid SECRETS_REFRESH_INTERVAL: %q", src.get("SECRETS_REFRESH_INTERVAL", "1m"))
+	}
+	cfg.SecretsRefreshInterval = secretsRefresh
+	if secretsRefresh > 0 {
+		cfg.secrets = &secretRefresher{interval: secretsRefresh, file: src, store: secrets, fetched: time.Now()}
+	}
+
+	// Окружение приложения
+	cfg.AppEnv = src.get("APP_ENV", AppEnvProduction)
+	if cfg.AppEnv != AppEnvDev && cfg.AppEnv != AppEnvProduction {
+		errs.addf("invalid APP_ENV: %q", cfg.AppEnv)
+	}
+	cfg.LogLevel = src.get("LOG_LEVEL", "info")
+	if _, err := zapcore.ParseLevel(cfg.LogLevel); err != nil {
+		errs.addf("invalid LOG_LEVEL: %q", cfg.LogLevel)
+	}
+	cfg.LogFormat = src.get("LOG_FORMAT", LogFormatJSON)
+	if cfg.LogFormat != LogFormatJSON && cfg.LogFormat != LogFormatConsole {
+		errs.addf("invalid LOG_FORMAT: %q", cfg.LogFormat)
+	}
+	cfg.LogOutput = src.get("LOG_OUTPUT", "stderr")
+	logMaxSizeMB, err := strconv.Atoi(src.get("LOG_MAX_SIZE_MB", "100"))
+	if err != nil || logMaxSizeMB <= 0 {
+		errs.addf("invalid LOG_MAX_SIZE_MB: %q", src.get("LOG_MAX_SIZE_MB", "100"))
+	}
+	cfg.LogMaxSizeMB = logMaxSizeMB
+	logMaxBackups, err := strconv.Atoi(src.get("LOG_MAX_BACKUPS", "5"))
+	if err != nil || logMaxBackups < 0 {
+		errs.addf("invalid LOG_MAX_BACKUPS: %q", src.get("LOG_MAX_BACKUPS", "5"))
+	}
+	cfg.LogMaxBackups = logMaxBackups
+	logMaxAgeDays, err := strconv.Atoi(src.get("LOG_MAX_AGE_DAYS", "28"))
+	if err != nil || logMaxAgeDays < 0 {
+		errs.addf("invalid LOG_MAX_AGE_DAYS: %q", src.get("LOG_MAX_AGE_DAYS", "28"))
+	}
+	cfg.LogMaxAgeDays = logMaxAgeDays
+	logCompress, err := strconv.ParseBool(src.get("LOG_COMPRESS", "false"))
+	if err != nil {
+		errs.addf("invalid LOG_COMPRESS: %v", err)
+	}
+	cfg.LogCompress = logCompress
+	logRawPayloads, err := strconv.ParseBool(src.get("LOG_RAW_PAYLOADS", "false"))
+	if err != nil {
+		errs.addf("invalid LOG_RAW_PAYLOADS: %v", err)
+	}
+	cfg.LogRawPayloads = logRawPayloads
+
 	// Параметры базы данных
-	cfg.DBHost = getEnv("DB_HOST", "localhost")
-	dbPortStr := getEnv("DB_PORT", "5432")
+	cfg.DBHost = src.get("DB_HOST", "localhost")
+	dbPortStr := src.get("DB_PORT", "5432")
 	port, err := strconv.Atoi(dbPortStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid DB_PORT: %w", err)
+		errs.addf("invalid DB_PORT: %v", err)
 	}
 	cfg.DBPort = port
-	cfg.DBUser = getEnv("DB_USER", "orders_user")
-	cfg.DBPassword = getEnv("DB_PASSWORD", "securepassword")
-	cfg.DBName = getEnv("DB_NAME", "orders_db")
+	cfg.DBUser, err = secrets.get("DB_USER", "orders_user")
+	if err != nil {
+		errs.add(err)
+	}
+	cfg.DBPassword, err = secrets.get("DB_PASSWORD", "securepassword")
+	if err != nil {
+		errs.add(err)
+	}
+	cfg.DBName = src.get("DB_NAME", "orders_db")
+	cfg.DBTargetSessionAttrs = src.get("DB_TARGET_SESSION_ATTRS", "read-write")
+	if !slices.Contains([]string{"any", "read-write", "read-only", "primary", "standby", "prefer-standby"}, cfg.DBTargetSessionAttrs) {
+		errs.addf("invalid DB_TARGET_SESSION_ATTRS: %q", cfg.DBTargetSessionAttrs)
+	}
+	cfg.DBSSLMode = src.get("DB_SSLMODE", "disable")
+	if !slices.Contains([]string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}, cfg.DBSSLMode) {
+		errs.addf("invalid DB_SSLMODE: %q", cfg.DBSSLMode)
+	}
+	cfg.DBSSLRootCert = src.get("DB_SSLROOTCERT", "")
+	cfg.DBSSLCert = src.get("DB_SSLCERT", "")
+	cfg.DBSSLKey = src.get("DB_SSLKEY", "")
+	// Строка подключения может содержать пароль, поэтому читается как секрет
+	cfg.DBDSN, err = secrets.get("DB_DSN", "")
+	if err != nil {
+		errs.add(err)
+	}
+	replicaDSNs, err := secrets.get("DB_REPLICA_DSNS", "")
+	if err != nil {
+		errs.add(err)
+	}
+	cfg.DBReplicaDSNs = splitList(replicaDSNs)
+	slowQueryThreshold, err := time.ParseDuration(src.get("DB_SLOW_QUERY_THRESHOLD", "200ms"))
+	if err != nil || slowQueryThreshold < 0 {
+		errs.addf("invalid DB_SLOW_QUERY_THRESHOLD: %q", src.get("DB_SLOW_QUERY_THRESHOLD", "200ms"))
+	}
+	cfg.DBSlowQueryThreshold = slowQueryThreshold
+	statementTimeout, err := time.ParseDuration(src.get("DB_STATEMENT_TIMEOUT", "30s"))
+	if err != nil || statementTimeout < 0 {
+		errs.addf("invalid DB_STATEMENT_TIMEOUT: %q", src.get("DB_STATEMENT_TIMEOUT", "30s"))
+	}
+	cfg.DBStatementTimeout = statementTimeout
+	queryTimeout, err := time.ParseDuration(src.get("DB_QUERY_TIMEOUT", "10s"))
+	if err != nil || queryTimeout < 0 {
+		errs.addf("invalid DB_QUERY_TIMEOUT: %q", src.get("DB_QUERY_TIMEOUT", "10s"))
+	}
+	cfg.DBQueryTimeout = queryTimeout
+	autoMigrate, err := strconv.ParseBool(src.get("DB_AUTO_MIGRATE", "true"))
+	if err != nil {
+		errs.addf("invalid DB_AUTO_MIGRATE: %v", err)
+	}
+	cfg.DBAutoMigrate = autoMigrate
+
+	// Параметры пула соединений
+	poolMaxConns, err := strconv.Atoi(src.get("DB_POOL_MAX_CONNS", "25"))
+	if err != nil || poolMaxConns < 1 {
+		errs.addf("invalid DB_POOL_MAX_CONNS: %q", src.get("DB_POOL_MAX_CONNS", "25"))
+	}
+	cfg.DBPoolMaxConns = poolMaxConns
+	poolMinConns, err := strconv.Atoi(src.get("DB_POOL_MIN_CONNS", "2"))
+	if err != nil || poolMinConns < 0 {
+		errs.addf("invalid DB_POOL_MIN_CONNS: %q", src.get("DB_POOL_MIN_CONNS", "2"))
+	}
+	cfg.DBPoolMinConns = poolMinConns
+	poolHealthCheck, err := time.ParseDuration(src.get("DB_POOL_HEALTH_CHECK_PERIOD", "30s"))
+	if err != nil || poolHealthCheck <= 0 {
+		errs.addf("invalid DB_POOL_HEALTH_CHECK_PERIOD: %q", src.get("DB_POOL_HEALTH_CHECK_PERIOD", "30s"))
+	}
+	cfg.DBPoolHealthCheckPeriod = poolHealthCheck
+	poolMaxLifetime, err := time.ParseDuration(src.get("DB_POOL_MAX_CONN_LIFETIME", "30m"))
+	if err != nil || poolMaxLifetime <= 0 {
+		errs.addf("invalid DB_POOL_MAX_CONN_LIFETIME: %q", src.get("DB_POOL_MAX_CONN_LIFETIME", "30m"))
+	}
+	cfg.DBPoolMaxConnLifetime = poolMaxLifetime
+	poolMaxIdle, err := time.ParseDuration(src.get("DB_POOL_MAX_CONN_IDLE_TIME", "5m"))
+	if err != nil || poolMaxIdle <= 0 {
+		errs.addf("invalid DB_POOL_MAX_CONN_IDLE_TIME: %q", src.get("DB_POOL_MAX_CONN_IDLE_TIME", "5m"))
+	}
+	cfg.DBPoolMaxConnIdleTime = poolMaxIdle
 
 	// Параметры Kafka
-	kafkaBrokersStr := getEnv("KAFKA_BROKERS", "localhost:9092")
-	cfg.KafkaBrokers = []string{kafkaBrokersStr}
-	cfg.KafkaTopic = getEnv("KAFKA_TOPIC", "orders")
-	cfg.KafkaGroupID = getEnv("KAFKA_GROUP_ID", "orders_group")
+	cfg.KafkaBrokers = src.list("KAFKA_BROKERS", "localhost:9092")
+	cfg.KafkaTopic = src.get("KAFKA_TOPIC", "orders")
+	cfg.KafkaGroupID = src.get("KAFKA_GROUP_ID", "orders_group")
+	cfg.KafkaSASLUser, err = secrets.get("KAFKA_SASL_USER", "")
+	if err != nil {
+		errs.add(err)
+	}
+	cfg.KafkaSASLPassword, err = secrets.get("KAFKA_SASL_PASSWORD", "")
+	if err != nil {
+		errs.add(err)
+	}
+	writeQueueSize, err := strconv.Atoi(src.get("KAFKA_WRITE_QUEUE_SIZE", "1000"))
+	if err != nil || writeQueueSize < 1 {
+		errs.addf("invalid KAFKA_WRITE_QUEUE_SIZE: %q", src.get("KAFKA_WRITE_QUEUE_SIZE", "1000"))
+	}
+	cfg.KafkaWriteQueueSize = writeQueueSize
+	writers, err := strconv.Atoi(src.get("KAFKA_WRITERS", "4"))
+	if err != nil || writers < 1 {
+		errs.addf("invalid KAFKA_WRITERS: %q", src.get("KAFKA_WRITERS", "4"))
+	}
+	cfg.KafkaWriters = writers
+	kafkaBatchSize, err := strconv.Atoi(src.get("KAFKA_BATCH_SIZE", "100"))
+	if err != nil || kafkaBatchSize < 1 {
+		errs.addf("invalid KAFKA_BATCH_SIZE: %q", src.get("KAFKA_BATCH_SIZE", "100"))
+	}
+	cfg.KafkaBatchSize = kafkaBatchSize
+	kafkaBatchTimeout, err := time.ParseDuration(src.get("KAFKA_BATCH_TIMEOUT", "200ms"))
+	if err != nil || kafkaBatchTimeout <= 0 {
+		errs.addf("invalid KAFKA_BATCH_TIMEOUT: %q", src.get("KAFKA_BATCH_TIMEOUT", "200ms"))
+	}
+	cfg.KafkaBatchTimeout = kafkaBatchTimeout
+	kafkaDrainTimeout, err := time.ParseDuration(src.get("KAFKA_DRAIN_TIMEOUT", "10s"))
+	if err != nil || kafkaDrainTimeout < 0 {
+		errs.addf("invalid KAFKA_DRAIN_TIMEOUT: %q", src.get("KAFKA_DRAIN_TIMEOUT", "10s"))
+	}
+	cfg.KafkaDrainTimeout = kafkaDrainTimeout
+	cfg.OrderEventsTopic = src.get("ORDER_EVENTS_TOPIC", "")
+	cfg.OrderEventsMode = src.get("ORDER_EVENTS_MODE", OrderEventsModeDiff)
+	if cfg.OrderEventsMode != OrderEventsModeFull && cfg.OrderEventsMode != OrderEventsModeDiff {
+		errs.addf("invalid ORDER_EVENTS_MODE: %q", cfg.OrderEventsMode)
+	}
+	amountTolerance, err := strconv.Atoi(src.get("ORDER_AMOUNT_TOLERANCE", "0"))
+	if err != nil || amountTolerance < 0 {
+		errs.addf("invalid ORDER_AMOUNT_TOLERANCE: %q", src.get("ORDER_AMOUNT_TOLERANCE", "0"))
+	}
+	cfg.OrderAmountTolerance = amountTolerance
+	dedupWindow, err := time.ParseDuration(src.get("ORDER_DEDUP_WINDOW", "24h"))
+	if err != nil || dedupWindow < 0 {
+		errs.addf("invalid ORDER_DEDUP_WINDOW: %q", src.get("ORDER_DEDUP_WINDOW", "24h"))
+	}
+	cfg.OrderDedupWindow = dedupWindow
 
 	// Параметры HTTP-сервера
-	cfg.HTTPPort = getEnv("HTTP_PORT", "8081")
+	cfg.HTTPPort = src.get("HTTP_PORT", "8081")
+	streamThreshold, err := strconv.Atoi(src.get("ORDER_STREAM_THRESHOLD", "1000"))
+	if err != nil {
+		errs.addf("invalid ORDER_STREAM_THRESHOLD: %v", err)
+	}
+	cfg.OrderStreamThreshold = streamThreshold
+	sseHeartbeat, err := time.ParseDuration(src.get("SSE_HEARTBEAT_INTERVAL", "15s"))
+	if err != nil || sseHeartbeat <= 0 {
+		errs.addf("invalid SSE_HEARTBEAT_INTERVAL: %q", src.get("SSE_HEARTBEAT_INTERVAL", "15s"))
+	}
+	cfg.SSEHeartbeatInterval = sseHeartbeat
+	cfg.StaticDir = src.get("STATIC_DIR", "")
+
+	// Параметры экспорта метрик
+	cfg.MetricsAddr = src.get("METRICS_ADDR", "")
+	cfg.MetricsPort = src.get("METRICS_PORT", "9100")
+	metricsOnMain, err := strconv.ParseBool(src.get("METRICS_ON_MAIN_SERVER", "false"))
+	if err != nil {
+		errs.addf("invalid METRICS_ON_MAIN_SERVER: %q", src.get("METRICS_ON_MAIN_SERVER", "false"))
+	}
+	cfg.MetricsOnMainServer = metricsOnMain
+	cfg.MetricsDiskPaths = src.list("METRICS_DISK_PATHS", "/")
+	for _, b := range []struct {
+		key, def string
+		dst      *[]float64
+	}{
+		{"METRICS_ORDER_PROCESSING_BUCKETS", "0.005,0.01,0.025,0.05,0.1,0.25,0.5,1,2.5,5,10,30", &cfg.MetricsOrderProcessingBuckets},
+		{"METRICS_HTTP_BUCKETS", "0.005,0.01,0.025,0.05,0.1,0.25,0.5,1,2.5,5,10", &cfg.MetricsHTTPBuckets},
+		{"METRICS_DB_BUCKETS", "0.005,0.01,0.025,0.05,0.1,0.25,0.5,1,2.5,5,10", &cfg.MetricsDBBuckets},
+	} {
+		buckets, err := parseBuckets(src.list(b.key, b.def))
+		if err != nil {
+			errs.addf("invalid %s: %v", b.key, err)
+		}
+		*b.dst = buckets
+	}
+
+	// Параметры TLS
+	cfg.TLSCertFile = src.get("TLS_CERT_FILE", "")
+	cfg.TLSKeyFile = src.get("TLS_KEY_FILE", "")
+	cfg.TLSAutocertDomains = src.list("TLS_AUTOCERT_DOMAINS", "")
+	cfg.TLSAutocertCacheDir = src.get("TLS_AUTOCERT_CACHE_DIR", "certs")
+	cfg.TLSAutocertEmail = src.get("TLS_AUTOCERT_EMAIL", "")
+	cfg.HTTPRedirectPort = src.get("HTTP_REDIRECT_PORT", "")
 
 	// Таймаут завершения работы приложения
-	shutdownTimeoutStr := getEnv("SHUTDOWN_TIMEOUT", "5s")
+	shutdownTimeoutStr := src.get("SHUTDOWN_TIMEOUT", "5s")
 	shutdownTimeout, err := time.ParseDuration(shutdownTimeoutStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid SHUTDOWN_TIMEOUT: %w", err)
+		errs.addf("invalid SHUTDOWN_TIMEOUT: %v", err)
 	}
 	cfg.ShutdownTimeout = shutdownTimeout
 
+	// Ограничения запросов
+	requestTimeout, err := time.ParseDuration(src.get("REQUEST_TIMEOUT", "5s"))
+	if err != nil || requestTimeout < 0 {
+		errs.addf("invalid REQUEST_TIMEOUT: %q", src.get("REQUEST_TIMEOUT", "5s"))
+	}
+	cfg.RequestTimeout = requestTimeout
+	maxBodyBytes, err := strconv.ParseInt(src.get("MAX_REQUEST_BODY_BYTES", "1048576"), 10, 64)
+	if err != nil || maxBodyBytes < 0 {
+		errs.addf("invalid MAX_REQUEST_BODY_BYTES: %q", src.get("MAX_REQUEST_BODY_BYTES", "1048576"))
+	}
+	cfg.MaxRequestBodyBytes = maxBodyBytes
+	bulkMaxBodyBytes, err := strconv.ParseInt(src.get("BULK_MAX_BODY_BYTES", "33554432"), 10, 64)
+	if err != nil || bulkMaxBodyBytes < 0 {
+		errs.addf("invalid BULK_MAX_BODY_BYTES: %q", src.get("BULK_MAX_BODY_BYTES", "33554432"))
+	}
+	cfg.BulkMaxBodyBytes = bulkMaxBodyBytes
+	bulkMaxOrders, err := strconv.Atoi(src.get("BULK_MAX_ORDERS", "10000"))
+	if err != nil || bulkMaxOrders < 0 {
+		errs.addf("invalid BULK_MAX_ORDERS: %q", src.get("BULK_MAX_ORDERS", "10000"))
+	}
+	cfg.BulkMaxOrders = bulkMaxOrders
+
+	// Параметры сжатия ответов
+	compressionEnabled, err := strconv.ParseBool(src.get("COMPRESSION_ENABLED", "true"))
+	if err != nil {
+		errs.addf("invalid COMPRESSION_ENABLED: %v", err)
+	}
+	cfg.CompressionEnabled = compressionEnabled
+	compressionMinSize, err := strconv.Atoi(src.get("COMPRESSION_MIN_SIZE", "1024"))
+	if err != nil || compressionMinSize < 0 {
+		errs.addf("invalid COMPRESSION_MIN_SIZE: %q", src.get("COMPRESSION_MIN_SIZE", "1024"))
+	}
+	cfg.CompressionMinSize = compressionMinSize
+	compressionLevel, err := strconv.Atoi(src.get("COMPRESSION_LEVEL", "-1"))
+	if err != nil || compressionLevel < -1 || compressionLevel > 9 {
+		errs.addf("invalid COMPRESSION_LEVEL: %q", src.get("COMPRESSION_LEVEL", "-1"))
+	}
+	cfg.CompressionLevel = compressionLevel
+
+	// Время кэширования статистики заказов
+	statsCacheTTL, err := time.ParseDuration(src.get("STATS_CACHE_TTL", "30s"))
+	if err != nil || statsCacheTTL < 0 {
+		errs.addf("invalid STATS_CACHE_TTL: %q", src.get("STATS_CACHE_TTL", "30s"))
+	}
+	cfg.StatsCacheTTL = statsCacheTTL
+
+	// Параметры кэша заказов
+	cfg.CacheBackend = src.get("CACHE_BACKEND", CacheBackendMemory)
+	switch cfg.CacheBackend {
+	case CacheBackendMemory:
+	case CacheBackendRedis:
+		errs.addf("CACHE_BACKEND %q is not supported in this build, use %q", cfg.CacheBackend, CacheBackendMemory)
+	default:
+		errs.addf("invalid CACHE_BACKEND: %q", cfg.CacheBackend)
+	}
+	cacheMaxEntries, err := strconv.Atoi(src.get("CACHE_MAX_ENTRIES", "0"))
+	if err != nil || cacheMaxEntries < 0 {
+		errs.addf("invalid CACHE_MAX_ENTRIES: %q", src.get("CACHE_MAX_ENTRIES", "0"))
+	}
+	cfg.CacheMaxEntries = cacheMaxEntries
+	cacheTTL, err := time.ParseDuration(src.get("CACHE_TTL", "0"))
+	if err != nil || cacheTTL < 0 {
+		errs.addf("invalid CACHE_TTL: %q", src.get("CACHE_TTL", "0"))
+	}
+	cfg.CacheTTL = cacheTTL
+	cachePreloadWindow, err := time.ParseDuration(src.get("CACHE_PRELOAD_WINDOW", "0"))
+	if err != nil || cachePreloadWindow < 0 {
+		errs.addf("invalid CACHE_PRELOAD_WINDOW: %q", src.get("CACHE_PRELOAD_WINDOW", "0"))
+	}
+	cfg.CachePreloadWindow = cachePreloadWindow
+	cacheCDCEnabled, err := strconv.ParseBool(src.get("CACHE_CDC_ENABLED", "false"))
+	if err != nil {
+		errs.addf("invalid CACHE_CDC_ENABLED: %v", err)
+	}
+	cfg.CacheCDCEnabled = cacheCDCEnabled
+	cacheCDCDebounce, err := time.ParseDuration(src.get("CACHE_CDC_DEBOUNCE", "200ms"))
+	if err != nil || cacheCDCDebounce <= 0 {
+		errs.addf("invalid CACHE_CDC_DEBOUNCE: %q", src.get("CACHE_CDC_DEBOUNCE", "200ms"))
+	}
+	cfg.CacheCDCDebounce = cacheCDCDebounce
+
+	// Параметры журнала доступа
+	accessLogEnabled, err := strconv.ParseBool(src.get("ACCESS_LOG_ENABLED", "true"))
+	if err != nil {
+		errs.addf("invalid ACCESS_LOG_ENABLED: %v", err)
+	}
+	cfg.AccessLogEnabled = accessLogEnabled
+	cfg.AccessLogOutput = src.get("ACCESS_LOG_OUTPUT", "stdout")
+	accessLogSampleEvery, err := strconv.Atoi(src.get("ACCESS_LOG_SAMPLE_EVERY", "1"))
+	if err != nil || accessLogSampleEvery < 1 {
+		errs.addf("invalid ACCESS_LOG_SAMPLE_EVERY: %q", src.get("ACCESS_LOG_SAMPLE_EVERY", "1"))
+	}
+	cfg.AccessLogSampleEvery = accessLogSampleEvery
+
+	// Параметры пагинации
+	cfg.CursorSecret, err = secrets.get("CURSOR_SECRET", "")
+	if err != nil {
+		errs.add(err)
+	}
+	cursorTTL, err := time.ParseDuration(src.get("CURSOR_TTL", "1h"))
+	if err != nil {
+		errs.addf("invalid CURSOR_TTL: %v", err)
+	}
+	cfg.CursorTTL = cursorTTL
+
+	// Параметры CORS
+	cfg.CORSAllowedOrigins = src.list("CORS_ALLOWED_ORIGINS", "")
+	cfg.CORSAllowedMethods = src.list("CORS_ALLOWED_METHODS", "GET,POST,OPTIONS")
+	cfg.CORSAllowedHeaders = src.list("CORS_ALLOWED_HEADERS", "Content-Type,X-API-Key,If-None-Match,If-Modified-Since")
+	corsMaxAge, err := time.ParseDuration(src.get("CORS_MAX_AGE", "10m"))
+	if err != nil {
+		errs.addf("invalid CORS_MAX_AGE: %v", err)
+	}
+	cfg.CORSMaxAge = corsMaxAge
+	corsAllowCredentials, err := strconv.ParseBool(src.get("CORS_ALLOW_CREDENTIALS", "false"))
+	if err != nil {
+		errs.addf("invalid CORS_ALLOW_CREDENTIALS: %v", err)
+	}
+	cfg.CORSAllowCredentials = corsAllowCredentials
+
+	// Параметры аутентификации по API-ключам
+	rawAPIKeys, err := secrets.get("API_KEYS", "")
+	if err != nil {
+		errs.add(err)
+	}
+	apiKeys, err := parseAPIKeys(rawAPIKeys)
+	if err != nil {
+		errs.addf("invalid API_KEYS: %v", err)
+	}
+	cfg.APIKeys = apiKeys
+	apiKeysFromDB, err := strconv.ParseBool(src.get("API_KEYS_FROM_DB", "false"))
+	if err != nil {
+		errs.addf("invalid API_KEYS_FROM_DB: %v", err)
+	}
+	cfg.APIKeysFromDB = apiKeysFromDB
+	defaultRateLimit, err := strconv.Atoi(src.get("API_KEY_DEFAULT_RPS", "100"))
+	if err != nil {
+		errs.addf("invalid API_KEY_DEFAULT_RPS: %v", err)
+	}
+	cfg.APIKeyDefaultRateLimit = defaultRateLimit
+
+	// Параметры административных маршрутов
+	cfg.AdminToken, err = secrets.get("ADMIN_TOKEN", "")
+	if err != nil {
+		errs.add(err)
+	}
+	cfg.AdminUser = src.get("ADMIN_USER", "")
+	cfg.AdminPassword, err = secrets.get("ADMIN_PASSWORD", "")
+	if err != nil {
+		errs.add(err)
+	}
+
+	// Параметры отправки ошибок в Sentry
+	cfg.SentryDSN, err = secrets.get("SENTRY_DSN", "")
+	if err != nil {
+		errs.add(err)
+	}
+	cfg.SentryEnvironment = src.get("SENTRY_ENVIRONMENT", cfg.AppEnv)
+	sentrySampleRate, err := strconv.ParseFloat(src.get("SENTRY_SAMPLE_RATE", "1"), 64)
+	if err != nil || sentrySampleRate < 0 || sentrySampleRate > 1 {
+		errs.addf("invalid SENTRY_SAMPLE_RATE: %q", src.get("SENTRY_SAMPLE_RATE", "1"))
+	}
+	cfg.SentrySampleRate = sentrySampleRate
+
+	// Параметры шифрования персональных данных
+	cfg.PIIEncryptionKey, err = secrets.get("PII_ENCRYPTION_KEY", "")
+	if err != nil {
+		errs.add(err)
+	}
+	previousKeys, err := secrets.get("PII_ENCRYPTION_PREVIOUS_KEYS", "")
+	if err != nil {
+		errs.add(err)
+	}
+	cfg.PIIEncryptionPreviousKeys = splitList(previousKeys)
+
+	// Параметры режима воспроизведения
+	replayEnabled, err := strconv.ParseBool(src.get("REPLAY_ENABLED", "false"))
+	if err != nil {
+		errs.addf("invalid REPLAY_ENABLED: %v", err)
+	}
+	cfg.ReplayEnabled = replayEnabled
+	replayRate, err := strconv.Atoi(src.get("REPLAY_RATE", "50"))
+	if err != nil || replayRate < 1 {
+		errs.addf("invalid REPLAY_RATE: %q", src.get("REPLAY_RATE", "50"))
+	}
+	cfg.ReplayRate = replayRate
+	cfg.ReplayTarget = src.get("REPLAY_TARGET", ReplayTargetDryRun)
+	if cfg.ReplayTarget != ReplayTargetDryRun && cfg.ReplayTarget != ReplayTargetScratch {
+		errs.addf("invalid REPLAY_TARGET: %q", cfg.ReplayTarget)
+	}
+	cfg.ReplaySchema = src.get("REPLAY_SCHEMA", "replay_scratch")
+
+	// Параметры SLI и защиты от перегрузки
+	sliWindow, err := time.ParseDuration(src.get("SLI_WINDOW", "1m"))
+	if err != nil {
+		errs.addf("invalid SLI_WINDOW: %v", err)
+	}
+	cfg.SLIWindow = sliWindow
+	maxErrorRate, err := strconv.ParseFloat(src.get("SLI_MAX_ERROR_RATE", "0.5"), 64)
+	if err != nil || maxErrorRate < 0 || maxErrorRate > 1 {
+		errs.addf("invalid SLI_MAX_ERROR_RATE: %q", src.get("SLI_MAX_ERROR_RATE", "0.5"))
+	}
+	cfg.SLIMaxErrorRate = maxErrorRate
+	minEvents, err := strconv.Atoi(src.get("SLI_MIN_EVENTS", "20"))
+	if err != nil || minEvents < 0 {
+		errs.addf("invalid SLI_MIN_EVENTS: %q", src.get("SLI_MIN_EVENTS", "20"))
+	}
+	cfg.SLIMinEvents = minEvents
+	loadShedding, err := strconv.ParseBool(src.get("LOAD_SHEDDING_ENABLED", "false"))
+	if err != nil {
+		errs.addf("invalid LOAD_SHEDDING_ENABLED: %v", err)
+	}
+	cfg.LoadSheddingEnabled = loadShedding
+
+	// Параметры наблюдения за зависимостями
+	watchdogEnabled, err := strconv.ParseBool(src.get("WATCHDOG_ENABLED", "true"))
+	if err != nil {
+		errs.addf("invalid WATCHDOG_ENABLED: %v", err)
+	}
+	cfg.WatchdogEnabled = watchdogEnabled
+	watchdogInterval, err := time.ParseDuration(src.get("WATCHDOG_INTERVAL", "10s"))
+	if err != nil || watchdogInterval <= 0 {
+		errs.addf("invalid WATCHDOG_INTERVAL: %q", src.get("WATCHDOG_INTERVAL", "10s"))
+	}
+	cfg.WatchdogInterval = watchdogInterval
+	failureThreshold, err := strconv.Atoi(src.get("WATCHDOG_FAILURE_THRESHOLD", "3"))
+	if err != nil || failureThreshold < 1 {
+		errs.addf("invalid WATCHDOG_FAILURE_THRESHOLD: %q", src.get("WATCHDOG_FAILURE_THRESHOLD", "3"))
+	}
+	cfg.WatchdogFailureThreshold = failureThreshold
+	recoveryThreshold, err := strconv.Atoi(src.get("WATCHDOG_RECOVERY_THRESHOLD", "2"))
+	if err != nil || recoveryThreshold < 1 {
+		errs.addf("invalid WATCHDOG_RECOVERY_THRESHOLD: %q", src.get("WATCHDOG_RECOVERY_THRESHOLD", "2"))
+	}
+	cfg.WatchdogRecoveryThreshold = recoveryThreshold
+
+	// Параметры автомата защиты БД
+	breakerEnabled, err := strconv.ParseBool(src.get("DB_BREAKER_ENABLED", "true"))
+	if err != nil {
+		errs.addf("invalid DB_BREAKER_ENABLED: %v", err)
+	}
+	cfg.DBBreakerEnabled = breakerEnabled
+	breakerOpenTimeout, err := time.ParseDuration(src.get("DB_BREAKER_OPEN_TIMEOUT", "10s"))
+	if err != nil || breakerOpenTimeout <= 0 {
+		errs.addf("invalid DB_BREAKER_OPEN_TIMEOUT: %q", src.get("DB_BREAKER_OPEN_TIMEOUT", "10s"))
+	}
+	cfg.DBBreakerOpenTimeout = breakerOpenTimeout
+
+	// Параметры повтора транзакций при временных ошибках БД
+	retryAttempts, err := strconv.Atoi(src.get("DB_RETRY_MAX_ATTEMPTS", "3"))
+	if err != nil || retryAttempts < 1 {
+		errs.addf("invalid DB_RETRY_MAX_ATTEMPTS: %q", src.get("DB_RETRY_MAX_ATTEMPTS", "3"))
+	}
+	cfg.DBRetryMaxAttempts = retryAttempts
+	retryBaseDelay, err := time.ParseDuration(src.get("DB_RETRY_BASE_DELAY", "50ms"))
+	if err != nil || retryBaseDelay <= 0 {
+		errs.addf("invalid DB_RETRY_BASE_DELAY: %q", src.get("DB_RETRY_BASE_DELAY", "50ms"))
+	}
+	cfg.DBRetryBaseDelay = retryBaseDelay
+	retryMaxDelay, err := time.ParseDuration(src.get("DB_RETRY_MAX_DELAY", "1s"))
+	if err != nil || retryMaxDelay < retryBaseDelay {
+		errs.addf("invalid DB_RETRY_MAX_DELAY: %q", src.get("DB_RETRY_MAX_DELAY", "1s"))
+	}
+	cfg.DBRetryMaxDelay = retryMaxDelay
+	cfg.DBTxIsolation = src.get("DB_TX_ISOLATION", "read_committed")
+	if !slices.Contains([]string{"read_committed", "repeatable_read", "serializable"}, cfg.DBTxIsolation) {
+		errs.addf("invalid DB_TX_ISOLATION: %q", cfg.DBTxIsolation)
+	}
+
+	// Параметры ожидания БД и Kafka при запуске
+	startupAttempts, err := strconv.Atoi(src.get("STARTUP_RETRY_MAX_ATTEMPTS", "10"))
+	if err != nil || startupAttempts < 1 {
+		errs.addf("invalid STARTUP_RETRY_MAX_ATTEMPTS: %q", src.get("STARTUP_RETRY_MAX_ATTEMPTS", "10"))
+	}
+	cfg.StartupRetryMaxAttempts = startupAttempts
+	startupBaseDelay, err := time.ParseDuration(src.get("STARTUP_RETRY_BASE_DELAY", "500ms"))
+	if err != nil || startupBaseDelay <= 0 {
+		errs.addf("invalid STARTUP_RETRY_BASE_DELAY: %q", src.get("STARTUP_RETRY_BASE_DELAY", "500ms"))
+	}
+	cfg.StartupRetryBaseDelay = startupBaseDelay
+	startupMaxDelay, err := time.ParseDuration(src.get("STARTUP_RETRY_MAX_DELAY", "10s"))
+	if err != nil || startupMaxDelay < startupBaseDelay {
+		errs.addf("invalid STARTUP_RETRY_MAX_DELAY: %q", src.get("STARTUP_RETRY_MAX_DELAY", "10s"))
+	}
+	cfg.StartupRetryMaxDelay = startupMaxDelay
+
+	// Параметры профилирования
+	profilingEnabled, err := strconv.ParseBool(src.get("PROFILING_ENABLED", "false"))
+	if err != nil {
+		errs.addf("invalid PROFILING_ENABLED: %v", err)
+	}
+	cfg.ProfilingEnabled = profilingEnabled
+	sampleEvery, err := strconv.Atoi(src.get("PROFILING_SAMPLE_EVERY", "10"))
+	if err != nil || sampleEvery < 1 {
+		errs.addf("invalid PROFILING_SAMPLE_EVERY: %q", src.get("PROFILING_SAMPLE_EVERY", "10"))
+	}
+	cfg.ProfilingSampleEvery = sampleEvery
+
+	if err := src.checkUnused(); err != nil {
+		errs.add(err)
+	}
+	errs = append(errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return nil, &ValidationError{Problems: errs}
+	}
+	cfg.configFile = path
+	cfg.sources = maps.Clone(src.origins)
 	return cfg, nil
 }
 
-// getEnv возвращает значение переменной окружения или значение по умолчанию, если переменная не установлена.
+// ConfigFile возвращает путь к файлу конфигурации, из которого загружена конфигурация (пусто — файл не задан).
+func (c *Config) ConfigFile() string {
+	return c.configFile
+}
+
+// Sources возвращает источник значения каждого параметра конфигурации.
+//
+//	Используется для диагностики: показывает, откуда взято действующее
+//	значение — флаг, переменная окружения, файл, Vault или значение по умолчанию.
+//	Возвращает:
+//	- map[string]string: источник (Origin*) по имени переменной окружения.
+func (c *Config) Sources() map[string]string {
+	return maps.Clone(c.sources)
+}
+
+// Secret возвращает актуальное значение секрета с учётом ротации учётных данных.
 //
+//	Источники секретов перечитываются не чаще SECRETS_REFRESH_INTERVAL, поэтому
+//	метод можно вызывать при каждом новом подключении к БД или Kafka.
 //	Параметры:
-//	- key: имя переменной окружения.
-//	- defaultVal: значение по умолчанию.
+//	- key: имя секрета (например, DB_PASSWORD).
+//	- current: значение из конфигурации; возвращается, если ротация выключена.
+//	Возвращает:
+//	- string: значение секрета; пригодно к использованию и при ошибке.
+//	- error: ошибку перечитывания источников секретов.
+func (c *Config) Secret(key, current string) (string, error) {
+	if c.secrets == nil {
+		return current, nil
+	}
+	return c.secrets.get(key, current)
+}
+
+// redactedValue заменяет значения секретов в Redacted.
+const redactedValue = "***"
+
+// Redacted возвращает копию конфигурации со скрытыми секретами.
+//
+//	Используется для вывода конфигурации в логи и административный API.
 //	Возвращает:
-//	- string: значение переменной окружения или значение по умолчанию.
-func getEnv(key, defaultVal string) string {
-	val := os.Getenv(key)
-	if val == "" {
-		return defaultVal
+//	- *Config: копия конфигурации, в которой пароли, ключи и токены заменены на "***".
+func (c *Config) Redacted() *Config {
+	r := *c
+	for _, secret := range []*string{&r.DBPassword, &r.DBDSN, &r.KafkaSASLPassword, &r.CursorSecret, &r.AdminToken, &r.AdminPassword, &r.PIIEncryptionKey, &r.SentryDSN} {
+		if *secret != "" {
+			*secret = redactedValue
+		}
+	}
+	// Строки подключения к репликам могут содержать пароли
+	r.DBReplicaDSNs = make([]string, len(c.DBReplicaDSNs))
+	for i := range r.DBReplicaDSNs {
+		r.DBReplicaDSNs[i] = redactedValue
+	}
+	r.PIIEncryptionPreviousKeys = make([]string, len(c.PIIEncryptionPreviousKeys))
+	for i := range r.PIIEncryptionPreviousKeys {
+		r.PIIEncryptionPreviousKeys[i] = redactedValue
+	}
+	r.APIKeys = make([]APIKeyConfig, len(c.APIKeys))
+	for i, k := range c.APIKeys {
+		k.Key = redactedValue
+		r.APIKeys[i] = k
+	}
+	return &r
+}
+
+// parseAPIKeys разбирает список ключей API в формате "name:key[:rps],name2:key2".
+//
+//	Параметры:
+//	- raw: строка со списком ключей.
+//	Возвращает:
+//	- []APIKeyConfig: разобранные ключи.
+//	- error: ошибку, если запись имеет неверный формат.
+func parseAPIKeys(raw string) ([]APIKeyConfig, error) {
+	var keys []APIKeyConfig
+	for i, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			// Запись без разделителей может оказаться самим ключом, поэтому в ошибке только её номер
+			return nil, fmt.Errorf("entry #%d must have format name:key[:rps]", i+1)
+		}
+		key := APIKeyConfig{Name: parts[0], Key: parts[1]}
+		if len(parts) == 3 {
+			rps, err := strconv.Atoi(parts[2])
+			if err != nil || rps < 0 {
+				return nil, fmt.Errorf("entry %q has invalid rate limit %q", parts[0], parts[2])
+			}
+			key.RateLimit = rps
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// parseBuckets разбирает границы корзин гистограммы.
+//
+//	Параметры:
+//	- values: границы в секундах.
+//	Возвращает:
+//	- []float64: границы корзин.
+//	- error: ошибку, если граница не число, не положительна или границы не возрастают.
+func parseBuckets(values []string) ([]float64, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("at least one bucket is required")
+	}
+	buckets := make([]float64, len(values))
+	for i, v := range values {
+		b, err := strconv.ParseFloat(v, 64)
+		if err != nil || b <= 0 {
+			return nil, fmt.Errorf("bucket %q must be a positive number", v)
+		}
+		if i > 0 && b <= buckets[i-1] {
+			return nil, fmt.Errorf("buckets must be in increasing order")
+		}
+		buckets[i] = b
+	}
+	return buckets, nil
+}
+
+// splitList разбирает список через запятую, отбрасывая пустые элементы и пробелы по краям.
+func splitList(raw string) []string {
+	var list []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
 	}
-	return val
+	return list
 }
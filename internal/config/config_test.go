@@ -0,0 +1,52 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseAPIKeys проверяет разбор API_KEYS и отказ на некорректных записях.
+func TestParseAPIKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []APIKeyConfig
+		wantErr string
+	}{
+		{name: "empty", raw: ""},
+		{
+			name: "keys with and without rate limit",
+			raw:  "billing:b-key:50, crm:c-key ,",
+			want: []APIKeyConfig{{Name: "billing", Key: "b-key", RateLimit: 50}, {Name: "crm", Key: "c-key"}},
+		},
+		{name: "zero rate limit", raw: "crm:c-key:0", want: []APIKeyConfig{{Name: "crm", Key: "c-key"}}},
+		{name: "key without name", raw: "crm:c-key,s3cr3t", wantErr: "entry #2 must have format name:key[:rps]"},
+		{name: "empty name", raw: ":s3cr3t", wantErr: "entry #1 must have format"},
+		{name: "empty key", raw: "crm:", wantErr: "entry #1 must have format"},
+		{name: "too many parts", raw: "crm:s3cr3t:5:1", wantErr: "entry #1 must have format"},
+		{name: "non-numeric rate limit", raw: "crm:s3cr3t:fast", wantErr: `entry "crm" has invalid rate limit "fast"`},
+		{name: "negative rate limit", raw: "crm:s3cr3t:-1", wantErr: `entry "crm" has invalid rate limit "-1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAPIKeys(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				// Значение ключа не должно попадать в сообщение об ошибке
+				if strings.Contains(err.Error(), "s3cr3t") {
+					t.Errorf("error leaks the key: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// source возвращает значения параметров конфигурации.
+//
+//	Порядок поиска: значение из командной строки, переменная окружения, файл
+//	конфигурации, значение по умолчанию. Параметры файла приводятся к именам переменных окружения:
+//	вложенные ключи соединяются через "_" и переводятся в верхний регистр,
+//	поэтому
+//
+//	kafka:
+//	  batch_size: 100
+//	  sasl:
+//	    user: orders
+//
+//	задаёт KAFKA_BATCH_SIZE и KAFKA_SASL_USER. Списки значений соединяются
+//	через запятую, как в переменных окружения.
+type source struct {
+	path      string            // Путь к файлу конфигурации (пусто — файл не задан)
+	values    map[string]string // Параметры файла по именам переменных окружения
+	used      map[string]bool   // Параметры файла, запрошенные при загрузке конфигурации
+	overrides map[string]string // Значения из командной строки по именам переменных окружения
+	origins   map[string]string // Источник значения каждого запрошенного параметра (см. Origin*)
+}
+
+// Источники значений параметров конфигурации (см. Config.Sources).
+const (
+	OriginFlag        = "flag"         // Флаг командной строки
+	OriginEnv         = "env"          // Переменная окружения
+	OriginEnvFile     = "env_file"     // Файл из переменной KEY_FILE
+	OriginVault       = "vault"        // Секрет HashiCorp Vault
+	OriginSecretsFile = "secrets_file" // Зашифрованный файл SECRETS_FILE
+	OriginConfigFile  = "config_file"  // Файл конфигурации
+	OriginDefault     = "default"      // Значение по умолчанию
+)
+
+// loadSource читает файл конфигурации YAML.
+//
+//	Параметры:
+//	- path: путь к файлу (пусто — только переменные окружения).
+//	Возвращает:
+//	- *source: источник параметров.
+//	- error: ошибку чтения или разбора файла.
+func loadSource(path string) (*source, error) {
+	src := &source{path: path, values: map[string]string{}, used: map[string]bool{}, origins: map[string]string{}}
+	if path == "" {
+		return src, nil
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if err := flatten("", doc, src.values); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return src, nil
+}
+
+// flatten записывает в out параметры вложенного документа YAML по именам переменных окружения.
+func flatten(prefix string, doc map[string]any, out map[string]string) error {
+	for key, value := range doc {
+		name := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+		switch v := value.(type) {
+		case nil:
+			// Пустое значение равносильно отсутствию параметра
+		case map[string]any:
+			if err := flatten(name, v, out); err != nil {
+				return err
+			}
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				s, ok := scalar(item)
+				if !ok {
+					return fmt.Errorf("%s: list items must be scalar values", name)
+				}
+				items = append(items, s)
+			}
+			out[name] = strings.Join(items, ",")
+		default:
+			s, ok := scalar(v)
+			if !ok {
+				return fmt.Errorf("%s: unsupported value %v", name, v)
+			}
+			out[name] = s
+		}
+	}
+	return nil
+}
+
+// scalar возвращает строковое представление скалярного значения YAML.
+func scalar(v any) (string, bool) {
+	switch v.(type) {
+	case string, bool, int, int64, uint64, float64:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+// lookup возвращает значение параметра из файла конфигурации и отмечает параметр как известный.
+func (s *source) lookup(key string) (string, bool) {
+	s.used[key] = true
+	val, ok := s.values[key]
+	return val, ok && val != ""
+}
+
+// override возвращает значение параметра, заданное в командной строке.
+func (s *source) override(key string) (string, bool) {
+	val, ok := s.overrides[key]
+	return val, ok
+}
+
+// get возвращает значение параметра.
+//
+//	Параметры:
+//	- key: имя переменной окружения.
+//	- defaultVal: значение по умолчанию.
+//	Возвращает:
+//	- string: значение из командной строки, переменной окружения, файла конфигурации или значение по умолчанию.
+func (s *source) get(key, defaultVal string) string {
+	fileVal, inFile := s.lookup(key)
+	if val, ok := s.override(key); ok {
+		s.origins[key] = OriginFlag
+		return val
+	}
+	if val := os.Getenv(key); val != "" {
+		s.origins[key] = OriginEnv
+		return val
+	}
+	if inFile {
+		s.origins[key] = OriginConfigFile
+		return fileVal
+	}
+	s.origins[key] = OriginDefault
+	return defaultVal
+}
+
+// list возвращает значение параметра, разобранное как список через запятую.
+//
+//	Пустые элементы и пробелы по краям отбрасываются.
+//	Параметры:
+//	- key: имя переменной окружения.
+//	- defaultVal: значение по умолчанию.
+//	Возвращает:
+//	- []string: элементы списка (nil для пустого значения).
+func (s *source) list(key, defaultVal string) []string {
+	return splitList(s.get(key, defaultVal))
+}
+
+// checkUnused возвращает ошибку, если в файле конфигурации есть параметры,
+// которые не были запрошены при загрузке, — обычно это опечатка в имени.
+func (s *source) checkUnused() error {
+	var unknown []string
+	for key := range s.values {
+		if !s.used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	slices.Sort(unknown)
+	return fmt.Errorf("config file %s: unknown parameters %s", s.path, strings.Join(unknown, ", "))
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLoadConfigFile проверяет чтение вложенных параметров из файла, приоритет окружения и отказ на неизвестных параметрах.
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write config file: %v", err)
+		}
+	}
+
+	write(`
+db:
+  host: db.internal
+  password: from-file
+kafka:
+  batch_size: 250
+  batch_timeout: 1s
+cors:
+  allowed_origins: [https://a.example, https://b.example]
+`)
+	t.Setenv("KAFKA_BATCH_SIZE", "50")
+	cfg, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile failed: %v", err)
+	}
+	if cfg.DBHost != "db.internal" || cfg.DBPassword != "from-file" || cfg.KafkaBatchTimeout != time.Second {
+		t.Errorf("file values not applied: host=%q password=%q timeout=%v", cfg.DBHost, cfg.DBPassword, cfg.KafkaBatchTimeout)
+	}
+	if cfg.KafkaBatchSize != 50 {
+		t.Errorf("environment must override file, got batch size %d", cfg.KafkaBatchSize)
+	}
+	if !slices.Equal(cfg.CORSAllowedOrigins, []string{"https://a.example", "https://b.example"}) {
+		t.Errorf("unexpected list value %v", cfg.CORSAllowedOrigins)
+	}
+	if cfg.DBPort != 5432 {
+		t.Errorf("missing parameters must get defaults, got port %d", cfg.DBPort)
+	}
+
+	// Флаги командной строки переопределяют и окружение, и файл
+	cfg, err = LoadConfigWithOverrides(path, map[string]string{"KAFKA_BATCH_SIZE": "75", "DB_HOST": "db.local", "DB_USER": "cli-user"})
+	if err != nil {
+		t.Fatalf("LoadConfigWithOverrides failed: %v", err)
+	}
+	if cfg.KafkaBatchSize != 75 || cfg.DBHost != "db.local" || cfg.DBUser != "cli-user" {
+		t.Errorf("overrides not applied: batch size=%d host=%q user=%q", cfg.KafkaBatchSize, cfg.DBHost, cfg.DBUser)
+	}
+	sources := cfg.Sources()
+	for key, want := range map[string]string{"KAFKA_BATCH_SIZE": OriginFlag, "DB_PASSWORD": OriginConfigFile, "KAFKA_BATCH_TIMEOUT": OriginConfigFile, "DB_PORT": OriginDefault} {
+		if sources[key] != want {
+			t.Errorf("source of %s = %q, want %q", key, sources[key], want)
+		}
+	}
+	if cfg.ConfigFile() != path {
+		t.Errorf("ConfigFile() = %q, want %q", cfg.ConfigFile(), path)
+	}
+
+	write("kafka:\n  batch_sise: 10\n")
+	if _, err := LoadConfigFile(path); err == nil || !strings.Contains(err.Error(), "KAFKA_BATCH_SISE") {
+		t.Errorf("expected unknown parameter error, got %v", err)
+	}
+	write("kafka:\n  brokers: [[a]]\n")
+	if _, err := LoadConfigFile(path); err == nil {
+		t.Error("expected error for nested list")
+	}
+	if _, err := LoadConfigFile(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+// TestLoadConfigReportsAllProblems проверяет, что все ошибки конфигурации перечисляются сразу.
+func TestLoadConfigReportsAllProblems(t *testing.T) {
+	t.Setenv("DB_PORT", "70000")
+	t.Setenv("KAFKA_BATCH_SIZE", "many")
+	t.Setenv("KAFKA_BROKERS", "kafka:9092,kafka")
+	t.Setenv("HTTP_PORT", "http")
+	t.Setenv("ADMIN_USER", "admin")
+	t.Setenv("TLS_CERT_FILE", "cert.pem")
+	t.Setenv("DB_POOL_MIN_CONNS", "50")
+	t.Setenv("DB_HOST", "pg-1,pg-2:postgres")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "*")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+
+	_, err := LoadConfig()
+	var cfgErr *ValidationError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	want := []string{"KAFKA_BATCH_SIZE", "DB_PORT", "HTTP_PORT", `"kafka" must have format host:port`,
+		"ADMIN_USER and ADMIN_PASSWORD", "TLS_CERT_FILE and TLS_KEY_FILE", "TLS_CERT_FILE: ",
+		"DB_POOL_MIN_CONNS (50) must not exceed DB_POOL_MAX_CONNS (25)", `DB_HOST "pg-2:postgres" must have a port`,
+		`CORS_ALLOW_CREDENTIALS cannot be combined with CORS_ALLOWED_ORIGINS "*"`}
+	for _, w := range want {
+		if !strings.Contains(err.Error(), w) {
+			t.Errorf("expected problem mentioning %q, got:\n%v", w, err)
+		}
+	}
+	if len(cfgErr.Problems) != len(want) {
+		t.Errorf("expected %d problems, got %d:\n%v", len(want), len(cfgErr.Problems), err)
+	}
+
+	cfg := &Config{DBHost: "db", DBUser: "u", DBName: "orders", DBPort: 5432, KafkaTopic: "orders", KafkaGroupID: "g",
+		KafkaBrokers: []string{"localhost:9092", "[::1]:9093"}, HTTPPort: "8081", MetricsPort: "9100"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"maps"
+	"sync"
+)
+
+// reloadable — параметры, которые можно изменить без перезапуска приложения.
+//
+//	apply переносит значение из src в dst и сообщает, изменилось ли оно.
+//	Остальные параметры (адреса, пулы соединений, TLS и т. п.) применяются
+//	только при старте.
+var reloadable = []struct {
+	key   string
+	apply func(dst, src *Config) bool
+}{
+	{"LOG_LEVEL", func(dst, src *Config) bool { return set(&dst.LogLevel, src.LogLevel) }},
+	{"API_KEY_DEFAULT_RPS", func(dst, src *Config) bool {
+		return set(&dst.APIKeyDefaultRateLimit, src.APIKeyDefaultRateLimit)
+	}},
+	{"STATS_CACHE_TTL", func(dst, src *Config) bool { return set(&dst.StatsCacheTTL, src.StatsCacheTTL) }},
+	{"KAFKA_BATCH_SIZE", func(dst, src *Config) bool { return set(&dst.KafkaBatchSize, src.KafkaBatchSize) }},
+}
+
+// set присваивает значение и сообщает, отличалось ли оно от прежнего.
+func set[T comparable](dst *T, v T) bool {
+	if *dst == v {
+		return false
+	}
+	*dst = v
+	return true
+}
+
+// Reloader перечитывает конфигурацию и применяет параметры, изменяемые на лету.
+//
+//	Конфигурация загружается тем же способом, что и при старте (файл,
+//	переменные окружения и флаги командной строки), и проверяется целиком: при ошибке действующие
+//	значения не меняются. Из новой конфигурации берутся только параметры
+//	из списка reloadable, поэтому состояние приложения (например, прогретый
+//	кэш заказов) сохраняется.
+type Reloader struct {
+	path      string
+	overrides map[string]string
+
+	mu       sync.Mutex
+	current  *Config
+	handlers []func(*Config)
+}
+
+// NewReloader создает Reloader.
+//
+//	Параметры:
+//	- path: путь к файлу конфигурации (пусто — только переменные окружения).
+//	- overrides: значения из командной строки (см. LoadConfigWithOverrides).
+//	- cfg: конфигурация, загруженная при старте.
+//	Возвращает:
+//	- *Reloader: экземпляр Reloader.
+func NewReloader(path string, overrides map[string]string, cfg *Config) *Reloader {
+	return &Reloader{path: path, overrides: overrides, current: cfg}
+}
+
+// OnReload регистрирует обработчик изменения конфигурации.
+//
+//	Обработчик вызывается после каждой перезагрузки, изменившей хотя бы один
+//	параметр, с новой конфигурацией. Конфигурацию нельзя изменять.
+//
+//	Параметры:
+//	- fn: обработчик.
+func (r *Reloader) OnReload(fn func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, fn)
+}
+
+// Reload перечитывает конфигурацию и применяет изменённые параметры.
+//
+//	Возвращает:
+//	- []string: имена изменённых параметров (пусто — изменений нет).
+//	- error: ошибку загрузки или *ValidationError; в этом случае ничего не применяется.
+func (r *Reloader) Reload() ([]string, error) {
+	loaded, err := LoadConfigWithOverrides(r.path, r.overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := *r.current
+	next.sources = make(map[string]string, len(r.current.sources))
+	maps.Copy(next.sources, r.current.sources)
+	var changed []string
+	for _, s := range reloadable {
+		if s.apply(&next, loaded) {
+			changed = append(changed, s.key)
+			next.sources[s.key] = loaded.sources[s.key]
+		}
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	r.current = &next
+	for _, fn := range r.handlers {
+		fn(r.current)
+	}
+	return changed, nil
+}
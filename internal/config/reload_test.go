@@ -0,0 +1,46 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestReloader проверяет, что перезагрузка применяет только изменяемые на лету параметры.
+func TestReloader(t *testing.T) {
+	t.Setenv("KAFKA_BATCH_SIZE", "100")
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	r := NewReloader("", nil, cfg)
+	var applied *Config
+	r.OnReload(func(c *Config) { applied = c })
+
+	if changed, err := r.Reload(); err != nil || len(changed) != 0 || applied != nil {
+		t.Fatalf("expected no changes, got %v, %v", changed, err)
+	}
+
+	t.Setenv("KAFKA_BATCH_SIZE", "500")
+	t.Setenv("STATS_CACHE_TTL", "1m")
+	t.Setenv("HTTP_PORT", "9090")
+	changed, err := r.Reload()
+	if err != nil || !slices.Equal(changed, []string{"STATS_CACHE_TTL", "KAFKA_BATCH_SIZE"}) {
+		t.Fatalf("Reload = %v, %v", changed, err)
+	}
+	if applied == nil || applied.KafkaBatchSize != 500 || applied.StatsCacheTTL != time.Minute {
+		t.Fatalf("handler got %+v", applied)
+	}
+	if applied.HTTPPort != cfg.HTTPPort {
+		t.Errorf("HTTP_PORT must not be reloaded, got %q", applied.HTTPPort)
+	}
+
+	// Некорректная конфигурация не применяется
+	t.Setenv("LOG_LEVEL", "loud")
+	if _, err := r.Reload(); err == nil {
+		t.Error("expected error for invalid LOG_LEVEL")
+	}
+	if applied.LogLevel != "info" {
+		t.Errorf("invalid config must not be applied, got LOG_LEVEL %q", applied.LogLevel)
+	}
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// secretStore хранит значения секретов из Vault и файла SECRETS_FILE.
+type secretStore struct {
+	vault  map[string]string // Поля секрета Vault (пусто, если Vault не настроен)
+	values map[string]string
+	file   *source // Файл конфигурации (nil — не задан)
+}
+
+// loadSecrets загружает секреты из Vault и зашифрованного файла секретов, если они заданы.
+//
+//	Файл SECRETS_FILE шифруется утилитой age (в бинарном или ASCII-armored виде)
+//	и после расшифровки содержит строки формата KEY=VALUE. Ключ расшифровки
+//	читается из файла SECRETS_IDENTITY_FILE. Параметры Vault описаны в newVaultClient.
+//	Возвращает:
+//	- *secretStore: хранилище секретов (пустое, если источники не заданы).
+//	- error: ошибку запроса к Vault, чтения или расшифровки файла.
+func loadSecrets() (*secretStore, error) {
+	store := &secretStore{vault: map[string]string{}, values: map[string]string{}}
+
+	vault, err := newVaultClient()
+	if err != nil {
+		return nil, err
+	}
+	if vault != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+		defer cancel()
+		if store.vault, err = vault.read(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	path := os.Getenv("SECRETS_FILE")
+	if path == "" {
+		return store, nil
+	}
+	identityPath := os.Getenv("SECRETS_IDENTITY_FILE")
+	if identityPath == "" {
+		return nil, fmt.Errorf("SECRETS_IDENTITY_FILE is required when SECRETS_FILE is set")
+	}
+
+	identityFile, err := os.Open(filepath.Clean(identityPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open identity file: %w", err)
+	}
+	defer func() { _ = identityFile.Close() }()
+	identities, err := age.ParseIdentities(identityFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse identity file: %w", err)
+	}
+
+	encrypted, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+	var src io.Reader = bytes.NewReader(encrypted)
+	if bytes.HasPrefix(encrypted, []byte(armor.Header)) {
+		src = armor.NewReader(src)
+	}
+	plain, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secrets file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(plain)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("secrets file line %d: expected KEY=VALUE", line)
+		}
+		store.values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read decrypted secrets: %w", err)
+	}
+	return store, nil
+}
+
+// get возвращает значение секрета.
+//
+//	Порядок поиска: значение из командной строки, переменная окружения KEY, файл из переменной KEY_FILE
+//	(например, Docker/Kubernetes secret), секрет Vault, файл секретов
+//	SECRETS_FILE, файл конфигурации, значение по умолчанию.
+//	Параметры:
+//	- key: имя секрета.
+//	- defaultVal: значение по умолчанию.
+//	Возвращает:
+//	- string: значение секрета.
+//	- error: ошибку чтения файла KEY_FILE.
+func (s *secretStore) get(key, defaultVal string) (string, error) {
+	var fileVal string
+	var inFile bool
+	if s.file != nil {
+		fileVal, inFile = s.file.lookup(key)
+		if val, ok := s.file.override(key); ok {
+			s.origin(key, OriginFlag)
+			return val, nil
+		}
+	}
+	if val := os.Getenv(key); val != "" {
+		s.origin(key, OriginEnv)
+		return val, nil
+	}
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s_FILE: %w", key, err)
+		}
+		s.origin(key, OriginEnvFile)
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	if val, ok := s.vault[key]; ok {
+		s.origin(key, OriginVault)
+		return val, nil
+	}
+	if val, ok := s.values[key]; ok {
+		s.origin(key, OriginSecretsFile)
+		return val, nil
+	}
+	if inFile {
+		s.origin(key, OriginConfigFile)
+		return fileVal, nil
+	}
+	s.origin(key, OriginDefault)
+	return defaultVal, nil
+}
+
+// origin запоминает источник значения секрета в файле конфигурации (если он подключён).
+func (s *secretStore) origin(key, origin string) {
+	if s.file != nil {
+		s.file.origins[key] = origin
+	}
+}
+
+// secretRefresher перечитывает источники секретов, чтобы подхватывать ротацию учётных данных.
+//
+//	Источники (KEY_FILE, Vault, SECRETS_FILE) перечитываются не чаще interval.
+//	Если перечитать их не удалось, используются значения последнего успешного чтения.
+type secretRefresher struct {
+	interval time.Duration
+	file     *source
+
+	mu      sync.Mutex
+	store   *secretStore
+	fetched time.Time
+}
+
+// get возвращает актуальное значение секрета.
+//
+//	Параметры:
+//	- key: имя секрета.
+//	- current: значение, загруженное при старте (используется, если секрет нигде не задан).
+//	Возвращает:
+//	- string: значение секрета; пригодно к использованию и при ошибке.
+//	- error: ошибку перечитывания источников.
+func (r *secretRefresher) get(key, current string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var refreshErr error
+	if time.Since(r.fetched) >= r.interval {
+		r.fetched = time.Now()
+		store, err := loadSecrets()
+		if err != nil {
+			refreshErr = fmt.Errorf("failed to refresh secrets: %w", err)
+		} else {
+			store.file = r.file
+			r.store = store
+		}
+	}
+	val, err := r.store.get(key, current)
+	if err != nil {
+		return current, err
+	}
+	return val, refreshErr
+}
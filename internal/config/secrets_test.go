@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+)
+
+// TestSecretStore проверяет порядок поиска секретов: переменная окружения, *_FILE, зашифрованный файл.
+func TestSecretStore(t *testing.T) {
+	dir := t.TempDir()
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("failed to generate identity: %v", err)
+	}
+	identityPath := filepath.Join(dir, "key.txt")
+	if err := os.WriteFile(identityPath, []byte(identity.String()+"\n"), 0600); err != nil {
+		t.Fatalf("failed to write identity: %v", err)
+	}
+
+	var encrypted bytes.Buffer
+	w, err := age.Encrypt(&encrypted, identity.Recipient())
+	if err != nil {
+		t.Fatalf("failed to create encryptor: %v", err)
+	}
+	if _, err := w.Write([]byte("# comment\nDB_PASSWORD=from-secrets-file\nKAFKA_SASL_PASSWORD=kafka-secret\n")); err != nil {
+		t.Fatalf("failed to encrypt: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to finish encryption: %v", err)
+	}
+	secretsPath := filepath.Join(dir, "secrets.age")
+	if err := os.WriteFile(secretsPath, encrypted.Bytes(), 0600); err != nil {
+		t.Fatalf("failed to write secrets file: %v", err)
+	}
+
+	passwordPath := filepath.Join(dir, "db_password")
+	if err := os.WriteFile(passwordPath, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("failed to write password file: %v", err)
+	}
+
+	t.Setenv("SECRETS_FILE", secretsPath)
+	t.Setenv("SECRETS_IDENTITY_FILE", identityPath)
+	t.Setenv("DB_PASSWORD", "")
+	t.Setenv("DB_PASSWORD_FILE", "")
+	t.Setenv("KAFKA_SASL_PASSWORD", "")
+	t.Setenv("KAFKA_SASL_PASSWORD_FILE", "")
+
+	store, err := loadSecrets()
+	if err != nil {
+		t.Fatalf("loadSecrets returned error: %v", err)
+	}
+
+	if got, _ := store.get("DB_PASSWORD", "default"); got != "from-secrets-file" {
+		t.Errorf("expected value from secrets file, got %q", got)
+	}
+
+	t.Setenv("DB_PASSWORD_FILE", passwordPath)
+	if got, _ := store.get("DB_PASSWORD", "default"); got != "from-file" {
+		t.Errorf("expected value from DB_PASSWORD_FILE, got %q", got)
+	}
+
+	t.Setenv("DB_PASSWORD", "from-env")
+	if got, _ := store.get("DB_PASSWORD", "default"); got != "from-env" {
+		t.Errorf("expected value from environment, got %q", got)
+	}
+
+	if got, _ := store.get("UNKNOWN_SECRET", "default"); got != "default" {
+		t.Errorf("expected default value, got %q", got)
+	}
+}
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ValidationError перечисляет все некорректные параметры конфигурации.
+//
+//	Конфигурация проверяется целиком, чтобы при запуске сообщить обо всех
+//	ошибках сразу, а не исправлять их по одной.
+type ValidationError struct {
+	Problems []string // Описания ошибок с именами параметров
+}
+
+// Error реализует интерфейс error.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration (%d problems):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// problems накапливает ошибки конфигурации.
+type problems []string
+
+// add добавляет ошибку.
+func (p *problems) add(err error) {
+	*p = append(*p, err.Error())
+}
+
+// addf добавляет ошибку, описанную форматом fmt.Sprintf.
+func (p *problems) addf(format string, args ...any) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+// Validate проверяет согласованность конфигурации.
+//
+//	Проверяются обязательные параметры, диапазоны портов, формат адресов
+//	брокеров Kafka, наличие файлов TLS и взаимоисключающие параметры.
+//	LoadConfig вызывает проверку сама; Validate нужна для конфигурации,
+//	собранной или изменённой в коде.
+//	Возвращает:
+//	- error: *ValidationError со всеми найденными ошибками или nil.
+func (c *Config) Validate() error {
+	if errs := c.validate(); len(errs) > 0 {
+		return &ValidationError{Problems: errs}
+	}
+	return nil
+}
+
+// validate возвращает ошибки согласованности конфигурации.
+func (c *Config) validate() problems {
+	var errs problems
+
+	// Обязательные параметры; параметры БД не нужны, если строка подключения задана целиком
+	required := []struct{ name, value string }{
+		{"KAFKA_TOPIC", c.KafkaTopic},
+		{"KAFKA_GROUP_ID", c.KafkaGroupID},
+		{"HTTP_PORT", c.HTTPPort},
+	}
+	if c.DBDSN == "" {
+		required = append([]struct{ name, value string }{
+			{"DB_HOST", c.DBHost},
+			{"DB_USER", c.DBUser},
+			{"DB_NAME", c.DBName},
+		}, required...)
+	}
+	for _, p := range required {
+		if strings.TrimSpace(p.value) == "" {
+			errs.addf("%s is required", p.name)
+		}
+	}
+
+	// Порты
+	if c.DBPort < 1 || c.DBPort > 65535 {
+		errs.addf("DB_PORT must be between 1 and 65535, got %d", c.DBPort)
+	}
+	if c.DBDSN == "" {
+		for _, host := range strings.Split(c.DBHost, ",") {
+			host = strings.TrimSpace(host)
+			if host == "" && c.DBHost != "" {
+				errs.addf("DB_HOST must not contain empty hosts, got %q", c.DBHost)
+				continue
+			}
+			if _, port, err := net.SplitHostPort(host); err == nil && !validPort(port) {
+				errs.addf("DB_HOST %q must have a port number between 1 and 65535", host)
+			}
+		}
+	}
+	for _, p := range []struct{ name, value string }{
+		{"HTTP_PORT", c.HTTPPort},
+		{"HTTP_REDIRECT_PORT", c.HTTPRedirectPort},
+	} {
+		if p.value != "" && !validPort(p.value) {
+			errs.addf("%s must be a port number between 1 and 65535, got %q", p.name, p.value)
+		}
+	}
+	if c.HTTPRedirectPort != "" && c.HTTPRedirectPort == c.HTTPPort {
+		errs.addf("HTTP_REDIRECT_PORT must differ from HTTP_PORT (%s)", c.HTTPPort)
+	}
+	if !c.MetricsOnMainServer {
+		if !validPort(c.MetricsPort) {
+			errs.addf("METRICS_PORT must be a port number between 1 and 65535, got %q", c.MetricsPort)
+		} else if c.MetricsPort == c.HTTPPort || c.MetricsPort == c.HTTPRedirectPort {
+			errs.addf("METRICS_PORT (%s) must differ from HTTP_PORT and HTTP_REDIRECT_PORT; set METRICS_ON_MAIN_SERVER=true to serve /metrics on the HTTP server", c.MetricsPort)
+		}
+	}
+
+	// Пул соединений с БД
+	if c.DBPoolMinConns > c.DBPoolMaxConns {
+		errs.addf("DB_POOL_MIN_CONNS (%d) must not exceed DB_POOL_MAX_CONNS (%d)", c.DBPoolMinConns, c.DBPoolMaxConns)
+	}
+
+	// Брокеры Kafka
+	if len(c.KafkaBrokers) == 0 {
+		errs.addf("KAFKA_BROKERS is required")
+	}
+	for _, broker := range c.KafkaBrokers {
+		host, port, err := net.SplitHostPort(broker)
+		if err != nil || host == "" || !validPort(port) {
+			errs.addf("KAFKA_BROKERS: %q must have format host:port", broker)
+		}
+	}
+	if (c.KafkaSASLUser == "") != (c.KafkaSASLPassword == "") {
+		errs.addf("KAFKA_SASL_USER and KAFKA_SASL_PASSWORD must be set together")
+	}
+
+	// С учётными данными любой сайт мог бы выполнять запросы от имени пользователя
+	if c.CORSAllowCredentials && slices.Contains(c.CORSAllowedOrigins, "*") {
+		errs.addf(`CORS_ALLOW_CREDENTIALS cannot be combined with CORS_ALLOWED_ORIGINS "*"`)
+	}
+
+	// TLS-подключение к БД
+	if (c.DBSSLCert == "") != (c.DBSSLKey == "") {
+		errs.addf("DB_SSLCERT and DB_SSLKEY must be set together")
+	}
+
+	// TLS
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs.addf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if len(c.TLSAutocertDomains) > 0 && c.TLSCertFile != "" {
+		errs.addf("TLS_AUTOCERT_DOMAINS cannot be combined with TLS_CERT_FILE")
+	}
+	if c.TLSAutocertEmail != "" && len(c.TLSAutocertDomains) == 0 {
+		errs.addf("TLS_AUTOCERT_EMAIL requires TLS_AUTOCERT_DOMAINS")
+	}
+	for _, domain := range c.TLSAutocertDomains {
+		if !validAutocertDomain(domain) {
+			errs.addf("TLS_AUTOCERT_DOMAINS: %q must be a host name without scheme, port or wildcard", domain)
+		}
+	}
+	// Несовпадающие сертификат и ключ иначе обнаружились бы только при запуске HTTPS-сервера
+	if c.TLSCertFile != "" && c.TLSKeyFile != "" && fileExists(c.TLSCertFile) && fileExists(c.TLSKeyFile) {
+		if _, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile); err != nil {
+			errs.addf("TLS_CERT_FILE and TLS_KEY_FILE: %v", err)
+		}
+	}
+	for _, p := range []struct{ name, path string }{
+		{"TLS_CERT_FILE", c.TLSCertFile},
+		{"TLS_KEY_FILE", c.TLSKeyFile},
+		{"DB_SSLROOTCERT", c.DBSSLRootCert},
+		{"DB_SSLCERT", c.DBSSLCert},
+		{"DB_SSLKEY", c.DBSSLKey},
+	} {
+		if p.path == "" {
+			continue
+		}
+		if _, err := os.Stat(p.path); err != nil {
+			errs.addf("%s: %v", p.name, err)
+		}
+	}
+	if c.StaticDir != "" {
+		if info, err := os.Stat(c.StaticDir); err != nil || !info.IsDir() {
+			errs.addf("STATIC_DIR: %q is not a directory", c.StaticDir)
+		}
+	}
+
+	// Доступ к API
+	if (c.AdminUser == "") != (c.AdminPassword == "") {
+		errs.addf("ADMIN_USER and ADMIN_PASSWORD must be set together")
+	}
+	if c.PIIEncryptionKey == "" && len(c.PIIEncryptionPreviousKeys) > 0 {
+		errs.addf("PII_ENCRYPTION_PREVIOUS_KEYS requires PII_ENCRYPTION_KEY")
+	}
+
+	// Режим воспроизведения
+	if c.ReplayEnabled && c.ReplayTarget == ReplayTargetScratch && strings.TrimSpace(c.ReplaySchema) == "" {
+		errs.addf("REPLAY_SCHEMA is required when REPLAY_TARGET=%s", ReplayTargetScratch)
+	}
+	return errs
+}
+
+// validPort сообщает, что строка — номер порта от 1 до 65535.
+func validPort(s string) bool {
+	port, err := strconv.Atoi(s)
+	return err == nil && port >= 1 && port <= 65535
+}
+
+// fileExists сообщает, существует ли файл; об отсутствующем файле сообщает отдельная проверка.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// validAutocertDomain сообщает, может ли Let's Encrypt выдать сертификат для домена по HTTP-01:
+// это имя хоста из точек и меток, не IP-адрес и не маска.
+func validAutocertDomain(domain string) bool {
+	if domain == "" || net.ParseIP(domain) != nil || !strings.Contains(domain, ".") {
+		return false
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
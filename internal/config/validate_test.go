@@ -0,0 +1,103 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeKeyPair создаёт самоподписанный сертификат и его ключ в dir.
+//
+//	Возвращает:
+//	- string: путь к сертификату.
+//	- string: путь к ключу.
+func writeKeyPair(t *testing.T, dir, name string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "orders.example"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	certPath, keyPath := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return certPath, keyPath
+}
+
+// TestValidateTLS проверяет, что ошибки настройки HTTPS обнаруживаются при загрузке конфигурации, а не при запуске сервера.
+func TestValidateTLS(t *testing.T) {
+	dir := t.TempDir()
+	certA, keyA := writeKeyPair(t, dir, "a")
+	_, keyB := writeKeyPair(t, dir, "b")
+
+	tests := []struct {
+		name    string
+		tweak   func(c *Config)
+		wantErr string // Пусто — конфигурация корректна
+	}{
+		{name: "certificate files", tweak: func(c *Config) { c.TLSCertFile, c.TLSKeyFile = certA, keyA }},
+		{name: "cert and key mismatch", tweak: func(c *Config) { c.TLSCertFile, c.TLSKeyFile = certA, keyB },
+			wantErr: "TLS_CERT_FILE and TLS_KEY_FILE: tls: private key does not match public key"},
+		{name: "key is not a key", tweak: func(c *Config) { c.TLSCertFile, c.TLSKeyFile = certA, certA },
+			wantErr: "TLS_CERT_FILE and TLS_KEY_FILE: "},
+		{name: "autocert", tweak: func(c *Config) {
+			c.TLSAutocertDomains, c.TLSAutocertEmail = []string{"orders.example.com", "api.example.com"}, "ops@example.com"
+		}},
+		{name: "autocert email without domains", tweak: func(c *Config) { c.TLSAutocertEmail = "ops@example.com" },
+			wantErr: "TLS_AUTOCERT_EMAIL requires TLS_AUTOCERT_DOMAINS"},
+		{name: "empty autocert domain", tweak: func(c *Config) { c.TLSAutocertDomains = []string{""} },
+			wantErr: `TLS_AUTOCERT_DOMAINS: "" must be a host name`},
+		{name: "autocert domain with scheme", tweak: func(c *Config) { c.TLSAutocertDomains = []string{"https://orders.example.com"} },
+			wantErr: `TLS_AUTOCERT_DOMAINS: "https://orders.example.com"`},
+		{name: "wildcard autocert domain", tweak: func(c *Config) { c.TLSAutocertDomains = []string{"*.example.com"} },
+			wantErr: `TLS_AUTOCERT_DOMAINS: "*.example.com"`},
+		{name: "autocert IP address", tweak: func(c *Config) { c.TLSAutocertDomains = []string{"203.0.113.10"} },
+			wantErr: `TLS_AUTOCERT_DOMAINS: "203.0.113.10"`},
+		{name: "autocert with certificate files", tweak: func(c *Config) {
+			c.TLSCertFile, c.TLSKeyFile, c.TLSAutocertDomains = certA, keyA, []string{"orders.example.com"}
+		}, wantErr: "TLS_AUTOCERT_DOMAINS cannot be combined with TLS_CERT_FILE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{DBHost: "db", DBUser: "u", DBName: "orders", DBPort: 5432, KafkaTopic: "orders", KafkaGroupID: "g",
+				KafkaBrokers: []string{"localhost:9092"}, HTTPPort: "8443", MetricsPort: "9100"}
+			tt.tweak(c)
+			err := c.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// vaultTimeout ограничивает время запроса секретов к Vault.
+const vaultTimeout = 10 * time.Second
+
+// vaultClient читает секреты из хранилища KV HashiCorp Vault.
+//
+//	Поддерживаются обе версии KV: для KV v2 в VAULT_SECRET_PATH указывается
+//	путь с сегментом data (например, "secret/data/l0_wb"). Имена полей
+//	секрета совпадают с именами переменных окружения (DB_PASSWORD и т. п.).
+type vaultClient struct {
+	addr      string
+	token     string
+	namespace string
+	path      string
+	http      *http.Client
+}
+
+// newVaultClient создаёт клиент Vault по переменным окружения.
+//
+//	Адрес берётся из VAULT_ADDR, путь секрета — из VAULT_SECRET_PATH, токен —
+//	из VAULT_TOKEN или файла VAULT_TOKEN_FILE (например, от Vault Agent),
+//	пространство имён Vault Enterprise — из VAULT_NAMESPACE.
+//	Возвращает:
+//	- *vaultClient: клиент или nil, если VAULT_ADDR не задан.
+//	- error: ошибку, если не заданы путь или токен.
+func newVaultClient() (*vaultClient, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil, nil
+	}
+	path := strings.Trim(os.Getenv("VAULT_SECRET_PATH"), "/")
+	if path == "" {
+		return nil, fmt.Errorf("VAULT_SECRET_PATH is required when VAULT_ADDR is set")
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if tokenPath := os.Getenv("VAULT_TOKEN_FILE"); token == "" && tokenPath != "" {
+		data, err := os.ReadFile(filepath.Clean(tokenPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read VAULT_TOKEN_FILE: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token == "" {
+		return nil, fmt.Errorf("VAULT_TOKEN or VAULT_TOKEN_FILE is required when VAULT_ADDR is set")
+	}
+	return &vaultClient{
+		addr:      strings.TrimRight(addr, "/"),
+		token:     token,
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		path:      path,
+		http:      &http.Client{Timeout: vaultTimeout},
+	}, nil
+}
+
+// read возвращает поля секрета.
+//
+//	Параметры:
+//	- ctx: контекст запроса.
+//	Возвращает:
+//	- map[string]string: значения полей секрета.
+//	- error: ошибку запроса или разбора ответа.
+func (v *vaultClient) read(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.addr+"/v1/"+v.path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+	resp, err := v.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("vault returned %s for %s: %s", resp.Status, v.path, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	fields := payload.Data
+	// KV v2 вкладывает поля секрета в data.data рядом с data.metadata
+	if nested, ok := fields["data"].(map[string]any); ok {
+		if _, ok := fields["metadata"]; ok {
+			fields = nested
+		}
+	}
+	values := make(map[string]string, len(fields))
+	for k, val := range fields {
+		if s, ok := val.(string); ok {
+			values[k] = s
+		} else {
+			values[k] = fmt.Sprint(val)
+		}
+	}
+	return values, nil
+}
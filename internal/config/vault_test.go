@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestVaultSecrets проверяет чтение секретов из Vault KV v2 и подхват ротации пароля.
+func TestVaultSecrets(t *testing.T) {
+	var password atomic.Value
+	password.Store("vault-v1")
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/l0_wb" || r.Header.Get("X-Vault-Token") != "root-token" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"data":{"DB_USER":"vault-user","DB_PASSWORD":%q,"KAFKA_SASL_USER":"kafka"},"metadata":{"version":3}}}`, password.Load())
+	}))
+	defer vault.Close()
+
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_SECRET_PATH", "secret/data/l0_wb")
+	t.Setenv("VAULT_TOKEN", "root-token")
+	t.Setenv("DB_USER", "")
+	t.Setenv("DB_PASSWORD", "")
+	t.Setenv("KAFKA_SASL_PASSWORD", "kafka-secret")
+	t.Setenv("SECRETS_REFRESH_INTERVAL", "1h")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.DBUser != "vault-user" || cfg.DBPassword != "vault-v1" || cfg.KafkaSASLUser != "kafka" {
+		t.Fatalf("expected credentials from vault, got %q/%q/%q", cfg.DBUser, cfg.DBPassword, cfg.KafkaSASLUser)
+	}
+
+	// Новое значение применяется только после интервала обновления
+	password.Store("vault-v2")
+	if got, _ := cfg.Secret("DB_PASSWORD", cfg.DBPassword); got != "vault-v1" {
+		t.Errorf("expected cached password, got %q", got)
+	}
+	cfg.secrets.fetched = time.Time{}
+	if got, err := cfg.Secret("DB_PASSWORD", cfg.DBPassword); err != nil || got != "vault-v2" {
+		t.Errorf("expected rotated password, got %q, %v", got, err)
+	}
+	if got, _ := cfg.Secret("KAFKA_SASL_PASSWORD", cfg.KafkaSASLPassword); got != "kafka-secret" {
+		t.Errorf("environment must win over vault, got %q", got)
+	}
+
+	// При недоступном Vault используются последние полученные значения
+	vault.Close()
+	cfg.secrets.fetched = time.Time{}
+	if got, err := cfg.Secret("DB_PASSWORD", cfg.DBPassword); err == nil || got != "vault-v2" {
+		t.Errorf("expected last known password and error, got %q, %v", got, err)
+	}
+
+	t.Setenv("VAULT_TOKEN", "")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("expected error without VAULT_TOKEN")
+	}
+}
@@ -3,166 +3,341 @@ package db
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
-	"os"
-	"path/filepath"
+	"net"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 	"l0_wb/internal/config"
+	"l0_wb/internal/startup"
 	"l0_wb/internal/util"
 )
 
+// ApplicationName — application_name соединений сервиса с основной БД и репликами.
+//
+//	Имя уникально для процесса: триггеры уведомлений об изменении заказов
+//	передают его, и cdc.Listener пропускает уведомления о собственных записях
+//	сервиса, но не о записях других экземпляров.
+var ApplicationName = newApplicationName()
+
+// newApplicationName возвращает application_name со случайным суффиксом экземпляра.
+func newApplicationName() string {
+	suffix := make([]byte, 6)
+	_, _ = rand.Read(suffix)
+	return "l0_wb-" + hex.EncodeToString(suffix)
+}
+
 // InitDB инициализирует подключение к базе данных, используя переданную конфигурацию.
 //
+//	Подключение повторяется согласно STARTUP_RETRY_*, пока Postgres не станет
+//	доступен. При DB_AUTO_MIGRATE=true применяются неприменённые миграции схемы; иначе
+//	отставание схемы от миграций сервиса только записывается в журнал.
+//	Параметры:
+//	- ctx: контекст выполнения; его отмена (например, по SIGTERM) прерывает ожидание Postgres.
+//	- cfg: конфигурация приложения.
 //	Возвращает:
 //	- *pgxpool.Pool: пул соединений к базе данных.
 //	- error: ошибка, если не удалось установить подключение.
-func InitDB(cfg *config.Config) (*pgxpool.Pool, error) {
+func InitDB(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
 	logger := util.GetLogger()
 
-	// Формируем строку подключения на основе конфигурации.
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		cfg.DBHost,
-		cfg.DBPort,
-		cfg.DBUser,
-		cfg.DBPassword,
-		cfg.DBName,
-	)
+	// Postgres может ещё запускаться (например, в docker-compose), поэтому подключение повторяется
+	var dbPool *pgxpool.Pool
+	err := startup.Wait(ctx, "postgres", startup.Policy{
+		MaxAttempts: cfg.StartupRetryMaxAttempts,
+		BaseDelay:   cfg.StartupRetryBaseDelay,
+		MaxDelay:    cfg.StartupRetryMaxDelay,
+	}, func(ctx context.Context) error {
+		var err error
+		dbPool, err = Connect(ctx, cfg)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Выполняем миграции.
+	if cfg.DBAutoMigrate {
+		err = MigrateUp(dbPool)
+	} else {
+		err = checkSchemaVersion(dbPool)
+	}
+	if err != nil {
+		dbPool.Close()
+		logger.Error("Failed to run migrations", zap.Error(err))
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	logger.Info("Database initialized successfully")
+	return dbPool, nil
+}
+
+// Connect создаёт пул соединений к базе данных и проверяет подключение.
+//
+//	Миграции не выполняются.
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- cfg: конфигурация приложения.
+//	Возвращает:
+//	- *pgxpool.Pool: пул соединений к базе данных.
+//	- error: ошибка, если не удалось установить подключение.
+func Connect(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	logger := util.GetLogger()
 
 	// Настройки пула соединений.
-	poolConfig, err := pgxpool.ParseConfig(dsn)
+	poolConfig, err := pgxpool.ParseConfig(buildDSN(cfg))
 	if err != nil {
 		logger.Error("Failed to parse DB config", zap.Error(err))
 		return nil, fmt.Errorf("failed to parse DB config: %w", err)
 	}
 
-	poolConfig.MaxConns = 500                       // Максимальное количество соединений
-	poolConfig.MinConns = 100                       // Минимальное количество соединений
-	poolConfig.HealthCheckPeriod = 30 * time.Second // Проверка соединений раз в 30 сек
-	poolConfig.MaxConnLifetime = 5 * time.Minute    // Соединения живут не более 5 минут
-	poolConfig.MaxConnIdleTime = 1 * time.Minute    // Простой соединения не больше 1 минуты
+	configurePool(poolConfig, cfg)
+	rotateCredentials(poolConfig, cfg)
 
-	// Создаем пул соединений
-	dbPool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	// Создаем пул соединений; контекст пула открывает минимальные соединения
+	// в фоне и не должен отменяться вместе с ctx попытки подключения
+	dbPool, err := pgxpool.NewWithConfig(context.WithoutCancel(ctx), poolConfig)
 	if err != nil {
 		logger.Error("Failed to create DB pool", zap.Error(err))
 		return nil, fmt.Errorf("failed to create DB pool: %w", err)
 	}
 
 	// Проверяем подключение
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	if err := dbPool.Ping(ctx); err != nil {
+	if err := dbPool.Ping(pingCtx); err != nil {
+		dbPool.Close()
 		logger.Error("Failed to ping DB", zap.Error(err))
 		return nil, fmt.Errorf("failed to ping DB: %w", err)
 	}
+	return dbPool, nil
+}
 
-	// Выполняем миграции.
-	if err := runMigrations(dbPool); err != nil {
-		logger.Error("Failed to run migrations", zap.Error(err))
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
+// configurePool задаёт размер пула, время жизни соединений, statement_timeout, application_name и трассировку запросов из конфигурации.
+//
+//	Параметры:
+//	- poolConfig: настройки пула pgx.
+//	- cfg: конфигурация приложения.
+func configurePool(poolConfig *pgxpool.Config, cfg *config.Config) {
+	poolConfig.MaxConns = int32(cfg.DBPoolMaxConns)
+	poolConfig.MinConns = int32(cfg.DBPoolMinConns)
+	poolConfig.HealthCheckPeriod = cfg.DBPoolHealthCheckPeriod
+	poolConfig.MaxConnLifetime = cfg.DBPoolMaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.DBPoolMaxConnIdleTime
+	poolConfig.ConnConfig.Tracer = newQueryTracer(cfg.DBSlowQueryThreshold)
+	poolConfig.ConnConfig.RuntimeParams["application_name"] = ApplicationName
+	// Сервер сам прерывает зависший запрос, даже если клиент не отменил его
+	if cfg.DBStatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.DBStatementTimeout.Milliseconds(), 10)
 	}
-
-	logger.Info("Database initialized successfully")
-	return dbPool, nil
 }
 
-// runMigrations выполняет SQL-миграции.
+// rotateCredentials подставляет в каждое новое соединение актуальные DB_USER и DB_PASSWORD.
 //
-//	Данный метод опционален и зависит от потребностей проекта.
-//	Возвращает:
-//	- error: ошибка, если не удалось применить миграции.
-func runMigrations(db *pgxpool.Pool) error {
+//	Учётные данные берутся через cfg.Secret, поэтому пароль, сменённый в Vault
+//	или файле DB_PASSWORD_FILE, применяется к новым соединениям без перезапуска;
+//	открытые соединения доживают до MaxConnLifetime. Если источники секретов
+//	недоступны, используются последние полученные значения. При DB_DSN
+//	учётные данные берутся из актуальной строки подключения.
+//	Параметры:
+//	- poolConfig: настройки пула pgx.
+//	- cfg: конфигурация приложения.
+func rotateCredentials(poolConfig *pgxpool.Config, cfg *config.Config) {
 	logger := util.GetLogger()
-	migrationsDir := "internal/db/migrations"
-
-	logger.Info("Starting database migrations", zap.String("migrationsDir", migrationsDir))
-
-	// Check if migrations directory exists
-	if _, err := os.Stat(migrationsDir); os.IsNotExist(err) {
-		logger.Error("Migrations directory does not exist", zap.String("dir", migrationsDir))
-		// Try alternative path
-		migrationsDir = "./internal/db/migrations"
-		logger.Info("Trying alternative migrations path", zap.String("migrationsDir", migrationsDir))
-		if _, err := os.Stat(migrationsDir); os.IsNotExist(err) {
-			logger.Error("Alternative migrations directory does not exist", zap.String("dir", migrationsDir))
-			return fmt.Errorf("migrations directory does not exist: %s", migrationsDir)
+	if cfg.DBDSN != "" {
+		poolConfig.BeforeConnect = func(_ context.Context, cc *pgx.ConnConfig) error {
+			dsn, err := cfg.Secret("DB_DSN", cfg.DBDSN)
+			if err != nil {
+				logger.Warn("Failed to refresh DB credentials", zap.Error(err))
+			}
+			current, err := pgx.ParseConfig(dsn)
+			if err != nil {
+				// Ошибка разбора может содержать фрагменты строки подключения
+				logger.Warn("Failed to parse refreshed DB_DSN, keeping previous credentials")
+				return nil
+			}
+			cc.User, cc.Password = current.User, current.Password
+			return nil
 		}
+		return
 	}
-
-	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.sql"))
-	if err != nil {
-		logger.Error("Failed to find migration files", zap.Error(err), zap.String("pattern", filepath.Join(migrationsDir, "*.sql")))
-		return fmt.Errorf("failed to find migration files: %w", err)
+	poolConfig.BeforeConnect = func(_ context.Context, cc *pgx.ConnConfig) error {
+		user, err := cfg.Secret("DB_USER", cfg.DBUser)
+		if err != nil {
+			logger.Warn("Failed to refresh DB credentials", zap.Error(err))
+		}
+		password, err := cfg.Secret("DB_PASSWORD", cfg.DBPassword)
+		if err != nil {
+			logger.Warn("Failed to refresh DB credentials", zap.Error(err))
+		}
+		cc.User, cc.Password = user, password
+		return nil
 	}
+}
 
-	logger.Info("Found migration files", zap.Int("count", len(files)), zap.Strings("files", files))
+// InitReplicas создаёт пулы соединений к репликам для чтения из DB_REPLICA_DSNS.
+//
+//	Миграции на репликах не выполняются: схема приходит с основной БД через репликацию.
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- cfg: конфигурация приложения.
+//	Возвращает:
+//	- []*pgxpool.Pool: пулы соединений к репликам (nil, если реплики не заданы).
+//	- error: ошибка, если к какой-либо реплике не удалось подключиться.
+func InitReplicas(ctx context.Context, cfg *config.Config) ([]*pgxpool.Pool, error) {
+	logger := util.GetLogger()
 
-	// Получаем абсолютный путь к папке миграций.
-	absDir, err := filepath.Abs(migrationsDir)
-	if err != nil {
-		logger.Error("Failed to resolve absolute path for migrations directory", zap.String("dir", migrationsDir), zap.Error(err))
-		return fmt.Errorf("failed to resolve absolute path for migrations directory %s: %w", migrationsDir, err)
+	pools := make([]*pgxpool.Pool, 0, len(cfg.DBReplicaDSNs))
+	closeAll := func() {
+		for _, p := range pools {
+			p.Close()
+		}
 	}
-
-	logger.Info("Resolved absolute path for migrations directory", zap.String("absDir", absDir))
-
-	for _, file := range files {
-		// Очистка пути для предотвращения path traversal атак
-		cleanFile := filepath.Clean(file)
-		logger.Info("Processing migration file", zap.String("file", cleanFile))
-
-		absFile, err := filepath.Abs(cleanFile)
+	for i, dsn := range cfg.DBReplicaDSNs {
+		poolConfig, err := pgxpool.ParseConfig(dsn)
 		if err != nil {
-			logger.Error("Failed to resolve absolute path", zap.String("file", cleanFile), zap.Error(err))
-			return fmt.Errorf("failed to resolve absolute path for %s: %w", cleanFile, err)
+			closeAll()
+			// Строка подключения может содержать пароль, поэтому в ошибку попадает только номер реплики
+			return nil, fmt.Errorf("failed to parse DB replica %d config", i)
 		}
-		logger.Info("Resolved absolute path", zap.String("absFile", absFile))
+		configurePool(poolConfig, cfg)
 
-		// Проверяем, что файл действительно находится в папке миграций
-		relPath, err := filepath.Rel(absDir, absFile)
+		pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 		if err != nil {
-			logger.Error("Failed to get relative path", zap.String("absDir", absDir), zap.String("absFile", absFile), zap.Error(err))
-			return fmt.Errorf("failed to get relative path: %w", err)
+			closeAll()
+			return nil, fmt.Errorf("failed to create DB replica %d pool: %w", i, err)
 		}
+		pools = append(pools, pool)
 
-		if relPath == "" || relPath[0] == '.' {
-			logger.Error("File is outside the migrations directory", zap.String("file", absFile), zap.String("relPath", relPath))
-			return fmt.Errorf("file %s is outside the migrations directory", absFile)
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err = pool.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("failed to ping DB replica %d: %w", i, err)
 		}
-		logger.Info("File is inside migrations directory", zap.String("relPath", relPath))
+		logger.Info("Database replica initialized", zap.Int("replica", i), zap.String("host", poolConfig.ConnConfig.Host))
+	}
+	if len(pools) == 0 {
+		return nil, nil
+	}
+	return pools, nil
+}
 
-		logger.Info("Applying migration", zap.String("file", absFile))
+// InitScratchDB создаёт отдельную схему и пул соединений к ней для режима воспроизведения.
+//
+//	Схема создаётся при необходимости, а миграции применяются внутри неё, поэтому
+//	воспроизведённые заказы не смешиваются с рабочими данными.
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- cfg: конфигурация приложения.
+//	- schema: имя схемы.
+//	Возвращает:
+//	- *pgxpool.Pool: пул соединений с search_path, указывающим на схему.
+//	- error: ошибка, если схему или пул не удалось подготовить.
+func InitScratchDB(ctx context.Context, cfg *config.Config, schema string) (*pgxpool.Pool, error) {
+	logger := util.GetLogger()
 
-		// Check if file exists and is readable
-		fileInfo, err := os.Stat(absFile)
-		if err != nil {
-			logger.Error("Failed to stat migration file", zap.String("file", absFile), zap.Error(err))
-			return fmt.Errorf("failed to stat migration file %s: %w", absFile, err)
-		}
-		logger.Info("File stats", zap.String("file", absFile), zap.Int64("size", fileInfo.Size()), zap.String("mode", fileInfo.Mode().String()))
+	poolConfig, err := pgxpool.ParseConfig(buildDSN(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DB config: %w", err)
+	}
+	poolConfig.MaxConns = 10
+	poolConfig.ConnConfig.RuntimeParams["search_path"] = schema
+	poolConfig.ConnConfig.Tracer = newQueryTracer(cfg.DBSlowQueryThreshold)
+	rotateCredentials(poolConfig, cfg)
 
-		// Безопасное чтение файла
-		//nolint:gosec // Безопасность гарантирована проверкой пути выше
-		content, err := os.ReadFile(absFile)
-		if err != nil {
-			logger.Error("Failed to read migration file", zap.String("file", absFile), zap.Error(err))
-			return fmt.Errorf("failed to read migration file %s: %w", absFile, err)
+	// Схему создаём через отдельное соединение: search_path пула ссылается на ещё не существующую схему.
+	conn, err := pgx.ConnectConfig(ctx, poolConfig.ConnConfig.Copy())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to DB: %w", err)
+	}
+	_, err = conn.Exec(ctx, "CREATE SCHEMA IF NOT EXISTS "+pgx.Identifier{schema}.Sanitize())
+	_ = conn.Close(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema %s: %w", schema, err)
+	}
+
+	scratchPool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch DB pool: %w", err)
+	}
+	if err := MigrateUp(scratchPool); err != nil {
+		scratchPool.Close()
+		return nil, fmt.Errorf("failed to run migrations in schema %s: %w", schema, err)
+	}
+
+	logger.Info("Scratch database schema initialized", zap.String("schema", schema))
+	return scratchPool, nil
+}
+
+// buildDSN формирует строку подключения на основе конфигурации.
+//
+//	Если задан DB_DSN, он используется как есть. Иначе строка собирается из
+//	DB_HOST..DB_SSLKEY; значения экранируются, поэтому пароль может содержать
+//	пробелы и кавычки.
+func buildDSN(cfg *config.Config) string {
+	if cfg.DBDSN != "" {
+		return cfg.DBDSN
+	}
+	hosts, ports := splitHosts(cfg.DBHost, cfg.DBPort)
+	params := []struct{ key, value string }{
+		{"host", strings.Join(hosts, ",")},
+		{"port", strings.Join(ports, ",")},
+		{"user", cfg.DBUser},
+		{"password", cfg.DBPassword},
+		{"dbname", cfg.DBName},
+		{"target_session_attrs", cfg.DBTargetSessionAttrs},
+		{"sslmode", cfg.DBSSLMode},
+		{"sslrootcert", cfg.DBSSLRootCert},
+		{"sslcert", cfg.DBSSLCert},
+		{"sslkey", cfg.DBSSLKey},
+	}
+	parts := make([]string, 0, len(params))
+	for _, p := range params {
+		if p.value != "" {
+			parts = append(parts, p.key+"="+quoteDSNValue(p.value))
 		}
-		logger.Info("Read migration file content", zap.String("file", absFile), zap.Int("contentLength", len(content)))
+	}
+	return strings.Join(parts, " ")
+}
 
-		// Выполняем SQL-запрос
-		logger.Info("Executing SQL migration", zap.String("file", absFile))
-		if _, err := db.Exec(context.Background(), string(content)); err != nil {
-			logger.Error("Failed to execute migration", zap.String("file", absFile), zap.Error(err))
-			return fmt.Errorf("failed to execute migration %s: %w", absFile, err)
+// splitHosts разбирает список хостов DB_HOST для строки подключения.
+//
+//	pgx перебирает хосты по порядку и подключается к первому, который отвечает
+//	требованию target_session_attrs, поэтому при переключении на резервный
+//	сервер новые соединения пула уходят на новый основной без перезапуска.
+//	Параметры:
+//	- hostList: хосты через запятую в виде host или host:port.
+//	- defaultPort: порт для хостов без порта.
+//	Возвращает:
+//	- []string: хосты.
+//	- []string: порты в том же порядке.
+func splitHosts(hostList string, defaultPort int) ([]string, []string) {
+	var hosts, ports []string
+	for _, entry := range strings.Split(hostList, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
 		}
-		logger.Info("Successfully executed migration", zap.String("file", absFile))
+		host, port, err := net.SplitHostPort(entry)
+		if err != nil {
+			host, port = entry, strconv.Itoa(defaultPort)
+		}
+		hosts = append(hosts, host)
+		ports = append(ports, port)
 	}
+	return hosts, ports
+}
 
-	logger.Info("All migrations applied successfully")
-	return nil
+// quoteDSNValue заключает значение строки подключения в кавычки, экранируя обратную косую черту и апостроф.
+func quoteDSNValue(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
 }
@@ -0,0 +1,366 @@
+package db
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+	"l0_wb/internal/util"
+)
+
+// migrationFiles — версионные миграции схемы: пары <версия>_<имя>.up.sql и
+// <версия>_<имя>.down.sql. Миграции встроены в бинарный файл.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrator применяет версионные миграции схемы БД.
+//
+//	Применённая версия хранится в таблице schema_migrations текущей схемы
+//	(search_path пула). На время миграции берётся advisory-блокировка, поэтому
+//	одновременно стартующие экземпляры сервиса применяют миграции по очереди.
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+// NewMigrator создаёт Migrator для пула соединений.
+//
+//	Параметры:
+//	- pool: пул соединений к БД, с параметрами которого открывается
+//	  соединение для миграций; после Close пул остаётся открытым.
+//	Возвращает:
+//	- *Migrator: экземпляр Migrator.
+//	- error: ошибка чтения миграций или подключения к БД.
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	source, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	conn := migrationDB(pool)
+	driver, err := pgxmigrate.WithInstance(conn, &pgxmigrate.Config{})
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to prepare migrations table: %w", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", source, "pgx", driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrator: %w", err)
+	}
+	m.Log = migrateLogger{logger: util.GetLogger()}
+	return &Migrator{m: m}, nil
+}
+
+// migrationDB открывает для миграций отдельное соединение с параметрами пула.
+//
+//	Миграции (например, построение индекса на большой таблице) могут
+//	выполняться дольше DB_STATEMENT_TIMEOUT, поэтому для их соединения
+//	statement_timeout не задаётся. Остальные параметры, включая search_path
+//	и обновление учётных данных, берутся из пула.
+//	Параметры:
+//	- pool: пул соединений к БД.
+//	Возвращает:
+//	- *sql.DB: подключение для golang-migrate; закрывается вместе с Migrator.
+func migrationDB(pool *pgxpool.Pool) *sql.DB {
+	poolConfig := pool.Config()
+	connConfig := poolConfig.ConnConfig.Copy()
+	delete(connConfig.RuntimeParams, "statement_timeout")
+
+	var opts []stdlib.OptionOpenDB
+	if poolConfig.BeforeConnect != nil {
+		opts = append(opts, stdlib.OptionBeforeConnect(poolConfig.BeforeConnect))
+	}
+	return stdlib.OpenDB(*connConfig, opts...)
+}
+
+// Up применяет все неприменённые миграции.
+//
+//	Возвращает:
+//	- error: ошибка применения миграции; если предыдущая миграция прервалась,
+//	  версия помечена dirty и требуется ручное исправление.
+func (mg *Migrator) Up() error {
+	if err := mg.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Down откатывает последние применённые миграции.
+//
+//	Параметры:
+//	- n: число откатываемых миграций (больше нуля).
+//	Возвращает:
+//	- error: ошибка отката; ErrNoChange не считается ошибкой.
+func (mg *Migrator) Down(n int) error {
+	if n < 1 {
+		return fmt.Errorf("number of migrations to roll back must be positive, got %d", n)
+	}
+	if err := mg.m.Steps(-n); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Force записывает версию схемы и снимает признак dirty без выполнения миграций.
+//
+//	Используется после ручного исправления схемы, когда миграция прервалась.
+//	Параметры:
+//	- version: версия, которую нужно записать (0 — миграции не применялись).
+//	Возвращает:
+//	- error: ошибка записи schema_migrations.
+func (mg *Migrator) Force(version uint) error {
+	if version == 0 {
+		return mg.m.Force(database.NilVersion)
+	}
+	return mg.m.Force(int(version))
+}
+
+// Version возвращает текущую версию схемы.
+//
+//	Возвращает:
+//	- uint: номер последней применённой миграции (0 — миграции не применялись).
+//	- bool: true, если последняя миграция прервалась (dirty).
+//	- error: ошибка чтения schema_migrations.
+func (mg *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := mg.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Close освобождает соединение, занятое Migrator.
+func (mg *Migrator) Close() error {
+	srcErr, dbErr := mg.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// MigrationStatus — состояние одной миграции схемы.
+type MigrationStatus struct {
+	Version uint
+	Name    string
+	Applied bool
+}
+
+// Status возвращает список встроенных миграций с признаком применения.
+//
+//	Возвращает:
+//	- []MigrationStatus: миграции по возрастанию версии.
+//	- uint: текущая версия схемы.
+//	- bool: true, если последняя миграция прервалась (dirty).
+//	- error: ошибка чтения миграций или schema_migrations.
+func (mg *Migrator) Status() ([]MigrationStatus, uint, bool, error) {
+	current, dirty, err := mg.Version()
+	if err != nil {
+		return nil, 0, false, err
+	}
+	migrations, err := listMigrations(migrationFiles, "migrations")
+	if err != nil {
+		return nil, 0, false, err
+	}
+	for i := range migrations {
+		// Прерванная миграция не считается применённой.
+		migrations[i].Applied = migrations[i].Version < current || (migrations[i].Version == current && !dirty)
+	}
+	return migrations, current, dirty, nil
+}
+
+// LatestVersion возвращает версию последней встроенной миграции.
+//
+//	Возвращает:
+//	- uint: версия последней миграции (0 — миграций нет).
+//	- error: ошибка чтения миграций.
+func LatestVersion() (uint, error) {
+	migrations, err := listMigrations(migrationFiles, "migrations")
+	if err != nil || len(migrations) == 0 {
+		return 0, err
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// MigrateUp применяет все неприменённые миграции к БД пула.
+//
+//	Миграции до появления schema_migrations писались идемпотентными
+//	(IF NOT EXISTS), поэтому на уже развёрнутой БД первый запуск
+//	повторяет их без изменений и записывает текущую версию.
+//	Параметры:
+//	- pool: пул соединений к БД.
+//	Возвращает:
+//	- error: ошибка, если не удалось применить миграции.
+func MigrateUp(pool *pgxpool.Pool) error {
+	logger := util.GetLogger()
+
+	mg, err := NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := mg.Close(); err != nil {
+			logger.Warn("Failed to close migrator", zap.Error(err))
+		}
+	}()
+
+	from, _, err := mg.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if err := mg.Up(); err != nil {
+		return err
+	}
+	to, _, err := mg.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	logger.Info("Database migrations applied", zap.Uint("from_version", from), zap.Uint("version", to))
+	return nil
+}
+
+// checkSchemaVersion проверяет версию схемы при отключённом автоприменении миграций.
+//
+//	Отставание схемы от встроенных миграций только записывается в журнал:
+//	миграции применяются отдельно (cmd/migrate). Прерванная миграция (dirty)
+//	считается ошибкой, так как схема находится в неизвестном состоянии.
+//	Параметры:
+//	- pool: пул соединений к БД.
+//	Возвращает:
+//	- error: ошибка чтения версии схемы или dirty-версия.
+func checkSchemaVersion(pool *pgxpool.Pool) error {
+	logger := util.GetLogger()
+
+	mg, err := NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := mg.Close(); err != nil {
+			logger.Warn("Failed to close migrator", zap.Error(err))
+		}
+	}()
+
+	current, dirty, err := mg.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty: fix the schema and run migrate force", current)
+	}
+	latest, err := LatestVersion()
+	if err != nil {
+		return err
+	}
+	if current < latest {
+		logger.Warn("Database schema is behind, run migrate up",
+			zap.Uint("version", current), zap.Uint("latest_version", latest))
+		return nil
+	}
+	logger.Info("Automatic migrations are disabled", zap.Uint("version", current))
+	return nil
+}
+
+// migrationName — имя файла миграции: <версия>_<имя>.<up|down>.sql.
+var migrationName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// listMigrations возвращает миграции каталога по возрастанию версии.
+//
+//	Параметры:
+//	- fsys: файловая система с миграциями.
+//	- dir: каталог миграций.
+//	Возвращает:
+//	- []MigrationStatus: миграции без признака применения.
+//	- error: ошибка чтения каталога или некорректное имя файла.
+func listMigrations(fsys fs.FS, dir string) ([]MigrationStatus, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	byVersion := make(map[uint]string)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+		m := migrationName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", e.Name())
+		}
+		version, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", e.Name(), err)
+		}
+		byVersion[uint(version)] = m[2]
+	}
+
+	migrations := make([]MigrationStatus, 0, len(byVersion))
+	for version, name := range byVersion {
+		migrations = append(migrations, MigrationStatus{Version: version, Name: name})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// CreateMigration создаёт пустую пару файлов следующей миграции.
+//
+//	Параметры:
+//	- dir: каталог миграций (обычно internal/db/migrations).
+//	- name: имя миграции: строчные латинские буквы, цифры и "_".
+//	Возвращает:
+//	- []string: пути созданных up- и down-файлов.
+//	- error: ошибка чтения каталога, некорректное имя или ошибка записи.
+func CreateMigration(dir, name string) ([]string, error) {
+	if !validMigrationName.MatchString(name) {
+		return nil, fmt.Errorf("invalid migration name %q: use lowercase letters, digits and underscores", name)
+	}
+	migrations, err := listMigrations(os.DirFS(dir), ".")
+	if err != nil {
+		return nil, err
+	}
+	var next uint = 1
+	if len(migrations) > 0 {
+		next = migrations[len(migrations)-1].Version + 1
+	}
+
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%04d_%s.%s.sql", next, name, direction))
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return paths, err
+		}
+		if err := f.Close(); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// validMigrationName — допустимое имя новой миграции.
+var validMigrationName = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// migrateLogger передаёт сообщения golang-migrate в журнал сервиса.
+type migrateLogger struct {
+	logger *zap.Logger
+}
+
+// Printf реализует migrate.Logger.
+func (l migrateLogger) Printf(format string, v ...any) {
+	l.logger.Info(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+// Verbose реализует migrate.Logger.
+func (l migrateLogger) Verbose() bool {
+	return false
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestMigrationFiles проверяет, что версии миграций идут подряд и у каждой есть up- и down-файл.
+func TestMigrationFiles(t *testing.T) {
+	files, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no embedded migrations: %v", err)
+	}
+	directions := make(map[int]map[string]bool)
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "migrations/")
+		prefix, rest, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			t.Fatalf("migration %s has no numeric version", name)
+		}
+		direction := ""
+		switch {
+		case strings.HasSuffix(rest, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(rest, ".down.sql"):
+			direction = "down"
+		default:
+			t.Fatalf("migration %s must end with .up.sql or .down.sql", name)
+		}
+		if directions[version] == nil {
+			directions[version] = make(map[string]bool)
+		}
+		directions[version][direction] = true
+	}
+	for version := 1; version <= len(directions); version++ {
+		if d := directions[version]; !d["up"] || !d["down"] {
+			t.Errorf("migration %04d must have both up and down files, got %v", version, d)
+		}
+	}
+}
+
+// TestCreateMigration проверяет выбор следующей версии и имена созданных файлов.
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+
+	paths, err := CreateMigration(dir, "create_users")
+	if err != nil {
+		t.Fatalf("CreateMigration: %v", err)
+	}
+	want := []string{filepath.Join(dir, "0001_create_users.up.sql"), filepath.Join(dir, "0001_create_users.down.sql")}
+	if len(paths) != 2 || paths[0] != want[0] || paths[1] != want[1] {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "0041_add_index.up.sql"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	paths, err = CreateMigration(dir, "add_column")
+	if err != nil {
+		t.Fatalf("CreateMigration: %v", err)
+	}
+	if got := filepath.Base(paths[0]); got != "0042_add_column.up.sql" {
+		t.Errorf("next migration = %s, want 0042_add_column.up.sql", got)
+	}
+
+	for _, name := range []string{"", "Add-Column", "add column", "_x"} {
+		if _, err := CreateMigration(dir, name); err == nil {
+			t.Errorf("CreateMigration(%q) succeeded, want error", name)
+		}
+	}
+}
+
+// TestLatestVersion проверяет, что последняя версия совпадает с числом встроенных миграций.
+func TestLatestVersion(t *testing.T) {
+	migrations, err := listMigrations(migrationFiles, "migrations")
+	if err != nil {
+		t.Fatalf("listMigrations: %v", err)
+	}
+	latest, err := LatestVersion()
+	if err != nil {
+		t.Fatalf("LatestVersion: %v", err)
+	}
+	if latest != uint(len(migrations)) {
+		t.Errorf("LatestVersion = %d, want %d", latest, len(migrations))
+	}
+}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    key_hash   TEXT PRIMARY KEY,
+    name       TEXT    NOT NULL,
+    rate_limit INTEGER NOT NULL DEFAULT 0,
+    revoked    BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
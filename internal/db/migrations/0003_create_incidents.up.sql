@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS incidents
+(
+    id         BIGSERIAL PRIMARY KEY,
+    dependency TEXT                     NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at   TIMESTAMP WITH TIME ZONE,
+    last_error TEXT                     NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS incidents_started_at_idx ON incidents (started_at);
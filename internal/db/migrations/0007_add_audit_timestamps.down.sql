@@ -0,0 +1,11 @@
+ALTER TABLE items DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE items DROP COLUMN IF EXISTS created_at;
+
+ALTER TABLE payments DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE payments DROP COLUMN IF EXISTS created_at;
+
+ALTER TABLE deliveries DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE deliveries DROP COLUMN IF EXISTS created_at;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS created_at;
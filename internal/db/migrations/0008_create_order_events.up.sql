@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS order_events
+(
+    id          BIGSERIAL PRIMARY KEY,
+    order_uid   TEXT                     NOT NULL,
+    action      TEXT                     NOT NULL,
+    actor       TEXT                     NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    diff        JSONB                    NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS order_events_order_uid_idx ON order_events (order_uid, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_amount_mismatch;
+ALTER TABLE orders DROP COLUMN IF EXISTS amount_mismatch;
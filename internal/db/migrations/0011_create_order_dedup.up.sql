@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS order_dedup
+(
+    order_uid    TEXT PRIMARY KEY,
+    payload_hash TEXT                     NOT NULL,
+    seen_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS order_dedup_seen_at_idx ON order_dedup (seen_at);
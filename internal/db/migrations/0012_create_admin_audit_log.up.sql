@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS admin_audit_log
+(
+    id          BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    actor       TEXT                     NOT NULL,
+    method      TEXT                     NOT NULL,
+    path        TEXT                     NOT NULL,
+    params      JSONB                    NOT NULL DEFAULT '{}',
+    status      INTEGER                  NOT NULL,
+    client_ip   TEXT                     NOT NULL DEFAULT '',
+    request_id  TEXT                     NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS admin_audit_log_occurred_at_idx ON admin_audit_log (occurred_at);
+CREATE INDEX IF NOT EXISTS admin_audit_log_actor_idx ON admin_audit_log (actor, id);
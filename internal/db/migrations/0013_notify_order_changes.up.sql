@@ -0,0 +1,39 @@
+CREATE OR REPLACE FUNCTION notify_order_change() RETURNS trigger AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM pg_notify('order_changes', OLD.order_uid);
+    ELSE
+        PERFORM pg_notify('order_changes', NEW.order_uid);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS orders_notify_change ON orders;
+CREATE TRIGGER orders_notify_change
+    AFTER INSERT OR UPDATE OR DELETE
+    ON orders
+    FOR EACH ROW
+EXECUTE FUNCTION notify_order_change();
+
+DROP TRIGGER IF EXISTS deliveries_notify_change ON deliveries;
+CREATE TRIGGER deliveries_notify_change
+    AFTER INSERT OR UPDATE OR DELETE
+    ON deliveries
+    FOR EACH ROW
+EXECUTE FUNCTION notify_order_change();
+
+DROP TRIGGER IF EXISTS payments_notify_change ON payments;
+CREATE TRIGGER payments_notify_change
+    AFTER INSERT OR UPDATE OR DELETE
+    ON payments
+    FOR EACH ROW
+EXECUTE FUNCTION notify_order_change();
+
+DROP TRIGGER IF EXISTS items_notify_change ON items;
+CREATE TRIGGER items_notify_change
+    AFTER INSERT OR UPDATE OR DELETE
+    ON items
+    FOR EACH ROW
+EXECUTE FUNCTION notify_order_change();
@@ -0,0 +1,11 @@
+CREATE OR REPLACE FUNCTION notify_order_change() RETURNS trigger AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM pg_notify('order_changes', OLD.order_uid);
+    ELSE
+        PERFORM pg_notify('order_changes', NEW.order_uid);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
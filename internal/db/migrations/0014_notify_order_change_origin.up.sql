@@ -0,0 +1,17 @@
+CREATE OR REPLACE FUNCTION notify_order_change() RETURNS trigger AS
+$$
+DECLARE
+    uid TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        uid := OLD.order_uid;
+    ELSE
+        uid := NEW.order_uid;
+    END IF;
+    PERFORM pg_notify('order_changes', json_build_object(
+            'order_uid', uid,
+            'origin', current_setting('application_name')
+        )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
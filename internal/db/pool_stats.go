@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"l0_wb/internal/metrics"
+)
+
+// poolStatsInterval — период обновления метрик пула соединений.
+const poolStatsInterval = 5 * time.Second
+
+// MonitorPool периодически публикует статистику пула соединений в метрики database_pool_*.
+//
+//	Метрики показывают заполнение пула (выданные, свободные и устанавливаемые
+//	соединения относительно максимума) и ожидание соединений, поэтому
+//	исчерпание пула видно до того, как запросы начнут завершаться по таймауту.
+//	Блокируется до отмены контекста.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- name: имя пула в метке pool (например, primary или replica-0).
+//	- pool: пул соединений.
+func MonitorPool(ctx context.Context, name string, pool *pgxpool.Pool) {
+	ticker := time.NewTicker(poolStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		metrics.SetDBPoolStats(name, poolStats(pool.Stat()))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poolStats преобразует статистику pgxpool в снимок для метрик.
+func poolStats(s *pgxpool.Stat) metrics.DBPoolStats {
+	return metrics.DBPoolStats{
+		AcquireCount:      s.AcquireCount(),
+		EmptyAcquireCount: s.EmptyAcquireCount(),
+		AcquireDuration:   s.AcquireDuration(),
+		AcquiredConns:     s.AcquiredConns(),
+		IdleConns:         s.IdleConns(),
+		ConstructingConns: s.ConstructingConns(),
+		TotalConns:        s.TotalConns(),
+		MaxConns:          s.MaxConns(),
+	}
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"l0_wb/internal/metrics"
+)
+
+// TestMonitorPool проверяет публикацию статистики пула в метрики.
+func TestMonitorPool(t *testing.T) {
+	poolConfig, err := pgxpool.ParseConfig("host=127.0.0.1 port=1 pool_max_conns=7")
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	// Пул без минимального числа соединений не подключается до первого запроса
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	defer pool.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	MonitorPool(ctx, "test", pool)
+
+	if got := testutil.ToFloat64(metrics.DBPoolConns.WithLabelValues("test", "max")); got != 7 {
+		t.Errorf("expected max conns 7, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.DBPoolConns.WithLabelValues("test", "acquired")); got != 0 {
+		t.Errorf("expected no acquired conns, got %v", got)
+	}
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"l0_wb/internal/metrics"
+	"l0_wb/internal/tracing"
+	"l0_wb/internal/util"
+)
+
+// maxLoggedSQLLength ограничивает длину текста запроса в журнале медленных запросов.
+const maxLoggedSQLLength = 512
+
+// statementKey — ключ контекста для данных выполняемой SQL-команды.
+type statementKey struct{}
+
+// statement хранит данные SQL-команды между началом и окончанием её выполнения.
+type statement struct {
+	command string
+	sql     string
+	args    int
+	started time.Time
+	span    trace.Span
+}
+
+// queryTracer измеряет каждую SQL-команду, выполненную через пул pgx.
+//
+//	Для каждой команды записываются спан трассировки и метрика
+//	database_statement_duration_seconds, а команды дольше порога попадают в журнал медленных запросов вместе с
+//	усечённым текстом SQL. Значения аргументов в журнал не пишутся, так как
+//	могут содержать персональные данные.
+type queryTracer struct {
+	slowThreshold time.Duration
+	now           func() time.Time
+	logger        *zap.Logger
+}
+
+var (
+	_ pgx.QueryTracer    = (*queryTracer)(nil)
+	_ pgx.CopyFromTracer = (*queryTracer)(nil)
+)
+
+// newQueryTracer создаёт трассировщик SQL-команд.
+//
+//	Параметры:
+//	- slowThreshold: длительность, начиная с которой команда пишется в журнал (0 — не писать).
+//	Возвращает:
+//	- *queryTracer: трассировщик для pgx.ConnConfig.Tracer.
+func newQueryTracer(slowThreshold time.Duration) *queryTracer {
+	return &queryTracer{
+		slowThreshold: slowThreshold,
+		now:           time.Now,
+		logger:        util.GetLogger(),
+	}
+}
+
+// TraceQueryStart запоминает начало выполнения запроса.
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return t.start(ctx, &statement{
+		command: sqlCommand(data.SQL),
+		sql:     data.SQL,
+		args:    len(data.Args),
+	})
+}
+
+// TraceQueryEnd записывает метрики запроса и при необходимости пишет его в журнал.
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	t.finish(ctx, data.CommandTag.RowsAffected(), data.Err)
+}
+
+// TraceCopyFromStart запоминает начало выполнения COPY.
+func (t *queryTracer) TraceCopyFromStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	return t.start(ctx, &statement{
+		command: "copy",
+		sql:     "COPY " + data.TableName.Sanitize() + " (" + strings.Join(data.ColumnNames, ", ") + ") FROM STDIN",
+	})
+}
+
+// TraceCopyFromEnd записывает метрики COPY и при необходимости пишет его в журнал.
+func (t *queryTracer) TraceCopyFromEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromEndData) {
+	t.finish(ctx, data.CommandTag.RowsAffected(), data.Err)
+}
+
+// start начинает измерение команды и её спан.
+//
+//	В спан записывается усечённый текст SQL без значений аргументов.
+func (t *queryTracer) start(ctx context.Context, st *statement) context.Context {
+	st.started = t.now()
+	ctx, st.span = tracing.Start(ctx, "db "+st.command,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(st.command),
+			semconv.DBQueryText(truncateSQL(st.sql)),
+		),
+	)
+	return context.WithValue(ctx, statementKey{}, st)
+}
+
+// finish завершает измерение команды, сохранённой в контексте.
+func (t *queryTracer) finish(ctx context.Context, rows int64, err error) {
+	st, ok := ctx.Value(statementKey{}).(*statement)
+	if !ok {
+		return
+	}
+	st.span.SetAttributes(attribute.Int64("db.rows_affected", rows))
+	tracing.End(st.span, err)
+	duration := t.now().Sub(st.started)
+	slow := t.slowThreshold > 0 && duration >= t.slowThreshold
+	metrics.RecordDBStatement(ctx, st.command, duration, err != nil, slow)
+	if !slow {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("command", st.command),
+		zap.Duration("duration", duration),
+		zap.String("sql", truncateSQL(st.sql)),
+		zap.Int("args", st.args),
+		zap.Int64("rows", rows),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	util.LoggerFromContext(ctx, t.logger).Warn("Slow database statement", fields...)
+}
+
+// sqlCommand возвращает тип SQL-команды для метки метрики.
+//
+//	Неизвестные команды объединяются в other, чтобы число значений метки оставалось ограниченным.
+func sqlCommand(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "other"
+	}
+	switch cmd := strings.ToLower(fields[0]); cmd {
+	case "select", "insert", "update", "delete", "with", "copy", "begin", "commit", "rollback", "create", "alter":
+		return cmd
+	default:
+		return "other"
+	}
+}
+
+// truncateSQL схлопывает пробелы в тексте запроса и обрезает его до maxLoggedSQLLength.
+func truncateSQL(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+	if len(sql) > maxLoggedSQLLength {
+		return sql[:maxLoggedSQLLength] + "..."
+	}
+	return sql
+}
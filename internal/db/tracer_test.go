@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSQLCommand(t *testing.T) {
+	cases := map[string]string{
+		"SELECT 1":                             "select",
+		"\n\t  insert INTO orders VALUES ($1)": "insert",
+		"WITH x AS (SELECT 1) SELECT * FROM x": "with",
+		"VACUUM orders":                        "other",
+		"":                                     "other",
+	}
+	for sql, want := range cases {
+		if got := sqlCommand(sql); got != want {
+			t.Errorf("sqlCommand(%q) = %q, want %q", sql, got, want)
+		}
+	}
+}
+
+func TestQueryTracerLogsSlowStatements(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	now := time.Unix(0, 0)
+	tracer := &queryTracer{
+		slowThreshold: 100 * time.Millisecond,
+		now:           func() time.Time { return now },
+		logger:        zap.New(core),
+	}
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL:  "SELECT order_uid\n  FROM orders WHERE order_uid = $1",
+		Args: []any{"secret"},
+	})
+	now = now.Add(50 * time.Millisecond)
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+	if logs.Len() != 0 {
+		t.Fatalf("fast statement logged: %v", logs.All())
+	}
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL:  "UPDATE orders SET locale = $1",
+		Args: []any{"ru"},
+	})
+	now = now.Add(150 * time.Millisecond)
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["command"] != "update" || fields["sql"] != "UPDATE orders SET locale = $1" || fields["error"] != "boom" {
+		t.Errorf("unexpected log fields: %v", fields)
+	}
+	if fields["args"] != int64(1) {
+		t.Errorf("args = %v, want argument count 1", fields["args"])
+	}
+}
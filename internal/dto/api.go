@@ -0,0 +1,245 @@
+// Package dto provides transport representations of orders for the HTTP API and Kafka.
+//
+// Внутренняя модель (model.Order) не сериализуется наружу напрямую: API и
+// сообщения Kafka описываются собственными структурами, а преобразование
+// выполняется явными функциями. Поэтому изменение одного формата не меняет
+// остальные.
+package dto
+
+import (
+	"time"
+
+	"l0_wb/internal/model"
+)
+
+// Order — заказ в запросах и ответах HTTP API.
+type Order struct {
+	OrderUID          string    `json:"order_uid"`
+	TrackNumber       string    `json:"track_number"`
+	Entry             string    `json:"entry"`
+	Delivery          Delivery  `json:"delivery"`
+	Payment           Payment   `json:"payment"`
+	Items             []Item    `json:"items"`
+	Locale            string    `json:"locale"`
+	InternalSignature string    `json:"internal_signature"`
+	CustomerID        string    `json:"customer_id"`
+	DeliveryService   string    `json:"delivery_service"`
+	Shardkey          string    `json:"shardkey"`
+	SmID              int       `json:"sm_id"`
+	DateCreated       time.Time `json:"date_created"`
+	OofShard          string    `json:"oof_shard"`
+
+	// Служебные поля только для ответов; во входящих заказах игнорируются
+	DeletedAt      *time.Time        `json:"deleted_at,omitempty"`
+	ArchivedAt     *time.Time        `json:"archived_at,omitempty"`
+	Status         model.OrderStatus `json:"status,omitempty"`
+	AmountMismatch int               `json:"amount_mismatch,omitempty"`
+	CreatedAt      *time.Time        `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time        `json:"updated_at,omitempty"`
+}
+
+// Delivery — данные доставки в HTTP API.
+type Delivery struct {
+	Name    string `json:"name"`
+	Phone   string `json:"phone"`
+	Zip     string `json:"zip"`
+	City    string `json:"city"`
+	Address string `json:"address"`
+	Region  string `json:"region"`
+	Email   string `json:"email"`
+}
+
+// Payment — данные оплаты в HTTP API.
+type Payment struct {
+	Transaction  string `json:"transaction"`
+	RequestID    string `json:"request_id"`
+	Currency     string `json:"currency"`
+	Provider     string `json:"provider"`
+	Amount       int    `json:"amount"`
+	PaymentDt    int64  `json:"payment_dt"`
+	Bank         string `json:"bank"`
+	DeliveryCost int    `json:"delivery_cost"`
+	GoodsTotal   int    `json:"goods_total"`
+	CustomFee    int    `json:"custom_fee"`
+}
+
+// Item — товар заказа в HTTP API.
+type Item struct {
+	ChrtID      int    `json:"chrt_id"`
+	TrackNumber string `json:"track_number"`
+	Price       int    `json:"price"`
+	Rid         string `json:"rid"`
+	Name        string `json:"name"`
+	Sale        int    `json:"sale"`
+	Size        string `json:"size"`
+	TotalPrice  int    `json:"total_price"`
+	NmID        int    `json:"nm_id"`
+	Brand       string `json:"brand"`
+	Status      int    `json:"status"`
+}
+
+// NewOrder преобразует заказ модели в представление API.
+//
+//	Параметры:
+//	- o: заказ модели.
+//	Возвращает:
+//	- *Order: заказ для ответа API (nil для nil).
+func NewOrder(o *model.Order) *Order {
+	if o == nil {
+		return nil
+	}
+	items := make([]Item, len(o.Items))
+	for i := range o.Items {
+		items[i] = newItem(&o.Items[i])
+	}
+	return &Order{
+		OrderUID:          o.OrderUID,
+		TrackNumber:       o.TrackNumber,
+		Entry:             o.Entry,
+		Delivery:          newDelivery(&o.Delivery),
+		Payment:           newPayment(&o.Payment),
+		Items:             items,
+		Locale:            o.Locale,
+		InternalSignature: o.InternalSignature,
+		CustomerID:        o.CustomerID,
+		DeliveryService:   o.DeliveryService,
+		Shardkey:          o.Shardkey,
+		SmID:              o.SmID,
+		DateCreated:       o.DateCreated,
+		OofShard:          o.OofShard,
+		DeletedAt:         o.DeletedAt,
+		ArchivedAt:        o.ArchivedAt,
+		Status:            o.Status,
+		AmountMismatch:    o.AmountMismatch,
+		CreatedAt:         o.CreatedAt,
+		UpdatedAt:         o.UpdatedAt,
+	}
+}
+
+// NewOrders преобразует список заказов модели в представление API.
+func NewOrders(orders []*model.Order) []*Order {
+	result := make([]*Order, len(orders))
+	for i, o := range orders {
+		result[i] = NewOrder(o)
+	}
+	return result
+}
+
+// ToModel преобразует заказ из запроса API в модель.
+//
+//	Служебные поля (статус, отметки удаления и архивации, аудит) задаёт
+//	сервис, поэтому из запроса они не переносятся.
+func (o *Order) ToModel() *model.Order {
+	items := make([]model.Item, len(o.Items))
+	for i := range o.Items {
+		items[i] = o.Items[i].toModel()
+	}
+	return &model.Order{
+		OrderUID:          o.OrderUID,
+		TrackNumber:       o.TrackNumber,
+		Entry:             o.Entry,
+		Delivery:          o.Delivery.toModel(),
+		Payment:           o.Payment.toModel(),
+		Items:             items,
+		Locale:            o.Locale,
+		InternalSignature: o.InternalSignature,
+		CustomerID:        o.CustomerID,
+		DeliveryService:   o.DeliveryService,
+		Shardkey:          o.Shardkey,
+		SmID:              o.SmID,
+		DateCreated:       o.DateCreated,
+		OofShard:          o.OofShard,
+	}
+}
+
+// newDelivery преобразует доставку модели в представление API.
+func newDelivery(d *model.Delivery) Delivery {
+	return Delivery{
+		Name:    d.Name,
+		Phone:   d.Phone,
+		Zip:     d.Zip,
+		City:    d.City,
+		Address: d.Address,
+		Region:  d.Region,
+		Email:   d.Email,
+	}
+}
+
+// toModel преобразует доставку из запроса API в модель.
+func (d *Delivery) toModel() model.Delivery {
+	return model.Delivery{
+		Name:    d.Name,
+		Phone:   d.Phone,
+		Zip:     d.Zip,
+		City:    d.City,
+		Address: d.Address,
+		Region:  d.Region,
+		Email:   d.Email,
+	}
+}
+
+// newPayment преобразует оплату модели в представление API.
+func newPayment(p *model.Payment) Payment {
+	return Payment{
+		Transaction:  p.Transaction,
+		RequestID:    p.RequestID,
+		Currency:     p.Currency,
+		Provider:     p.Provider,
+		Amount:       p.Amount,
+		PaymentDt:    p.PaymentDt,
+		Bank:         p.Bank,
+		DeliveryCost: p.DeliveryCost,
+		GoodsTotal:   p.GoodsTotal,
+		CustomFee:    p.CustomFee,
+	}
+}
+
+// toModel преобразует оплату из запроса API в модель.
+func (p *Payment) toModel() model.Payment {
+	return model.Payment{
+		Transaction:  p.Transaction,
+		RequestID:    p.RequestID,
+		Currency:     p.Currency,
+		Provider:     p.Provider,
+		Amount:       p.Amount,
+		PaymentDt:    p.PaymentDt,
+		Bank:         p.Bank,
+		DeliveryCost: p.DeliveryCost,
+		GoodsTotal:   p.GoodsTotal,
+		CustomFee:    p.CustomFee,
+	}
+}
+
+// newItem преобразует товар модели в представление API.
+func newItem(it *model.Item) Item {
+	return Item{
+		ChrtID:      it.ChrtID,
+		TrackNumber: it.TrackNumber,
+		Price:       it.Price,
+		Rid:         it.Rid,
+		Name:        it.Name,
+		Sale:        it.Sale,
+		Size:        it.Size,
+		TotalPrice:  it.TotalPrice,
+		NmID:        it.NmID,
+		Brand:       it.Brand,
+		Status:      it.Status,
+	}
+}
+
+// toModel преобразует товар из запроса API в модель.
+func (it *Item) toModel() model.Item {
+	return model.Item{
+		ChrtID:      it.ChrtID,
+		TrackNumber: it.TrackNumber,
+		Price:       it.Price,
+		Rid:         it.Rid,
+		Name:        it.Name,
+		Sale:        it.Sale,
+		Size:        it.Size,
+		TotalPrice:  it.TotalPrice,
+		NmID:        it.NmID,
+		Brand:       it.Brand,
+		Status:      it.Status,
+	}
+}
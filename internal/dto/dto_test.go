@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"l0_wb/internal/model"
+)
+
+// randomOrder возвращает заказ, все поля которого заполнены случайными значениями.
+func randomOrder(t *testing.T) *model.Order {
+	t.Helper()
+	var order model.Order
+	if err := gofakeit.Struct(&order); err != nil {
+		t.Fatalf("failed to generate order: %v", err)
+	}
+	if len(order.Items) == 0 {
+		t.Fatal("generated order has no items")
+	}
+	return &order
+}
+
+// contentOf возвращает копию заказа без служебных полей, которые задаёт сервис.
+func contentOf(o *model.Order) *model.Order {
+	c := *o
+	c.DeletedAt, c.ArchivedAt, c.CreatedAt, c.UpdatedAt = nil, nil, nil, nil
+	c.Status, c.AmountMismatch = "", 0
+	return &c
+}
+
+// TestAPIOrderMapping проверяет, что преобразования API не теряют полей и не переносят служебные поля из запроса.
+func TestAPIOrderMapping(t *testing.T) {
+	order := randomOrder(t)
+
+	api := NewOrder(order)
+	if api.Status != order.Status || api.CreatedAt != order.CreatedAt || api.AmountMismatch != order.AmountMismatch {
+		t.Errorf("service fields are not exposed in API order: %+v", api)
+	}
+	if got := api.ToModel(); !reflect.DeepEqual(got, contentOf(order)) {
+		t.Errorf("API round trip mismatch:\n got %+v\nwant %+v", got, contentOf(order))
+	}
+	if NewOrder(nil) != nil {
+		t.Error("NewOrder(nil) must return nil")
+	}
+}
+
+// TestKafkaOrderMapping проверяет, что преобразования Kafka не теряют полей и не публикуют служебные поля.
+func TestKafkaOrderMapping(t *testing.T) {
+	order := randomOrder(t)
+
+	msg := NewKafkaOrder(order)
+	if got := msg.ToModel(); !reflect.DeepEqual(got, contentOf(order)) {
+		t.Errorf("Kafka round trip mismatch:\n got %+v\nwant %+v", got, contentOf(order))
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("failed to encode message: %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+	for _, field := range []string{"status", "deleted_at", "archived_at", "amount_mismatch", "created_at", "updated_at"} {
+		if _, ok := doc[field]; ok {
+			t.Errorf("Kafka message must not contain %q", field)
+		}
+	}
+}
+
+// TestWireFormatCompatibility проверяет, что DTO сохраняют прежний JSON-формат заказа.
+func TestWireFormatCompatibility(t *testing.T) {
+	order := randomOrder(t)
+
+	legacy, err := json.Marshal(order)
+	if err != nil {
+		t.Fatalf("failed to encode model: %v", err)
+	}
+	api, err := json.Marshal(NewOrder(order))
+	if err != nil {
+		t.Fatalf("failed to encode API order: %v", err)
+	}
+	if string(api) != string(legacy) {
+		t.Errorf("API format changed:\n got %s\nwant %s", api, legacy)
+	}
+
+	legacyContent, err := json.Marshal(contentOf(order))
+	if err != nil {
+		t.Fatalf("failed to encode model: %v", err)
+	}
+	kafka, err := json.Marshal(NewKafkaOrder(order))
+	if err != nil {
+		t.Fatalf("failed to encode Kafka order: %v", err)
+	}
+	if string(kafka) != string(legacyContent) {
+		t.Errorf("Kafka format changed:\n got %s\nwant %s", kafka, legacyContent)
+	}
+}
@@ -0,0 +1,213 @@
+package dto
+
+import (
+	"time"
+
+	"l0_wb/internal/model"
+)
+
+// KafkaOrder — заказ в сообщении топика заказов Kafka.
+//
+//	Сообщение содержит только данные заказа: служебные поля задаются
+//	сервисом при сохранении.
+type KafkaOrder struct {
+	OrderUID          string        `json:"order_uid"`
+	TrackNumber       string        `json:"track_number"`
+	Entry             string        `json:"entry"`
+	Delivery          KafkaDelivery `json:"delivery"`
+	Payment           KafkaPayment  `json:"payment"`
+	Items             []KafkaItem   `json:"items"`
+	Locale            string        `json:"locale"`
+	InternalSignature string        `json:"internal_signature"`
+	CustomerID        string        `json:"customer_id"`
+	DeliveryService   string        `json:"delivery_service"`
+	Shardkey          string        `json:"shardkey"`
+	SmID              int           `json:"sm_id"`
+	DateCreated       time.Time     `json:"date_created"`
+	OofShard          string        `json:"oof_shard"`
+}
+
+// KafkaDelivery — данные доставки в сообщении Kafka.
+type KafkaDelivery struct {
+	Name    string `json:"name"`
+	Phone   string `json:"phone"`
+	Zip     string `json:"zip"`
+	City    string `json:"city"`
+	Address string `json:"address"`
+	Region  string `json:"region"`
+	Email   string `json:"email"`
+}
+
+// KafkaPayment — данные оплаты в сообщении Kafka.
+type KafkaPayment struct {
+	Transaction  string `json:"transaction"`
+	RequestID    string `json:"request_id"`
+	Currency     string `json:"currency"`
+	Provider     string `json:"provider"`
+	Amount       int    `json:"amount"`
+	PaymentDt    int64  `json:"payment_dt"`
+	Bank         string `json:"bank"`
+	DeliveryCost int    `json:"delivery_cost"`
+	GoodsTotal   int    `json:"goods_total"`
+	CustomFee    int    `json:"custom_fee"`
+}
+
+// KafkaItem — товар заказа в сообщении Kafka.
+type KafkaItem struct {
+	ChrtID      int    `json:"chrt_id"`
+	TrackNumber string `json:"track_number"`
+	Price       int    `json:"price"`
+	Rid         string `json:"rid"`
+	Name        string `json:"name"`
+	Sale        int    `json:"sale"`
+	Size        string `json:"size"`
+	TotalPrice  int    `json:"total_price"`
+	NmID        int    `json:"nm_id"`
+	Brand       string `json:"brand"`
+	Status      int    `json:"status"`
+}
+
+// NewKafkaOrder преобразует заказ модели в сообщение Kafka.
+//
+//	Параметры:
+//	- o: заказ модели.
+//	Возвращает:
+//	- *KafkaOrder: сообщение для публикации.
+func NewKafkaOrder(o *model.Order) *KafkaOrder {
+	items := make([]KafkaItem, len(o.Items))
+	for i := range o.Items {
+		items[i] = newKafkaItem(&o.Items[i])
+	}
+	return &KafkaOrder{
+		OrderUID:          o.OrderUID,
+		TrackNumber:       o.TrackNumber,
+		Entry:             o.Entry,
+		Delivery:          newKafkaDelivery(&o.Delivery),
+		Payment:           newKafkaPayment(&o.Payment),
+		Items:             items,
+		Locale:            o.Locale,
+		InternalSignature: o.InternalSignature,
+		CustomerID:        o.CustomerID,
+		DeliveryService:   o.DeliveryService,
+		Shardkey:          o.Shardkey,
+		SmID:              o.SmID,
+		DateCreated:       o.DateCreated,
+		OofShard:          o.OofShard,
+	}
+}
+
+// ToModel преобразует сообщение Kafka в заказ модели.
+func (o *KafkaOrder) ToModel() *model.Order {
+	items := make([]model.Item, len(o.Items))
+	for i := range o.Items {
+		items[i] = o.Items[i].toModel()
+	}
+	return &model.Order{
+		OrderUID:          o.OrderUID,
+		TrackNumber:       o.TrackNumber,
+		Entry:             o.Entry,
+		Delivery:          o.Delivery.toModel(),
+		Payment:           o.Payment.toModel(),
+		Items:             items,
+		Locale:            o.Locale,
+		InternalSignature: o.InternalSignature,
+		CustomerID:        o.CustomerID,
+		DeliveryService:   o.DeliveryService,
+		Shardkey:          o.Shardkey,
+		SmID:              o.SmID,
+		DateCreated:       o.DateCreated,
+		OofShard:          o.OofShard,
+	}
+}
+
+// newKafkaDelivery преобразует доставку модели в представление Kafka.
+func newKafkaDelivery(d *model.Delivery) KafkaDelivery {
+	return KafkaDelivery{
+		Name:    d.Name,
+		Phone:   d.Phone,
+		Zip:     d.Zip,
+		City:    d.City,
+		Address: d.Address,
+		Region:  d.Region,
+		Email:   d.Email,
+	}
+}
+
+// toModel преобразует доставку из сообщения Kafka в модель.
+func (d *KafkaDelivery) toModel() model.Delivery {
+	return model.Delivery{
+		Name:    d.Name,
+		Phone:   d.Phone,
+		Zip:     d.Zip,
+		City:    d.City,
+		Address: d.Address,
+		Region:  d.Region,
+		Email:   d.Email,
+	}
+}
+
+// newKafkaPayment преобразует оплату модели в представление Kafka.
+func newKafkaPayment(p *model.Payment) KafkaPayment {
+	return KafkaPayment{
+		Transaction:  p.Transaction,
+		RequestID:    p.RequestID,
+		Currency:     p.Currency,
+		Provider:     p.Provider,
+		Amount:       p.Amount,
+		PaymentDt:    p.PaymentDt,
+		Bank:         p.Bank,
+		DeliveryCost: p.DeliveryCost,
+		GoodsTotal:   p.GoodsTotal,
+		CustomFee:    p.CustomFee,
+	}
+}
+
+// toModel преобразует оплату из сообщения Kafka в модель.
+func (p *KafkaPayment) toModel() model.Payment {
+	return model.Payment{
+		Transaction:  p.Transaction,
+		RequestID:    p.RequestID,
+		Currency:     p.Currency,
+		Provider:     p.Provider,
+		Amount:       p.Amount,
+		PaymentDt:    p.PaymentDt,
+		Bank:         p.Bank,
+		DeliveryCost: p.DeliveryCost,
+		GoodsTotal:   p.GoodsTotal,
+		CustomFee:    p.CustomFee,
+	}
+}
+
+// newKafkaItem преобразует товар модели в представление Kafka.
+func newKafkaItem(it *model.Item) KafkaItem {
+	return KafkaItem{
+		ChrtID:      it.ChrtID,
+		TrackNumber: it.TrackNumber,
+		Price:       it.Price,
+		Rid:         it.Rid,
+		Name:        it.Name,
+		Sale:        it.Sale,
+		Size:        it.Size,
+		TotalPrice:  it.TotalPrice,
+		NmID:        it.NmID,
+		Brand:       it.Brand,
+		Status:      it.Status,
+	}
+}
+
+// toModel преобразует товар из сообщения Kafka в модель.
+func (it *KafkaItem) toModel() model.Item {
+	return model.Item{
+		ChrtID:      it.ChrtID,
+		TrackNumber: it.TrackNumber,
+		Price:       it.Price,
+		Rid:         it.Rid,
+		Name:        it.Name,
+		Sale:        it.Sale,
+		Size:        it.Size,
+		TotalPrice:  it.TotalPrice,
+		NmID:        it.NmID,
+		Brand:       it.Brand,
+		Status:      it.Status,
+	}
+}
@@ -0,0 +1,98 @@
+// Package errtrack reports panics and failures to Sentry.
+package errtrack
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// enabled сообщает, настроена ли отправка событий (см. Init).
+var enabled atomic.Bool
+
+// Options — параметры отправки ошибок.
+type Options struct {
+	DSN         string  // DSN проекта Sentry (пусто — ошибки не отправляются)
+	Environment string  // Окружение (тег environment)
+	Release     string  // Версия приложения (тег release)
+	SampleRate  float64 // Доля отправляемых событий (0..1)
+}
+
+// Init настраивает отправку ошибок в Sentry.
+//
+//	Без DSN отправка выключена, а CaptureError и CapturePanic ничего не делают.
+//	Параметры:
+//	- opts: параметры отправки.
+//	Возвращает:
+//	- func(time.Duration): ожидание отправки накопленных событий при завершении работы.
+//	- error: ошибку разбора DSN.
+func Init(opts Options) (func(time.Duration), error) {
+	if opts.DSN == "" {
+		return func(time.Duration) {}, nil
+	}
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:              opts.DSN,
+		Environment:      opts.Environment,
+		Release:          opts.Release,
+		SampleRate:       opts.SampleRate,
+		AttachStacktrace: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize sentry: %w", err)
+	}
+	enabled.Store(true)
+	return func(timeout time.Duration) { sentry.Flush(timeout) }, nil
+}
+
+// Enabled сообщает, отправляются ли ошибки.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// CaptureError отправляет ошибку.
+//
+//	К событию добавляются теги и trace_id спана из контекста, чтобы событие
+//	можно было сопоставить с трассировкой и журналом.
+//	Параметры:
+//	- ctx: контекст операции.
+//	- err: ошибка.
+//	- tags: теги события (компонент, операция и т. п.).
+func CaptureError(ctx context.Context, err error, tags map[string]string) {
+	if !enabled.Load() || err == nil {
+		return
+	}
+	hub := sentry.CurrentHub().Clone()
+	hub.WithScope(func(scope *sentry.Scope) {
+		setTags(ctx, scope, tags)
+		hub.CaptureException(err)
+	})
+}
+
+// CapturePanic отправляет перехваченную панику со стеком вызовов.
+//
+//	Параметры:
+//	- ctx: контекст операции.
+//	- p: значение, возвращённое recover.
+//	- tags: теги события.
+func CapturePanic(ctx context.Context, p any, tags map[string]string) {
+	if !enabled.Load() || p == nil {
+		return
+	}
+	hub := sentry.CurrentHub().Clone()
+	hub.WithScope(func(scope *sentry.Scope) {
+		setTags(ctx, scope, tags)
+		hub.RecoverWithContext(ctx, p)
+	})
+}
+
+// setTags добавляет к событию теги и trace_id.
+func setTags(ctx context.Context, scope *sentry.Scope, tags map[string]string) {
+	scope.SetTags(tags)
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		scope.SetTag("trace_id", sc.TraceID().String())
+	}
+}
@@ -0,0 +1,54 @@
+package errtrack
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// fakeTransport запоминает отправленные события.
+type fakeTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *fakeTransport) Flush(time.Duration) bool       { return true }
+func (t *fakeTransport) Configure(sentry.ClientOptions) {}
+func (t *fakeTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+// TestCapture проверяет отправку ошибок и паник с тегами, окружением и версией.
+func TestCapture(t *testing.T) {
+	CaptureError(context.Background(), errors.New("ignored"), nil) // Отправка ещё не настроена
+
+	transport := &fakeTransport{}
+	if err := sentry.Init(sentry.ClientOptions{
+		Dsn:         "https://public@sentry.example.com/1",
+		Environment: "production",
+		Release:     "v1.2.3",
+		Transport:   transport,
+	}); err != nil {
+		t.Fatalf("failed to initialize sentry: %v", err)
+	}
+	enabled.Store(true)
+	defer enabled.Store(false)
+
+	CaptureError(context.Background(), errors.New("connection refused"), map[string]string{"component": "db"})
+	CapturePanic(context.Background(), "boom", map[string]string{"component": "http"})
+
+	if len(transport.events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(transport.events))
+	}
+	for _, event := range transport.events {
+		if event.Environment != "production" || event.Release != "v1.2.3" || event.Tags["component"] == "" {
+			t.Errorf("unexpected event %+v", event)
+		}
+	}
+}
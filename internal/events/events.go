@@ -0,0 +1,212 @@
+// Package events provides order change events for downstream consumers.
+package events
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"l0_wb/internal/model"
+)
+
+// Типы событий о заказах.
+const (
+	TypeOrderUpdated       = "order.updated"        // Изменилось содержимое существующего заказа
+	TypeOrderStatusChanged = "order.status_changed" // Изменился статус заказа
+)
+
+// Mode определяет формат публикуемых событий.
+type Mode string
+
+// Допустимые форматы событий.
+const (
+	ModeFull Mode = "full" // Событие содержит заказ целиком
+	ModeDiff Mode = "diff" // Событие содержит только изменённые поля
+)
+
+// Update описывает изменение заказа: состояние до и после сохранения.
+type Update struct {
+	Previous *model.Order
+	Current  *model.Order
+}
+
+// StatusChange описывает переход заказа между статусами.
+type StatusChange struct {
+	OrderUID   string            `json:"order_uid"`
+	From       model.OrderStatus `json:"from"`
+	To         model.OrderStatus `json:"to"`
+	Actor      string            `json:"actor"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// Publisher публикует события об изменении заказов.
+type Publisher interface {
+	PublishUpdates(ctx context.Context, updates []Update) error
+	PublishStatusChange(ctx context.Context, change StatusChange) error
+}
+
+// OrderEvent — событие об изменении заказа.
+//
+//	В режиме diff заполняется Changes: ключи — пути к полям в JSON-представлении
+//	заказа (например, "delivery.city"), значения — новые значения полей.
+//	В режиме full заполняется Order.
+type OrderEvent struct {
+	Type             string         `json:"type"`
+	OrderUID         string         `json:"order_uid"`
+	Checksum         string         `json:"checksum"`
+	PreviousChecksum string         `json:"previous_checksum"`
+	Changes          map[string]any `json:"changes,omitempty"`
+	Order            *model.Order   `json:"order,omitempty"`
+	OccurredAt       time.Time      `json:"occurred_at"`
+}
+
+// NewUpdateEvent формирует событие об изменении заказа.
+//
+//	Параметры:
+//	- mode: формат события.
+//	- u: состояние заказа до и после сохранения.
+//	Возвращает:
+//	- *OrderEvent: событие или nil, если заказ не изменился.
+//	- error: ошибку сериализации заказа.
+func NewUpdateEvent(mode Mode, u Update) (*OrderEvent, error) {
+	prevSum, err := Checksum(u.Previous)
+	if err != nil {
+		return nil, err
+	}
+	currSum, err := Checksum(u.Current)
+	if err != nil {
+		return nil, err
+	}
+	if prevSum == currSum {
+		return nil, nil
+	}
+
+	event := &OrderEvent{
+		Type:             TypeOrderUpdated,
+		OrderUID:         u.Current.OrderUID,
+		Checksum:         currSum,
+		PreviousChecksum: prevSum,
+		OccurredAt:       time.Now().UTC(),
+	}
+	if mode == ModeFull {
+		event.Order = u.Current
+		return event, nil
+	}
+	if event.Changes, err = Diff(u.Previous, u.Current); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// StatusEvent — событие об изменении статуса заказа.
+type StatusEvent struct {
+	Type string `json:"type"`
+	StatusChange
+}
+
+// NewStatusEvent формирует событие об изменении статуса заказа.
+//
+//	Параметры:
+//	- change: переход заказа между статусами.
+//	Возвращает:
+//	- *StatusEvent: событие с типом TypeOrderStatusChanged.
+func NewStatusEvent(change StatusChange) *StatusEvent {
+	return &StatusEvent{Type: TypeOrderStatusChanged, StatusChange: change}
+}
+
+// Checksum вычисляет контрольную сумму содержимого заказа.
+//
+//	Время создания приводится к UTC с точностью до микросекунды, как оно
+//	хранится в PostgreSQL, чтобы заказ из БД и из Kafka давал одинаковую сумму.
+//
+//	Параметры:
+//	- order: заказ.
+//	Возвращает:
+//	- string: SHA-256 JSON-представления заказа в hex.
+//	- error: ошибку сериализации.
+func Checksum(order *model.Order) (string, error) {
+	data, err := json.Marshal(normalize(order))
+	if err != nil {
+		return "", fmt.Errorf("marshal order: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Diff возвращает поля, изменившиеся между двумя состояниями заказа.
+//
+//	Вложенные объекты сравниваются по полям, массивы (items) — целиком: при
+//	любом изменении в событие попадает новый массив. Удалённые поля получают
+//	значение nil. Если prev равен nil, изменёнными считаются все поля curr.
+//
+//	Параметры:
+//	- prev: предыдущее состояние заказа или nil для нового заказа.
+//	- curr: текущее состояние заказа.
+//	Возвращает:
+//	- map[string]any: изменённые поля по путям в JSON-представлении.
+//	- error: ошибку сериализации.
+func Diff(prev, curr *model.Order) (map[string]any, error) {
+	prevMap := map[string]any{}
+	if prev != nil {
+		var err error
+		if prevMap, err = toMap(normalize(prev)); err != nil {
+			return nil, err
+		}
+	}
+	currMap, err := toMap(normalize(curr))
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[string]any)
+	diffMaps("", prevMap, currMap, changes)
+	return changes, nil
+}
+
+// diffMaps рекурсивно сравнивает два JSON-объекта и записывает изменения в changes.
+func diffMaps(prefix string, prev, curr map[string]any, changes map[string]any) {
+	for key, currVal := range curr {
+		path := prefix + key
+		prevVal, ok := prev[key]
+		prevObj, prevIsObj := prevVal.(map[string]any)
+		currObj, currIsObj := currVal.(map[string]any)
+		switch {
+		case ok && prevIsObj && currIsObj:
+			diffMaps(path+".", prevObj, currObj, changes)
+		case !ok || !reflect.DeepEqual(prevVal, currVal):
+			changes[path] = currVal
+		}
+	}
+	for key := range prev {
+		if _, ok := curr[key]; !ok {
+			changes[prefix+key] = nil
+		}
+	}
+}
+
+// normalize возвращает копию заказа с временем создания, приведённым к виду хранения в БД.
+func normalize(order *model.Order) *model.Order {
+	normalized := *order
+	normalized.DateCreated = order.DateCreated.UTC().Truncate(time.Microsecond)
+	// Служебные отметки и время аудита не относятся к содержимому заказа
+	normalized.DeletedAt, normalized.ArchivedAt = nil, nil
+	normalized.Status, normalized.AmountMismatch = "", 0
+	normalized.CreatedAt, normalized.UpdatedAt = nil, nil
+	return &normalized
+}
+
+// toMap преобразует заказ в JSON-объект.
+func toMap(order *model.Order) (map[string]any, error) {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return nil, fmt.Errorf("marshal order: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("unmarshal order: %w", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"l0_wb/internal/model"
+)
+
+// TestNewUpdateEvent проверяет, что в режиме diff событие содержит только изменённые поля.
+func TestNewUpdateEvent(t *testing.T) {
+	prev := &model.Order{
+		OrderUID:    "b563feb7b2b84b6test",
+		TrackNumber: "WBILMTESTTRACK",
+		Delivery:    model.Delivery{Name: "Test Testov", Phone: "+9720000000", City: "Kiryat Mozkin"},
+		Payment:     model.Payment{Currency: "USD", Amount: 1817},
+		Items:       []model.Item{{ChrtID: 9934930, Name: "Mascaras", Status: 202}},
+		DateCreated: time.Date(2021, 11, 26, 6, 22, 19, 123456789, time.UTC),
+	}
+	curr := *prev
+	curr.Delivery.City = "Tel Aviv"
+	curr.Items = []model.Item{{ChrtID: 9934930, Name: "Mascaras", Status: 203}}
+	// Время из БД хранится с точностью до микросекунды и не считается изменением.
+	curr.DateCreated = prev.DateCreated.Truncate(time.Microsecond)
+
+	event, err := NewUpdateEvent(ModeDiff, Update{Previous: prev, Current: &curr})
+	if err != nil {
+		t.Fatalf("NewUpdateEvent returned error: %v", err)
+	}
+	if event == nil {
+		t.Fatal("expected event for changed order")
+	}
+	if event.Order != nil {
+		t.Error("expected no full order in diff mode")
+	}
+	if len(event.Changes) != 2 || event.Changes["delivery.city"] != "Tel Aviv" {
+		t.Errorf("unexpected changes: %v", event.Changes)
+	}
+	items, ok := event.Changes["items"].([]any)
+	if !ok || len(items) != 1 || !reflect.DeepEqual(items[0].(map[string]any)["status"], float64(203)) {
+		t.Errorf("expected new items array in changes, got %v", event.Changes["items"])
+	}
+
+	full, err := NewUpdateEvent(ModeFull, Update{Previous: prev, Current: &curr})
+	if err != nil {
+		t.Fatalf("NewUpdateEvent returned error: %v", err)
+	}
+	if full.Order != &curr || full.Changes != nil {
+		t.Error("expected full order without changes in full mode")
+	}
+
+	// Неизменённый заказ не порождает событие.
+	same, err := NewUpdateEvent(ModeDiff, Update{Previous: prev, Current: prev})
+	if err != nil {
+		t.Fatalf("NewUpdateEvent returned error: %v", err)
+	}
+	if same != nil {
+		t.Errorf("expected no event for unchanged order, got %+v", same)
+	}
+}
+
+// TestDiffNewOrder проверяет, что для нового заказа изменёнными считаются все поля.
+func TestDiffNewOrder(t *testing.T) {
+	order := &model.Order{OrderUID: "b563feb7b2b84b6test", Delivery: model.Delivery{City: "Tel Aviv"}}
+	changes, err := Diff(nil, order)
+	if err != nil {
+		t.Fatalf("Diff returned error: %v", err)
+	}
+	delivery, ok := changes["delivery"].(map[string]any)
+	if changes["order_uid"] != "b563feb7b2b84b6test" || !ok || delivery["city"] != "Tel Aviv" {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+	if _, ok := changes["created_at"]; ok {
+		t.Errorf("audit timestamps must not be part of the diff: %v", changes)
+	}
+}
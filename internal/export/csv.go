@@ -0,0 +1,32 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// csvWriter записывает таблицу в формате CSV.
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+// newCSVWriter создаёт csvWriter.
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+// WriteRow записывает строку CSV.
+func (c *csvWriter) WriteRow(values []any) error {
+	c.record = c.record[:0]
+	for _, v := range values {
+		c.record = append(c.record, formatValue(v))
+	}
+	return c.w.Write(c.record)
+}
+
+// Close сбрасывает буфер CSV.
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
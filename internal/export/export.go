@@ -0,0 +1,139 @@
+// Package export writes tabular order reports in CSV and XLSX formats.
+package export
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"l0_wb/internal/model"
+)
+
+// Format — формат выгрузки.
+type Format string
+
+// Поддерживаемые форматы выгрузки.
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// ErrUnsupportedFormat возвращается для неизвестного формата выгрузки.
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// ContentType возвращает MIME-тип файла выгрузки.
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Writer построчно записывает таблицу.
+//
+//	Значения типа int записываются в XLSX как числа, остальные — как строки.
+type Writer interface {
+	WriteRow(values []any) error
+	Close() error
+}
+
+// NewWriter создаёт Writer для указанного формата.
+//
+//	Параметры:
+//	- format: формат выгрузки.
+//	- w: поток вывода.
+//	Возвращает:
+//	- Writer: экземпляр Writer.
+//	- error: ErrUnsupportedFormat для неизвестного формата.
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Columns — заголовок таблицы заказов.
+//
+//	Каждая строка таблицы соответствует одному товару заказа; поля заказа,
+//	доставки и оплаты повторяются для всех товаров. Заказ без товаров
+//	занимает одну строку с пустыми полями товара.
+var Columns = []string{
+	"order_uid", "track_number", "entry", "locale", "customer_id", "delivery_service",
+	"shardkey", "sm_id", "date_created", "oof_shard",
+	"delivery_name", "delivery_phone", "delivery_zip", "delivery_city", "delivery_address",
+	"delivery_region", "delivery_email",
+	"payment_transaction", "payment_currency", "payment_provider", "payment_amount",
+	"payment_dt", "payment_bank", "payment_delivery_cost", "payment_goods_total", "payment_custom_fee",
+	"item_chrt_id", "item_track_number", "item_price", "item_rid", "item_name", "item_sale",
+	"item_size", "item_total_price", "item_nm_id", "item_brand", "item_status",
+}
+
+// WriteOrders записывает заголовок и заказы с развёрнутыми товарами.
+//
+//	Параметры:
+//	- w: Writer выгрузки.
+//	- orders: заказы.
+//	Возвращает:
+//	- error: ошибку записи.
+func WriteOrders(w Writer, orders []*model.Order) error {
+	header := make([]any, len(Columns))
+	for i, c := range Columns {
+		header[i] = c
+	}
+	if err := w.WriteRow(header); err != nil {
+		return err
+	}
+	for _, order := range orders {
+		for _, row := range orderRows(order) {
+			if err := w.WriteRow(row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// orderRows разворачивает заказ в строки таблицы, по одной на товар.
+func orderRows(o *model.Order) [][]any {
+	d, p := o.Delivery, o.Payment
+	base := []any{
+		o.OrderUID, o.TrackNumber, o.Entry, o.Locale, o.CustomerID, o.DeliveryService,
+		o.Shardkey, o.SmID, o.DateCreated.UTC().Format(time.RFC3339), o.OofShard,
+		d.Name, d.Phone, d.Zip, d.City, d.Address, d.Region, d.Email,
+		p.Transaction, p.Currency, p.Provider, p.Amount,
+		strconv.FormatInt(p.PaymentDt, 10), p.Bank, p.DeliveryCost, p.GoodsTotal, p.CustomFee,
+	}
+	if len(o.Items) == 0 {
+		return [][]any{append(base, make([]any, len(Columns)-len(base))...)}
+	}
+	rows := make([][]any, 0, len(o.Items))
+	for _, it := range o.Items {
+		row := make([]any, 0, len(Columns))
+		row = append(row, base...)
+		row = append(row,
+			it.ChrtID, it.TrackNumber, it.Price, it.Rid, it.Name, it.Sale,
+			it.Size, it.TotalPrice, it.NmID, it.Brand, it.Status,
+		)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// formatValue приводит значение ячейки к строке.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return ""
+	}
+}
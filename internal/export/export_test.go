@@ -0,0 +1,110 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"l0_wb/internal/model"
+)
+
+// testOrders возвращает заказ с двумя товарами и заказ без товаров.
+func testOrders() []*model.Order {
+	created := time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)
+	return []*model.Order{
+		{
+			OrderUID:    "b563feb7b2b84b6test",
+			DateCreated: created,
+			Payment:     model.Payment{Amount: 1817},
+			Items: []model.Item{
+				{ChrtID: 9934930, Name: "Mascaras", Price: 453},
+				{ChrtID: 9934931, Name: `Brush "soft" <large>`, Price: 100},
+			},
+		},
+		{OrderUID: "empty", DateCreated: created},
+	}
+}
+
+// TestWriteOrdersCSV проверяет разворачивание товаров в строки CSV.
+func TestWriteOrdersCSV(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf)
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	if err := WriteOrders(w, testOrders()); err != nil {
+		t.Fatalf("WriteOrders failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected header and 3 rows, got %d records", len(records))
+	}
+	for _, rec := range records {
+		if len(rec) != len(Columns) {
+			t.Fatalf("expected %d columns, got %d", len(Columns), len(rec))
+		}
+	}
+	if records[2][0] != "b563feb7b2b84b6test" || records[2][30] != `Brush "soft" <large>` {
+		t.Errorf("unexpected item row %v", records[2])
+	}
+	if records[3][0] != "empty" || records[3][26] != "" {
+		t.Errorf("unexpected row for order without items %v", records[3])
+	}
+}
+
+// TestWriteOrdersXLSX проверяет, что выгрузка XLSX — корректный архив с листом заказов.
+func TestWriteOrdersXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatXLSX, &buf)
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	if err := WriteOrders(w, testOrders()); err != nil {
+		t.Fatalf("WriteOrders failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip archive: %v", err)
+	}
+	var sheet string
+	for _, f := range zr.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open sheet: %v", err)
+		}
+		b, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		sheet = string(b)
+	}
+	if strings.Count(sheet, "<row>") != 4 {
+		t.Errorf("expected 4 rows in sheet, got %d", strings.Count(sheet, "<row>"))
+	}
+	if !strings.Contains(sheet, "<c><v>1817</v></c>") || !strings.Contains(sheet, "&lt;large&gt;") {
+		t.Errorf("unexpected sheet content: %s", sheet)
+	}
+}
+
+// TestNewWriterUnsupportedFormat проверяет отказ для неизвестного формата.
+func TestNewWriterUnsupportedFormat(t *testing.T) {
+	if _, err := NewWriter("pdf", io.Discard); err != ErrUnsupportedFormat {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
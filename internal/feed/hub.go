@@ -0,0 +1,103 @@
+// Package feed provides a live feed of processed orders for streaming clients.
+package feed
+
+import (
+	"sync"
+
+	"l0_wb/internal/metrics"
+	"l0_wb/internal/model"
+)
+
+// Hub рассылает обработанные заказы подписчикам (WebSocket, SSE).
+//
+//	Публикация не блокируется: если буфер подписчика заполнен, заказ для
+//	него пропускается, чтобы медленный клиент не тормозил обработку Kafka.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscriber]struct{}
+	buffer      int
+	closed      bool
+}
+
+// Subscriber — подписка на поток заказов.
+type Subscriber struct {
+	orders chan *model.Order
+}
+
+// Orders возвращает канал заказов подписчика; канал закрывается при отписке или закрытии Hub.
+func (s *Subscriber) Orders() <-chan *model.Order {
+	return s.orders
+}
+
+// NewHub создаёт Hub.
+//
+//	Параметры:
+//	- buffer: размер буфера заказов каждого подписчика.
+//	Возвращает:
+//	- *Hub: экземпляр Hub.
+func NewHub(buffer int) *Hub {
+	return &Hub{
+		subscribers: make(map[*Subscriber]struct{}),
+		buffer:      buffer,
+	}
+}
+
+// Subscribe регистрирует нового подписчика.
+//
+//	Возвращает:
+//	- *Subscriber: подписка; после использования её нужно передать в Unsubscribe.
+func (h *Hub) Subscribe() *Subscriber {
+	sub := &Subscriber{orders: make(chan *model.Order, h.buffer)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(sub.orders)
+		return sub
+	}
+	h.subscribers[sub] = struct{}{}
+	metrics.LiveFeedSubscribers.Set(float64(len(h.subscribers)))
+	return sub
+}
+
+// Unsubscribe удаляет подписчика и закрывает его канал.
+func (h *Hub) Unsubscribe(sub *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	close(sub.orders)
+	metrics.LiveFeedSubscribers.Set(float64(len(h.subscribers)))
+}
+
+// Publish отправляет заказ всем подписчикам.
+//
+//	Параметры:
+//	- order: обработанный заказ.
+func (h *Hub) Publish(order *model.Order) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subscribers {
+		select {
+		case sub.orders <- order:
+		default:
+			metrics.LiveFeedDropped.Inc()
+		}
+	}
+}
+
+// Close отключает всех подписчиков; используется при остановке сервера.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for sub := range h.subscribers {
+		close(sub.orders)
+	}
+	h.subscribers = make(map[*Subscriber]struct{})
+	metrics.LiveFeedSubscribers.Set(0)
+}
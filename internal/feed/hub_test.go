@@ -0,0 +1,41 @@
+package feed
+
+import (
+	"testing"
+
+	"l0_wb/internal/model"
+)
+
+// TestHub проверяет рассылку заказов, пропуск при заполненном буфере и отключение при закрытии.
+func TestHub(t *testing.T) {
+	hub := NewHub(1)
+	fast := hub.Subscribe()
+	slow := hub.Subscribe()
+
+	hub.Publish(&model.Order{OrderUID: "first"})
+	if got := <-fast.Orders(); got.OrderUID != "first" {
+		t.Fatalf("expected first order, got %q", got.OrderUID)
+	}
+
+	// Буфер медленного подписчика заполнен: второй заказ для него пропускается.
+	hub.Publish(&model.Order{OrderUID: "second"})
+	if got := <-fast.Orders(); got.OrderUID != "second" {
+		t.Fatalf("expected second order, got %q", got.OrderUID)
+	}
+	if got := <-slow.Orders(); got.OrderUID != "first" {
+		t.Fatalf("expected slow subscriber to keep first order, got %q", got.OrderUID)
+	}
+
+	hub.Unsubscribe(fast)
+	if _, ok := <-fast.Orders(); ok {
+		t.Error("expected channel to be closed after unsubscribe")
+	}
+
+	hub.Close()
+	if _, ok := <-slow.Orders(); ok {
+		t.Error("expected channel to be closed after hub close")
+	}
+	if _, ok := <-hub.Subscribe().Orders(); ok {
+		t.Error("expected subscription to closed hub to be closed")
+	}
+}
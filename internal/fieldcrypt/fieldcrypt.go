@@ -0,0 +1,194 @@
+// Package fieldcrypt encrypts individual database column values with AES-GCM.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix отличает зашифрованное значение от открытого текста.
+const prefix = "enc:v1:"
+
+// KeySize — длина ключа AES-256 в байтах.
+const KeySize = 32
+
+// Ошибки расшифровки.
+var (
+	ErrUnknownKey = errors.New("value is encrypted with an unknown key")
+	ErrMalformed  = errors.New("malformed encrypted value")
+)
+
+// Cipher шифрует значения полей ключом AES-256-GCM.
+//
+//	Зашифрованное значение имеет вид "enc:v1:<id ключа>:<base64(nonce||шифротекст)>",
+//	где id ключа — первые 4 байта SHA-256 ключа в hex. Шифруется всегда
+//	основным ключом, расшифровывается любым из известных, поэтому при смене
+//	ключа прежний передаётся в previous до перешифрования всех значений.
+//	Значения без префикса считаются открытым текстом и возвращаются как есть:
+//	это позволяет включить шифрование до перешифрования существующих строк.
+//	Пустая строка не шифруется.
+//
+//	Методы безопасно вызывать на nil: такой Cipher не шифрует значения, а
+//	расшифровка зашифрованного значения завершается ErrUnknownKey.
+type Cipher struct {
+	primary string
+	aeads   map[string]cipher.AEAD
+}
+
+// New создает Cipher.
+//
+//	Параметры:
+//	- key: основной ключ длиной KeySize байт.
+//	- previous: прежние ключи, которые используются только для расшифровки.
+//	Возвращает:
+//	- *Cipher: экземпляр шифратора.
+//	- error: ошибку, если длина ключа неверна.
+func New(key []byte, previous ...[]byte) (*Cipher, error) {
+	c := &Cipher{aeads: make(map[string]cipher.AEAD, 1+len(previous))}
+	for i, k := range append([][]byte{key}, previous...) {
+		if len(k) != KeySize {
+			return nil, fmt.Errorf("encryption key %d must be %d bytes, got %d", i, KeySize, len(k))
+		}
+		block, err := aes.NewCipher(k)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		id := keyID(k)
+		if i == 0 {
+			c.primary = id
+		}
+		if _, ok := c.aeads[id]; !ok {
+			c.aeads[id] = aead
+		}
+	}
+	return c, nil
+}
+
+// ParseKey декодирует ключ из base64.
+//
+//	Параметры:
+//	- s: ключ в стандартной кодировке base64.
+//	Возвращает:
+//	- []byte: ключ длиной KeySize байт.
+//	- error: ошибку декодирования или неверной длины.
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("decode encryption key: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// NewFromBase64 создает Cipher из ключей в base64.
+//
+//	Параметры:
+//	- key: основной ключ в base64.
+//	- previous: прежние ключи в base64.
+//	Возвращает:
+//	- *Cipher: экземпляр шифратора или nil, если основной ключ не задан.
+//	- error: ошибку декодирования или неверной длины ключа.
+func NewFromBase64(key string, previous []string) (*Cipher, error) {
+	if key == "" {
+		return nil, nil
+	}
+	primary, err := ParseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([][]byte, 0, len(previous))
+	for i, p := range previous {
+		k, err := ParseKey(p)
+		if err != nil {
+			return nil, fmt.Errorf("previous key %d: %w", i, err)
+		}
+		keys = append(keys, k)
+	}
+	return New(primary, keys...)
+}
+
+// keyID возвращает идентификатор ключа для заголовка зашифрованного значения.
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
+
+// Encrypt шифрует значение основным ключом.
+//
+//	Параметры:
+//	- plain: открытое значение.
+//	Возвращает:
+//	- string: зашифрованное значение; пустая строка и nil Cipher возвращают plain.
+//	- error: ошибку генерации nonce.
+func (c *Cipher) Encrypt(plain string) (string, error) {
+	if c == nil || plain == "" {
+		return plain, nil
+	}
+	aead := c.aeads[c.primary]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plain)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plain), nil)
+	return prefix + c.primary + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt расшифровывает значение.
+//
+//	Параметры:
+//	- value: зашифрованное значение или открытый текст.
+//	Возвращает:
+//	- string: открытое значение.
+//	- error: ErrUnknownKey, ErrMalformed или ошибку проверки подлинности.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return value, nil
+	}
+	id, payload, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+	if c == nil {
+		return "", ErrUnknownKey
+	}
+	aead, ok := c.aeads[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize()+aead.Overhead() {
+		return "", ErrMalformed
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt value: %w", err)
+	}
+	return string(plain), nil
+}
+
+// Current сообщает, что значение не нужно перешифровывать: оно пустое или
+// зашифровано основным ключом. Для nil Cipher текущим считается открытый текст.
+func (c *Cipher) Current(value string) bool {
+	if value == "" {
+		return true
+	}
+	rest, encrypted := strings.CutPrefix(value, prefix)
+	if c == nil {
+		return !encrypted
+	}
+	return encrypted && strings.HasPrefix(rest, c.primary+":")
+}
@@ -0,0 +1,71 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestCipher проверяет шифрование, расшифровку прежним ключом и обработку открытого текста.
+func TestCipher(t *testing.T) {
+	oldKey, newKey := bytes.Repeat([]byte{1}, KeySize), bytes.Repeat([]byte{2}, KeySize)
+	old, err := New(oldKey)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	c, err := New(newKey, oldKey)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	enc, err := c.Encrypt("+9720000000")
+	if err != nil || !strings.HasPrefix(enc, prefix) || strings.Contains(enc, "9720000000") {
+		t.Fatalf("Encrypt = %q, %v", enc, err)
+	}
+	if again, _ := c.Encrypt("+9720000000"); again == enc {
+		t.Error("encryption must use a random nonce")
+	}
+	if plain, err := c.Decrypt(enc); err != nil || plain != "+9720000000" {
+		t.Errorf("Decrypt = %q, %v", plain, err)
+	}
+	if !c.Current(enc) || old.Current(enc) {
+		t.Error("value must be current only for the cipher with the same primary key")
+	}
+
+	// Значение, зашифрованное прежним ключом, расшифровывается, но требует перешифрования
+	legacy, _ := old.Encrypt("test@gmail.com")
+	if plain, err := c.Decrypt(legacy); err != nil || plain != "test@gmail.com" || c.Current(legacy) {
+		t.Errorf("Decrypt(previous key) = %q, %v", plain, err)
+	}
+	if _, err := old.Decrypt(enc); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+
+	// Открытый текст и пустые значения проходят без изменений
+	if plain, err := c.Decrypt("Kiryat Mozkin"); err != nil || plain != "Kiryat Mozkin" || c.Current("Kiryat Mozkin") {
+		t.Errorf("Decrypt(plain) = %q, %v", plain, err)
+	}
+	if enc, _ := c.Encrypt(""); enc != "" || !c.Current("") {
+		t.Errorf("empty value must stay empty, got %q", enc)
+	}
+
+	tampered := enc[:len(enc)-2] + "AA"
+	if _, err := c.Decrypt(tampered); err == nil {
+		t.Error("tampered value must not decrypt")
+	}
+	if _, err := c.Decrypt(prefix + "broken"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("expected ErrMalformed, got %v", err)
+	}
+
+	var disabled *Cipher
+	if plain, _ := disabled.Encrypt("x"); plain != "x" || !disabled.Current("x") || disabled.Current(enc) {
+		t.Error("nil cipher must pass plain text through")
+	}
+	if _, err := disabled.Decrypt(enc); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("nil cipher: expected ErrUnknownKey, got %v", err)
+	}
+	if _, err := New([]byte("short")); err == nil {
+		t.Error("expected error for short key")
+	}
+}
@@ -0,0 +1,97 @@
+// Package i18n translates user-facing messages into supported languages.
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые языки сообщений.
+const (
+	EN = "en"
+	RU = "ru"
+)
+
+// Default — язык сообщений, если предпочтения клиента не заданы или не поддерживаются.
+const Default = EN
+
+// catalogs — переводы сообщений по языкам. Ключом служит исходное сообщение
+// на английском (формат для fmt.Sprintf), поэтому английский каталог не нужен.
+var catalogs = map[string]map[string]string{
+	RU: ru,
+}
+
+// Parse приводит локаль к поддерживаемому языку.
+//
+//	Учитывается только основной подтег: "ru-RU", "ru_RU" и "RU" дают "ru".
+//
+//	Параметры:
+//	- locale: локаль, например из поля заказа locale.
+//	Возвращает:
+//	- string: поддерживаемый язык.
+//	- bool: false, если язык не поддерживается.
+func Parse(locale string) (string, bool) {
+	lang, _, _ := strings.Cut(strings.TrimSpace(locale), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	lang = strings.ToLower(lang)
+	if lang == EN {
+		return lang, true
+	}
+	if _, ok := catalogs[lang]; ok {
+		return lang, true
+	}
+	return "", false
+}
+
+// FromAcceptLanguage выбирает язык по заголовку Accept-Language.
+//
+//	Из поддерживаемых языков выбирается язык с наибольшим весом q, при
+//	равных весах — указанный раньше. Языки с q=0 и "*" не учитываются.
+//
+//	Параметры:
+//	- header: значение заголовка, например "ru-RU,ru;q=0.9,en;q=0.8".
+//	Возвращает:
+//	- string: поддерживаемый язык.
+//	- bool: false, если ни один язык заголовка не поддерживается.
+func FromAcceptLanguage(header string) (string, bool) {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		lang, ok := Parse(tag)
+		if ok && q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best, best != ""
+}
+
+// Sprintf переводит сообщение на язык lang и подставляет в него параметры.
+//
+//	Сообщение без перевода возвращается на английском. Без параметров
+//	сообщение не форматируется, поэтому в него можно передавать текст ошибок,
+//	содержащий '%'.
+//
+//	Параметры:
+//	- lang: язык (см. Parse); неизвестный язык означает английский.
+//	- format: сообщение на английском в формате fmt.Sprintf.
+//	- args: параметры сообщения.
+//	Возвращает:
+//	- string: переведённое сообщение.
+func Sprintf(lang, format string, args ...any) string {
+	if translated, ok := catalogs[lang][format]; ok {
+		format = translated
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
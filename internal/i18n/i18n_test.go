@@ -0,0 +1,49 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFromAcceptLanguage проверяет выбор языка по весам заголовка Accept-Language.
+func TestFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"ru-RU,ru;q=0.9,en;q=0.8", RU},
+		{"de-DE, en;q=0.5, ru;q=0.7", RU},
+		{"en-US,ru", EN},
+		{"ru;q=0, en;q=0.1", EN},
+		{"de, *", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, ok := FromAcceptLanguage(tt.header)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("%q: expected %q, got %q, %v", tt.header, tt.want, got, ok)
+		}
+	}
+	if lang, ok := Parse("ru_RU"); !ok || lang != RU {
+		t.Errorf("Parse(ru_RU) = %q, %v", lang, ok)
+	}
+}
+
+// TestSprintf проверяет перевод, подстановку параметров и откат на английский.
+func TestSprintf(t *testing.T) {
+	if got := Sprintf(RU, "request body exceeds %d bytes", 1024); got != "тело запроса превышает 1024 байт" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := Sprintf(EN, "request body exceeds %d bytes", 1024); got != "request body exceeds 1024 bytes" {
+		t.Errorf("unexpected message %q", got)
+	}
+	if got := Sprintf(RU, "100% broken"); got != "100% broken" {
+		t.Errorf("message without translation and args must be returned as is, got %q", got)
+	}
+	// В переводах те же параметры, что и в исходных сообщениях
+	for format, translated := range ru {
+		if strings.Count(format, "%") != strings.Count(translated, "%") {
+			t.Errorf("%q: translation %q has different parameters", format, translated)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package i18n
+
+// ru — русский каталог сообщений.
+var ru = map[string]string{
+	// Валидация заказа
+	"invalid order: %s":  "некорректный заказ: %s",
+	"order is nil":       "заказ не передан",
+	"is required":        "обязательное поле",
+	"order has no items": "в заказе нет товаров",
+	"must contain only letters, digits, '-' and '_' and be at most %d characters": "может содержать только буквы, цифры, '-' и '_' и быть не длиннее %d символов",
+	"must contain 7 to 15 digits with an optional leading '+'":                    "должен содержать от 7 до 15 цифр, допускается '+' в начале",
+	"must be a valid email address":                                               "должен быть корректным адресом электронной почты",
+	"must be 2 to 10 letters, digits, spaces or '-'":                              "должен содержать от 2 до 10 букв, цифр, пробелов или '-'",
+	"must be a three-letter ISO 4217 code":                                        "должен быть трёхбуквенным кодом ISO 4217",
+	"must not be negative":                                                        "не может быть отрицательным",
+	"must be between 0 and 100":                                                   "должно быть от 0 до 100",
+	"must equal price minus sale (%d)":                                            "должно равняться цене за вычетом скидки (%d)",
+	"must equal the sum of items total_price (%d)":                                "должно равняться сумме total_price товаров (%d)",
+
+	// Ошибки API
+	"admin credentials required":                           "требуются учётные данные администратора",
+	"api key is required":                                  "требуется API-ключ",
+	"invalid api key":                                      "недействительный API-ключ",
+	"failed to verify api key":                             "не удалось проверить API-ключ",
+	"rate limit exceeded":                                  "превышен лимит запросов",
+	"internal server error":                                "внутренняя ошибка сервера",
+	"failed to encode response":                            "не удалось сформировать ответ",
+	"service is overloaded, retry later":                   "сервис перегружен, повторите запрос позже",
+	"database is temporarily unavailable":                  "база данных временно недоступна",
+	"order storage is not available":                       "хранилище заказов недоступно",
+	"statistics are not available":                         "статистика недоступна",
+	"kafka consumer is not running":                        "потребитель Kafka не запущен",
+	"request timed out":                                    "истекло время ожидания запроса",
+	"request processing timed out":                         "истекло время обработки запроса",
+	"request body is too large":                            "тело запроса слишком велико",
+	"request body exceeds %d bytes":                        "тело запроса превышает %d байт",
+	"method %s is not allowed":                             "метод %s не поддерживается",
+	"resource not found":                                   "ресурс не найден",
+	"order not found":                                      "заказ не найден",
+	"order id is required":                                 "требуется идентификатор заказа",
+	"no orders available":                                  "нет доступных заказов",
+	"failed to send test order":                            "не удалось отправить тестовый заказ",
+	"failed to save order":                                 "не удалось сохранить заказ",
+	"test orders are disabled outside the dev environment": "тестовые заказы доступны только в окружении dev",
+	"too many orders in request":                           "слишком много заказов в запросе",
+	"invalid request body: %v":                             "некорректное тело запроса: %v",
+	"invalid order payload: %v":                            "некорректные данные заказа: %v",
+	"invalid status payload: %v":                           "некорректные данные статуса: %v",
+	"format must be csv or xlsx":                           "формат должен быть csv или xlsx",
+	"unknown field: %s":                                    "неизвестное поле: %s",
+	"limit must be between 1 and %d":                       "limit должен быть от 1 до %d",
+	"%s must be between %d and %d":                         "%s должен быть от %d до %d",
+	"%s must be an RFC 3339 timestamp or YYYY-MM-DD date":  "%s должен быть временем в формате RFC 3339 или датой YYYY-MM-DD",
+	"invalid cursor":                                       "некорректный курсор",
+	"unsupported cursor version":                           "неподдерживаемая версия курсора",
+	"cursor has expired, restart pagination":               "срок действия курсора истёк, начните постраничный просмотр заново",
+	"unknown order status":                                 "неизвестный статус заказа",
+	"order status cannot change from %s to %s":             "статус заказа нельзя изменить с %s на %s",
+}
@@ -4,144 +4,482 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
-	"l0_wb/internal/cache"
+	"l0_wb/internal/breaker"
+	"l0_wb/internal/config"
+	"l0_wb/internal/dto"
+	"l0_wb/internal/errtrack"
+	"l0_wb/internal/feed"
 	"l0_wb/internal/metrics"
 	"l0_wb/internal/model"
 	"l0_wb/internal/service"
+	"l0_wb/internal/tracing"
 	"l0_wb/internal/util"
 )
 
-const batchSize = 1 // Размер батча для тестирования
+// defaultRetryDelay — пауза перед повторным сохранением, если DB_BREAKER_OPEN_TIMEOUT не задан.
+const defaultRetryDelay = time.Second
 
 // Consumer представляет собой Kafka-консумер, который слушает топик с заказами.
 type Consumer struct {
 	reader       *kafka.Reader
 	orderService service.OrderService
-	orderCache   *cache.OrderCache
+	orderFeed    *feed.Hub
+	running      atomic.Bool
+	retryDelay   time.Duration // Пауза перед повторным сохранением, пока автомат защиты БД разомкнут
 	logger       *zap.Logger
+
+	// Параметры очереди записи, см. writeQueue
+	queueSize    int
+	writers      int
+	batchSize    atomic.Int64
+	batchTimeout time.Duration
+	drainTimeout time.Duration // Время на сохранение заказов из очереди после остановки чтения
+
+	queue    atomic.Pointer[writeQueue] // Очередь записи запущенного консумера (nil — консумер не запущен)
+	received sync.Map                   // Сведения о сообщениях заказов в очереди: *model.Order → receipt
+
+	pauseMu sync.Mutex
+	resume  chan struct{} // Закрывается при возобновлении чтения; nil, если консумер не на паузе
+}
+
+// receipt — сведения о сообщении, из которого получен заказ, ожидающий в очереди записи.
+type receipt struct {
+	span      trace.SpanContext // Контекст спана получения (пустой, если спан не записывается)
+	partition int
+	offset    int64
+}
+
+// logFields возвращает поля корреляции сообщения для журнала.
+func (r receipt) logFields() []zap.Field {
+	return []zap.Field{zap.Int("partition", r.partition), zap.Int64("offset", r.offset)}
 }
 
 // NewConsumer создает новый экземпляр Consumer.
 //
 //	Параметры:
-//	- brokers: список адресов Kafka-брокеров.
-//	- topic: название топика Kafka для чтения сообщений.
-//	- groupID: идентификатор группы потребителей Kafka.
+//	- cfg: конфигурация приложения (брокеры, топик, группа потребителей, SASL).
 //	- orderService: сервис для работы с заказами.
-//	- orderCache: кэш для хранения заказов.
+//	- orderFeed: поток обработанных заказов для подписчиков (nil — не публиковать).
 //	Возвращает:
 //	- *Consumer: экземпляр Kafka-консумера.
-func NewConsumer(brokers []string, topic, groupID string, orderService service.OrderService, orderCache *cache.OrderCache) *Consumer {
+func NewConsumer(cfg *config.Config, orderService service.OrderService, orderFeed *feed.Hub) *Consumer {
 	logger := util.GetLogger()
 	r := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:     brokers,
-		Topic:       topic,
-		GroupID:     groupID,
+		Brokers:     cfg.KafkaBrokers,
+		Topic:       cfg.KafkaTopic,
+		GroupID:     cfg.KafkaGroupID,
+		Dialer:      newDialer(cfg),
 		StartOffset: kafka.FirstOffset, // Начинаем чтение с первого сообщения.
 		MinBytes:    10e3,              // Минимальный размер данных 10KB
 		MaxBytes:    10e6,              // Максимальный размер данных 10MB
 	})
 
 	logger.Info("Kafka consumer created",
-		zap.String("topic", topic),
-		zap.String("group_id", groupID),
+		zap.String("topic", cfg.KafkaTopic),
+		zap.String("group_id", cfg.KafkaGroupID),
 	)
 
-	return &Consumer{
+	retryDelay := cfg.DBBreakerOpenTimeout
+	if retryDelay <= 0 {
+		retryDelay = defaultRetryDelay
+	}
+
+	c := &Consumer{
 		reader:       r,
 		orderService: orderService,
-		orderCache:   orderCache,
+		orderFeed:    orderFeed,
+		retryDelay:   retryDelay,
 		logger:       logger,
+		queueSize:    cfg.KafkaWriteQueueSize,
+		writers:      cfg.KafkaWriters,
+		batchTimeout: cfg.KafkaBatchTimeout,
+		drainTimeout: cfg.KafkaDrainTimeout,
+	}
+	c.batchSize.Store(int64(cfg.KafkaBatchSize))
+	return c
+}
+
+// SetBatchSize меняет максимальный размер батча сохранения без перезапуска консумера.
+//
+//	Параметры:
+//	- n: размер батча (значения меньше 1 считаются 1).
+func (c *Consumer) SetBatchSize(n int) {
+	c.batchSize.Store(int64(n))
+	if q := c.queue.Load(); q != nil {
+		q.setBatchSize(n)
 	}
 }
 
 // Run запускает процесс чтения сообщений из Kafka-топика до отмены контекста.
 //
+//	Чтение и сохранение разделены очередью записи: консумер декодирует
+//	сообщения и ставит заказы в очередь, а обработчики сохраняют их батчами.
+//	Когда очередь заполнена, чтение ждёт освобождения места. После отмены
+//	контекста заказы, уже поставленные в очередь, сохраняются в течение
+//	drainTimeout.
+//
+//	Смещение сообщения фиксируется в Kafka только после того, как заказ
+//	сохранён (или отклонён сервисом) и обработаны все сообщения партиции
+//	до него, см. offsetTracker. Заказы, не сохранённые до остановки, будут
+//	прочитаны снова.
+//
 //	Параметры:
 //	- ctx: контекст выполнения для управления остановкой консумера.
 //	Возвращает:
 //	- error: ошибку, если произошел сбой при чтении сообщений.
 func (c *Consumer) Run(ctx context.Context) error {
-	c.logger.Info("Kafka consumer started")
+	c.logger.Info("Kafka consumer started",
+		zap.Int("writers", c.writers),
+		zap.Int("queue_size", c.queueSize),
+		zap.Int64("batch_size", c.batchSize.Load()),
+	)
+	c.running.Store(true)
+	defer c.running.Store(false)
 
-	// Запускаем горутину для периодического обновления метрики размера очереди
-	go c.monitorQueueSize(ctx)
+	workCtx, stopWork := drainContext(ctx, c.drainTimeout)
+	defer stopWork()
+	offsets := newOffsetTracker(c.reader.Config().Topic)
+	queue := newWriteQueue(c.writers, c.queueSize, int(c.batchSize.Load()), c.batchTimeout,
+		func(ctx context.Context, batch []*model.Order) {
+			receipts := c.processBatch(ctx, batch)
+			c.commit(ctx, offsets, receipts...)
+		})
+	queue.start(workCtx)
+	defer queue.close()
+	c.queue.Store(queue)
+	defer c.queue.Store(nil)
+	// Размер мог измениться между созданием очереди и её публикацией
+	queue.setBatchSize(int(c.batchSize.Load()))
 
-	var orders []*model.Order // Изменено на слайс указателей
+	// Запускаем горутину для периодического обновления метрики размера очереди
+	go c.monitorQueueSize(ctx, queue)
 
 	for {
-		startTime := time.Now()
-		// Чтение следующего сообщения из топика
-		m, err := c.reader.ReadMessage(ctx)
+		// На паузе новые сообщения не читаются; смещения не фиксируются, поэтому ничего не теряется
+		if err := c.waitIfPaused(ctx); err != nil {
+			return err
+		}
+
+		// Чтение следующего сообщения из топика; смещение фиксируется после сохранения заказа
+		m, err := c.reader.FetchMessage(ctx)
 		if err != nil {
 			metrics.OrderProcessingErrors.Inc()
 			c.logger.Error("Failed to read message", zap.Error(err))
+			errtrack.CaptureError(ctx, err, map[string]string{"component": "kafka", "operation": "read"})
 			return fmt.Errorf("failed to read message: %w", err)
 		}
 
-		var order model.Order
+		span := startReceive(ctx, m, c.reader.Config().GroupID)
+		rcpt := receipt{partition: m.Partition, offset: m.Offset}
+		offsets.track(m.Partition, m.Offset)
+		if span.IsRecording() {
+			rcpt.span = span.SpanContext()
+		}
+
 		// Декодируем JSON-сообщение в структуру заказа
-		if err := json.Unmarshal(m.Value, &order); err != nil {
+		decodeStart := time.Now()
+		order, err := decodeOrder(m.Value)
+		metrics.RecordOrderStage(trace.ContextWithSpan(ctx, span), metrics.StageDecode, time.Since(decodeStart))
+		if err != nil {
 			metrics.OrderProcessingErrors.Inc()
+			metrics.RecordOrderResult("undecodable")
 			c.logger.Warn("Failed to unmarshal order",
-				zap.ByteString("message", m.Value),
-				zap.Error(err),
+				append(rcpt.logFields(),
+					util.Payload("message", m.Value),
+					zap.Error(err),
+				)...,
 			)
+			tracing.End(span, err)
+			// Повторное чтение не исправит сообщение
+			c.commit(workCtx, offsets, rcpt)
 			continue
 		}
+		c.received.Store(order, rcpt)
+
+		// Блокируется, пока в очереди нет места
+		err = queue.push(ctx, order)
+		tracing.End(span, err)
+		if err != nil {
+			c.received.Delete(order)
+			return err
+		}
+	}
+}
+
+// drainContext возвращает контекст, который отменяется через timeout после отмены parent.
+//
+//	Параметры:
+//	- parent: родительский контекст.
+//	- timeout: отсрочка отмены.
+//	Возвращает:
+//	- context.Context: производный контекст.
+//	- context.CancelFunc: немедленная отмена производного контекста.
+func drainContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+	stop := context.AfterFunc(parent, func() {
+		time.AfterFunc(timeout, cancel)
+	})
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// processBatch сохраняет батч из очереди записи и учитывает результаты.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- batch: заказы батча.
+//	Возвращает:
+//	- []receipt: сведения о сообщениях обработанных заказов (nil, если батч
+//	  не сохранён до отмены контекста и его сообщения нельзя фиксировать).
+func (c *Consumer) processBatch(ctx context.Context, batch []*model.Order) []receipt {
+	// Спан батча ссылается на спаны получения его сообщений
+	receipts := make([]receipt, len(batch))
+	links := make([]trace.Link, 0, len(batch))
+	for i, order := range batch {
+		if r, ok := c.received.LoadAndDelete(order); ok {
+			receipts[i] = r.(receipt)
+		}
+		if receipts[i].span.IsValid() {
+			links = append(links, trace.Link{SpanContext: receipts[i].span})
+		}
+	}
+	topic := c.reader.Config().Topic
+	// Записи сервиса и репозиториев о батче помечаются топиком и размером батча
+	ctx = util.ContextWithLogger(ctx, c.logger.With(zap.String("topic", topic), zap.Int("batch_size", len(batch))))
+	ctx, span := tracing.Start(ctx, topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeDeliver,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingBatchMessageCount(len(batch)),
+		),
+	)
+
+	startTime := time.Now()
+	results, err := c.saveBatch(ctx, batch)
+	tracing.End(span, err)
+	if err != nil {
+		metrics.OrderProcessingErrors.Add(float64(len(batch)))
+		util.LoggerFromContext(ctx, c.logger).Error("Batch not saved before shutdown, offsets left uncommitted", zap.Error(err))
+		return nil
+	}
+	metrics.RecordOrderProcessing(ctx, time.Since(startTime))
+	c.handleResults(batch, receipts, results)
+	return receipts
+}
+
+// commit отмечает сообщения обработанными и фиксирует смещения, которые это позволяет.
+//
+//	Ошибка фиксации только записывается в журнал: сообщения будут прочитаны
+//	снова, а их заказы пропущены как дубликаты.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- offsets: трекер смещений запущенного консумера.
+//	- receipts: сведения об обработанных сообщениях.
+func (c *Consumer) commit(ctx context.Context, offsets *offsetTracker, receipts ...receipt) {
+	var msgs []kafka.Message
+	for _, r := range receipts {
+		if m, ok := offsets.done(r.partition, r.offset); ok {
+			msgs = append(msgs, m)
+		}
+	}
+	if len(msgs) == 0 {
+		return
+	}
+	if err := c.reader.CommitMessages(ctx, msgs...); err != nil {
+		c.logger.Warn("Failed to commit offsets", zap.Int("messages", len(msgs)), zap.Error(err))
+		errtrack.CaptureError(ctx, err, map[string]string{"component": "kafka", "operation": "commit"})
+	}
+}
 
-		// Добавляем указатель на заказ в слайс
-		orders = append(orders, &order)
-
-		// Сохраняем батч заказов в базу данных через OrderService
-		if len(orders) >= batchSize {
-			if err := c.orderService.SaveBatch(ctx, orders); err != nil {
-				metrics.OrderProcessingErrors.Inc()
-				c.logger.Error("Failed to save batch", zap.Error(err))
-			} else {
-				metrics.OrdersProcessed.Add(float64(len(orders)))
-				fmt.Println("OrdersProcessed incremented:", len(orders))
+// handleResults учитывает результаты сохранения батча.
+//
+//	Новые и изменившиеся заказы публикуются в поток заказов (в кэш их
+//	записывает OrderService); некорректные и несохранённые заказы учитываются как ошибки обработки.
+//	Итог каждого заказа учитывается в orders_results_total.
+//	Записи журнала о заказе содержат order_uid, партицию и смещение его сообщения.
+//
+//	Параметры:
+//	- batch: сохранявшиеся заказы.
+//	- receipts: сведения о сообщениях заказов в том же порядке.
+//	- results: результаты SaveBatch в том же порядке.
+func (c *Consumer) handleResults(batch []*model.Order, receipts []receipt, results []service.SaveResult) {
+	stored := 0
+	for i, res := range results {
+		order := batch[i]
+		logger := c.logger.With(append(receipts[i].logFields(), zap.String("order_uid", res.OrderUID))...)
+		metrics.RecordOrderResult(string(res.Status))
+		switch res.Status {
+		case service.SaveStatusSaved, service.SaveStatusDuplicate, service.SaveStatusSuperseded:
+			stored++
+			if c.orderFeed != nil && res.Status == service.SaveStatusSaved {
+				c.orderFeed.Publish(order)
+			}
+			logger.Info("Order processed successfully", zap.String("status", string(res.Status)))
+		case service.SaveStatusInvalid:
+			metrics.OrderProcessingErrors.Inc()
+			fields := []zap.Field{zap.Error(res.Err)}
+			var validationErr *service.ValidationError
+			if errors.As(res.Err, &validationErr) {
+				fields = append(fields, zap.Any("invalid_fields", validationErr.Fields))
 			}
-			orders = nil // Очищаем слайс после сохранения
+			logger.Warn("Invalid order skipped", fields...)
+		default:
+			metrics.OrderProcessingErrors.Inc()
+			logger.Error("Failed to save order", zap.Error(res.Err))
+			errtrack.CaptureError(context.Background(), res.Err, map[string]string{
+				"component": "kafka",
+				"operation": "save_order",
+				"order_uid": res.OrderUID,
+			})
 		}
+	}
+	metrics.OrdersProcessed.Add(float64(stored))
+}
+
+// Running сообщает, читает ли консумер сообщения из Kafka.
+func (c *Consumer) Running() bool {
+	return c.running.Load()
+}
+
+// Pause приостанавливает чтение сообщений из Kafka.
+//
+//	Сообщение, чтение которого уже началось, будет обработано; следующие
+//	останутся в топике до вызова Resume.
+func (c *Consumer) Pause() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	if c.resume == nil {
+		c.resume = make(chan struct{})
+		c.logger.Info("Kafka consumer paused")
+	}
+}
 
-		metrics.OrderProcessingTime.Observe(time.Since(startTime).Seconds())
-		// Если заказ успешно сохранен, добавляем его в кэш
-		c.orderCache.Set(&order)
-		c.logger.Info("Order processed successfully",
-			zap.String("order_uid", order.OrderUID),
-		)
+// Resume возобновляет чтение сообщений после Pause.
+func (c *Consumer) Resume() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	if c.resume != nil {
+		close(c.resume)
+		c.resume = nil
+		c.logger.Info("Kafka consumer resumed")
 	}
 }
 
-// monitorQueueSize периодически обновляет метрику размера очереди Kafka.
-// Поскольку точное определение размера очереди может быть сложным,
-// мы используем простую метрику - количество сообщений в текущем батче.
+// Paused сообщает, приостановлено ли чтение сообщений.
+func (c *Consumer) Paused() bool {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	return c.resume != nil
+}
+
+// waitIfPaused блокируется, пока консумер на паузе.
+//
+//	Возвращает:
+//	- error: ошибку контекста, если он отменён во время паузы.
+func (c *Consumer) waitIfPaused(ctx context.Context) error {
+	c.pauseMu.Lock()
+	resume := c.resume
+	c.pauseMu.Unlock()
+	if resume == nil {
+		return nil
+	}
+	select {
+	case <-resume:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// saveBatch сохраняет батч заказов, ожидая восстановления БД.
+//
+//	Ошибку батча целиком SaveBatch возвращает, только если БД недоступна
+//	(в том числе при разомкнутом автомате защиты). Такой батч не
+//	отбрасывается: обработчик повторяет сохранение раз в retryDelay, а
+//	заполнившаяся очередь приостанавливает чтение топика.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- orders: батч заказов.
+//	Возвращает:
+//	- []service.SaveResult: результат по каждому заказу батча.
+//	- error: ошибку сохранения или ошибку контекста, если он отменён во время ожидания.
+func (c *Consumer) saveBatch(ctx context.Context, orders []*model.Order) ([]service.SaveResult, error) {
+	actorCtx := service.WithActor(ctx, "kafka:"+c.reader.Config().Topic)
+	for {
+		results, err := c.orderService.SaveBatch(actorCtx, orders)
+		if err == nil {
+			return results, nil
+		}
+		if errors.Is(err, breaker.ErrOpen) {
+			c.logger.Warn("Database circuit breaker is open, backing off",
+				zap.Int("orders", len(orders)),
+				zap.Duration("retry_in", c.retryDelay),
+			)
+		} else {
+			util.LoggerFromContext(ctx, c.logger).Error("Failed to save batch, retrying",
+				zap.Duration("retry_in", c.retryDelay),
+				zap.Error(err),
+			)
+			errtrack.CaptureError(ctx, err, map[string]string{"component": "kafka", "operation": "save_batch", "topic": c.reader.Config().Topic})
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(c.retryDelay):
+		}
+	}
+}
+
+// decodeOrder декодирует сообщение Kafka в структуру заказа.
+//
+//	Параметры:
+//	- value: тело сообщения в формате JSON.
+//	Возвращает:
+//	- *model.Order: декодированный заказ.
+//	- error: ошибку, если сообщение не является корректным JSON заказа.
+func decodeOrder(value []byte) (*model.Order, error) {
+	var msg dto.KafkaOrder
+	if err := json.Unmarshal(value, &msg); err != nil {
+		return nil, err
+	}
+	return msg.ToModel(), nil
+}
+
+// monitorQueueSize периодически обновляет метрику размера очереди записи.
 //
 //	Параметры:
 //	- ctx: контекст выполнения для управления остановкой мониторинга.
-func (c *Consumer) monitorQueueSize(ctx context.Context) {
+//	- queue: очередь записи консумера.
+func (c *Consumer) monitorQueueSize(ctx context.Context, queue *writeQueue) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
-	// Начальное значение для метрики
-	metrics.SetQueueSize(c.reader.Config().Topic, 0)
+	topic := c.reader.Config().Topic
+	metrics.SetQueueSize(topic, 0)
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			// Обновляем метрику размера очереди
-			// В данной реализации мы просто устанавливаем примерное значение
-			queueSize := 0
-
-			metrics.SetQueueSize(c.reader.Config().Topic, queueSize)
+			metrics.SetQueueSize(topic, queue.len())
 		}
 	}
 }
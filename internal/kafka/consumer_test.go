@@ -0,0 +1,88 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"l0_wb/internal/model"
+	"l0_wb/internal/service"
+)
+
+// TestHandleResultsLogFields проверяет, что записи о заказе содержат order_uid, партицию и смещение сообщения.
+func TestHandleResultsLogFields(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	c := &Consumer{logger: zap.New(core)}
+
+	batch := []*model.Order{{OrderUID: "b563feb7b2b84b6test"}, {OrderUID: "broken"}}
+	receipts := []receipt{{partition: 2, offset: 41}, {partition: 0, offset: 7}}
+	results := []service.SaveResult{
+		{OrderUID: "b563feb7b2b84b6test", Status: service.SaveStatusSaved},
+		{OrderUID: "broken", Status: service.SaveStatusFailed, Err: errors.New("boom")},
+	}
+	c.handleResults(batch, receipts, results)
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 log entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		fields := entry.ContextMap()
+		if fields["order_uid"] != results[i].OrderUID || fields["partition"] != int64(receipts[i].partition) || fields["offset"] != receipts[i].offset {
+			t.Errorf("entry %q has unexpected fields %v", entry.Message, fields)
+		}
+	}
+}
+
+// flakyOrderService — OrderService, у которого SaveBatch первые failures раз возвращает ошибку.
+type flakyOrderService struct {
+	service.OrderService
+	failures int
+	calls    int
+}
+
+func (s *flakyOrderService) SaveBatch(_ context.Context, orders []*model.Order) ([]service.SaveResult, error) {
+	s.calls++
+	if s.calls <= s.failures {
+		return nil, errors.New("connection refused")
+	}
+	results := make([]service.SaveResult, len(orders))
+	for i, order := range orders {
+		results[i] = service.SaveResult{OrderUID: order.OrderUID, Status: service.SaveStatusSaved}
+	}
+	return results, nil
+}
+
+// TestProcessBatchRetriesFailedBatch проверяет, что батч с ошибкой сохранения повторяется, а не отбрасывается.
+func TestProcessBatchRetriesFailedBatch(t *testing.T) {
+	orders := &flakyOrderService{failures: 2}
+	c := &Consumer{
+		reader:       kafka.NewReader(kafka.ReaderConfig{Brokers: []string{"localhost:9092"}, Topic: "orders"}),
+		orderService: orders,
+		retryDelay:   time.Millisecond,
+		logger:       zap.NewNop(),
+	}
+	defer c.reader.Close()
+
+	order := &model.Order{OrderUID: "b563feb7b2b84b6test"}
+	c.received.Store(order, receipt{partition: 0, offset: 3})
+	receipts := c.processBatch(context.Background(), []*model.Order{order})
+	if orders.calls != 3 {
+		t.Errorf("expected 3 save attempts, got %d", orders.calls)
+	}
+	if len(receipts) != 1 || receipts[0].offset != 3 {
+		t.Errorf("expected receipt of the saved message, got %+v", receipts)
+	}
+
+	// Батч, не сохранённый до отмены контекста, не фиксируется
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	orders.calls, orders.failures = 0, 1
+	if receipts := c.processBatch(ctx, []*model.Order{order}); receipts != nil {
+		t.Errorf("expected no receipts for an unsaved batch, got %+v", receipts)
+	}
+}
@@ -0,0 +1,108 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+	"l0_wb/internal/events"
+	"l0_wb/internal/util"
+)
+
+// EventPublisher публикует события об изменении заказов в Kafka.
+type EventPublisher struct {
+	writer *kafka.Writer
+	mode   events.Mode
+	logger *zap.Logger
+}
+
+// NewEventPublisher создаёт EventPublisher для топика ORDER_EVENTS_TOPIC.
+//
+//	Параметры:
+//	- cfg: конфигурация приложения (брокеры, топик и формат событий, SASL).
+//	Возвращает:
+//	- *EventPublisher: экземпляр публикатора или nil, если топик событий не задан.
+func NewEventPublisher(cfg *config.Config) *EventPublisher {
+	if cfg.OrderEventsTopic == "" {
+		return nil
+	}
+	logger := util.GetLogger()
+	logger.Info("Order events publisher created",
+		zap.String("topic", cfg.OrderEventsTopic),
+		zap.String("mode", cfg.OrderEventsMode),
+	)
+	return &EventPublisher{
+		writer: NewWriter(cfg, cfg.OrderEventsTopic),
+		mode:   events.Mode(cfg.OrderEventsMode),
+		logger: logger,
+	}
+}
+
+// PublishUpdates публикует события для изменившихся заказов.
+//
+//	Заказы, содержимое которых не изменилось, пропускаются. Ключ сообщения —
+//	order_uid, поэтому события одного заказа попадают в одну партицию по порядку.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- updates: состояния заказов до и после сохранения.
+//	Возвращает:
+//	- error: ошибку формирования или отправки событий.
+func (p *EventPublisher) PublishUpdates(ctx context.Context, updates []events.Update) error {
+	messages := make([]kafka.Message, 0, len(updates))
+	for _, u := range updates {
+		event, err := events.NewUpdateEvent(p.mode, u)
+		if err != nil {
+			return fmt.Errorf("build order event: %w", err)
+		}
+		if event == nil {
+			continue
+		}
+		value, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("marshal order event: %w", err)
+		}
+		messages = append(messages, kafka.Message{Key: []byte(event.OrderUID), Value: value})
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	if err := writeMessages(ctx, p.writer, messages...); err != nil {
+		return fmt.Errorf("write order events: %w", err)
+	}
+	p.logger.Debug("Order events published", zap.Int("count", len(messages)))
+	return nil
+}
+
+// PublishStatusChange публикует событие об изменении статуса заказа.
+//
+//	Событие отправляется в тот же топик и с тем же ключом order_uid, что и
+//	события об изменении содержимого, поэтому потребители видят их по порядку.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- change: переход заказа между статусами.
+//	Возвращает:
+//	- error: ошибку формирования или отправки события.
+func (p *EventPublisher) PublishStatusChange(ctx context.Context, change events.StatusChange) error {
+	value, err := json.Marshal(events.NewStatusEvent(change))
+	if err != nil {
+		return fmt.Errorf("marshal status event: %w", err)
+	}
+	if err := writeMessages(ctx, p.writer, kafka.Message{Key: []byte(change.OrderUID), Value: value}); err != nil {
+		return fmt.Errorf("write status event: %w", err)
+	}
+	p.logger.Debug("Order status event published",
+		zap.String("order_uid", change.OrderUID),
+		zap.String("status", string(change.To)),
+	)
+	return nil
+}
+
+// Close закрывает Kafka writer.
+func (p *EventPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,82 @@
+package kafka
+
+import (
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// offsetTracker определяет смещения, которые можно зафиксировать в Kafka.
+//
+//	Батчи разных обработчиков очереди записи завершаются в произвольном
+//	порядке, а фиксация смещения в партиции подтверждает все сообщения до
+//	него. Поэтому смещение фиксируется только тогда, когда обработаны все
+//	прочитанные до него сообщения партиции: при перезапуске несохранённые
+//	заказы будут прочитаны снова.
+type offsetTracker struct {
+	topic      string
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+// partitionOffsets — прочитанные, но ещё не зафиксированные сообщения партиции.
+type partitionOffsets struct {
+	pending []int64        // Смещения в порядке чтения
+	done    map[int64]bool // Обработанные смещения из pending
+}
+
+// newOffsetTracker создаёт offsetTracker для топика.
+//
+//	Параметры:
+//	- topic: топик консумера.
+//	Возвращает:
+//	- *offsetTracker: пустой трекер.
+func newOffsetTracker(topic string) *offsetTracker {
+	return &offsetTracker{topic: topic, partitions: make(map[int]*partitionOffsets)}
+}
+
+// track регистрирует прочитанное сообщение; вызывается в порядке чтения партиции.
+//
+//	Параметры:
+//	- partition: партиция сообщения.
+//	- offset: смещение сообщения.
+func (t *offsetTracker) track(partition int, offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.partitions[partition]
+	if p == nil {
+		p = &partitionOffsets{done: make(map[int64]bool)}
+		t.partitions[partition] = p
+	}
+	p.pending = append(p.pending, offset)
+}
+
+// done отмечает сообщение обработанным.
+//
+//	Параметры:
+//	- partition: партиция сообщения.
+//	- offset: смещение сообщения.
+//	Возвращает:
+//	- kafka.Message: последнее сообщение партиции, все сообщения до которого обработаны.
+//	- bool: false, если фиксировать пока нечего.
+func (t *offsetTracker) done(partition int, offset int64) (kafka.Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.partitions[partition]
+	if p == nil {
+		return kafka.Message{}, false
+	}
+	p.done[offset] = true
+
+	n := 0
+	for n < len(p.pending) && p.done[p.pending[n]] {
+		delete(p.done, p.pending[n])
+		n++
+	}
+	if n == 0 {
+		return kafka.Message{}, false
+	}
+	last := p.pending[n-1]
+	p.pending = p.pending[n:]
+	return kafka.Message{Topic: t.topic, Partition: partition, Offset: last}, true
+}
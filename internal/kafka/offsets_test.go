@@ -0,0 +1,29 @@
+package kafka
+
+import "testing"
+
+// TestOffsetTrackerCommitsContiguousPrefix проверяет, что смещение фиксируется только после обработки всех предыдущих сообщений партиции.
+func TestOffsetTrackerCommitsContiguousPrefix(t *testing.T) {
+	tr := newOffsetTracker("orders")
+	for _, off := range []int64{10, 11, 12} {
+		tr.track(0, off)
+	}
+	tr.track(1, 5)
+
+	// Батч с более поздним сообщением сохранён раньше
+	if m, ok := tr.done(0, 12); ok {
+		t.Fatalf("offset 12 must wait for 10 and 11, got commit of %d", m.Offset)
+	}
+	if m, ok := tr.done(0, 10); !ok || m.Offset != 10 || m.Topic != "orders" {
+		t.Fatalf("expected commit of offset 10, got %+v, %v", m, ok)
+	}
+	if m, ok := tr.done(0, 11); !ok || m.Offset != 12 || m.Partition != 0 {
+		t.Fatalf("expected commit of offset 12, got %+v, %v", m, ok)
+	}
+	if m, ok := tr.done(1, 5); !ok || m.Offset != 5 || m.Partition != 1 {
+		t.Fatalf("expected commit of partition 1 offset 5, got %+v, %v", m, ok)
+	}
+	if _, ok := tr.done(2, 1); ok {
+		t.Error("untracked partition must not be committed")
+	}
+}
@@ -4,37 +4,39 @@ package kafka
 import (
 	"context"
 	"encoding/json"
-	"go.uber.org/zap"
+	"fmt"
 	"time"
 
+	"go.uber.org/zap"
+
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/segmentio/kafka-go"
 	"l0_wb/internal/config"
+	"l0_wb/internal/dto"
 	"l0_wb/internal/model"
 	"l0_wb/internal/util"
 )
 
-// ProduceTestMessage генерирует тестовый заказ, сериализует его в JSON и отправляет в Kafka.
+// ProduceTestMessage сериализует заказ в JSON и отправляет его в Kafka.
 //
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- order: заказ для отправки; nil — сгенерировать случайный тестовый заказ.
 //	Возвращает:
 //	- string: Уникальный идентификатор заказа (OrderUID), отправленного в Kafka.
 //	- error: Ошибку, если не удалось отправить сообщение.
-func ProduceTestMessage() (string, error) {
+func ProduceTestMessage(ctx context.Context, order *model.Order) (string, error) {
 	logger := util.GetLogger()
 	logger.Info("Starting Kafka producer")
 
 	// Загружаем конфигурацию
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		logger.Fatal("Failed to load config", zap.Error(err))
+		return "", fmt.Errorf("load config: %w", err)
 	}
 
 	// Создаем Kafka writer
-	writer := &kafka.Writer{
-		Addr:     kafka.TCP(cfg.KafkaBrokers...),
-		Topic:    cfg.KafkaTopic,
-		Balancer: &kafka.LeastBytes{},
-	}
+	writer := NewWriter(cfg, cfg.KafkaTopic)
 	defer func() {
 		if err := writer.Close(); err != nil {
 			logger.Warn("Failed to close Kafka writer", zap.Error(err))
@@ -43,28 +45,30 @@ func ProduceTestMessage() (string, error) {
 
 	logger.Info("Kafka writer initialized", zap.String("topic", cfg.KafkaTopic))
 
-	// Инициализация gofakeit
-	gofakeit.Seed(0)
+	if order == nil {
+		// Инициализация gofakeit
+		gofakeit.Seed(0)
 
-	// Генерируем и публикуем сообщение
-	order := generateOrder()
+		// Генерируем случайный заказ
+		order = generateOrder()
+	}
 
 	// Преобразуем сообщение в JSON
-	data, err := json.Marshal(order)
+	data, err := json.Marshal(dto.NewKafkaOrder(order))
 	if err != nil {
-		logger.Fatal("Failed to marshal order", zap.Error(err))
+		return "", fmt.Errorf("marshal order: %w", err)
 	}
 
 	// Публикуем сообщение в Kafka
-	err = writer.WriteMessages(context.Background(), kafka.Message{
+	err = writeMessages(ctx, writer, kafka.Message{
 		Key:   []byte(order.OrderUID),
 		Value: data,
 	})
 	if err != nil {
 		logger.Error("Failed to write message to Kafka", zap.Error(err))
-	} else {
-		logger.Info("Message published successfully", zap.String("orderUID", order.OrderUID))
+		return "", fmt.Errorf("write message: %w", err)
 	}
+	logger.Info("Message published successfully", zap.String("order_uid", order.OrderUID))
 	return order.OrderUID, nil
 }
 
@@ -99,36 +103,40 @@ func generateOrder() *model.Order {
 		Email:   gofakeit.Email(),
 	}
 
-	// Генерация данных для payments
-	order.Payment = model.Payment{
-		Transaction:  gofakeit.UUID(),
-		RequestID:    gofakeit.UUID(),
-		Currency:     gofakeit.CurrencyShort(),
-		Provider:     gofakeit.Company(),
-		Amount:       gofakeit.Number(100, 10000),
-		PaymentDt:    time.Now().Unix(),
-		Bank:         gofakeit.Company(),
-		DeliveryCost: gofakeit.Number(10, 500),
-		GoodsTotal:   gofakeit.Number(50, 5000),
-		CustomFee:    gofakeit.Number(0, 100),
-	}
-
 	// Генерация данных для items
 	for i := 0; i < gofakeit.Number(1, 5); i++ { // Случайное количество товаров в заказе
+		price, sale := gofakeit.Number(100, 1000), gofakeit.Number(0, 50)
 		order.Items = append(order.Items, model.Item{
 			ChrtID:      gofakeit.Number(1000, 9999),
 			TrackNumber: gofakeit.Word(),
-			Price:       gofakeit.Number(100, 1000),
+			Price:       price,
 			Rid:         gofakeit.UUID(),
 			Name:        gofakeit.Word(),
-			Sale:        gofakeit.Number(0, 50),
+			Sale:        sale,
 			Size:        gofakeit.Letter(),
-			TotalPrice:  gofakeit.Number(100, 2000),
+			TotalPrice:  price * (100 - sale) / 100,
 			NmID:        gofakeit.Number(100000, 999999),
 			Brand:       gofakeit.Company(),
 			Status:      gofakeit.Number(1, 3),
 		})
 	}
 
+	// Генерация данных для payments
+	order.Payment = model.Payment{
+		Transaction:  gofakeit.UUID(),
+		RequestID:    gofakeit.UUID(),
+		Currency:     gofakeit.CurrencyShort(),
+		Provider:     gofakeit.Company(),
+		PaymentDt:    time.Now().Unix(),
+		Bank:         gofakeit.Company(),
+		DeliveryCost: gofakeit.Number(10, 500),
+		CustomFee:    gofakeit.Number(0, 100),
+	}
+	// Суммы оплаты согласованы с товарами, чтобы заказ проходил валидацию
+	for _, item := range order.Items {
+		order.Payment.GoodsTotal += item.TotalPrice
+	}
+	order.Payment.Amount = order.Payment.GoodsTotal + order.Payment.DeliveryCost
+
 	return order
 }
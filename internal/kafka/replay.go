@@ -0,0 +1,147 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/dto"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/service"
+	"l0_wb/internal/util"
+)
+
+// ReplayStats содержит итоги воспроизведения.
+type ReplayStats struct {
+	Total     int // Заказов найдено в БД
+	Processed int // Заказов успешно прошло конвейер
+	Invalid   int // Заказов не прошло валидацию
+	Failed    int // Заказов, на которых произошла ошибка чтения, декодирования или сохранения
+}
+
+// Replayer прогоняет сохранённые в БД заказы через конвейер обработки консьюмера.
+//
+//	Каждый заказ читается из БД, сериализуется так же, как приходит из Kafka,
+//	и проходит декодирование и валидацию. Это позволяет проверить новые правила
+//	валидации на исторических данных до их включения. Если sink не задан,
+//	заказы не сохраняются (dry-run); иначе они сохраняются через sink, например
+//	в отдельную схему БД.
+type Replayer struct {
+	orders repository.OrdersRepository
+	source service.OrderService
+	sink   service.OrderService
+	rate   int
+	logger *zap.Logger
+}
+
+// NewReplayer создаёт новый экземпляр Replayer.
+//
+//	Параметры:
+//	- orders: репозиторий заказов рабочей БД, из которой читается список заказов.
+//	- source: сервис для чтения полных заказов из рабочей БД.
+//	- sink: сервис для сохранения заказов (nil — режим dry-run).
+//	- rate: скорость воспроизведения, заказов в секунду.
+//	Возвращает:
+//	- *Replayer: экземпляр Replayer.
+func NewReplayer(orders repository.OrdersRepository, source, sink service.OrderService, rate int) *Replayer {
+	if rate < 1 {
+		rate = 1
+	}
+	return &Replayer{
+		orders: orders,
+		source: source,
+		sink:   sink,
+		rate:   rate,
+		logger: util.GetLogger(),
+	}
+}
+
+// Run воспроизводит все заказы из БД с заданной скоростью.
+//
+//	Параметры:
+//	- ctx: контекст выполнения для остановки воспроизведения.
+//	Возвращает:
+//	- ReplayStats: итоги воспроизведения.
+//	- error: ошибку, если не удалось получить список заказов или контекст был отменён.
+func (r *Replayer) Run(ctx context.Context) (ReplayStats, error) {
+	var stats ReplayStats
+
+	orderUIDs, err := r.orders.GetAllOrderIDs(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("failed to fetch order UIDs: %w", err)
+	}
+	stats.Total = len(orderUIDs)
+	r.logger.Info("Replay started",
+		zap.Int("orders", stats.Total),
+		zap.Int("rate", r.rate),
+		zap.Bool("dry_run", r.sink == nil),
+	)
+
+	// При rate больше 1e9 интервал округляется до нуля, а NewTicker не принимает нулевой интервал
+	ticker := time.NewTicker(max(time.Second/time.Duration(r.rate), time.Nanosecond))
+	defer ticker.Stop()
+
+	for _, uid := range orderUIDs {
+		select {
+		case <-ctx.Done():
+			return stats, ctx.Err()
+		case <-ticker.C:
+		}
+
+		order, err := r.replayOrder(ctx, uid)
+		switch {
+		case err != nil:
+			stats.Failed++
+			r.logger.Warn("Replay failed for order", zap.String("order_uid", uid), zap.Error(err))
+		case order == nil:
+			stats.Invalid++
+		default:
+			stats.Processed++
+		}
+	}
+
+	r.logger.Info("Replay finished",
+		zap.Int("total", stats.Total),
+		zap.Int("processed", stats.Processed),
+		zap.Int("invalid", stats.Invalid),
+		zap.Int("failed", stats.Failed),
+	)
+	return stats, nil
+}
+
+// replayOrder прогоняет один заказ через конвейер.
+//
+//	Возвращает:
+//	- *model.Order: обработанный заказ или nil, если заказ не прошёл валидацию.
+//	- error: ошибку чтения, декодирования или сохранения.
+func (r *Replayer) replayOrder(ctx context.Context, orderUID string) (*model.Order, error) {
+	stored, err := r.source.GetOrderByID(ctx, orderUID)
+	if err != nil {
+		return nil, fmt.Errorf("load order: %w", err)
+	}
+
+	// Сериализуем заказ в формат сообщения Kafka и декодируем его тем же кодом, что и консьюмер.
+	data, err := json.Marshal(dto.NewKafkaOrder(stored))
+	if err != nil {
+		return nil, fmt.Errorf("encode order: %w", err)
+	}
+	order, err := decodeOrder(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode order: %w", err)
+	}
+
+	if err := service.ValidateOrder(order); err != nil {
+		r.logger.Info("Replayed order failed validation", zap.String("order_uid", orderUID), zap.Error(err))
+		return nil, nil
+	}
+
+	if r.sink != nil {
+		if err := r.sink.SaveOrder(service.WithActor(ctx, "replay"), order); err != nil {
+			return nil, fmt.Errorf("save order: %w", err)
+		}
+	}
+	return order, nil
+}
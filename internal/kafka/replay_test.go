@@ -0,0 +1,110 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository/mocks"
+	"l0_wb/internal/service"
+	"l0_wb/internal/util"
+)
+
+// replayOrderService — OrderService, который отдаёт заказы из orders и запоминает сохранённые.
+type replayOrderService struct {
+	service.OrderService
+	orders map[string]*model.Order
+	saved  []string
+}
+
+func (s *replayOrderService) GetOrderByID(_ context.Context, orderUID string) (*model.Order, error) {
+	if o, ok := s.orders[orderUID]; ok {
+		return o, nil
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (s *replayOrderService) SaveOrder(_ context.Context, order *model.Order) error {
+	if order.OrderUID == "broken-save" {
+		return errors.New("constraint violation")
+	}
+	s.saved = append(s.saved, order.OrderUID)
+	return nil
+}
+
+// replayOrder возвращает заказ, проходящий валидацию.
+func replayOrder(uid string) *model.Order {
+	return &model.Order{
+		OrderUID: uid,
+		Delivery: model.Delivery{Name: "Test Testov", Phone: "+9720000000", Zip: "2639809", Email: "test@gmail.com"},
+		Payment:  model.Payment{Currency: "USD", Amount: 1817, DeliveryCost: 1500, GoodsTotal: 317},
+		Items:    []model.Item{{ChrtID: 9934930, Price: 453, Sale: 30, TotalPrice: 317}},
+	}
+}
+
+// newTestReplayer создаёт Replayer по заказам source с максимальной скоростью.
+func newTestReplayer(t *testing.T, source *replayOrderService, uids []string, sink service.OrderService) *Replayer {
+	t.Helper()
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	repo := &mocks.OrdersRepositoryMock{
+		GetAllOrderIDsFunc: func(context.Context) ([]string, error) { return uids, nil },
+	}
+	// Скорость больше 1e9 заказов в секунду не должна обнулять интервал тикера
+	return NewReplayer(repo, source, sink, math.MaxInt)
+}
+
+// TestReplayerDryRun проверяет, что в режиме dry-run заказы только проверяются, а невалидные учитываются отдельно.
+func TestReplayerDryRun(t *testing.T) {
+	invalid := replayOrder("invalid")
+	invalid.Delivery.Phone = "call me"
+	source := &replayOrderService{orders: map[string]*model.Order{
+		"valid":   replayOrder("valid"),
+		"invalid": invalid,
+	}}
+
+	stats, err := newTestReplayer(t, source, []string{"valid", "invalid", "missing"}, nil).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := ReplayStats{Total: 3, Processed: 1, Invalid: 1, Failed: 1}
+	if stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+	if len(source.saved) != 0 {
+		t.Errorf("dry run must not save orders, saved %v", source.saved)
+	}
+}
+
+// TestReplayerScratch проверяет сохранение прошедших валидацию заказов в sink.
+func TestReplayerScratch(t *testing.T) {
+	invalid := replayOrder("invalid")
+	invalid.Items = nil
+	source := &replayOrderService{orders: map[string]*model.Order{
+		"first":       replayOrder("first"),
+		"second":      replayOrder("second"),
+		"invalid":     invalid,
+		"broken-save": replayOrder("broken-save"),
+	}}
+	sink := &replayOrderService{}
+
+	stats, err := newTestReplayer(t, source, []string{"first", "invalid", "second", "broken-save"}, sink).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := ReplayStats{Total: 4, Processed: 2, Invalid: 1, Failed: 1}
+	if stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+	if len(sink.saved) != 2 || sink.saved[0] != "first" || sink.saved[1] != "second" {
+		t.Errorf("expected first and second saved into sink, got %v", sink.saved)
+	}
+	if len(source.saved) != 0 {
+		t.Errorf("orders must not be saved into the source, saved %v", source.saved)
+	}
+
+}
@@ -0,0 +1,104 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"l0_wb/internal/tracing"
+)
+
+// headerCarrier передаёт контекст трассировки в заголовках сообщения Kafka.
+type headerCarrier struct {
+	headers *[]kafka.Header
+}
+
+// Get возвращает значение заголовка.
+func (c headerCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set задаёт значение заголовка, заменяя существующее.
+func (c headerCarrier) Set(key, value string) {
+	for i, h := range *c.headers {
+		if h.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys возвращает имена заголовков.
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, h := range *c.headers {
+		keys[i] = h.Key
+	}
+	return keys
+}
+
+// writeMessages отправляет сообщения в спане публикации.
+//
+//	Контекст трассировки добавляется в заголовки каждого сообщения, поэтому
+//	обработка у потребителя продолжает трассировку отправителя.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- w: Kafka writer с заданным топиком.
+//	- messages: сообщения.
+//	Возвращает:
+//	- error: ошибку отправки.
+func writeMessages(ctx context.Context, w *kafka.Writer, messages ...kafka.Message) (err error) {
+	ctx, span := tracing.Start(ctx, w.Topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(w.Topic),
+			semconv.MessagingBatchMessageCount(len(messages)),
+		),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	propagator := otel.GetTextMapPropagator()
+	for i := range messages {
+		propagator.Inject(ctx, headerCarrier{headers: &messages[i].Headers})
+	}
+	return w.WriteMessages(ctx, messages...)
+}
+
+// startReceive начинает спан получения сообщения.
+//
+//	Родителем спана становится контекст трассировки из заголовков сообщения.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- m: полученное сообщение.
+//	- group: группа потребителей.
+//	Возвращает:
+//	- trace.Span: спан; завершается вызовом tracing.End.
+func startReceive(ctx context.Context, m kafka.Message, group string) trace.Span {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier{headers: &m.Headers})
+	_, span := tracing.Start(ctx, m.Topic+" receive",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeReceive,
+			semconv.MessagingDestinationName(m.Topic),
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(m.Partition)),
+			semconv.MessagingKafkaMessageOffset(int(m.Offset)),
+			semconv.MessagingKafkaMessageKey(string(m.Key)),
+			semconv.MessagingKafkaConsumerGroup(group),
+		),
+	)
+	return span
+}
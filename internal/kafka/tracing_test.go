@@ -0,0 +1,40 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestTraceContextPropagation проверяет, что спан получения продолжает трассировку из заголовков сообщения.
+func TestTraceContextPropagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "publish")
+	m := kafka.Message{Topic: "orders", Key: []byte("order-1"), Headers: []kafka.Header{{Key: "traceparent", Value: []byte("stale")}}}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier{headers: &m.Headers})
+	parent.End()
+	if len(m.Headers) != 1 {
+		t.Fatalf("expected traceparent header to be replaced, got %v", m.Headers)
+	}
+
+	span := startReceive(context.Background(), m, "orders_group")
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	receive := spans[1]
+	if receive.Name() != "orders receive" || receive.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("expected receive span to be a child of the publish span, got %q with parent %v", receive.Name(), receive.Parent().SpanID())
+	}
+}
@@ -0,0 +1,100 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+	"l0_wb/internal/util"
+)
+
+// saslMechanism возвращает механизм SASL/PLAIN, если в конфигурации задан пользователь.
+//
+//	Параметры:
+//	- cfg: конфигурация приложения.
+//	Возвращает:
+//	- sasl.Mechanism: механизм аутентификации или nil, если аутентификация не настроена.
+func saslMechanism(cfg *config.Config) sasl.Mechanism {
+	if cfg.KafkaSASLUser == "" {
+		return nil
+	}
+	return rotatingPlain{cfg: cfg, logger: util.GetLogger()}
+}
+
+// rotatingPlain — механизм SASL/PLAIN, получающий учётные данные при каждом подключении к брокеру.
+//
+//	Значения берутся через cfg.Secret, поэтому пароль, сменённый в Vault или
+//	файле KAFKA_SASL_PASSWORD_FILE, применяется при следующем подключении.
+type rotatingPlain struct {
+	cfg    *config.Config
+	logger *zap.Logger
+}
+
+// Name возвращает имя механизма.
+func (m rotatingPlain) Name() string {
+	return plain.Mechanism{}.Name()
+}
+
+// Start начинает аутентификацию с актуальными учётными данными.
+func (m rotatingPlain) Start(ctx context.Context) (sasl.StateMachine, []byte, error) {
+	user, err := m.cfg.Secret("KAFKA_SASL_USER", m.cfg.KafkaSASLUser)
+	if err != nil {
+		m.logger.Warn("Failed to refresh Kafka credentials", zap.Error(err))
+	}
+	password, err := m.cfg.Secret("KAFKA_SASL_PASSWORD", m.cfg.KafkaSASLPassword)
+	if err != nil {
+		m.logger.Warn("Failed to refresh Kafka credentials", zap.Error(err))
+	}
+	return plain.Mechanism{Username: user, Password: password}.Start(ctx)
+}
+
+// newDialer создаёт Dialer для Kafka reader с учётом параметров аутентификации.
+func newDialer(cfg *config.Config) *kafka.Dialer {
+	return &kafka.Dialer{
+		Timeout:       10 * time.Second,
+		DualStack:     true,
+		SASLMechanism: saslMechanism(cfg),
+	}
+}
+
+// NewWriter создаёт Kafka writer для указанного топика с учётом параметров аутентификации.
+//
+//	Параметры:
+//	- cfg: конфигурация приложения.
+//	- topic: топик для публикации сообщений.
+//	Возвращает:
+//	- *kafka.Writer: настроенный writer; закрывается вызывающей стороной.
+func NewWriter(cfg *config.Config, topic string) *kafka.Writer {
+	return &kafka.Writer{
+		Addr:      kafka.TCP(cfg.KafkaBrokers...),
+		Topic:     topic,
+		Balancer:  &kafka.LeastBytes{},
+		Transport: &kafka.Transport{SASL: saslMechanism(cfg)},
+	}
+}
+
+// Ping проверяет доступность брокеров Kafka.
+//
+//	Параметры:
+//	- ctx: контекст выполнения, ограничивающий время проверки.
+//	- cfg: конфигурация приложения (брокеры, SASL).
+//	Возвращает:
+//	- error: ошибку, если ни к одному брокеру не удалось подключиться.
+func Ping(ctx context.Context, cfg *config.Config) error {
+	dialer := newDialer(cfg)
+	var errs []error
+	for _, broker := range cfg.KafkaBrokers {
+		conn, err := dialer.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return conn.Close()
+	}
+	return errors.Join(errs...)
+}
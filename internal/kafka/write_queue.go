@@ -0,0 +1,144 @@
+package kafka
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"l0_wb/internal/model"
+)
+
+// writeQueue — ограниченная очередь заказов между чтением Kafka и сохранением в БД.
+//
+//	Заказы распределяются между обработчиками по order_uid, поэтому изменения
+//	одного заказа сохраняются в порядке чтения. Каждый обработчик копит батч
+//	до batchSize заказов или batchTimeout с момента первого заказа и передаёт
+//	его в save. Если очередь обработчика заполнена, push блокируется, и чтение
+//	Kafka приостанавливается, пока БД не разберёт накопившиеся заказы.
+type writeQueue struct {
+	shards       []chan *model.Order
+	batchSize    atomic.Int64
+	batchTimeout time.Duration
+	save         func(ctx context.Context, batch []*model.Order)
+	wg           sync.WaitGroup
+}
+
+// newWriteQueue создаёт очередь записи.
+//
+//	Параметры:
+//	- workers: число обработчиков.
+//	- capacity: общая ёмкость очереди; делится между обработчиками поровну.
+//	- batchSize: максимальный размер батча.
+//	- batchTimeout: максимальное ожидание заполнения батча.
+//	- save: сохранение батча; вызывается из горутины обработчика.
+//	Возвращает:
+//	- *writeQueue: очередь, готовая к запуску start.
+func newWriteQueue(workers, capacity, batchSize int, batchTimeout time.Duration, save func(context.Context, []*model.Order)) *writeQueue {
+	workers = max(workers, 1)
+	perShard := max(capacity/workers, 1)
+	q := &writeQueue{
+		shards:       make([]chan *model.Order, workers),
+		batchTimeout: batchTimeout,
+		save:         save,
+	}
+	q.setBatchSize(batchSize)
+	for i := range q.shards {
+		q.shards[i] = make(chan *model.Order, perShard)
+	}
+	return q
+}
+
+// setBatchSize меняет максимальный размер батча; применяется к батчам, собираемым после вызова.
+func (q *writeQueue) setBatchSize(n int) {
+	q.batchSize.Store(int64(max(n, 1)))
+}
+
+// start запускает обработчиков.
+//
+//	Параметры:
+//	- ctx: контекст, передаваемый в save; обработчики завершаются только после close.
+func (q *writeQueue) start(ctx context.Context) {
+	for _, shard := range q.shards {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			q.work(ctx, shard)
+		}()
+	}
+}
+
+// push добавляет заказ в очередь, ожидая свободного места.
+//
+//	Параметры:
+//	- ctx: контекст ожидания.
+//	- order: заказ.
+//	Возвращает:
+//	- error: ошибку контекста, если он отменён до постановки заказа в очередь.
+func (q *writeQueue) push(ctx context.Context, order *model.Order) error {
+	select {
+	case q.shard(order.OrderUID) <- order:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close закрывает очередь и ждёт, пока обработчики сохранят оставшиеся заказы.
+func (q *writeQueue) close() {
+	for _, shard := range q.shards {
+		close(shard)
+	}
+	q.wg.Wait()
+}
+
+// len возвращает число заказов, ожидающих сохранения.
+func (q *writeQueue) len() int {
+	n := 0
+	for _, shard := range q.shards {
+		n += len(shard)
+	}
+	return n
+}
+
+// shard возвращает очередь обработчика заказа.
+func (q *writeQueue) shard(orderUID string) chan *model.Order {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(orderUID))
+	return q.shards[h.Sum32()%uint32(len(q.shards))]
+}
+
+// work собирает батчи из очереди обработчика и сохраняет их до закрытия очереди.
+func (q *writeQueue) work(ctx context.Context, shard <-chan *model.Order) {
+	batch := make([]*model.Order, 0, q.batchSize.Load())
+	timer := time.NewTimer(q.batchTimeout)
+	timer.Stop()
+	flush := func() {
+		timer.Stop()
+		if len(batch) == 0 {
+			return
+		}
+		q.save(ctx, batch)
+		batch = make([]*model.Order, 0, q.batchSize.Load())
+	}
+
+	for {
+		select {
+		case order, ok := <-shard:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, order)
+			if len(batch) == 1 {
+				timer.Reset(q.batchTimeout)
+			}
+			if int64(len(batch)) >= q.batchSize.Load() {
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"l0_wb/internal/model"
+)
+
+// TestWriteQueueBatchesAndOrder проверяет сбор батчей, порядок заказов одного order_uid и сохранение остатка при закрытии.
+func TestWriteQueueBatchesAndOrder(t *testing.T) {
+	var mu sync.Mutex
+	var saved []*model.Order
+	batches := 0
+	q := newWriteQueue(3, 30, 4, time.Hour, func(_ context.Context, batch []*model.Order) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(batch) > 4 {
+			t.Errorf("batch of %d orders exceeds batch size", len(batch))
+		}
+		batches++
+		saved = append(saved, batch...)
+	})
+	ctx := context.Background()
+	q.start(ctx)
+
+	for i := range 20 {
+		order := &model.Order{OrderUID: fmt.Sprintf("order-%d", i%5), TrackNumber: fmt.Sprint(i)}
+		if err := q.push(ctx, order); err != nil {
+			t.Fatalf("push failed: %v", err)
+		}
+	}
+	q.close()
+
+	if len(saved) != 20 {
+		t.Fatalf("expected 20 saved orders, got %d", len(saved))
+	}
+	last := make(map[string]int)
+	for _, o := range saved {
+		var n int
+		fmt.Sscan(o.TrackNumber, &n)
+		if prev, ok := last[o.OrderUID]; ok && n < prev {
+			t.Errorf("order %s saved out of order: %d after %d", o.OrderUID, n, prev)
+		}
+		last[o.OrderUID] = n
+	}
+	if batches < 5 {
+		t.Errorf("expected orders to be saved in batches, got %d batches", batches)
+	}
+}
+
+// TestWriteQueueBackpressure проверяет, что push блокируется на заполненной очереди.
+func TestWriteQueueBackpressure(t *testing.T) {
+	release := make(chan struct{})
+	q := newWriteQueue(1, 1, 1, time.Millisecond, func(context.Context, []*model.Order) {
+		<-release
+	})
+	q.start(context.Background())
+	defer q.close()
+	defer close(release)
+
+	// Первый заказ занимает обработчик, второй — единственное место в очереди
+	for i := range 2 {
+		if err := q.push(context.Background(), &model.Order{OrderUID: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("push failed: %v", err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := q.push(ctx, &model.Order{OrderUID: "2"}); err == nil {
+		t.Fatal("expected push to block on a full queue")
+	}
+}
@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultOrderProcessingBuckets — границы корзин order_processing_duration_seconds по умолчанию, в секундах.
+//
+//	Сохранение батча под нагрузкой занимает секунды, поэтому верхняя граница — 30 с.
+var DefaultOrderProcessingBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// DefaultLatencyBuckets — границы корзин гистограмм задержек HTTP и БД по умолчанию, в секундах.
+var DefaultLatencyBuckets = prometheus.DefBuckets
+
+// Buckets — границы корзин гистограмм задержек; пустой список оставляет границы по умолчанию.
+type Buckets struct {
+	OrderProcessing []float64 // order_processing_duration_seconds и order_processing_stage_duration_seconds
+	HTTP            []float64 // http_response_time_seconds
+	DB              []float64 // database_query_duration_seconds и database_statement_duration_seconds
+}
+
+// newOrderProcessingTime создаёт гистограмму времени обработки заказов.
+func newOrderProcessingTime(buckets []float64) prometheus.Histogram {
+	return prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "order_processing_duration_seconds",
+			Help:    "Histogram of order processing times",
+			Buckets: buckets,
+		},
+	)
+}
+
+// newOrderStageDuration создаёт гистограмму времени этапов обработки заказов.
+func newOrderStageDuration(buckets []float64) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "order_processing_stage_duration_seconds",
+			Help:    "Duration of order processing stages in seconds",
+			Buckets: buckets,
+		},
+		[]string{"stage"},
+	)
+}
+
+// newHTTPResponseTime создаёт гистограмму времени ответа HTTP.
+func newHTTPResponseTime(buckets []float64) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_response_time_seconds",
+			Help:    "HTTP response time in seconds",
+			Buckets: buckets,
+		},
+		[]string{"method", "endpoint"},
+	)
+}
+
+// newDBQueryDuration создаёт гистограмму времени запросов репозиториев.
+func newDBQueryDuration(buckets []float64) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "database_query_duration_seconds",
+			Help:    "Database query duration in seconds",
+			Buckets: buckets,
+		},
+		[]string{"operation", "table"},
+	)
+}
+
+// newDBStatementDuration создаёт гистограмму времени отдельных SQL-команд.
+func newDBStatementDuration(buckets []float64) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "database_statement_duration_seconds",
+			Help:    "Duration of individual SQL statements in seconds",
+			Buckets: buckets,
+		},
+		[]string{"command", "status"},
+	)
+}
+
+// SetBuckets пересоздаёт гистограммы задержек с заданными границами корзин.
+//
+//	Накопленные наблюдения при этом теряются, поэтому SetBuckets вызывается
+//	при старте, до начала обработки запросов и сообщений.
+//	Параметры:
+//	- b: границы корзин; пустые списки не меняют соответствующие гистограммы.
+func SetBuckets(b Buckets) {
+	if len(b.OrderProcessing) > 0 {
+		OrderProcessingTime = replace(OrderProcessingTime, newOrderProcessingTime(b.OrderProcessing))
+		OrderStageDuration = replace(OrderStageDuration, newOrderStageDuration(b.OrderProcessing))
+	}
+	if len(b.HTTP) > 0 {
+		HTTPResponseTime = replace(HTTPResponseTime, newHTTPResponseTime(b.HTTP))
+	}
+	if len(b.DB) > 0 {
+		DBQueryDuration = replace(DBQueryDuration, newDBQueryDuration(b.DB))
+		DBStatementDuration = replace(DBStatementDuration, newDBStatementDuration(b.DB))
+	}
+}
+
+// replace заменяет метрику в реестре сервиса.
+func replace[C prometheus.Collector](old, next C) C {
+	registry.Unregister(old)
+	registry.MustRegister(next)
+	return next
+}
+
+// observe записывает наблюдение гистограммы.
+//
+//	Если в контексте есть сэмплированный спан, к наблюдению прикрепляется
+//	exemplar с trace_id, по которому Grafana открывает трассировку.
+func observe(ctx context.Context, o prometheus.Observer, value float64) {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsSampled() {
+		if eo, ok := o.(prometheus.ExemplarObserver); ok {
+			eo.ObserveWithExemplar(value, prometheus.Labels{"trace_id": sc.TraceID().String()})
+			return
+		}
+	}
+	o.Observe(value)
+}
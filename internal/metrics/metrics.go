@@ -2,12 +2,24 @@
 package metrics
 
 import (
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"context"
+	"fmt"
 	"net/http"
-	"runtime"
-	"sync"
+	"strconv"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"l0_wb/internal/buildinfo"
+)
+
+// Этапы обработки заказа (метка stage у order_processing_stage_duration_seconds).
+const (
+	StageDecode   = "decode"    // Декодирование сообщения Kafka
+	StageValidate = "validate"  // Валидация батча
+	StageDBSave   = "db_save"   // Сохранение батча в БД
+	StageCacheSet = "cache_set" // Запись заказа в кэш
 )
 
 // Метрики сервиса
@@ -21,12 +33,18 @@ var (
 	)
 
 	// OrderProcessingTime измеряет время обработки заказа (гистограмма).
-	OrderProcessingTime = prometheus.NewHistogram(
-		prometheus.HistogramOpts{
-			Name:    "order_processing_duration_seconds",
-			Help:    "Histogram of order processing times",
-			Buckets: prometheus.LinearBuckets(0.01, 0.05, 10),
+	OrderProcessingTime = newOrderProcessingTime(DefaultOrderProcessingBuckets)
+
+	// OrderStageDuration измеряет время этапов обработки заказов (см. Stage*).
+	OrderStageDuration = newOrderStageDuration(DefaultOrderProcessingBuckets)
+
+	// OrderResults считает заказы из Kafka по итогу обработки: saved, skipped_duplicate, superseded, invalid, failed, undecodable.
+	OrderResults = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "orders_results_total",
+			Help: "Total number of consumed orders by processing result",
 		},
+		[]string{"result"},
 	)
 
 	// OrderProcessingErrors считает общее количество ошибок при обработке заказов.
@@ -37,13 +55,38 @@ var (
 		},
 	)
 
+	// OrderAmountMismatches считает сохранённые заказы, сумма оплаты которых не сходится с товарами и доставкой.
+	OrderAmountMismatches = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "order_amount_mismatches_total",
+			Help: "Total number of saved orders whose payment amount does not match items and delivery cost",
+		},
+	)
+
+	// OrdersDeduplicated считает заказы, пропущенные как повторы уже сохранённых без обращения к транзакции.
+	OrdersDeduplicated = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "orders_deduplicated_total",
+			Help: "Total number of orders skipped as replays of recently saved identical orders",
+		},
+	)
+
+	// CacheCDCEvents считает заказы из уведомлений об изменениях в БД по итогу обновления кэша: refreshed, evicted, skipped, failed.
+	CacheCDCEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_cdc_events_total",
+			Help: "Total number of order change notifications by cache refresh result",
+		},
+		[]string{"result"},
+	)
+
 	// RPS (Requests Per Second) - счетчик запросов в секунду
 	RequestsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "http_requests_total",
 			Help: "Total number of HTTP requests",
 		},
-		[]string{"method", "endpoint", "status"},
+		[]string{"method", "endpoint", "status", "status_class"},
 	)
 
 	// TPS (Transactions Per Second) - счетчик транзакций в секунду
@@ -64,25 +107,77 @@ var (
 	)
 
 	// DBQueryDuration - время выполнения запросов к БД
-	DBQueryDuration = prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "database_query_duration_seconds",
-			Help:    "Database query duration in seconds",
-			Buckets: prometheus.DefBuckets,
+	DBQueryDuration = newDBQueryDuration(DefaultLatencyBuckets)
+
+	// DBStatementDuration - время выполнения отдельных SQL-команд по типу команды
+	DBStatementDuration = newDBStatementDuration(DefaultLatencyBuckets)
+
+	// DBSlowStatements - количество SQL-команд, превысивших порог медленного запроса
+	DBSlowStatements = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "database_slow_statements_total",
+			Help: "Total number of SQL statements slower than the configured threshold",
 		},
-		[]string{"operation", "table"},
+		[]string{"command"},
 	)
 
-	// ResponseTime - время ответа HTTP запросов
-	HTTPResponseTime = prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "http_response_time_seconds",
-			Help:    "HTTP response time in seconds",
-			Buckets: prometheus.DefBuckets,
+	// DBRetries - количество повторов транзакций после временных ошибок БД
+	DBRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "database_retries_total",
+			Help: "Total number of transaction retries after transient database errors",
+		},
+		[]string{"operation", "reason"},
+	)
+
+	// DBRetriesExhausted - количество операций, не выполненных после всех повторов
+	DBRetriesExhausted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "database_retries_exhausted_total",
+			Help: "Total number of operations that failed after all retries",
+		},
+		[]string{"operation"},
+	)
+
+	// DBPoolAcquireCount - количество успешных получений соединения из пула с момента старта
+	DBPoolAcquireCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "database_pool_acquire_count",
+			Help: "Cumulative number of successful connection acquires from the pool",
+		},
+		[]string{"pool"},
+	)
+
+	// DBPoolEmptyAcquireCount - количество получений соединения, ожидавших свободного соединения
+	DBPoolEmptyAcquireCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "database_pool_empty_acquire_count",
+			Help: "Cumulative number of acquires that waited because the pool had no idle connection",
+		},
+		[]string{"pool"},
+	)
+
+	// DBPoolAcquireWaitSeconds - суммарное время получения соединений из пула
+	DBPoolAcquireWaitSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "database_pool_acquire_wait_seconds",
+			Help: "Cumulative time spent acquiring connections from the pool in seconds",
+		},
+		[]string{"pool"},
+	)
+
+	// DBPoolConns - текущее число соединений пула по состоянию
+	DBPoolConns = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "database_pool_conns",
+			Help: "Current number of pool connections by state (acquired, idle, constructing, total, max)",
 		},
-		[]string{"method", "endpoint"},
+		[]string{"pool", "state"},
 	)
 
+	// ResponseTime - время ответа HTTP запросов
+	HTTPResponseTime = newHTTPResponseTime(DefaultLatencyBuckets)
+
 	// ErrorRate - процент ошибок
 	ErrorsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -101,27 +196,89 @@ var (
 		[]string{"direction"}, // in, out
 	)
 
-	// CPU Usage
-	CPUUsage = prometheus.NewGauge(
+	// HTTPCompressedResponses - количество сжатых HTTP-ответов
+	HTTPCompressedResponses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_compressed_responses_total",
+			Help: "Total number of compressed HTTP responses",
+		},
+		[]string{"encoding"},
+	)
+
+	// HTTPCompressionSavedBytes - байты, сэкономленные сжатием ответов
+	HTTPCompressionSavedBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_compression_saved_bytes_total",
+			Help: "Total number of bytes saved by HTTP response compression",
+		},
+		[]string{"encoding"},
+	)
+
+	// DependencyIncidentOpen - признак открытого инцидента по зависимости (1 — открыт)
+	DependencyIncidentOpen = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "cpu_usage_percent",
-			Help: "Current CPU usage in percent",
+			Name: "dependency_incident_open",
+			Help: "Whether an incident is currently open for the dependency (1 - open, 0 - closed)",
 		},
+		[]string{"dependency"},
 	)
 
-	// Memory Usage
-	MemoryUsage = prometheus.NewGauge(
+	// DependencyIncidentsTotal - количество открытых инцидентов по зависимости
+	DependencyIncidentsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dependency_incidents_total",
+			Help: "Total number of incidents opened for the dependency",
+		},
+		[]string{"dependency"},
+	)
+
+	// CircuitBreakerState - состояние автомата защиты зависимости (0 — замкнут, 1 — полуоткрыт, 2 — разомкнут)
+	CircuitBreakerState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "circuit_breaker_state",
+			Help: "Circuit breaker state of the dependency (0 - closed, 1 - half-open, 2 - open)",
+		},
+		[]string{"dependency"},
+	)
+
+	// CircuitBreakerRejected - количество вызовов, отклонённых разомкнутым автоматом защиты
+	CircuitBreakerRejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "circuit_breaker_rejected_total",
+			Help: "Total number of calls rejected by an open circuit breaker",
+		},
+		[]string{"dependency"},
+	)
+
+	// LiveFeedSubscribers - количество подключённых клиентов потока заказов (WebSocket, SSE)
+	LiveFeedSubscribers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "live_feed_subscribers",
+			Help: "Current number of live order feed subscribers",
+		},
+	)
+
+	// LiveFeedDropped - количество заказов, не доставленных медленным подписчикам
+	LiveFeedDropped = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "live_feed_dropped_total",
+			Help: "Total number of orders dropped for slow live feed subscribers",
+		},
+	)
+
+	// CPU Usage - загрузка CPU процессом в процентах от всех ядер
+	CPUUsage = prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Name: "memory_usage_bytes",
-			Help: "Current memory usage in bytes",
+			Name: "cpu_usage_percent",
+			Help: "CPU usage of the process in percent of all cores",
 		},
 	)
 
-	// Disk Usage
+	// Disk Usage - занятое место на файловых системах каталогов METRICS_DISK_PATHS
 	DiskUsage = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "disk_usage_bytes",
-			Help: "Current disk usage in bytes",
+			Help: "Used space of the filesystem in bytes",
 		},
 		[]string{"device", "mountpoint"},
 	)
@@ -143,79 +300,173 @@ var (
 		[]string{"queue_name"},
 	)
 
-	// Goroutines Count
-	GoroutinesCount = prometheus.NewGauge(
+	// BuildInfo всегда равна 1; сведения о сборке передаются в метках.
+	BuildInfo = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "goroutines_count",
-			Help: "Current number of goroutines",
+			Name: "app_build_info",
+			Help: "Build information of the running binary; always 1",
 		},
+		[]string{"version", "commit", "build_date", "go_version"},
 	)
 )
 
-// Init инициализирует метрики и регистрирует их в Prometheus.
-func Init() {
-	// Регистрация существующих метрик
-	prometheus.MustRegister(OrdersProcessed)
-	prometheus.MustRegister(OrderProcessingTime)
-	prometheus.MustRegister(OrderProcessingErrors)
-
-	// Регистрация новых метрик
-	prometheus.MustRegister(RequestsTotal)
-	prometheus.MustRegister(TransactionsTotal)
-	prometheus.MustRegister(QueriesTotal)
-	prometheus.MustRegister(DBQueryDuration)
-	prometheus.MustRegister(HTTPResponseTime)
-	prometheus.MustRegister(ErrorsTotal)
-	prometheus.MustRegister(NetworkTrafficBytes)
-	prometheus.MustRegister(CPUUsage)
-	prometheus.MustRegister(MemoryUsage)
-	prometheus.MustRegister(DiskUsage)
-	prometheus.MustRegister(Uptime)
-	prometheus.MustRegister(QueueSize)
-	prometheus.MustRegister(GoroutinesCount)
-
-	// Запуск обновления метрик времени работы
+// registry — реестр метрик сервиса, отдаваемый Handler.
+var registry = newRegistry()
+
+// newRegistry создаёт реестр со всеми метриками сервиса и стандартными
+// метриками среды выполнения Go (go_*) и процесса (process_*).
+//
+//	Используется отдельный реестр вместо глобального prometheus.DefaultRegisterer:
+//	метрики регистрируются один раз при загрузке пакета, поэтому повторный
+//	вызов Init (например, в тестах) не приводит к ошибке повторной регистрации,
+//	а сторонние пакеты не добавляют в ответ свои метрики.
+func newRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+
+	// Метрики среды выполнения Go (горутины, память, GC) и процесса (CPU, память, дескрипторы)
+	r.MustRegister(collectors.NewGoCollector())
+	r.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	// Регистрация метрик обработки заказов
+	r.MustRegister(OrdersProcessed)
+	r.MustRegister(OrderProcessingTime)
+	r.MustRegister(OrderStageDuration)
+	r.MustRegister(OrderResults)
+	r.MustRegister(OrderProcessingErrors)
+	r.MustRegister(OrderAmountMismatches)
+	r.MustRegister(OrdersDeduplicated)
+	r.MustRegister(CacheCDCEvents)
+
+	// Регистрация метрик HTTP, БД и зависимостей
+	r.MustRegister(RequestsTotal)
+	r.MustRegister(TransactionsTotal)
+	r.MustRegister(QueriesTotal)
+	r.MustRegister(DBQueryDuration)
+	r.MustRegister(DBStatementDuration)
+	r.MustRegister(DBSlowStatements)
+	r.MustRegister(DBRetries)
+	r.MustRegister(DBRetriesExhausted)
+	r.MustRegister(DBPoolAcquireCount)
+	r.MustRegister(DBPoolEmptyAcquireCount)
+	r.MustRegister(DBPoolAcquireWaitSeconds)
+	r.MustRegister(DBPoolConns)
+	r.MustRegister(HTTPResponseTime)
+	r.MustRegister(ErrorsTotal)
+	r.MustRegister(NetworkTrafficBytes)
+	r.MustRegister(HTTPCompressedResponses)
+	r.MustRegister(HTTPCompressionSavedBytes)
+	r.MustRegister(DependencyIncidentOpen)
+	r.MustRegister(DependencyIncidentsTotal)
+	r.MustRegister(CircuitBreakerState)
+	r.MustRegister(CircuitBreakerRejected)
+	r.MustRegister(LiveFeedSubscribers)
+	r.MustRegister(LiveFeedDropped)
+	r.MustRegister(CPUUsage)
+	r.MustRegister(DiskUsage)
+	r.MustRegister(Uptime)
+	r.MustRegister(QueueSize)
+	r.MustRegister(BuildInfo)
+	return r
+}
+
+// Init публикует сведения о сборке и запускает периодическое обновление метрик
+// времени работы, загрузки CPU и заполнения дисков.
+//
+//	Параметры:
+//	- diskPaths: каталоги, заполнение дисков которых публикуется в disk_usage_bytes.
+func Init(diskPaths []string) {
+	info := buildinfo.Get()
+	BuildInfo.WithLabelValues(info.Version, info.Commit, info.BuildDate, info.GoVersion).Set(1)
+
+	system := newSystemCollector(diskPaths)
+
 	go func() {
 		ticker := time.NewTicker(1 * time.Second)
 		defer ticker.Stop()
 
 		for range ticker.C {
-			// Обновление Uptime
 			Uptime.Add(1)
-
-			// Обновление количества горутин
-			GoroutinesCount.Set(float64(runtime.NumGoroutine()))
-
-			// Обновление использования памяти
-			var memStats runtime.MemStats
-			runtime.ReadMemStats(&memStats)
-			MemoryUsage.Set(float64(memStats.Alloc))
-
-			// Здесь можно добавить обновление других метрик, требующих периодического обновления
+			system.collect()
 		}
 	}()
 }
 
-// StartMetricsServer запускает HTTP-сервер для экспорта метрик Prometheus.
-func StartMetricsServer(port string, wg *sync.WaitGroup) {
-	http.Handle("/metrics", promhttp.Handler())
+// Handler возвращает обработчик, отдающий метрики в формате Prometheus.
+//
+//	Exemplars гистограмм отдаются только в формате OpenMetrics, который
+//	Prometheus запрашивает при включённом хранении exemplars.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry, EnableOpenMetrics: true})
+}
+
+// StartMetricsServer запускает HTTP-сервер для экспорта метрик Prometheus и блокируется до завершения работы.
+//
+//	Параметры:
+//	- ctx: контекст, отмена которого запускает корректное завершение сервера.
+//	- addr: адрес сервера метрик в формате host:port (пустой host — все интерфейсы).
+//	- shutdownTimeout: время на завершение обработки текущих запросов.
+//	Возвращает:
+//	- error: ошибку запуска или завершения сервера.
+func StartMetricsServer(ctx context.Context, addr string, shutdownTimeout time.Duration) error {
+	// Используем отдельный маршрутизатор, чтобы не публиковать обработчики,
+	// зарегистрированные сторонними пакетами в http.DefaultServeMux (например, pprof).
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
 	server := &http.Server{
-		Addr:              ":" + port,
+		Addr:              addr,
+		Handler:           mux,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
+	errCh := make(chan error, 1)
 	go func() {
-		defer wg.Done()
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			panic("Failed to start metrics server: " + err.Error())
+			errCh <- fmt.Errorf("metrics server failed: %w", err)
 		}
 	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return err
+	}
+}
+
+// RecordHTTPRequest записывает метрику HTTP запроса; время ответа получает exemplar с trace_id запроса
+func RecordHTTPRequest(ctx context.Context, method, endpoint string, status int, duration time.Duration) {
+	RequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(status), statusClass(status)).Inc()
+	observe(ctx, HTTPResponseTime.WithLabelValues(method, endpoint), duration.Seconds())
+}
+
+// RecordOrderProcessing записывает время обработки батча заказов с exemplar trace_id
+func RecordOrderProcessing(ctx context.Context, duration time.Duration) {
+	observe(ctx, OrderProcessingTime, duration.Seconds())
 }
 
-// RecordHTTPRequest записывает метрику HTTP запроса
-func RecordHTTPRequest(method, endpoint string, status int, duration time.Duration) {
-	RequestsTotal.WithLabelValues(method, endpoint, string(rune(status))).Inc()
-	HTTPResponseTime.WithLabelValues(method, endpoint).Observe(duration.Seconds())
+// RecordOrderStage записывает время этапа обработки заказов с exemplar trace_id
+func RecordOrderStage(ctx context.Context, stage string, duration time.Duration) {
+	observe(ctx, OrderStageDuration.WithLabelValues(stage), duration.Seconds())
+}
+
+// RecordOrderResult учитывает итог обработки заказа из Kafka
+func RecordOrderResult(result string) {
+	OrderResults.WithLabelValues(result).Inc()
+}
+
+// RecordCacheCDCEvent учитывает итог обновления кэша по уведомлению об изменении заказа
+func RecordCacheCDCEvent(result string) {
+	CacheCDCEvents.WithLabelValues(result).Inc()
+}
+
+// statusClass возвращает класс HTTP-статуса: 2xx, 4xx, 5xx и т. п.
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
 }
 
 // RecordDBQuery записывает метрику запроса к базе данных
@@ -224,6 +475,42 @@ func RecordDBQuery(operation, table string, duration time.Duration) {
 	DBQueryDuration.WithLabelValues(operation, table).Observe(duration.Seconds())
 }
 
+// RecordDBStatement записывает метрики отдельной SQL-команды; время выполнения получает exemplar с trace_id
+func RecordDBStatement(ctx context.Context, command string, duration time.Duration, failed, slow bool) {
+	status := "ok"
+	if failed {
+		status = "error"
+	}
+	observe(ctx, DBStatementDuration.WithLabelValues(command, status), duration.Seconds())
+	if slow {
+		DBSlowStatements.WithLabelValues(command).Inc()
+	}
+}
+
+// DBPoolStats — снимок статистики пула соединений к БД.
+type DBPoolStats struct {
+	AcquireCount      int64         // Успешные получения соединения с момента старта
+	EmptyAcquireCount int64         // Получения, ожидавшие свободного соединения
+	AcquireDuration   time.Duration // Суммарное время получения соединений
+	AcquiredConns     int32         // Соединения, выданные в работу
+	IdleConns         int32         // Свободные соединения
+	ConstructingConns int32         // Устанавливаемые соединения
+	TotalConns        int32         // Все соединения пула
+	MaxConns          int32         // Максимальный размер пула
+}
+
+// SetDBPoolStats устанавливает метрики пула соединений к БД
+func SetDBPoolStats(pool string, s DBPoolStats) {
+	DBPoolAcquireCount.WithLabelValues(pool).Set(float64(s.AcquireCount))
+	DBPoolEmptyAcquireCount.WithLabelValues(pool).Set(float64(s.EmptyAcquireCount))
+	DBPoolAcquireWaitSeconds.WithLabelValues(pool).Set(s.AcquireDuration.Seconds())
+	DBPoolConns.WithLabelValues(pool, "acquired").Set(float64(s.AcquiredConns))
+	DBPoolConns.WithLabelValues(pool, "idle").Set(float64(s.IdleConns))
+	DBPoolConns.WithLabelValues(pool, "constructing").Set(float64(s.ConstructingConns))
+	DBPoolConns.WithLabelValues(pool, "total").Set(float64(s.TotalConns))
+	DBPoolConns.WithLabelValues(pool, "max").Set(float64(s.MaxConns))
+}
+
 // RecordTransaction записывает метрику транзакции
 func RecordTransaction() {
 	TransactionsTotal.Inc()
@@ -239,6 +526,29 @@ func RecordNetworkTraffic(direction string, bytes int) {
 	NetworkTrafficBytes.WithLabelValues(direction).Add(float64(bytes))
 }
 
+// RecordCompression записывает метрики сжатого HTTP-ответа
+func RecordCompression(encoding string, rawBytes, compressedBytes int) {
+	HTTPCompressedResponses.WithLabelValues(encoding).Inc()
+	if saved := rawBytes - compressedBytes; saved > 0 {
+		HTTPCompressionSavedBytes.WithLabelValues(encoding).Add(float64(saved))
+	}
+}
+
+// SetIncidentOpen отмечает открытие или закрытие инцидента по зависимости
+func SetIncidentOpen(dependency string, open bool) {
+	if open {
+		DependencyIncidentOpen.WithLabelValues(dependency).Set(1)
+		DependencyIncidentsTotal.WithLabelValues(dependency).Inc()
+		return
+	}
+	DependencyIncidentOpen.WithLabelValues(dependency).Set(0)
+}
+
+// SetCircuitBreakerState устанавливает текущее состояние автомата защиты зависимости
+func SetCircuitBreakerState(dependency string, state int) {
+	CircuitBreakerState.WithLabelValues(dependency).Set(float64(state))
+}
+
 // SetQueueSize устанавливает текущий размер очереди
 func SetQueueSize(queueName string, size int) {
 	QueueSize.WithLabelValues(queueName).Set(float64(size))
@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/trace"
+	"l0_wb/internal/util"
+)
+
+// TestRecordHTTPRequest проверяет метки статуса и класса статуса HTTP-запроса.
+func TestRecordHTTPRequest(t *testing.T) {
+	RecordHTTPRequest(context.Background(), "GET", "/api/v1/orders/{id}", 404, time.Millisecond)
+	RecordHTTPRequest(context.Background(), "GET", "/api/v1/orders/{id}", 503, time.Millisecond)
+
+	if got := testutil.ToFloat64(RequestsTotal.WithLabelValues("GET", "/api/v1/orders/{id}", "404", "4xx")); got != 1 {
+		t.Errorf("expected one 404 request, got %v", got)
+	}
+	if got := testutil.ToFloat64(RequestsTotal.WithLabelValues("GET", "/api/v1/orders/{id}", "503", "5xx")); got != 1 {
+		t.Errorf("expected one 503 request, got %v", got)
+	}
+	if got := statusClass(42); got != "unknown" {
+		t.Errorf("expected unknown class, got %q", got)
+	}
+}
+
+// TestSystemCollector проверяет определение точки монтирования и заполнение диска.
+func TestSystemCollector(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	c := newSystemCollector([]string{t.TempDir()})
+	if len(c.disks) != 1 || c.disks[0].mountpoint == "" {
+		t.Fatalf("expected disk path with mountpoint, got %+v", c.disks)
+	}
+	c.collect()
+	d := c.disks[0]
+	if got := testutil.ToFloat64(DiskUsage.WithLabelValues(d.device, d.mountpoint)); got <= 0 {
+		t.Errorf("expected used disk space, got %v", got)
+	}
+	if !containsPath("/", "/var/lib") || containsPath("/var/lib", "/var/library") {
+		t.Error("unexpected containsPath result")
+	}
+}
+
+// TestRegistry проверяет, что реестр сервиса содержит метрики среды выполнения Go и процесса.
+func TestRegistry(t *testing.T) {
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	names := make(map[string]bool, len(families))
+	for _, f := range families {
+		names[f.GetName()] = true
+	}
+	for _, name := range []string{"go_goroutines", "go_memstats_alloc_bytes", "process_start_time_seconds", "uptime_seconds_total"} {
+		if !names[name] {
+			t.Errorf("expected metric %s in registry", name)
+		}
+	}
+}
+
+// TestHistogramBucketsAndExemplars проверяет настройку корзин и exemplar с trace_id у времени ответа HTTP.
+func TestHistogramBucketsAndExemplars(t *testing.T) {
+	SetBuckets(Buckets{HTTP: []float64{0.1, 1}})
+	defer SetBuckets(Buckets{HTTP: DefaultLatencyBuckets})
+
+	traceID := trace.TraceID{1, 2, 3}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	}))
+	RecordHTTPRequest(ctx, "GET", "/api/v1/stats", 200, 300*time.Millisecond)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != "http_response_time_seconds" {
+			continue
+		}
+		buckets := f.GetMetric()[0].GetHistogram().GetBucket()
+		if len(buckets) != 2 {
+			t.Fatalf("expected 2 buckets, got %d", len(buckets))
+		}
+		exemplar := buckets[1].GetExemplar()
+		if exemplar == nil || exemplar.GetLabel()[0].GetValue() != traceID.String() {
+			t.Errorf("expected exemplar with trace_id %s, got %v", traceID, exemplar)
+		}
+		return
+	}
+	t.Fatal("http_response_time_seconds not found")
+}
+
+// TestRecordOrderStage проверяет, что гистограмма этапов использует корзины времени обработки заказов.
+func TestRecordOrderStage(t *testing.T) {
+	SetBuckets(Buckets{OrderProcessing: []float64{0.01, 0.1, 1}})
+	defer SetBuckets(Buckets{OrderProcessing: DefaultOrderProcessingBuckets})
+
+	RecordOrderStage(context.Background(), StageDBSave, 50*time.Millisecond)
+	RecordOrderResult("skipped_duplicate")
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != "order_processing_stage_duration_seconds" {
+			continue
+		}
+		m := f.GetMetric()[0]
+		if got := m.GetLabel()[0].GetValue(); got != StageDBSave {
+			t.Errorf("expected stage %q, got %q", StageDBSave, got)
+		}
+		if got := len(m.GetHistogram().GetBucket()); got != 3 {
+			t.Errorf("expected 3 buckets, got %d", got)
+		}
+		if got := testutil.ToFloat64(OrderResults.WithLabelValues("skipped_duplicate")); got != 1 {
+			t.Errorf("expected one skipped duplicate, got %v", got)
+		}
+		return
+	}
+	t.Fatal("order_processing_stage_duration_seconds not found")
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/process"
+	"go.uber.org/zap"
+	"l0_wb/internal/util"
+)
+
+// diskPath — каталог, заполнение файловой системы которого публикуется в DiskUsage.
+type diskPath struct {
+	path       string
+	device     string
+	mountpoint string
+}
+
+// systemCollector обновляет метрики загрузки CPU процессом и заполнения дисков.
+type systemCollector struct {
+	proc  *process.Process // nil, если сведения о процессе недоступны
+	disks []diskPath
+}
+
+// newSystemCollector создаёт сборщик системных метрик.
+//
+//	Для каждого каталога определяется точка монтирования, на которой он
+//	находится, и её устройство — они становятся метками DiskUsage.
+//	Параметры:
+//	- paths: каталоги, заполнение дисков которых нужно публиковать.
+//	Возвращает:
+//	- *systemCollector: сборщик; ошибки получения сведений пишутся в журнал.
+func newSystemCollector(paths []string) *systemCollector {
+	logger := util.GetLogger()
+	c := &systemCollector{}
+
+	proc, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		logger.Warn("CPU usage metrics are unavailable", zap.Error(err))
+	} else {
+		c.proc = proc
+		_, _ = proc.Percent(0) // Первый замер задаёт точку отсчёта
+	}
+
+	partitions, err := disk.Partitions(false)
+	if err != nil {
+		logger.Warn("Failed to list disk partitions", zap.Error(err))
+	}
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			logger.Warn("Invalid disk usage path", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		// Каталог находится на самой глубокой из содержащих его точек монтирования
+		d := diskPath{path: abs, mountpoint: abs}
+		longest := -1
+		for _, p := range partitions {
+			if containsPath(p.Mountpoint, abs) && len(p.Mountpoint) > longest {
+				longest = len(p.Mountpoint)
+				d.device, d.mountpoint = p.Device, p.Mountpoint
+			}
+		}
+		c.disks = append(c.disks, d)
+	}
+	return c
+}
+
+// containsPath сообщает, находится ли path внутри каталога dir.
+func containsPath(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// collect обновляет CPUUsage и DiskUsage.
+//
+//	Загрузка CPU считается с момента предыдущего вызова и приводится к доле
+//	всех ядер, поэтому значение лежит в пределах 0–100.
+func (c *systemCollector) collect() {
+	if c.proc != nil {
+		if percent, err := c.proc.Percent(0); err == nil {
+			CPUUsage.Set(percent / float64(runtime.NumCPU()))
+		}
+	}
+	for _, d := range c.disks {
+		usage, err := disk.Usage(d.path)
+		if err != nil {
+			continue
+		}
+		DiskUsage.WithLabelValues(d.device, d.mountpoint).Set(float64(usage.Used))
+	}
+}
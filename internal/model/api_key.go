@@ -0,0 +1,9 @@
+package model
+
+// APIKey представляет ключ доступа машинного клиента к API.
+type APIKey struct {
+	Name      string // Имя клиента, которому выдан ключ
+	KeyHash   string // SHA-256 хеш ключа в hex; сам ключ не хранится
+	RateLimit int    // Лимит запросов в секунду (0 — используется лимит по умолчанию)
+	Revoked   bool   // Признак отозванного ключа
+}
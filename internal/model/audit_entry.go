@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// AuditEntry представляет запись журнала аудита административных вызовов (таблица admin_audit_log).
+type AuditEntry struct {
+	ID         int64          `json:"id"`          // Идентификатор записи (0 — ещё не сохранена)
+	OccurredAt time.Time      `json:"occurred_at"` // Время вызова
+	Actor      string         `json:"actor"`       // Администратор: имя пользователя Basic-аутентификации или token
+	Method     string         `json:"method"`      // HTTP-метод
+	Path       string         `json:"path"`        // Путь запроса
+	Params     map[string]any `json:"params"`      // Параметры вызова: параметры пути и запроса, JSON-тело
+	Status     int            `json:"status"`      // HTTP-статус ответа
+	ClientIP   string         `json:"client_ip"`   // IP-адрес клиента
+	RequestID  string         `json:"request_id"`  // Идентификатор запроса (X-Request-ID)
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Incident представляет окно недоступности внешней зависимости (БД, Kafka).
+type Incident struct {
+	ID         int64      // Идентификатор инцидента (0 — ещё не сохранён)
+	Dependency string     // Имя зависимости
+	StartedAt  time.Time  // Время открытия инцидента
+	EndedAt    *time.Time // Время восстановления (nil — инцидент открыт)
+	LastError  string     // Последняя ошибка проверки
+}
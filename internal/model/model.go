@@ -59,4 +59,17 @@ type Order struct {
 	SmID              int       `json:"sm_id"`
 	DateCreated       time.Time `json:"date_created"`
 	OofShard          string    `json:"oof_shard"`
+
+	// Служебные отметки; задаются только через сервис и не считаются содержимым заказа
+	DeletedAt  *time.Time  `json:"deleted_at,omitempty"`  // Время мягкого удаления (nil — заказ не удалён)
+	ArchivedAt *time.Time  `json:"archived_at,omitempty"` // Время архивации (nil — заказ не в архиве)
+	Status     OrderStatus `json:"status,omitempty"`      // Этап жизненного цикла заказа
+
+	// AmountMismatch — расхождение payment.amount с суммой товаров и доставки сверх допуска
+	// (0 — суммы сходятся). Вычисляется сервисом при сохранении.
+	AmountMismatch int `json:"amount_mismatch,omitempty"`
+
+	// Аудит записи в БД; заполняются репозиторием и не считаются содержимым заказа
+	CreatedAt *time.Time `json:"created_at,omitempty"` // Время первого сохранения заказа (nil — заказ не сохранён)
+	UpdatedAt *time.Time `json:"updated_at,omitempty"` // Время последнего изменения записи заказа
 }
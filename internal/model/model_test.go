@@ -29,3 +29,30 @@ func TestOrder_Validation(t *testing.T) {
 		t.Errorf("expected items in the order > 0, got %d", len(order.Items))
 	}
 }
+
+// TestOrderStatusTransitions проверяет жизненный цикл заказа.
+func TestOrderStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to OrderStatus
+		ok       bool
+	}{
+		{StatusCreated, StatusPaid, true},
+		{StatusPaid, StatusShipped, true},
+		{StatusShipped, StatusDelivered, true},
+		{StatusDelivered, StatusCancelled, true},
+		{StatusCreated, StatusCancelled, true},
+		{StatusCreated, StatusShipped, false},
+		{StatusPaid, StatusCreated, false},
+		{StatusCancelled, StatusPaid, false},
+		{StatusPaid, StatusPaid, false},
+		{OrderStatus("lost"), StatusPaid, false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.ok {
+			t.Errorf("%s -> %s: expected %v, got %v", tt.from, tt.to, tt.ok, got)
+		}
+	}
+	if OrderStatus("lost").Valid() || !StatusCancelled.Valid() {
+		t.Error("unexpected Valid result")
+	}
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Действия над заказом, фиксируемые в журнале изменений.
+const (
+	ChangeInsert  = "insert"  // Заказ сохранён впервые
+	ChangeUpdate  = "update"  // Содержимое заказа изменилось
+	ChangeDelete  = "delete"  // Заказ мягко удалён
+	ChangeRestore = "restore" // С заказа снята отметка удаления
+	ChangeArchive = "archive" // Заказ помечен архивным
+	ChangeStatus  = "status"  // Статус заказа изменён
+	ChangeErase   = "erase"   // Персональные данные получателя удалены по запросу покупателя
+)
+
+// OrderChange представляет запись журнала изменений заказа (таблица order_events).
+type OrderChange struct {
+	ID         int64          `json:"id"`          // Идентификатор записи (0 — ещё не сохранена)
+	OrderUID   string         `json:"order_uid"`   // Идентификатор заказа
+	Action     string         `json:"action"`      // Действие: одна из констант Change*
+	Actor      string         `json:"actor"`       // Кто выполнил изменение, например kafka:orders или api_key:partner
+	OccurredAt time.Time      `json:"occurred_at"` // Время изменения
+	Diff       map[string]any `json:"diff"`        // Изменённые поля по путям в JSON-представлении заказа
+}
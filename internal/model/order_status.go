@@ -0,0 +1,46 @@
+package model
+
+// OrderStatus — этап жизненного цикла заказа.
+type OrderStatus string
+
+// Статусы заказа.
+const (
+	StatusCreated   OrderStatus = "created"   // Заказ принят; статус новых заказов
+	StatusPaid      OrderStatus = "paid"      // Заказ оплачен
+	StatusShipped   OrderStatus = "shipped"   // Заказ передан в доставку
+	StatusDelivered OrderStatus = "delivered" // Заказ доставлен покупателю
+	StatusCancelled OrderStatus = "cancelled" // Заказ отменён; конечный статус
+)
+
+// orderTransitions — допустимые переходы между статусами заказа.
+//
+//	Заказ проходит этапы created → paid → shipped → delivered и может быть
+//	отменён на любом из них; из cancelled переходов нет.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	StatusCreated:   {StatusPaid, StatusCancelled},
+	StatusPaid:      {StatusShipped, StatusCancelled},
+	StatusShipped:   {StatusDelivered, StatusCancelled},
+	StatusDelivered: {StatusCancelled},
+	StatusCancelled: {},
+}
+
+// Valid сообщает, является ли значение известным статусом заказа.
+func (s OrderStatus) Valid() bool {
+	_, ok := orderTransitions[s]
+	return ok
+}
+
+// Next возвращает статусы, в которые заказ может перейти из s.
+func (s OrderStatus) Next() []OrderStatus {
+	return orderTransitions[s]
+}
+
+// CanTransitionTo сообщает, допустим ли переход из s в next.
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// DayCount — число заказов и суммы оплат за день.
+type DayCount struct {
+	Day     string           `json:"day"` // Дата в формате YYYY-MM-DD (UTC)
+	Orders  int64            `json:"orders"`
+	Amounts []CurrencyAmount `json:"amounts"` // Суммы оплат по валютам в порядке убывания
+}
+
+// CurrencyAmount — число заказов и сумма оплат в валюте.
+type CurrencyAmount struct {
+	Currency string `json:"currency"`
+	Orders   int64  `json:"orders"`
+	Amount   int64  `json:"amount"`
+}
+
+// DeliveryServiceCount — число заказов и суммы оплат службы доставки.
+type DeliveryServiceCount struct {
+	DeliveryService string           `json:"delivery_service"`
+	Orders          int64            `json:"orders"`
+	Amounts         []CurrencyAmount `json:"amounts"` // Суммы оплат по валютам в порядке убывания
+}
+
+// OrderStats представляет сводную статистику по заказам.
+type OrderStats struct {
+	OrdersPerDay        []DayCount             `json:"orders_per_day"`
+	AmountByCurrency    []CurrencyAmount       `json:"amount_by_currency"`
+	TopDeliveryServices []DeliveryServiceCount `json:"top_delivery_services"`
+	AvgItemsPerOrder    float64                `json:"avg_items_per_order"`
+	GeneratedAt         time.Time              `json:"generated_at"`
+}
@@ -0,0 +1,119 @@
+// Package pagination provides signed opaque cursors for keyset pagination.
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// CurrentVersion — версия формата курсора, выпускаемого Signer.
+//
+//	При изменении набора ключей пагинации версия увеличивается, а курсоры
+//	старых версий отклоняются с ErrUnsupportedVersion.
+const CurrentVersion = 1
+
+// Ошибки разбора курсора.
+var (
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrExpiredCursor      = errors.New("cursor has expired")
+	ErrUnsupportedVersion = errors.New("unsupported cursor version")
+)
+
+// Cursor — позиция в выборке, упорядоченной по (date_created, order_uid).
+type Cursor struct {
+	DateCreated time.Time
+	OrderUID    string
+}
+
+// payload — содержимое курсора, защищённое подписью.
+type payload struct {
+	Version     int    `json:"v"`
+	DateCreated int64  `json:"t"`
+	OrderUID    string `json:"id"`
+	ExpiresAt   int64  `json:"exp"`
+}
+
+// Signer выпускает и проверяет курсоры, подписанные HMAC-SHA256.
+//
+//	Курсор непрозрачен для клиента: подделать позицию или продлить срок
+//	действия без знания секрета нельзя.
+type Signer struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewSigner создаёт Signer.
+//
+//	Параметры:
+//	- secret: секрет для подписи курсоров.
+//	- ttl: срок действия выпускаемых курсоров.
+//	Возвращает:
+//	- *Signer: экземпляр Signer.
+func NewSigner(secret []byte, ttl time.Duration) *Signer {
+	return &Signer{key: secret, ttl: ttl, now: time.Now}
+}
+
+// Encode кодирует позицию в подписанный курсор.
+//
+//	Параметры:
+//	- c: позиция последнего элемента страницы.
+//	Возвращает:
+//	- string: курсор в формате base64url(payload).base64url(signature).
+func (s *Signer) Encode(c Cursor) string {
+	data, _ := json.Marshal(payload{
+		Version:     CurrentVersion,
+		DateCreated: c.DateCreated.UnixNano(),
+		OrderUID:    c.OrderUID,
+		ExpiresAt:   s.now().Add(s.ttl).Unix(),
+	})
+	body := base64.RawURLEncoding.EncodeToString(data)
+	return body + "." + base64.RawURLEncoding.EncodeToString(s.sign(body))
+}
+
+// Decode проверяет подпись и срок действия курсора и возвращает позицию.
+//
+//	Параметры:
+//	- token: курсор, полученный от клиента.
+//	Возвращает:
+//	- Cursor: позиция в выборке.
+//	- error: ErrInvalidCursor, ErrExpiredCursor или ErrUnsupportedVersion.
+func (s *Signer) Decode(token string) (Cursor, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	gotSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(gotSig, s.sign(body)) {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var p payload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if p.Version != CurrentVersion {
+		return Cursor{}, ErrUnsupportedVersion
+	}
+	if s.now().Unix() > p.ExpiresAt {
+		return Cursor{}, ErrExpiredCursor
+	}
+
+	return Cursor{DateCreated: time.Unix(0, p.DateCreated).UTC(), OrderUID: p.OrderUID}, nil
+}
+
+// sign вычисляет HMAC-SHA256 закодированного содержимого курсора.
+func (s *Signer) sign(body string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(body))
+	return mac.Sum(nil)
+}
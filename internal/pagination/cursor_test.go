@@ -0,0 +1,45 @@
+package pagination
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSigner проверяет кодирование курсора и отказ для подделанных и просроченных курсоров.
+func TestSigner(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	signer := NewSigner([]byte("secret"), time.Hour)
+	signer.now = func() time.Time { return now }
+
+	want := Cursor{DateCreated: now.Add(-time.Minute), OrderUID: "b563feb7b2b84b6test"}
+	token := signer.Encode(want)
+
+	got, err := signer.Decode(token)
+	if err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if !got.DateCreated.Equal(want.DateCreated) || got.OrderUID != want.OrderUID {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	// Подпись другим секретом не принимается.
+	other := NewSigner([]byte("other"), time.Hour)
+	if _, err := other.Decode(token); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor for foreign signature, got %v", err)
+	}
+
+	// Изменённое содержимое не принимается.
+	body, sig, _ := strings.Cut(token, ".")
+	tampered := body[:len(body)-1] + "A" + "." + sig
+	if _, err := signer.Decode(tampered); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor for tampered cursor, got %v", err)
+	}
+
+	// Просроченный курсор не принимается.
+	now = now.Add(2 * time.Hour)
+	if _, err := signer.Decode(token); !errors.Is(err, ErrExpiredCursor) {
+		t.Errorf("expected ErrExpiredCursor, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"l0_wb/internal/model"
+	sqlcdb "l0_wb/internal/repository/db"
+)
+
+// AuditRepository определяет методы для взаимодействия с журналом аудита 'admin_audit_log'.
+type AuditRepository interface {
+	Insert(ctx context.Context, entry *model.AuditEntry) (int64, error)
+	List(ctx context.Context, filter AuditFilter, limit int) ([]model.AuditEntry, error)
+}
+
+type auditRepository struct {
+	db      DBTX
+	q       *sqlcdb.Queries // Запросы из queries/admin_audit_log.sql
+	metrics *MetricsWrapper
+}
+
+// NewAuditRepository создает новый экземпляр AuditRepository.
+//
+//	Параметры:
+//	- db: пул соединений или активная транзакция.
+//	Возвращает:
+//	- AuditRepository: экземпляр интерфейса для взаимодействия с таблицей 'admin_audit_log'.
+func NewAuditRepository(db DBTX) AuditRepository {
+	return &auditRepository{
+		db:      db,
+		q:       sqlcdb.New(db),
+		metrics: NewMetricsWrapper(),
+	}
+}
+
+// Insert добавляет запись в журнал аудита.
+//
+//	Запись с нулевым OccurredAt получает текущее время.
+//
+//	Параметры:
+//	- entry: запись журнала аудита.
+//	Возвращает:
+//	- int64: идентификатор созданной записи.
+//	- error: ошибка сериализации параметров или выполнения запроса (если возникла).
+func (r *auditRepository) Insert(ctx context.Context, entry *model.AuditEntry) (int64, error) {
+	params := []byte("{}")
+	if entry.Params != nil {
+		var err error
+		if params, err = json.Marshal(entry.Params); err != nil {
+			return 0, fmt.Errorf("marshal audit params: %w", err)
+		}
+	}
+	occurredAt := entry.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now().UTC()
+	}
+	var id int64
+
+	err := r.metrics.RecordDBOperation(ctx, "insert", "admin_audit_log", true, func(ctx context.Context) error {
+		var err error
+		id, err = r.q.InsertAuditEntry(ctx, sqlcdb.InsertAuditEntryParams{
+			OccurredAt: occurredAt,
+			Actor:      entry.Actor,
+			Method:     entry.Method,
+			Path:       entry.Path,
+			Params:     params,
+			Status:     entry.Status,
+			ClientIp:   entry.ClientIP,
+			RequestID:  entry.RequestID,
+		})
+		return err
+	})
+	return id, err
+}
+
+// List возвращает записи журнала аудита от новых к старым.
+//
+//	Параметры:
+//	- filter: условия отбора.
+//	- limit: максимальное число записей.
+//	Возвращает:
+//	- []model.AuditEntry: записи журнала (пустой список, если записей нет).
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *auditRepository) List(ctx context.Context, filter AuditFilter, limit int) ([]model.AuditEntry, error) {
+	where, args := filter.where()
+	args = append(args, limit)
+	query := "SELECT id, occurred_at, actor, method, path, params, status, client_ip, request_id FROM admin_audit_log" +
+		where + " ORDER BY id DESC LIMIT $" + strconv.Itoa(len(args))
+
+	var entries []model.AuditEntry
+	err := r.metrics.RecordDBOperation(ctx, "select", "admin_audit_log", false, func(ctx context.Context) error {
+		rows, err := r.db.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var e model.AuditEntry
+			if err := rows.Scan(&e.ID, &e.OccurredAt, &e.Actor, &e.Method, &e.Path, &e.Params, &e.Status, &e.ClientIP, &e.RequestID); err != nil {
+				return err
+			}
+			entries = append(entries, e)
+		}
+		return rows.Err()
+	})
+
+	return entries, err
+}
+
+// AuditFilter содержит условия отбора записей журнала аудита для List.
+//
+//	Нулевые значения полей не ограничивают выборку; From и To задают
+//	полуинтервал [From, To) по occurred_at, BeforeID — позицию
+//	постраничной выдачи (записи с меньшим id).
+type AuditFilter struct {
+	Actor    string
+	Path     string // Префикс пути запроса
+	From     time.Time
+	To       time.Time
+	BeforeID int64
+}
+
+// where возвращает условие WHERE и его аргументы для фильтра.
+func (f AuditFilter) where() (string, []any) {
+	var conds []string
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, strings.ReplaceAll(cond, "?", "$"+strconv.Itoa(len(args))))
+	}
+
+	if f.Actor != "" {
+		add("actor = ?", f.Actor)
+	}
+	if f.Path != "" {
+		add("starts_with(path, ?)", f.Path)
+	}
+	if !f.From.IsZero() {
+		add("occurred_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		add("occurred_at < ?", f.To)
+	}
+	if f.BeforeID > 0 {
+		add("id < ?", f.BeforeID)
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
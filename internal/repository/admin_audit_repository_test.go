@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAuditFilterWhere проверяет условие отбора записей журнала аудита.
+func TestAuditFilterWhere(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter AuditFilter
+		where  string
+		args   int
+	}{
+		{name: "empty", where: "", args: 0},
+		{
+			name:   "filters",
+			filter: AuditFilter{Actor: "ops", Path: "/api/admin/orders/", From: from},
+			where:  " WHERE actor = $1 AND starts_with(path, $2) AND occurred_at >= $3",
+			args:   3,
+		},
+		{
+			name:   "page",
+			filter: AuditFilter{To: from, BeforeID: 42},
+			where:  " WHERE occurred_at < $1 AND id < $2",
+			args:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := tt.filter.where()
+			if where != tt.where {
+				t.Errorf("where = %q, want %q", where, tt.where)
+			}
+			if len(args) != tt.args {
+				t.Errorf("len(args) = %d, want %d", len(args), tt.args)
+			}
+		})
+	}
+}
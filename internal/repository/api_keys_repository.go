@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"l0_wb/internal/model"
+	sqlcdb "l0_wb/internal/repository/db"
+)
+
+// APIKeysRepository определяет методы для взаимодействия с таблицей 'api_keys'.
+type APIKeysRepository interface {
+	GetByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+}
+
+type apiKeysRepository struct {
+	q       *sqlcdb.Queries // Запросы из queries/api_keys.sql
+	metrics *MetricsWrapper
+}
+
+// NewAPIKeysRepository создает новый экземпляр APIKeysRepository.
+//
+//	Параметры:
+//	- db: пул соединений или активная транзакция.
+//	Возвращает:
+//	- APIKeysRepository: экземпляр интерфейса для взаимодействия с таблицей 'api_keys'.
+func NewAPIKeysRepository(db DBTX) APIKeysRepository {
+	return &apiKeysRepository{
+		q:       sqlcdb.New(db),
+		metrics: NewMetricsWrapper(),
+	}
+}
+
+// GetByHash получает ключ API по SHA-256 хешу.
+//
+//	Параметры:
+//	- keyHash: hex-представление SHA-256 хеша ключа.
+//	Возвращает:
+//	- *model.APIKey: найденный ключ.
+//	- error: ошибка при выполнении запроса (если возникла) или pgx.ErrNoRows, если ключ не найден.
+func (r *apiKeysRepository) GetByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	var row sqlcdb.GetAPIKeyByHashRow
+
+	err := r.metrics.RecordDBOperation(ctx, "select", "api_keys", false, func(ctx context.Context) error {
+		var err error
+		row, err = r.q.GetAPIKeyByHash(ctx, keyHash)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.APIKey{KeyHash: row.KeyHash, Name: row.Name, RateLimit: row.RateLimit, Revoked: row.Revoked}, nil
+}
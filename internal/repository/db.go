@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Моки интерфейсов пакета для модульных тестов (make generate).
+//go:generate go run github.com/matryer/moq@v0.5.3 -rm -out mocks/repository_mock.go -pkg mocks . OrdersRepository DeliveriesRepository PaymentsRepository ItemsRepository OrderEventsRepository OrderDedupRepository APIKeysRepository IncidentsRepository StatsRepository AuditRepository TxManager
+
+// DBTX — общий интерфейс пула соединений и транзакции.
+//
+//	Ему удовлетворяют *pgxpool.Pool и pgx.Tx, поэтому один и тот же
+//	репозиторий работает как с общим пулом, так и внутри транзакции.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// Пул соединений и транзакция взаимозаменяемы для репозиториев.
+var (
+	_ DBTX = (*pgxpool.Pool)(nil)
+	_ DBTX = (pgx.Tx)(nil)
+)
+
+// Repositories объединяет репозитории таблиц заказа и журнала его изменений, работающие через одно подключение.
+type Repositories struct {
+	Orders     OrdersRepository
+	Deliveries DeliveriesRepository
+	Payments   PaymentsRepository
+	Items      ItemsRepository
+	Events     OrderEventsRepository
+}
+
+// NewRepositories создает репозитории заказа поверх общего подключения.
+//
+//	Параметры:
+//	- db: пул соединений или активная транзакция.
+//	Возвращает:
+//	- *Repositories: набор репозиториев заказа.
+func NewRepositories(db DBTX) *Repositories {
+	return NewRepositoriesWithReads(db, db)
+}
+
+// NewRepositoriesWithReads создает репозитории заказа с отдельным подключением для чтения заказов.
+//
+//	Параметры:
+//	- db: подключение к основной БД.
+//	- read: подключение для запросов только на чтение (см. NewOrdersRepositoryWithReads).
+//	Возвращает:
+//	- *Repositories: набор репозиториев заказа.
+func NewRepositoriesWithReads(db, read DBTX) *Repositories {
+	return &Repositories{
+		Orders:     NewOrdersRepositoryWithReads(db, read),
+		Deliveries: NewDeliveriesRepository(db),
+		Payments:   NewPaymentsRepository(db),
+		Items:      NewItemsRepository(db),
+		Events:     NewOrderEventsRepository(db),
+	}
+}
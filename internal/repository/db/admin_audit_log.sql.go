@@ -0,0 +1,43 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: admin_audit_log.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const insertAuditEntry = `-- name: InsertAuditEntry :one
+INSERT INTO admin_audit_log (occurred_at, actor, method, path, params, status, client_ip, request_id)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id
+`
+
+type InsertAuditEntryParams struct {
+	OccurredAt time.Time
+	Actor      string
+	Method     string
+	Path       string
+	Params     []byte
+	Status     int
+	ClientIp   string
+	RequestID  string
+}
+
+func (q *Queries) InsertAuditEntry(ctx context.Context, arg InsertAuditEntryParams) (int64, error) {
+	row := q.db.QueryRow(ctx, insertAuditEntry,
+		arg.OccurredAt,
+		arg.Actor,
+		arg.Method,
+		arg.Path,
+		arg.Params,
+		arg.Status,
+		arg.ClientIp,
+		arg.RequestID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
@@ -0,0 +1,33 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: api_keys.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getAPIKeyByHash = `-- name: GetAPIKeyByHash :one
+SELECT key_hash, name, rate_limit, revoked FROM api_keys WHERE key_hash = $1
+`
+
+type GetAPIKeyByHashRow struct {
+	KeyHash   string
+	Name      string
+	RateLimit int
+	Revoked   bool
+}
+
+func (q *Queries) GetAPIKeyByHash(ctx context.Context, keyHash string) (GetAPIKeyByHashRow, error) {
+	row := q.db.QueryRow(ctx, getAPIKeyByHash, keyHash)
+	var i GetAPIKeyByHashRow
+	err := row.Scan(
+		&i.KeyHash,
+		&i.Name,
+		&i.RateLimit,
+		&i.Revoked,
+	)
+	return i, err
+}
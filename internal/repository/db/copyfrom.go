@@ -0,0 +1,213 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: copyfrom.go
+
+package db
+
+import (
+	"context"
+)
+
+// iteratorForCopyDeliveries implements pgx.CopyFromSource.
+type iteratorForCopyDeliveries struct {
+	rows                 []CopyDeliveriesParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyDeliveries) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyDeliveries) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].OrderUid,
+		r.rows[0].Name,
+		r.rows[0].Phone,
+		r.rows[0].Zip,
+		r.rows[0].City,
+		r.rows[0].Address,
+		r.rows[0].Region,
+		r.rows[0].Email,
+	}, nil
+}
+
+func (r iteratorForCopyDeliveries) Err() error {
+	return nil
+}
+
+func (q *Queries) CopyDeliveries(ctx context.Context, arg []CopyDeliveriesParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"deliveries"}, []string{"order_uid", "name", "phone", "zip", "city", "address", "region", "email"}, &iteratorForCopyDeliveries{rows: arg})
+}
+
+// iteratorForCopyItems implements pgx.CopyFromSource.
+type iteratorForCopyItems struct {
+	rows                 []CopyItemsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyItems) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyItems) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].OrderUid,
+		r.rows[0].ChrtID,
+		r.rows[0].TrackNumber,
+		r.rows[0].Price,
+		r.rows[0].Rid,
+		r.rows[0].Name,
+		r.rows[0].Sale,
+		r.rows[0].Size,
+		r.rows[0].TotalPrice,
+		r.rows[0].NmID,
+		r.rows[0].Brand,
+		r.rows[0].Status,
+	}, nil
+}
+
+func (r iteratorForCopyItems) Err() error {
+	return nil
+}
+
+func (q *Queries) CopyItems(ctx context.Context, arg []CopyItemsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"items"}, []string{"order_uid", "chrt_id", "track_number", "price", "rid", "name", "sale", "size", "total_price", "nm_id", "brand", "status"}, &iteratorForCopyItems{rows: arg})
+}
+
+// iteratorForCopyOrderEvents implements pgx.CopyFromSource.
+type iteratorForCopyOrderEvents struct {
+	rows                 []CopyOrderEventsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyOrderEvents) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyOrderEvents) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].OrderUid,
+		r.rows[0].Action,
+		r.rows[0].Actor,
+		r.rows[0].OccurredAt,
+		r.rows[0].Diff,
+	}, nil
+}
+
+func (r iteratorForCopyOrderEvents) Err() error {
+	return nil
+}
+
+func (q *Queries) CopyOrderEvents(ctx context.Context, arg []CopyOrderEventsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"order_events"}, []string{"order_uid", "action", "actor", "occurred_at", "diff"}, &iteratorForCopyOrderEvents{rows: arg})
+}
+
+// iteratorForCopyOrders implements pgx.CopyFromSource.
+type iteratorForCopyOrders struct {
+	rows                 []CopyOrdersParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyOrders) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyOrders) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].OrderUid,
+		r.rows[0].TrackNumber,
+		r.rows[0].Entry,
+		r.rows[0].Locale,
+		r.rows[0].InternalSignature,
+		r.rows[0].CustomerID,
+		r.rows[0].DeliveryService,
+		r.rows[0].Shardkey,
+		r.rows[0].SmID,
+		r.rows[0].DateCreated,
+		r.rows[0].OofShard,
+		r.rows[0].AmountMismatch,
+	}, nil
+}
+
+func (r iteratorForCopyOrders) Err() error {
+	return nil
+}
+
+func (q *Queries) CopyOrders(ctx context.Context, arg []CopyOrdersParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"orders"}, []string{"order_uid", "track_number", "entry", "locale", "internal_signature", "customer_id", "delivery_service", "shardkey", "sm_id", "date_created", "oof_shard", "amount_mismatch"}, &iteratorForCopyOrders{rows: arg})
+}
+
+// iteratorForCopyPayments implements pgx.CopyFromSource.
+type iteratorForCopyPayments struct {
+	rows                 []CopyPaymentsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyPayments) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyPayments) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].OrderUid,
+		r.rows[0].Transaction,
+		r.rows[0].RequestID,
+		r.rows[0].Currency,
+		r.rows[0].Provider,
+		r.rows[0].Amount,
+		r.rows[0].PaymentDt,
+		r.rows[0].Bank,
+		r.rows[0].DeliveryCost,
+		r.rows[0].GoodsTotal,
+		r.rows[0].CustomFee,
+	}, nil
+}
+
+func (r iteratorForCopyPayments) Err() error {
+	return nil
+}
+
+func (q *Queries) CopyPayments(ctx context.Context, arg []CopyPaymentsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"payments"}, []string{"order_uid", "transaction", "request_id", "currency", "provider", "amount", "payment_dt", "bank", "delivery_cost", "goods_total", "custom_fee"}, &iteratorForCopyPayments{rows: arg})
+}
@@ -0,0 +1,139 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: deliveries.sql
+
+package db
+
+import (
+	"context"
+)
+
+type CopyDeliveriesParams struct {
+	OrderUid string
+	Name     string
+	Phone    string
+	Zip      string
+	City     string
+	Address  string
+	Region   string
+	Email    string
+}
+
+const eraseDeliveriesByCustomerID = `-- name: EraseDeliveriesByCustomerID :many
+UPDATE deliveries d SET name = '', phone = '', zip = '', address = '', email = '', updated_at = now()
+    FROM orders o
+    WHERE o.order_uid = d.order_uid AND o.customer_id = $1
+    RETURNING d.order_uid
+`
+
+func (q *Queries) EraseDeliveriesByCustomerID(ctx context.Context, customerID string) ([]string, error) {
+	rows, err := q.db.Query(ctx, eraseDeliveriesByCustomerID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var order_uid string
+		if err := rows.Scan(&order_uid); err != nil {
+			return nil, err
+		}
+		items = append(items, order_uid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeliveryByOrderID = `-- name: GetDeliveryByOrderID :one
+SELECT name, phone, zip, city, address, region, email
+    FROM deliveries WHERE order_uid = $1
+`
+
+type GetDeliveryByOrderIDRow struct {
+	Name    string
+	Phone   string
+	Zip     string
+	City    string
+	Address string
+	Region  string
+	Email   string
+}
+
+func (q *Queries) GetDeliveryByOrderID(ctx context.Context, orderUid string) (GetDeliveryByOrderIDRow, error) {
+	row := q.db.QueryRow(ctx, getDeliveryByOrderID, orderUid)
+	var i GetDeliveryByOrderIDRow
+	err := row.Scan(
+		&i.Name,
+		&i.Phone,
+		&i.Zip,
+		&i.City,
+		&i.Address,
+		&i.Region,
+		&i.Email,
+	)
+	return i, err
+}
+
+const insertDelivery = `-- name: InsertDelivery :exec
+INSERT INTO deliveries (order_uid, name, phone, zip, city, address, region, email, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now(), now())
+`
+
+type InsertDeliveryParams struct {
+	OrderUid string
+	Name     string
+	Phone    string
+	Zip      string
+	City     string
+	Address  string
+	Region   string
+	Email    string
+}
+
+func (q *Queries) InsertDelivery(ctx context.Context, arg InsertDeliveryParams) error {
+	_, err := q.db.Exec(ctx, insertDelivery,
+		arg.OrderUid,
+		arg.Name,
+		arg.Phone,
+		arg.Zip,
+		arg.City,
+		arg.Address,
+		arg.Region,
+		arg.Email,
+	)
+	return err
+}
+
+const updateDelivery = `-- name: UpdateDelivery :exec
+UPDATE deliveries SET name = $2, phone = $3, zip = $4, city = $5, address = $6, region = $7, email = $8,
+        updated_at = now()
+    WHERE order_uid = $1
+`
+
+type UpdateDeliveryParams struct {
+	OrderUid string
+	Name     string
+	Phone    string
+	Zip      string
+	City     string
+	Address  string
+	Region   string
+	Email    string
+}
+
+func (q *Queries) UpdateDelivery(ctx context.Context, arg UpdateDeliveryParams) error {
+	_, err := q.db.Exec(ctx, updateDelivery,
+		arg.OrderUid,
+		arg.Name,
+		arg.Phone,
+		arg.Zip,
+		arg.City,
+		arg.Address,
+		arg.Region,
+		arg.Email,
+	)
+	return err
+}
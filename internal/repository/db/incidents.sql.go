@@ -0,0 +1,50 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: incidents.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const closeIncident = `-- name: CloseIncident :exec
+UPDATE incidents SET ended_at = $2, last_error = $3 WHERE id = $1
+`
+
+type CloseIncidentParams struct {
+	ID        int64
+	EndedAt   *time.Time
+	LastError string
+}
+
+func (q *Queries) CloseIncident(ctx context.Context, arg CloseIncidentParams) error {
+	_, err := q.db.Exec(ctx, closeIncident, arg.ID, arg.EndedAt, arg.LastError)
+	return err
+}
+
+const insertIncident = `-- name: InsertIncident :one
+INSERT INTO incidents (dependency, started_at, ended_at, last_error)
+    VALUES ($1, $2, $3, $4) RETURNING id
+`
+
+type InsertIncidentParams struct {
+	Dependency string
+	StartedAt  time.Time
+	EndedAt    *time.Time
+	LastError  string
+}
+
+func (q *Queries) InsertIncident(ctx context.Context, arg InsertIncidentParams) (int64, error) {
+	row := q.db.QueryRow(ctx, insertIncident,
+		arg.Dependency,
+		arg.StartedAt,
+		arg.EndedAt,
+		arg.LastError,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
@@ -0,0 +1,85 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: items.sql
+
+package db
+
+import (
+	"context"
+)
+
+type CopyItemsParams struct {
+	OrderUid    string
+	ChrtID      int
+	TrackNumber string
+	Price       int
+	Rid         string
+	Name        string
+	Sale        int
+	Size        string
+	TotalPrice  int
+	NmID        int
+	Brand       string
+	Status      int
+}
+
+const deleteItemsByOrderID = `-- name: DeleteItemsByOrderID :exec
+DELETE FROM items WHERE order_uid = $1
+`
+
+func (q *Queries) DeleteItemsByOrderID(ctx context.Context, orderUid string) error {
+	_, err := q.db.Exec(ctx, deleteItemsByOrderID, orderUid)
+	return err
+}
+
+const getItemsByOrderID = `-- name: GetItemsByOrderID :many
+SELECT chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status
+    FROM items WHERE order_uid = $1
+`
+
+type GetItemsByOrderIDRow struct {
+	ChrtID      int
+	TrackNumber string
+	Price       int
+	Rid         string
+	Name        string
+	Sale        int
+	Size        string
+	TotalPrice  int
+	NmID        int
+	Brand       string
+	Status      int
+}
+
+func (q *Queries) GetItemsByOrderID(ctx context.Context, orderUid string) ([]GetItemsByOrderIDRow, error) {
+	rows, err := q.db.Query(ctx, getItemsByOrderID, orderUid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetItemsByOrderIDRow
+	for rows.Next() {
+		var i GetItemsByOrderIDRow
+		if err := rows.Scan(
+			&i.ChrtID,
+			&i.TrackNumber,
+			&i.Price,
+			&i.Rid,
+			&i.Name,
+			&i.Sale,
+			&i.Size,
+			&i.TotalPrice,
+			&i.NmID,
+			&i.Brand,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
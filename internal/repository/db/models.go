@@ -0,0 +1,119 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+
+package db
+
+import (
+	"time"
+)
+
+type AdminAuditLog struct {
+	ID         int64
+	OccurredAt time.Time
+	Actor      string
+	Method     string
+	Path       string
+	Params     []byte
+	Status     int
+	ClientIp   string
+	RequestID  string
+}
+
+type ApiKey struct {
+	KeyHash   string
+	Name      string
+	RateLimit int
+	Revoked   bool
+	CreatedAt time.Time
+}
+
+type Delivery struct {
+	OrderUid  string
+	Name      string
+	Phone     string
+	Zip       string
+	City      string
+	Address   string
+	Region    string
+	Email     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Incident struct {
+	ID         int64
+	Dependency string
+	StartedAt  time.Time
+	EndedAt    *time.Time
+	LastError  string
+}
+
+type Item struct {
+	ID          int32
+	OrderUid    string
+	ChrtID      int
+	TrackNumber string
+	Price       int
+	Rid         string
+	Name        string
+	Sale        int
+	Size        string
+	TotalPrice  int
+	NmID        int
+	Brand       string
+	Status      int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Order struct {
+	OrderUid          string
+	TrackNumber       string
+	Entry             string
+	Locale            string
+	InternalSignature string
+	CustomerID        string
+	DeliveryService   string
+	Shardkey          string
+	SmID              int
+	DateCreated       time.Time
+	OofShard          string
+	DeletedAt         *time.Time
+	ArchivedAt        *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Status            string
+	AmountMismatch    int
+}
+
+type OrderDedup struct {
+	OrderUid    string
+	PayloadHash string
+	SeenAt      time.Time
+}
+
+type OrderEvent struct {
+	ID         int64
+	OrderUid   string
+	Action     string
+	Actor      string
+	OccurredAt time.Time
+	Diff       []byte
+}
+
+type Payment struct {
+	OrderUid     string
+	Transaction  string
+	RequestID    string
+	Currency     string
+	Provider     string
+	Amount       int
+	PaymentDt    int64
+	Bank         string
+	DeliveryCost int
+	GoodsTotal   int
+	CustomFee    int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
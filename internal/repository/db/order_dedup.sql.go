@@ -0,0 +1,75 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: order_dedup.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const deleteOrderDedupBefore = `-- name: DeleteOrderDedupBefore :execrows
+DELETE FROM order_dedup WHERE seen_at < $1
+`
+
+func (q *Queries) DeleteOrderDedupBefore(ctx context.Context, seenAt time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOrderDedupBefore, seenAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listRecentOrderDedup = `-- name: ListRecentOrderDedup :many
+SELECT order_uid, payload_hash
+    FROM order_dedup WHERE order_uid = ANY($1::text[]) AND seen_at >= $2
+`
+
+type ListRecentOrderDedupParams struct {
+	OrderUids []string
+	Since     time.Time
+}
+
+type ListRecentOrderDedupRow struct {
+	OrderUid    string
+	PayloadHash string
+}
+
+func (q *Queries) ListRecentOrderDedup(ctx context.Context, arg ListRecentOrderDedupParams) ([]ListRecentOrderDedupRow, error) {
+	rows, err := q.db.Query(ctx, listRecentOrderDedup, arg.OrderUids, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRecentOrderDedupRow
+	for rows.Next() {
+		var i ListRecentOrderDedupRow
+		if err := rows.Scan(&i.OrderUid, &i.PayloadHash); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertOrderDedup = `-- name: UpsertOrderDedup :exec
+INSERT INTO order_dedup (order_uid, payload_hash, seen_at)
+    SELECT unnest($1::text[]), unnest($2::text[]), $3::timestamptz
+    ON CONFLICT (order_uid) DO UPDATE SET payload_hash = EXCLUDED.payload_hash, seen_at = EXCLUDED.seen_at
+`
+
+type UpsertOrderDedupParams struct {
+	OrderUids     []string
+	PayloadHashes []string
+	SeenAt        time.Time
+}
+
+func (q *Queries) UpsertOrderDedup(ctx context.Context, arg UpsertOrderDedupParams) error {
+	_, err := q.db.Exec(ctx, upsertOrderDedup, arg.OrderUids, arg.PayloadHashes, arg.SeenAt)
+	return err
+}
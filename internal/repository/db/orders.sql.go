@@ -0,0 +1,263 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: orders.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const archiveOrder = `-- name: ArchiveOrder :execrows
+UPDATE orders SET archived_at = COALESCE(archived_at, now()), updated_at = now() WHERE order_uid = $1
+`
+
+func (q *Queries) ArchiveOrder(ctx context.Context, orderUid string) (int64, error) {
+	result, err := q.db.Exec(ctx, archiveOrder, orderUid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+type CopyOrdersParams struct {
+	OrderUid          string
+	TrackNumber       string
+	Entry             string
+	Locale            string
+	InternalSignature string
+	CustomerID        string
+	DeliveryService   string
+	Shardkey          string
+	SmID              int
+	DateCreated       time.Time
+	OofShard          string
+	AmountMismatch    int
+}
+
+const getAllOrderIDs = `-- name: GetAllOrderIDs :many
+SELECT order_uid FROM orders WHERE deleted_at IS NULL ORDER BY date_created, order_uid
+`
+
+func (q *Queries) GetAllOrderIDs(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, getAllOrderIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var order_uid string
+		if err := rows.Scan(&order_uid); err != nil {
+			return nil, err
+		}
+		items = append(items, order_uid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrderByID = `-- name: GetOrderByID :one
+SELECT order_uid, track_number, entry, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard,
+        deleted_at, archived_at, created_at, updated_at, status, amount_mismatch
+    FROM orders WHERE order_uid = $1
+`
+
+func (q *Queries) GetOrderByID(ctx context.Context, orderUid string) (Order, error) {
+	row := q.db.QueryRow(ctx, getOrderByID, orderUid)
+	var i Order
+	err := row.Scan(
+		&i.OrderUid,
+		&i.TrackNumber,
+		&i.Entry,
+		&i.Locale,
+		&i.InternalSignature,
+		&i.CustomerID,
+		&i.DeliveryService,
+		&i.Shardkey,
+		&i.SmID,
+		&i.DateCreated,
+		&i.OofShard,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.AmountMismatch,
+	)
+	return i, err
+}
+
+const getOrderIDsSince = `-- name: GetOrderIDsSince :many
+SELECT order_uid FROM orders WHERE deleted_at IS NULL AND date_created >= $1 ORDER BY date_created, order_uid
+`
+
+func (q *Queries) GetOrderIDsSince(ctx context.Context, since time.Time) ([]string, error) {
+	rows, err := q.db.Query(ctx, getOrderIDsSince, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var order_uid string
+		if err := rows.Scan(&order_uid); err != nil {
+			return nil, err
+		}
+		items = append(items, order_uid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertOrder = `-- name: InsertOrder :exec
+INSERT INTO orders (order_uid, track_number, entry, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard,
+        amount_mismatch, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, now(), now())
+`
+
+type InsertOrderParams struct {
+	OrderUid          string
+	TrackNumber       string
+	Entry             string
+	Locale            string
+	InternalSignature string
+	CustomerID        string
+	DeliveryService   string
+	Shardkey          string
+	SmID              int
+	DateCreated       time.Time
+	OofShard          string
+	AmountMismatch    int
+}
+
+func (q *Queries) InsertOrder(ctx context.Context, arg InsertOrderParams) error {
+	_, err := q.db.Exec(ctx, insertOrder,
+		arg.OrderUid,
+		arg.TrackNumber,
+		arg.Entry,
+		arg.Locale,
+		arg.InternalSignature,
+		arg.CustomerID,
+		arg.DeliveryService,
+		arg.Shardkey,
+		arg.SmID,
+		arg.DateCreated,
+		arg.OofShard,
+		arg.AmountMismatch,
+	)
+	return err
+}
+
+const lockExistingOrders = `-- name: LockExistingOrders :many
+SELECT order_uid FROM orders WHERE order_uid = ANY($1::text[]) FOR UPDATE
+`
+
+func (q *Queries) LockExistingOrders(ctx context.Context, orderUids []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, lockExistingOrders, orderUids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var order_uid string
+		if err := rows.Scan(&order_uid); err != nil {
+			return nil, err
+		}
+		items = append(items, order_uid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreOrder = `-- name: RestoreOrder :execrows
+UPDATE orders SET deleted_at = NULL, updated_at = now() WHERE order_uid = $1
+`
+
+func (q *Queries) RestoreOrder(ctx context.Context, orderUid string) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreOrder, orderUid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setOrderStatus = `-- name: SetOrderStatus :execrows
+UPDATE orders SET status = $1, updated_at = now()
+    WHERE order_uid = $2 AND status = $3 AND deleted_at IS NULL
+`
+
+type SetOrderStatusParams struct {
+	ToStatus   string
+	OrderUid   string
+	FromStatus string
+}
+
+func (q *Queries) SetOrderStatus(ctx context.Context, arg SetOrderStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setOrderStatus, arg.ToStatus, arg.OrderUid, arg.FromStatus)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const softDeleteOrder = `-- name: SoftDeleteOrder :execrows
+UPDATE orders SET deleted_at = COALESCE(deleted_at, now()), updated_at = now() WHERE order_uid = $1
+`
+
+func (q *Queries) SoftDeleteOrder(ctx context.Context, orderUid string) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteOrder, orderUid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateOrder = `-- name: UpdateOrder :exec
+UPDATE orders SET track_number = $2, entry = $3, locale = $4, internal_signature = $5, customer_id = $6,
+        delivery_service = $7, shardkey = $8, sm_id = $9, date_created = $10, oof_shard = $11, amount_mismatch = $12,
+        updated_at = now()
+    WHERE order_uid = $1
+`
+
+type UpdateOrderParams struct {
+	OrderUid          string
+	TrackNumber       string
+	Entry             string
+	Locale            string
+	InternalSignature string
+	CustomerID        string
+	DeliveryService   string
+	Shardkey          string
+	SmID              int
+	DateCreated       time.Time
+	OofShard          string
+	AmountMismatch    int
+}
+
+func (q *Queries) UpdateOrder(ctx context.Context, arg UpdateOrderParams) error {
+	_, err := q.db.Exec(ctx, updateOrder,
+		arg.OrderUid,
+		arg.TrackNumber,
+		arg.Entry,
+		arg.Locale,
+		arg.InternalSignature,
+		arg.CustomerID,
+		arg.DeliveryService,
+		arg.Shardkey,
+		arg.SmID,
+		arg.DateCreated,
+		arg.OofShard,
+		arg.AmountMismatch,
+	)
+	return err
+}
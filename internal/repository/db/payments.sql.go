@@ -0,0 +1,135 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: payments.sql
+
+package db
+
+import (
+	"context"
+)
+
+type CopyPaymentsParams struct {
+	OrderUid     string
+	Transaction  string
+	RequestID    string
+	Currency     string
+	Provider     string
+	Amount       int
+	PaymentDt    int64
+	Bank         string
+	DeliveryCost int
+	GoodsTotal   int
+	CustomFee    int
+}
+
+const getPaymentByOrderID = `-- name: GetPaymentByOrderID :one
+SELECT transaction, request_id, currency, provider, amount, payment_dt, bank, delivery_cost, goods_total, custom_fee
+    FROM payments WHERE order_uid = $1
+`
+
+type GetPaymentByOrderIDRow struct {
+	Transaction  string
+	RequestID    string
+	Currency     string
+	Provider     string
+	Amount       int
+	PaymentDt    int64
+	Bank         string
+	DeliveryCost int
+	GoodsTotal   int
+	CustomFee    int
+}
+
+func (q *Queries) GetPaymentByOrderID(ctx context.Context, orderUid string) (GetPaymentByOrderIDRow, error) {
+	row := q.db.QueryRow(ctx, getPaymentByOrderID, orderUid)
+	var i GetPaymentByOrderIDRow
+	err := row.Scan(
+		&i.Transaction,
+		&i.RequestID,
+		&i.Currency,
+		&i.Provider,
+		&i.Amount,
+		&i.PaymentDt,
+		&i.Bank,
+		&i.DeliveryCost,
+		&i.GoodsTotal,
+		&i.CustomFee,
+	)
+	return i, err
+}
+
+const insertPayment = `-- name: InsertPayment :exec
+INSERT INTO payments (order_uid, transaction, request_id, currency, provider, amount, payment_dt, bank, delivery_cost, goods_total, custom_fee,
+        created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, now(), now())
+`
+
+type InsertPaymentParams struct {
+	OrderUid     string
+	Transaction  string
+	RequestID    string
+	Currency     string
+	Provider     string
+	Amount       int
+	PaymentDt    int64
+	Bank         string
+	DeliveryCost int
+	GoodsTotal   int
+	CustomFee    int
+}
+
+func (q *Queries) InsertPayment(ctx context.Context, arg InsertPaymentParams) error {
+	_, err := q.db.Exec(ctx, insertPayment,
+		arg.OrderUid,
+		arg.Transaction,
+		arg.RequestID,
+		arg.Currency,
+		arg.Provider,
+		arg.Amount,
+		arg.PaymentDt,
+		arg.Bank,
+		arg.DeliveryCost,
+		arg.GoodsTotal,
+		arg.CustomFee,
+	)
+	return err
+}
+
+const updatePayment = `-- name: UpdatePayment :exec
+UPDATE payments SET transaction = $2, request_id = $3, currency = $4, provider = $5, amount = $6,
+        payment_dt = $7, bank = $8, delivery_cost = $9, goods_total = $10, custom_fee = $11,
+        updated_at = now()
+    WHERE order_uid = $1
+`
+
+type UpdatePaymentParams struct {
+	OrderUid     string
+	Transaction  string
+	RequestID    string
+	Currency     string
+	Provider     string
+	Amount       int
+	PaymentDt    int64
+	Bank         string
+	DeliveryCost int
+	GoodsTotal   int
+	CustomFee    int
+}
+
+func (q *Queries) UpdatePayment(ctx context.Context, arg UpdatePaymentParams) error {
+	_, err := q.db.Exec(ctx, updatePayment,
+		arg.OrderUid,
+		arg.Transaction,
+		arg.RequestID,
+		arg.Currency,
+		arg.Provider,
+		arg.Amount,
+		arg.PaymentDt,
+		arg.Bank,
+		arg.DeliveryCost,
+		arg.GoodsTotal,
+		arg.CustomFee,
+	)
+	return err
+}
@@ -0,0 +1,139 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: pii.sql
+
+package db
+
+import (
+	"context"
+)
+
+const listDeliveriesPII = `-- name: ListDeliveriesPII :many
+SELECT order_uid, phone, email, address
+    FROM deliveries WHERE order_uid > $1
+    ORDER BY order_uid LIMIT $2::int
+`
+
+type ListDeliveriesPIIParams struct {
+	After     string
+	BatchSize int
+}
+
+type ListDeliveriesPIIRow struct {
+	OrderUid string
+	Phone    string
+	Email    string
+	Address  string
+}
+
+func (q *Queries) ListDeliveriesPII(ctx context.Context, arg ListDeliveriesPIIParams) ([]ListDeliveriesPIIRow, error) {
+	rows, err := q.db.Query(ctx, listDeliveriesPII, arg.After, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDeliveriesPIIRow
+	for rows.Next() {
+		var i ListDeliveriesPIIRow
+		if err := rows.Scan(
+			&i.OrderUid,
+			&i.Phone,
+			&i.Email,
+			&i.Address,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderEventDiffs = `-- name: ListOrderEventDiffs :many
+SELECT id, diff
+    FROM order_events WHERE id > $1
+    ORDER BY id LIMIT $2::int
+`
+
+type ListOrderEventDiffsParams struct {
+	After     int64
+	BatchSize int
+}
+
+type ListOrderEventDiffsRow struct {
+	ID   int64
+	Diff []byte
+}
+
+func (q *Queries) ListOrderEventDiffs(ctx context.Context, arg ListOrderEventDiffsParams) ([]ListOrderEventDiffsRow, error) {
+	rows, err := q.db.Query(ctx, listOrderEventDiffs, arg.After, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrderEventDiffsRow
+	for rows.Next() {
+		var i ListOrderEventDiffsRow
+		if err := rows.Scan(&i.ID, &i.Diff); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateDeliveryPII = `-- name: UpdateDeliveryPII :execrows
+UPDATE deliveries SET phone = $1, email = $2, address = $3
+    WHERE order_uid = $4 AND phone IS NOT DISTINCT FROM $5 AND email IS NOT DISTINCT FROM $6
+        AND address IS NOT DISTINCT FROM $7
+`
+
+type UpdateDeliveryPIIParams struct {
+	Phone      string
+	Email      string
+	Address    string
+	OrderUid   string
+	OldPhone   string
+	OldEmail   string
+	OldAddress string
+}
+
+func (q *Queries) UpdateDeliveryPII(ctx context.Context, arg UpdateDeliveryPIIParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateDeliveryPII,
+		arg.Phone,
+		arg.Email,
+		arg.Address,
+		arg.OrderUid,
+		arg.OldPhone,
+		arg.OldEmail,
+		arg.OldAddress,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateOrderEventDiff = `-- name: UpdateOrderEventDiff :execrows
+UPDATE order_events SET diff = $1 WHERE id = $2 AND diff = $3
+`
+
+type UpdateOrderEventDiffParams struct {
+	Diff    []byte
+	ID      int64
+	OldDiff []byte
+}
+
+func (q *Queries) UpdateOrderEventDiff(ctx context.Context, arg UpdateOrderEventDiffParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateOrderEventDiff, arg.Diff, arg.ID, arg.OldDiff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -4,51 +4,64 @@ package repository
 import (
 	"context"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"l0_wb/internal/model"
+	sqlcdb "l0_wb/internal/repository/db"
 )
 
 // DeliveriesRepository определяет методы для взаимодействия с таблицей 'deliveries'.
 type DeliveriesRepository interface {
 	Insert(ctx context.Context, delivery *model.Delivery, orderUID string) error
+	InsertMany(ctx context.Context, orders []*model.Order) error
+	Update(ctx context.Context, delivery *model.Delivery, orderUID string) error
 	GetByOrderID(ctx context.Context, orderUID string) (*model.Delivery, error)
+	EraseByCustomerID(ctx context.Context, customerID string) ([]string, error)
 }
 
 type deliveriesRepository struct {
-	db *pgxpool.Pool
+	q       *sqlcdb.Queries // Запросы из queries/deliveries.sql
+	metrics *MetricsWrapper
 }
 
 // NewDeliveriesRepository создает новый экземпляр DeliveriesRepository.
 //
 //	Параметры:
-//	- db: подключение к базе данных (sql.DB).
+//	- db: пул соединений или активная транзакция.
 //	Возвращает:
 //	- DeliveriesRepository: экземпляр интерфейса для взаимодействия с таблицей 'deliveries'.
-func NewDeliveriesRepository(db *pgxpool.Pool) DeliveriesRepository {
-	return &deliveriesRepository{db: db}
+func NewDeliveriesRepository(db DBTX) DeliveriesRepository {
+	return &deliveriesRepository{
+		q:       sqlcdb.New(db),
+		metrics: NewMetricsWrapper(),
+	}
 }
 
 // Insert добавляет новую запись о доставке в таблицу 'deliveries'.
 //
+//	Телефон, email и адрес шифруются, если задан шифратор (см. SetFieldCipher).
+//
 //	Параметры:
 //	- delivery: объект доставки, содержащий данные о получателе.
 //	- orderUID: уникальный идентификатор заказа.
 //	Возвращает:
 //	- error: ошибка при выполнении запроса (если возникла).
 func (r *deliveriesRepository) Insert(ctx context.Context, delivery *model.Delivery, orderUID string) error {
-	query := `INSERT INTO deliveries (order_uid, name, phone, zip, city, address, region, email)
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
-	_, err := r.db.Exec(ctx, query,
-		orderUID,
-		delivery.Name,
-		delivery.Phone,
-		delivery.Zip,
-		delivery.City,
-		delivery.Address,
-		delivery.Region,
-		delivery.Email,
-	)
-	return err
+	sealed, err := sealDelivery(delivery)
+	if err != nil {
+		return err
+	}
+	delivery = &sealed
+	return r.metrics.RecordDBOperation(ctx, "insert", "deliveries", true, func(ctx context.Context) error {
+		return r.q.InsertDelivery(ctx, sqlcdb.InsertDeliveryParams{
+			OrderUid: orderUID,
+			Name:     delivery.Name,
+			Phone:    delivery.Phone,
+			Zip:      delivery.Zip,
+			City:     delivery.City,
+			Address:  delivery.Address,
+			Region:   delivery.Region,
+			Email:    delivery.Email,
+		})
+	})
 }
 
 // GetByOrderID получает запись о доставке по order_uid.
@@ -59,13 +72,109 @@ func (r *deliveriesRepository) Insert(ctx context.Context, delivery *model.Deliv
 //	- *model.Delivery: объект доставки, если запись найдена.
 //	- error: ошибка при выполнении запроса (если возникла) или sql.ErrNoRows, если запись не найдена.
 func (r *deliveriesRepository) GetByOrderID(ctx context.Context, orderUID string) (*model.Delivery, error) {
-	query := `SELECT name, phone, zip, city, address, region, email
-              FROM deliveries WHERE order_uid = $1`
-	row := r.db.QueryRow(ctx, query, orderUID)
-	var d model.Delivery
-	err := row.Scan(&d.Name, &d.Phone, &d.Zip, &d.City, &d.Address, &d.Region, &d.Email)
+	var row sqlcdb.GetDeliveryByOrderIDRow
+
+	err := r.metrics.RecordDBOperation(ctx, "select", "deliveries", false, func(ctx context.Context) error {
+		var err error
+		row, err = r.q.GetDeliveryByOrderID(ctx, orderUID)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	d := model.Delivery{
+		Name:    row.Name,
+		Phone:   row.Phone,
+		Zip:     row.Zip,
+		City:    row.City,
+		Address: row.Address,
+		Region:  row.Region,
+		Email:   row.Email,
+	}
+	if err := openDelivery(&d); err != nil {
+		return nil, err
+	}
 	return &d, nil
 }
+
+// Update заменяет данные доставки заказа в таблице 'deliveries'.
+//
+//	Параметры:
+//	- delivery: объект доставки с новыми данными получателя.
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *deliveriesRepository) Update(ctx context.Context, delivery *model.Delivery, orderUID string) error {
+	sealed, err := sealDelivery(delivery)
+	if err != nil {
+		return err
+	}
+	delivery = &sealed
+	return r.metrics.RecordDBOperation(ctx, "update", "deliveries", true, func(ctx context.Context) error {
+		return r.q.UpdateDelivery(ctx, sqlcdb.UpdateDeliveryParams{
+			OrderUid: orderUID,
+			Name:     delivery.Name,
+			Phone:    delivery.Phone,
+			Zip:      delivery.Zip,
+			City:     delivery.City,
+			Address:  delivery.Address,
+			Region:   delivery.Region,
+			Email:    delivery.Email,
+		})
+	})
+}
+
+// InsertMany добавляет записи о доставке заказов в таблицу 'deliveries' одной командой COPY.
+//
+//	Параметры:
+//	- orders: заказы, данные доставки которых вставляются.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *deliveriesRepository) InsertMany(ctx context.Context, orders []*model.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	rows := make([]sqlcdb.CopyDeliveriesParams, len(orders))
+	for i, o := range orders {
+		d, err := sealDelivery(&o.Delivery)
+		if err != nil {
+			return err
+		}
+		rows[i] = sqlcdb.CopyDeliveriesParams{
+			OrderUid: o.OrderUID,
+			Name:     d.Name,
+			Phone:    d.Phone,
+			Zip:      d.Zip,
+			City:     d.City,
+			Address:  d.Address,
+			Region:   d.Region,
+			Email:    d.Email,
+		}
+	}
+	return r.metrics.RecordDBOperation(ctx, "copy", "deliveries", true, func(ctx context.Context) error {
+		_, err := r.q.CopyDeliveries(ctx, rows)
+		return err
+	})
+}
+
+// EraseByCustomerID удаляет персональные данные получателя из доставок всех заказов покупателя.
+//
+//	Имя, телефон, индекс, адрес и email заменяются пустыми строками; город и
+//	регион сохраняются для статистики. Затрагиваются и мягко удалённые заказы.
+//
+//	Параметры:
+//	- customerID: идентификатор покупателя.
+//	Возвращает:
+//	- []string: order_uid заказов, доставки которых изменены.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *deliveriesRepository) EraseByCustomerID(ctx context.Context, customerID string) ([]string, error) {
+	var uids []string
+
+	err := r.metrics.RecordDBOperation(ctx, "update", "deliveries", true, func(ctx context.Context) error {
+		var err error
+		uids, err = r.q.EraseDeliveriesByCustomerID(ctx, customerID)
+		return err
+	})
+
+	return uids, err
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"l0_wb/internal/model"
+	sqlcdb "l0_wb/internal/repository/db"
+)
+
+// IncidentsRepository определяет методы для взаимодействия с таблицей 'incidents'.
+type IncidentsRepository interface {
+	Insert(ctx context.Context, incident *model.Incident) (int64, error)
+	Close(ctx context.Context, id int64, endedAt time.Time, lastError string) error
+}
+
+type incidentsRepository struct {
+	q       *sqlcdb.Queries // Запросы из queries/incidents.sql
+	metrics *MetricsWrapper
+}
+
+// NewIncidentsRepository создает новый экземпляр IncidentsRepository.
+//
+//	Параметры:
+//	- db: пул соединений или активная транзакция.
+//	Возвращает:
+//	- IncidentsRepository: экземпляр интерфейса для взаимодействия с таблицей 'incidents'.
+func NewIncidentsRepository(db DBTX) IncidentsRepository {
+	return &incidentsRepository{
+		q:       sqlcdb.New(db),
+		metrics: NewMetricsWrapper(),
+	}
+}
+
+// Insert добавляет запись об инциденте в таблицу 'incidents'.
+//
+//	Параметры:
+//	- incident: объект model.Incident; EndedAt может быть nil для открытого инцидента.
+//	Возвращает:
+//	- int64: идентификатор созданной записи.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *incidentsRepository) Insert(ctx context.Context, incident *model.Incident) (int64, error) {
+	var id int64
+
+	err := r.metrics.RecordDBOperation(ctx, "insert", "incidents", true, func(ctx context.Context) error {
+		var err error
+		id, err = r.q.InsertIncident(ctx, sqlcdb.InsertIncidentParams{
+			Dependency: incident.Dependency,
+			StartedAt:  incident.StartedAt,
+			EndedAt:    incident.EndedAt,
+			LastError:  incident.LastError,
+		})
+		return err
+	})
+	return id, err
+}
+
+// Close закрывает инцидент, записывая время восстановления.
+//
+//	Параметры:
+//	- id: идентификатор инцидента.
+//	- endedAt: время восстановления зависимости.
+//	- lastError: последняя ошибка проверки за время инцидента.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *incidentsRepository) Close(ctx context.Context, id int64, endedAt time.Time, lastError string) error {
+	return r.metrics.RecordDBOperation(ctx, "update", "incidents", true, func(ctx context.Context) error {
+		return r.q.CloseIncident(ctx, sqlcdb.CloseIncidentParams{ID: id, EndedAt: &endedAt, LastError: lastError})
+	})
+}
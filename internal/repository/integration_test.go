@@ -0,0 +1,678 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"l0_wb/internal/db"
+	"l0_wb/internal/model"
+)
+
+// Интеграционные тесты репозиториев выполняются на настоящем PostgreSQL,
+// который TestMain запускает в контейнере (нужен Docker):
+//
+//	go test -tags integration ./internal/repository/
+//
+// С заданным TEST_DATABASE_URL контейнер не запускается и используется
+// указанная БД. Каждый тест работает в отдельной схеме, в которой
+// применяются миграции из internal/db/migrations; по завершении теста
+// схема удаляется.
+
+// testDSN — строка подключения к PostgreSQL для интеграционных тестов.
+var testDSN string
+
+// TestMain запускает PostgreSQL в контейнере, если TEST_DATABASE_URL не задан.
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+// runTests выполняет тесты и останавливает контейнер после них.
+func runTests(m *testing.M) int {
+	testDSN = os.Getenv("TEST_DATABASE_URL")
+	if testDSN != "" {
+		return m.Run()
+	}
+
+	ctx := context.Background()
+	container, err := postgres.Run(ctx, "postgres:16",
+		postgres.WithDatabase("orders_test"),
+		postgres.WithUsername("test"),
+		postgres.WithPassword("test"),
+		// Сообщение выводится дважды: после initdb сервер перезапускается
+		testcontainers.WithWaitStrategy(wait.ForLog("database system is ready to accept connections").
+			WithOccurrence(2).
+			WithStartupTimeout(time.Minute)),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "start postgres container: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "terminate postgres container: %v\n", err)
+		}
+	}()
+
+	if testDSN, err = container.ConnectionString(ctx, "sslmode=disable"); err != nil {
+		fmt.Fprintf(os.Stderr, "postgres connection string: %v\n", err)
+		return 1
+	}
+	return m.Run()
+}
+
+// testDB создаёт схему с применёнными миграциями и пул соединений к ней.
+func testDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	ctx := context.Background()
+
+	schema := fmt.Sprintf("repo_test_%d", time.Now().UnixNano())
+	admin, err := pgx.Connect(ctx, testDSN)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+pgx.Identifier{schema}.Sanitize()); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = admin.Exec(context.Background(), "DROP SCHEMA "+pgx.Identifier{schema}.Sanitize()+" CASCADE")
+		_ = admin.Close(context.Background())
+	})
+
+	cfg, err := pgxpool.ParseConfig(testDSN)
+	if err != nil {
+		t.Fatalf("parse dsn: %v", err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("create pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	if err := db.MigrateUp(pool); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	return pool
+}
+
+// testOrder возвращает заказ с доставкой, оплатой и двумя товарами.
+func testOrder(uid, customerID string, created time.Time) *model.Order {
+	return &model.Order{
+		OrderUID:        uid,
+		TrackNumber:     "WBILMTESTTRACK",
+		Entry:           "WBIL",
+		Locale:          "en",
+		CustomerID:      customerID,
+		DeliveryService: "meest",
+		Shardkey:        "9",
+		SmID:            99,
+		DateCreated:     created.UTC().Truncate(time.Microsecond),
+		OofShard:        "1",
+		Delivery:        model.Delivery{Name: "Test Testov", Phone: "+9720000000", City: "Kiryat Mozkin", Email: "test@gmail.com"},
+		Payment:         model.Payment{Transaction: uid, Currency: "USD", Provider: "wbpay", Amount: 1817, DeliveryCost: 1500},
+		Items: []model.Item{
+			{ChrtID: 9934930, TrackNumber: "WBILMTESTTRACK", Price: 453, Name: "Mascaras", Status: 202},
+			{ChrtID: 9934931, TrackNumber: "WBILMTESTTRACK", Price: 100, Name: "Brush", Status: 202},
+		},
+	}
+}
+
+// saveOrder вставляет заказ во все таблицы по отдельности.
+func saveOrder(t *testing.T, repos *Repositories, o *model.Order) {
+	t.Helper()
+	ctx := context.Background()
+	if err := repos.Orders.Insert(ctx, o); err != nil {
+		t.Fatalf("insert order: %v", err)
+	}
+	if err := repos.Deliveries.Insert(ctx, &o.Delivery, o.OrderUID); err != nil {
+		t.Fatalf("insert delivery: %v", err)
+	}
+	if err := repos.Payments.Insert(ctx, &o.Payment, o.OrderUID); err != nil {
+		t.Fatalf("insert payment: %v", err)
+	}
+	if err := repos.Items.Insert(ctx, o.Items, o.OrderUID); err != nil {
+		t.Fatalf("insert items: %v", err)
+	}
+}
+
+// TestOrderRepositoriesRoundTrip проверяет вставку и чтение заказа по частям и целиком.
+func TestOrderRepositoriesRoundTrip(t *testing.T) {
+	repos := NewRepositories(testDB(t))
+	ctx := context.Background()
+	want := testOrder("order-1", "alice", time.Now())
+	saveOrder(t, repos, want)
+
+	head, err := repos.Orders.GetByID(ctx, want.OrderUID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if head.TrackNumber != want.TrackNumber || !head.DateCreated.Equal(want.DateCreated) || head.CreatedAt == nil || head.UpdatedAt == nil {
+		t.Errorf("unexpected order header: %+v", head)
+	}
+	if d, err := repos.Deliveries.GetByOrderID(ctx, want.OrderUID); err != nil || *d != want.Delivery {
+		t.Errorf("GetByOrderID delivery = %+v, %v", d, err)
+	}
+	if p, err := repos.Payments.GetByOrderID(ctx, want.OrderUID); err != nil || *p != want.Payment {
+		t.Errorf("GetByOrderID payment = %+v, %v", p, err)
+	}
+	if items, err := repos.Items.GetByOrderID(ctx, want.OrderUID); err != nil || len(items) != 2 {
+		t.Errorf("GetByOrderID items = %+v, %v", items, err)
+	}
+
+	full, err := repos.Orders.GetFullByID(ctx, want.OrderUID)
+	if err != nil {
+		t.Fatalf("GetFullByID: %v", err)
+	}
+	if full.Delivery != want.Delivery || full.Payment != want.Payment || len(full.Items) != 2 || full.Items[1] != want.Items[1] {
+		t.Errorf("unexpected full order: %+v", full)
+	}
+
+	for name, err := range map[string]error{
+		"orders":     errOf(repos.Orders.GetByID(ctx, "missing")),
+		"full order": errOf(repos.Orders.GetFullByID(ctx, "missing")),
+		"deliveries": errOf(repos.Deliveries.GetByOrderID(ctx, "missing")),
+		"payments":   errOf(repos.Payments.GetByOrderID(ctx, "missing")),
+	} {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			t.Errorf("%s: expected pgx.ErrNoRows for missing order, got %v", name, err)
+		}
+	}
+	if err := repos.Orders.Insert(ctx, want); err == nil {
+		t.Error("expected duplicate order_uid to be rejected")
+	}
+}
+
+// errOf возвращает ошибку из пары (значение, ошибка).
+func errOf[T any](_ T, err error) error {
+	return err
+}
+
+// TestOrderRepositoriesUpdate проверяет замену данных заказа и обновление updated_at.
+func TestOrderRepositoriesUpdate(t *testing.T) {
+	repos := NewRepositories(testDB(t))
+	ctx := context.Background()
+	o := testOrder("order-1", "alice", time.Now())
+	saveOrder(t, repos, o)
+	before, err := repos.Orders.GetByID(ctx, o.OrderUID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	o.Locale = "ru"
+	o.Delivery.City = "Tel Aviv"
+	o.Payment.Amount = 2000
+	o.Items = o.Items[:1]
+	if err := repos.Orders.Update(ctx, o); err != nil {
+		t.Fatalf("update order: %v", err)
+	}
+	if err := repos.Deliveries.Update(ctx, &o.Delivery, o.OrderUID); err != nil {
+		t.Fatalf("update delivery: %v", err)
+	}
+	if err := repos.Payments.Update(ctx, &o.Payment, o.OrderUID); err != nil {
+		t.Fatalf("update payment: %v", err)
+	}
+	if err := repos.Items.DeleteByOrderID(ctx, o.OrderUID); err != nil {
+		t.Fatalf("delete items: %v", err)
+	}
+	if err := repos.Items.Insert(ctx, o.Items, o.OrderUID); err != nil {
+		t.Fatalf("insert items: %v", err)
+	}
+
+	full, err := repos.Orders.GetFullByID(ctx, o.OrderUID)
+	if err != nil {
+		t.Fatalf("GetFullByID: %v", err)
+	}
+	if full.Locale != "ru" || full.Delivery.City != "Tel Aviv" || full.Payment.Amount != 2000 || len(full.Items) != 1 {
+		t.Errorf("update not applied: %+v", full)
+	}
+	if full.UpdatedAt.Before(*before.UpdatedAt) || !full.CreatedAt.Equal(*before.CreatedAt) {
+		t.Errorf("unexpected audit timestamps: before %v/%v, after %v/%v", before.CreatedAt, before.UpdatedAt, full.CreatedAt, full.UpdatedAt)
+	}
+}
+
+// TestInsertManyAndList проверяет пакетную вставку COPY, фильтры и keyset-пагинацию.
+func TestInsertManyAndList(t *testing.T) {
+	repos := NewRepositories(testDB(t))
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	orders := []*model.Order{
+		testOrder("a", "alice", base),
+		testOrder("b", "bob", base.Add(time.Hour)),
+		testOrder("c", "alice", base.Add(2*time.Hour)),
+	}
+	if err := repos.Orders.InsertMany(ctx, orders); err != nil {
+		t.Fatalf("orders InsertMany: %v", err)
+	}
+	if err := repos.Deliveries.InsertMany(ctx, orders); err != nil {
+		t.Fatalf("deliveries InsertMany: %v", err)
+	}
+	if err := repos.Payments.InsertMany(ctx, orders); err != nil {
+		t.Fatalf("payments InsertMany: %v", err)
+	}
+	if err := repos.Items.InsertMany(ctx, orders); err != nil {
+		t.Fatalf("items InsertMany: %v", err)
+	}
+
+	page, next, err := repos.Orders.List(ctx, OrderFilter{}, nil, 2)
+	if err != nil || len(page) != 2 || page[0].OrderUID != "c" || page[1].OrderUID != "b" || next == nil {
+		t.Fatalf("first page = %v, %v, %v", uids(page), next, err)
+	}
+	page, next, err = repos.Orders.List(ctx, OrderFilter{}, next, 2)
+	if err != nil || len(page) != 1 || page[0].OrderUID != "a" || next != nil {
+		t.Fatalf("second page = %v, %v, %v", uids(page), next, err)
+	}
+	page, _, err = repos.Orders.List(ctx, OrderFilter{CustomerID: "alice", From: base.Add(time.Minute)}, nil, 10)
+	if err != nil || len(page) != 1 || page[0].OrderUID != "c" {
+		t.Errorf("filtered page = %v, %v", uids(page), err)
+	}
+
+	history, next, err := repos.Orders.GetByCustomerID(ctx, "alice", nil, 1)
+	if err != nil || len(history) != 1 || history[0].OrderUID != "c" || len(history[0].Items) != 2 || next == nil {
+		t.Fatalf("customer history = %v, %v, %v", uids(history), next, err)
+	}
+	history, next, err = repos.Orders.GetByCustomerID(ctx, "alice", next, 1)
+	if err != nil || len(history) != 1 || history[0].OrderUID != "a" || next != nil {
+		t.Errorf("customer history second page = %v, %v, %v", uids(history), next, err)
+	}
+
+	ids, err := repos.Orders.GetAllOrderIDs(ctx)
+	if err != nil || len(ids) != 3 || ids[0] != "a" || ids[2] != "c" {
+		t.Errorf("GetAllOrderIDs = %v, %v", ids, err)
+	}
+}
+
+// uids возвращает order_uid заказов для сообщений об ошибках.
+func uids(orders []*model.Order) []string {
+	result := make([]string, 0, len(orders))
+	for _, o := range orders {
+		result = append(result, o.OrderUID)
+	}
+	return result
+}
+
+// TestOrderMarks проверяет мягкое удаление, восстановление и архивацию.
+func TestOrderMarks(t *testing.T) {
+	repos := NewRepositories(testDB(t))
+	ctx := context.Background()
+	saveOrder(t, repos, testOrder("order-1", "alice", time.Now()))
+
+	if err := repos.Orders.SoftDelete(ctx, "order-1"); err != nil {
+		t.Fatalf("SoftDelete: %v", err)
+	}
+	if ids, _ := repos.Orders.GetAllOrderIDs(ctx); len(ids) != 0 {
+		t.Errorf("deleted order listed: %v", ids)
+	}
+	if page, _, _ := repos.Orders.List(ctx, OrderFilter{}, nil, 10); len(page) != 0 {
+		t.Errorf("deleted order listed: %v", uids(page))
+	}
+	if o, err := repos.Orders.GetByID(ctx, "order-1"); err != nil || o.DeletedAt == nil {
+		t.Errorf("deleted order by id = %+v, %v", o, err)
+	}
+
+	if err := repos.Orders.Restore(ctx, "order-1"); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if err := repos.Orders.Archive(ctx, "order-1"); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+	o, err := repos.Orders.GetFullByID(ctx, "order-1")
+	if err != nil || o.DeletedAt != nil || o.ArchivedAt == nil {
+		t.Errorf("restored archived order = %+v, %v", o, err)
+	}
+
+	for name, mark := range map[string]func(context.Context, string) error{
+		"SoftDelete": repos.Orders.SoftDelete,
+		"Restore":    repos.Orders.Restore,
+		"Archive":    repos.Orders.Archive,
+	} {
+		if err := mark(ctx, "missing"); !errors.Is(err, pgx.ErrNoRows) {
+			t.Errorf("%s: expected pgx.ErrNoRows for missing order, got %v", name, err)
+		}
+	}
+}
+
+// TestOrderStatus проверяет статус новых заказов и условный переход между статусами.
+func TestOrderStatus(t *testing.T) {
+	repos := NewRepositories(testDB(t))
+	ctx := context.Background()
+	saveOrder(t, repos, testOrder("order-1", "alice", time.Now()))
+
+	if o, err := repos.Orders.GetFullByID(ctx, "order-1"); err != nil || o.Status != model.StatusCreated {
+		t.Fatalf("new order = %+v, %v", o, err)
+	}
+	if err := repos.Orders.SetStatus(ctx, "order-1", model.StatusCreated, model.StatusPaid); err != nil {
+		t.Fatalf("SetStatus: %v", err)
+	}
+	// Статус уже изменён, поэтому повтор с прежним ожидаемым статусом не применяется
+	if err := repos.Orders.SetStatus(ctx, "order-1", model.StatusCreated, model.StatusCancelled); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows for stale status, got %v", err)
+	}
+	if o, err := repos.Orders.GetByID(ctx, "order-1"); err != nil || o.Status != model.StatusPaid {
+		t.Errorf("paid order = %+v, %v", o, err)
+	}
+
+	paid, _, err := repos.Orders.ListFull(ctx, OrderFilter{Status: model.StatusPaid}, nil, 10)
+	if err != nil || len(paid) != 1 || len(paid[0].Items) == 0 {
+		t.Errorf("paid orders = %v, %v", uids(paid), err)
+	}
+	created, _, err := repos.Orders.ListFull(ctx, OrderFilter{Status: model.StatusCreated}, nil, 10)
+	if err != nil || len(created) != 0 {
+		t.Errorf("created orders = %v, %v", uids(created), err)
+	}
+}
+
+// TestTxManager проверяет фиксацию, откат, блокировку сохранённых заказов и параметры транзакции.
+func TestTxManager(t *testing.T) {
+	pool := testDB(t)
+	repos := NewRepositories(pool)
+	tx := NewTxManager(pool)
+	ctx := context.Background()
+	saveOrder(t, repos, testOrder("existing", "alice", time.Now()))
+
+	errRollback := errors.New("rollback")
+	err := tx.WithinTx(ctx, func(ctx context.Context, txRepos *Repositories) error {
+		existing, err := txRepos.Orders.LockExisting(ctx, []string{"existing", "new"})
+		if err != nil {
+			return err
+		}
+		if !existing["existing"] || existing["new"] {
+			t.Errorf("LockExisting = %v", existing)
+		}
+		if err := txRepos.Orders.Insert(ctx, testOrder("new", "bob", time.Now())); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("WithinTx returned %v", err)
+	}
+	if _, err := repos.Orders.GetByID(ctx, "new"); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("rolled back order is visible: %v", err)
+	}
+
+	err = tx.WithinTx(ctx, func(ctx context.Context, txRepos *Repositories) error {
+		return txRepos.Orders.Insert(ctx, testOrder("new", "bob", time.Now()))
+	})
+	if err != nil {
+		t.Fatalf("WithinTx: %v", err)
+	}
+	if _, err := repos.Orders.GetByID(ctx, "new"); err != nil {
+		t.Errorf("committed order is not visible: %v", err)
+	}
+
+	err = tx.WithinTxOptions(ctx, TxOptions{IsoLevel: RepeatableRead, ReadOnly: true}, func(ctx context.Context, txRepos *Repositories) error {
+		return txRepos.Orders.Insert(ctx, testOrder("read-only", "carol", time.Now()))
+	})
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "25006" {
+		t.Errorf("write in read-only transaction: got %v, want read_only_sql_transaction", err)
+	}
+	if err := tx.WithinTxOptions(ctx, TxOptions{IsoLevel: "snapshot"}, func(context.Context, *Repositories) error { return nil }); err == nil {
+		t.Error("unknown isolation level must be rejected")
+	}
+}
+
+// TestOrderEventsRepository проверяет запись и чтение журнала изменений.
+func TestOrderEventsRepository(t *testing.T) {
+	repos := NewRepositories(testDB(t))
+	ctx := context.Background()
+
+	changes := []model.OrderChange{
+		{OrderUID: "order-1", Action: model.ChangeInsert, Actor: "kafka:orders", Diff: map[string]any{"locale": "en"}},
+		{OrderUID: "order-1", Action: model.ChangeUpdate, Actor: "api_key:partner", Diff: map[string]any{"locale": "ru"}},
+		{OrderUID: "order-2", Action: model.ChangeInsert, Actor: "kafka:orders", Diff: map[string]any{}},
+	}
+	if err := repos.Events.InsertMany(ctx, changes); err != nil {
+		t.Fatalf("InsertMany: %v", err)
+	}
+	got, err := repos.Events.ListByOrderID(ctx, "order-1")
+	if err != nil || len(got) != 2 || got[0].Action != model.ChangeInsert || got[1].Diff["locale"] != "ru" || got[1].OccurredAt.IsZero() {
+		t.Errorf("ListByOrderID = %+v, %v", got, err)
+	}
+	if got, err := repos.Events.ListByOrderID(ctx, "missing"); err != nil || len(got) != 0 {
+		t.Errorf("ListByOrderID(missing) = %+v, %v", got, err)
+	}
+}
+
+// TestEraseCustomerPII проверяет удаление персональных данных получателя из доставок и журнала изменений.
+func TestEraseCustomerPII(t *testing.T) {
+	repos := NewRepositories(testDB(t))
+	ctx := context.Background()
+	now := time.Now()
+	saveOrder(t, repos, testOrder("order-1", "alice", now))
+	saveOrder(t, repos, testOrder("order-2", "bob", now))
+
+	changes := []model.OrderChange{
+		{OrderUID: "order-1", Action: model.ChangeInsert, Actor: "kafka:orders",
+			Diff: map[string]any{"locale": "en", "delivery": map[string]any{"name": "Test Testov", "city": "Kiryat Mozkin"}}},
+		{OrderUID: "order-1", Action: model.ChangeUpdate, Actor: "kafka:orders",
+			Diff: map[string]any{"delivery.email": "new@gmail.com", "delivery.city": "Haifa"}},
+		{OrderUID: "order-2", Action: model.ChangeInsert, Actor: "kafka:orders",
+			Diff: map[string]any{"delivery": map[string]any{"name": "Test Testov"}}},
+	}
+	if err := repos.Events.InsertMany(ctx, changes); err != nil {
+		t.Fatalf("InsertMany: %v", err)
+	}
+
+	uids, err := repos.Deliveries.EraseByCustomerID(ctx, "alice")
+	if err != nil || len(uids) != 1 || uids[0] != "order-1" {
+		t.Fatalf("EraseByCustomerID = %v, %v", uids, err)
+	}
+	if d, err := repos.Deliveries.GetByOrderID(ctx, "order-1"); err != nil || d.Name != "" || d.Phone != "" || d.Email != "" || d.City != "Kiryat Mozkin" {
+		t.Errorf("erased delivery = %+v, %v", d, err)
+	}
+	if d, err := repos.Deliveries.GetByOrderID(ctx, "order-2"); err != nil || d.Name != "Test Testov" {
+		t.Errorf("other customer's delivery = %+v, %v", d, err)
+	}
+	if uids, err := repos.Deliveries.EraseByCustomerID(ctx, "nobody"); err != nil || len(uids) != 0 {
+		t.Errorf("EraseByCustomerID(nobody) = %v, %v", uids, err)
+	}
+
+	if n, err := repos.Events.EraseDeliveryPII(ctx, uids); err != nil || n != 2 {
+		t.Fatalf("EraseDeliveryPII = %d, %v", n, err)
+	}
+	got, err := repos.Events.ListByOrderID(ctx, "order-1")
+	if err != nil || len(got) != 2 {
+		t.Fatalf("ListByOrderID = %+v, %v", got, err)
+	}
+	if delivery, _ := got[0].Diff["delivery"].(map[string]any); delivery["name"] != nil || delivery["city"] != "Kiryat Mozkin" || got[0].Diff["locale"] != "en" {
+		t.Errorf("erased insert diff = %v", got[0].Diff)
+	}
+	if _, ok := got[1].Diff["delivery.email"]; ok || got[1].Diff["delivery.city"] != "Haifa" {
+		t.Errorf("erased update diff = %v", got[1].Diff)
+	}
+	if other, err := repos.Events.ListByOrderID(ctx, "order-2"); err != nil || other[0].Diff["delivery"].(map[string]any)["name"] != "Test Testov" {
+		t.Errorf("other customer's events = %+v, %v", other, err)
+	}
+}
+
+// TestDeliveryPIIEncryption проверяет хранение персональных данных в зашифрованном виде
+// и перешифрование строк, записанных открытым текстом.
+func TestDeliveryPIIEncryption(t *testing.T) {
+	pool := testDB(t)
+	repos := NewRepositories(pool)
+	ctx := context.Background()
+
+	// Заказ, сохранённый до включения шифрования
+	saveOrder(t, repos, testOrder("order-1", "alice", time.Now()))
+	if err := repos.Events.InsertMany(ctx, []model.OrderChange{{OrderUID: "order-1", Action: model.ChangeUpdate, Actor: "kafka:orders",
+		Diff: map[string]any{"delivery.email": "new@gmail.com"}}}); err != nil {
+		t.Fatalf("InsertMany: %v", err)
+	}
+
+	useTestCipher(t)
+	saveOrder(t, repos, testOrder("order-2", "alice", time.Now()))
+
+	rawEmail := func(uid string) string {
+		t.Helper()
+		var email string
+		if err := pool.QueryRow(ctx, `SELECT email FROM deliveries WHERE order_uid = $1`, uid).Scan(&email); err != nil {
+			t.Fatalf("select email: %v", err)
+		}
+		return email
+	}
+	if email := rawEmail("order-2"); !strings.HasPrefix(email, "enc:") {
+		t.Errorf("email stored unencrypted: %q", email)
+	}
+	for _, uid := range []string{"order-1", "order-2"} {
+		if o, err := repos.Orders.GetFullByID(ctx, uid); err != nil || o.Delivery.Email != "test@gmail.com" || o.Delivery.Phone != "+9720000000" {
+			t.Errorf("GetFullByID(%s) delivery = %+v, %v", uid, o.Delivery, err)
+		}
+	}
+
+	next, updated, err := ReencryptDeliveries(ctx, pool, "", 10)
+	if err != nil || next != "order-2" || updated != 1 {
+		t.Fatalf("ReencryptDeliveries = %q, %d, %v", next, updated, err)
+	}
+	if email := rawEmail("order-1"); !strings.HasPrefix(email, "enc:") {
+		t.Errorf("email not re-encrypted: %q", email)
+	}
+	if next, updated, err := ReencryptDeliveries(ctx, pool, next, 10); err != nil || next != "" || updated != 0 {
+		t.Errorf("ReencryptDeliveries(last page) = %q, %d, %v", next, updated, err)
+	}
+
+	if _, updated, err := ReencryptOrderEvents(ctx, pool, 0, 10); err != nil || updated != 1 {
+		t.Fatalf("ReencryptOrderEvents = %d, %v", updated, err)
+	}
+	changes, err := repos.Events.ListByOrderID(ctx, "order-1")
+	if err != nil || len(changes) != 1 || changes[0].Diff["delivery.email"] != "new@gmail.com" {
+		t.Errorf("ListByOrderID = %+v, %v", changes, err)
+	}
+}
+
+// TestOrderDedupRepository проверяет запись, выборку в окне и очистку контрольных сумм заказов.
+func TestOrderDedupRepository(t *testing.T) {
+	dedup := NewOrderDedupRepository(testDB(t))
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	if err := dedup.Upsert(ctx, map[string]string{"order-1": "a", "order-2": "b"}, now.Add(-2*time.Hour)); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if err := dedup.Upsert(ctx, map[string]string{"order-1": "c"}, now); err != nil {
+		t.Fatalf("Upsert existing: %v", err)
+	}
+	got, err := dedup.ListRecent(ctx, []string{"order-1", "order-2", "missing"}, now.Add(-time.Hour))
+	if err != nil || len(got) != 1 || got["order-1"] != "c" {
+		t.Errorf("ListRecent = %v, %v", got, err)
+	}
+	if n, err := dedup.DeleteBefore(ctx, now.Add(-time.Hour)); err != nil || n != 1 {
+		t.Errorf("DeleteBefore = %d, %v", n, err)
+	}
+	if got, err := dedup.ListRecent(ctx, []string{"order-2"}, time.Time{}); err != nil || len(got) != 0 {
+		t.Errorf("ListRecent after delete = %v, %v", got, err)
+	}
+}
+
+// TestAPIKeysAndIncidentsRepositories проверяет служебные таблицы api_keys и incidents.
+func TestAPIKeysAndIncidentsRepositories(t *testing.T) {
+	pool := testDB(t)
+	ctx := context.Background()
+
+	if _, err := pool.Exec(ctx, `INSERT INTO api_keys (key_hash, name, rate_limit, revoked) VALUES ('h1', 'partner', 10, true)`); err != nil {
+		t.Fatalf("insert api key: %v", err)
+	}
+	keys := NewAPIKeysRepository(pool)
+	if k, err := keys.GetByHash(ctx, "h1"); err != nil || k.Name != "partner" || k.RateLimit != 10 || !k.Revoked {
+		t.Errorf("GetByHash = %+v, %v", k, err)
+	}
+	if _, err := keys.GetByHash(ctx, "missing"); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows for missing key, got %v", err)
+	}
+
+	incidents := NewIncidentsRepository(pool)
+	started := time.Now().UTC().Truncate(time.Microsecond)
+	id, err := incidents.Insert(ctx, &model.Incident{Dependency: "kafka", StartedAt: started, LastError: "dial"})
+	if err != nil || id == 0 {
+		t.Fatalf("Insert incident = %d, %v", id, err)
+	}
+	ended := started.Add(time.Minute)
+	if err := incidents.Close(ctx, id, ended, "timeout"); err != nil {
+		t.Fatalf("Close incident: %v", err)
+	}
+	var gotEnded time.Time
+	var lastError string
+	if err := pool.QueryRow(ctx, `SELECT ended_at, last_error FROM incidents WHERE id = $1`, id).Scan(&gotEnded, &lastError); err != nil ||
+		!gotEnded.Equal(ended) || lastError != "timeout" {
+		t.Errorf("closed incident = %v %q, %v", gotEnded, lastError, err)
+	}
+}
+
+// TestAuditRepository проверяет запись и постраничную выборку журнала аудита.
+func TestAuditRepository(t *testing.T) {
+	audit := NewAuditRepository(testDB(t))
+	ctx := context.Background()
+
+	for _, e := range []model.AuditEntry{
+		{Actor: "ops", Method: "POST", Path: "/api/admin/cache/clear", Status: 200},
+		{Actor: "token", Method: "DELETE", Path: "/api/admin/orders/order-1", Params: map[string]any{"id": "order-1"}, Status: 200},
+		{Actor: "ops", Method: "PUT", Path: "/api/admin/log/level", Params: map[string]any{"body": map[string]any{"level": "debug"}}, Status: 200},
+	} {
+		if _, err := audit.Insert(ctx, &e); err != nil {
+			t.Fatalf("Insert: %v", err)
+		}
+	}
+
+	page, err := audit.List(ctx, AuditFilter{Actor: "ops"}, 1)
+	if err != nil || len(page) != 1 || page[0].Path != "/api/admin/log/level" || page[0].Params["body"] == nil {
+		t.Fatalf("List = %+v, %v", page, err)
+	}
+	page, err = audit.List(ctx, AuditFilter{Actor: "ops", BeforeID: page[0].ID}, 10)
+	if err != nil || len(page) != 1 || page[0].Path != "/api/admin/cache/clear" || len(page[0].Params) != 0 {
+		t.Errorf("List next page = %+v, %v", page, err)
+	}
+	if page, err := audit.List(ctx, AuditFilter{Path: "/api/admin/orders/"}, 10); err != nil || len(page) != 1 || page[0].Params["id"] != "order-1" {
+		t.Errorf("List by path = %+v, %v", page, err)
+	}
+}
+
+// TestStatsRepository проверяет агрегирующие запросы.
+func TestStatsRepository(t *testing.T) {
+	pool := testDB(t)
+	repos := NewRepositories(pool)
+	ctx := context.Background()
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	a := testOrder("a", "alice", day)
+	b := testOrder("b", "bob", day)
+	b.Payment.Currency = "RUB"
+	b.DeliveryService = "cdek"
+	c := testOrder("c", "alice", day.Add(24*time.Hour))
+	deleted := testOrder("d", "alice", day)
+	for _, o := range []*model.Order{a, b, c, deleted} {
+		saveOrder(t, repos, o)
+	}
+	if err := repos.Orders.SoftDelete(ctx, deleted.OrderUID); err != nil {
+		t.Fatalf("SoftDelete: %v", err)
+	}
+
+	stats := NewStatsRepository(pool)
+	perDay, err := stats.OrdersPerDay(ctx, day.Add(-time.Hour))
+	if err != nil || len(perDay) != 2 || perDay[0].Day != "2024-03-01" || perDay[0].Orders != 2 || len(perDay[0].Amounts) != 2 {
+		t.Errorf("OrdersPerDay = %+v, %v", perDay, err)
+	}
+	byCurrency, err := stats.AmountByCurrency(ctx)
+	if err != nil || len(byCurrency) != 2 || byCurrency[0] != (model.CurrencyAmount{Currency: "USD", Orders: 2, Amount: 2 * 1817}) {
+		t.Errorf("AmountByCurrency = %+v, %v", byCurrency, err)
+	}
+	top, err := stats.TopDeliveryServices(ctx, 1)
+	if err != nil || len(top) != 1 || top[0].DeliveryService != "meest" || top[0].Orders != 2 || len(top[0].Amounts) != 1 {
+		t.Errorf("TopDeliveryServices = %+v, %v", top, err)
+	}
+	if avg, err := stats.AvgItemsPerOrder(ctx); err != nil || avg != 2 {
+		t.Errorf("AvgItemsPerOrder = %v, %v", avg, err)
+	}
+}
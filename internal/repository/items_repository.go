@@ -3,60 +3,97 @@ package repository
 import (
 	"context"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"l0_wb/internal/model"
+	sqlcdb "l0_wb/internal/repository/db"
 )
 
 // ItemsRepository определяет методы для взаимодействия с таблицей 'items'.
 type ItemsRepository interface {
 	Insert(ctx context.Context, items []model.Item, orderUID string) error
+	InsertMany(ctx context.Context, orders []*model.Order) error
+	DeleteByOrderID(ctx context.Context, orderUID string) error
 	GetByOrderID(ctx context.Context, orderUID string) ([]model.Item, error)
 }
 
 type itemsRepository struct {
-	db *pgxpool.Pool
+	q       *sqlcdb.Queries // Запросы из queries/items.sql
+	metrics *MetricsWrapper
 }
 
 // NewItemsRepository создает новый экземпляр ItemsRepository.
 //
 //	Параметры:
-//	- db: подключение к базе данных (sql.DB).
+//	- db: пул соединений или активная транзакция.
 //	Возвращает:
 //	- ItemsRepository: экземпляр интерфейса для взаимодействия с таблицей 'items'.
-func NewItemsRepository(db *pgxpool.Pool) ItemsRepository {
-	return &itemsRepository{db: db}
+func NewItemsRepository(db DBTX) ItemsRepository {
+	return &itemsRepository{
+		q:       sqlcdb.New(db),
+		metrics: NewMetricsWrapper(),
+	}
+}
+
+// itemRow возвращает строку COPY для товара заказа.
+func itemRow(orderUID string, it *model.Item) sqlcdb.CopyItemsParams {
+	return sqlcdb.CopyItemsParams{
+		OrderUid:    orderUID,
+		ChrtID:      it.ChrtID,
+		TrackNumber: it.TrackNumber,
+		Price:       it.Price,
+		Rid:         it.Rid,
+		Name:        it.Name,
+		Sale:        it.Sale,
+		Size:        it.Size,
+		TotalPrice:  it.TotalPrice,
+		NmID:        it.NmID,
+		Brand:       it.Brand,
+		Status:      it.Status,
+	}
 }
 
 // Insert добавляет несколько записей о товарах в таблицу 'items'.
 //
+//	Все товары передаются одной командой COPY вместо отдельного INSERT на строку.
+//
 //	Параметры:
 //	- items: массив объектов model.Item, представляющих товары.
 //	- orderUID: уникальный идентификатор заказа.
 //	Возвращает:
 //	- error: ошибка при выполнении запроса (если возникла).
 func (r *itemsRepository) Insert(ctx context.Context, items []model.Item, orderUID string) error {
-	query := `INSERT INTO items (order_uid, chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status)
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
-	for _, it := range items {
-		_, err := r.db.Exec(ctx, query,
-			orderUID,
-			it.ChrtID,
-			it.TrackNumber,
-			it.Price,
-			it.Rid,
-			it.Name,
-			it.Sale,
-			it.Size,
-			it.TotalPrice,
-			it.NmID,
-			it.Brand,
-			it.Status,
-		)
-		if err != nil {
-			return err
+	if len(items) == 0 {
+		return nil
+	}
+	rows := make([]sqlcdb.CopyItemsParams, len(items))
+	for i := range items {
+		rows[i] = itemRow(orderUID, &items[i])
+	}
+	return r.metrics.RecordDBOperation(ctx, "copy", "items", true, func(ctx context.Context) error {
+		_, err := r.q.CopyItems(ctx, rows)
+		return err
+	})
+}
+
+// InsertMany добавляет товары нескольких заказов в таблицу 'items' одной командой COPY.
+//
+//	Параметры:
+//	- orders: заказы, товары которых вставляются.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *itemsRepository) InsertMany(ctx context.Context, orders []*model.Order) error {
+	rows := make([]sqlcdb.CopyItemsParams, 0, len(orders))
+	for _, o := range orders {
+		for i := range o.Items {
+			rows = append(rows, itemRow(o.OrderUID, &o.Items[i]))
 		}
 	}
-	return nil
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.metrics.RecordDBOperation(ctx, "copy", "items", true, func(ctx context.Context) error {
+		_, err := r.q.CopyItems(ctx, rows)
+		return err
+	})
 }
 
 // GetByOrderID получает все записи о товарах, связанных с указанным order_uid.
@@ -67,34 +104,44 @@ func (r *itemsRepository) Insert(ctx context.Context, items []model.Item, orderU
 //	- []model.Item: массив объектов товаров, если записи найдены.
 //	- error: ошибка при выполнении запроса (если возникла).
 func (r *itemsRepository) GetByOrderID(ctx context.Context, orderUID string) ([]model.Item, error) {
-	query := `SELECT chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status
-              FROM items WHERE order_uid = $1`
-	rows, err := r.db.Query(ctx, query, orderUID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
 	var items []model.Item
-	for rows.Next() {
-		var it model.Item
-		err := rows.Scan(
-			&it.ChrtID,
-			&it.TrackNumber,
-			&it.Price,
-			&it.Rid,
-			&it.Name,
-			&it.Sale,
-			&it.Size,
-			&it.TotalPrice,
-			&it.NmID,
-			&it.Brand,
-			&it.Status,
-		)
+
+	err := r.metrics.RecordDBOperation(ctx, "select", "items", false, func(ctx context.Context) error {
+		rows, err := r.q.GetItemsByOrderID(ctx, orderUID)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		items = append(items, it)
+		for _, row := range rows {
+			items = append(items, model.Item{
+				ChrtID:      row.ChrtID,
+				TrackNumber: row.TrackNumber,
+				Price:       row.Price,
+				Rid:         row.Rid,
+				Name:        row.Name,
+				Sale:        row.Sale,
+				Size:        row.Size,
+				TotalPrice:  row.TotalPrice,
+				NmID:        row.NmID,
+				Brand:       row.Brand,
+				Status:      row.Status,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return items, rows.Err()
+	return items, nil
+}
+
+// DeleteByOrderID удаляет все товары, связанные с указанным order_uid.
+//
+//	Параметры:
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *itemsRepository) DeleteByOrderID(ctx context.Context, orderUID string) error {
+	return r.metrics.RecordDBOperation(ctx, "delete", "items", true, func(ctx context.Context) error {
+		return r.q.DeleteItemsByOrderID(ctx, orderUID)
+	})
 }
@@ -2,11 +2,76 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"l0_wb/internal/breaker"
 	"l0_wb/internal/metrics"
+	"l0_wb/internal/sli"
 )
 
+// dbBreaker — автомат защиты, через который выполняются все операции репозиториев.
+var dbBreaker atomic.Pointer[breaker.Breaker]
+
+// SetCircuitBreaker включает автомат защиты для операций с базой данных.
+//
+//	Пока автомат разомкнут, операции репозиториев сразу возвращают
+//	breaker.ErrOpen, не занимая соединения пула.
+//
+//	Параметры:
+//	- b: автомат защиты (nil отключает его).
+func SetCircuitBreaker(b *breaker.Breaker) {
+	dbBreaker.Store(b)
+}
+
+// queryTimeout — крайний срок одной операции репозитория (0 — без ограничения).
+var queryTimeout atomic.Int64
+
+// SetQueryTimeout задаёт крайний срок каждой операции репозиториев.
+//
+//	Операция получает контекст с этим сроком, если срок вызывающего не
+//	наступает раньше, поэтому один зависший запрос не удерживает соединение
+//	пула дольше timeout. Транзакция WithinTx считается одной операцией:
+//	срок ограничивает её целиком, а запросы внутри неё — дополнительно.
+//
+//	Параметры:
+//	- timeout: крайний срок операции (0 отключает ограничение).
+func SetQueryTimeout(timeout time.Duration) {
+	queryTimeout.Store(int64(timeout))
+}
+
+// IsDBFailure сообщает, указывает ли ошибка на недоступность базы данных.
+//
+//	Ошибки, возвращённые сервером (*pgconn.PgError), и отсутствие строки
+//	означают, что база отвечает, и сбоем не считаются.
+//
+//	Параметры:
+//	- err: ошибка операции.
+//	Возвращает:
+//	- bool: true для ошибок соединения и таймаутов.
+func IsDBFailure(err error) bool {
+	if err == nil || errors.Is(err, pgx.ErrNoRows) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return false
+	}
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	return errors.As(err, &connectErr) ||
+		errors.As(err, &netErr) ||
+		pgconn.Timeout(err) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
 // MetricsWrapper предоставляет способ записи метрик для операций с базой данных.
 // Может использоваться для записи метрик TPS и QPS.
 type MetricsWrapper struct{}
@@ -19,6 +84,7 @@ func NewMetricsWrapper() *MetricsWrapper {
 // RecordDBOperation записывает метрики для операции с базой данных.
 //
 //	Измеряет продолжительность операции и записывает ее как метрику QPS.
+//	Операция выполняется с крайним сроком из SetQueryTimeout.
 //	Если операция является транзакцией, также записывает ее как метрику TPS.
 //
 //	Параметры:
@@ -36,10 +102,21 @@ func (mw *MetricsWrapper) RecordDBOperation(
 	isTransaction bool,
 	fn func(ctx context.Context) error,
 ) error {
+	if timeout := time.Duration(queryTimeout.Load()); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	startTime := time.Now()
 
-	// Выполнить операцию
-	err := fn(ctx)
+	// Выполнить операцию (через автомат защиты, если он включён)
+	var err error
+	if b := dbBreaker.Load(); b != nil {
+		err = b.Execute(func() error { return fn(ctx) })
+	} else {
+		err = fn(ctx)
+	}
 
 	// Записать продолжительность
 	duration := time.Since(startTime)
@@ -57,5 +134,11 @@ func (mw *MetricsWrapper) RecordDBOperation(
 		metrics.RecordError("database", operation+":"+table)
 	}
 
+	// Отсутствие строки — штатный результат запроса, а не сбой БД. Вызовы,
+	// отклонённые автоматом защиты, не учитываются, иначе он удерживал бы сам себя
+	if !errors.Is(err, breaker.ErrOpen) {
+		sli.Record(sli.SourceDB, err == nil || errors.Is(err, pgx.ErrNoRows))
+	}
+
 	return err
 }
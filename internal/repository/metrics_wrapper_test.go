@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRecordDBOperationTimeout проверяет, что операция получает крайний срок из SetQueryTimeout.
+func TestRecordDBOperationTimeout(t *testing.T) {
+	SetQueryTimeout(20 * time.Millisecond)
+	defer SetQueryTimeout(0)
+
+	mw := NewMetricsWrapper()
+	err := mw.RecordDBOperation(context.Background(), "select", "orders", false, func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("operation context has no deadline")
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+
+	// Более ранний срок вызывающего сохраняется
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	_ = mw.RecordDBOperation(ctx, "select", "orders", false, func(ctx context.Context) error {
+		if got, _ := ctx.Deadline(); !got.Equal(want) {
+			t.Errorf("deadline = %v, want caller deadline %v", got, want)
+		}
+		return nil
+	})
+
+	SetQueryTimeout(0)
+	_ = mw.RecordDBOperation(context.Background(), "select", "orders", false, func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("operation context must have no deadline when the timeout is disabled")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sqlcdb "l0_wb/internal/repository/db"
+)
+
+// OrderDedupRepository определяет методы для взаимодействия с таблицей 'order_dedup'.
+//
+//	Таблица хранит контрольную сумму последнего сохранённого содержимого
+//	каждого заказа и время, когда оно было сохранено.
+type OrderDedupRepository interface {
+	ListRecent(ctx context.Context, orderUIDs []string, since time.Time) (map[string]string, error)
+	Upsert(ctx context.Context, hashes map[string]string, seenAt time.Time) error
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type orderDedupRepository struct {
+	q       *sqlcdb.Queries // Запросы из queries/order_dedup.sql
+	metrics *MetricsWrapper
+}
+
+// NewOrderDedupRepository создает новый экземпляр OrderDedupRepository.
+//
+//	Параметры:
+//	- db: пул соединений или активная транзакция.
+//	Возвращает:
+//	- OrderDedupRepository: экземпляр интерфейса для взаимодействия с таблицей 'order_dedup'.
+func NewOrderDedupRepository(db DBTX) OrderDedupRepository {
+	return &orderDedupRepository{
+		q:       sqlcdb.New(db),
+		metrics: NewMetricsWrapper(),
+	}
+}
+
+// ListRecent возвращает контрольные суммы заказов, сохранённых не раньше since.
+//
+//	Параметры:
+//	- orderUIDs: идентификаторы заказов.
+//	- since: начало окна дедупликации.
+//	Возвращает:
+//	- map[string]string: контрольные суммы по order_uid; заказов вне окна в ней нет.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *orderDedupRepository) ListRecent(ctx context.Context, orderUIDs []string, since time.Time) (map[string]string, error) {
+	hashes := make(map[string]string, len(orderUIDs))
+	if len(orderUIDs) == 0 {
+		return hashes, nil
+	}
+
+	err := r.metrics.RecordDBOperation(ctx, "select", "order_dedup", false, func(ctx context.Context) error {
+		rows, err := r.q.ListRecentOrderDedup(ctx, sqlcdb.ListRecentOrderDedupParams{OrderUids: orderUIDs, Since: since})
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			hashes[row.OrderUid] = row.PayloadHash
+		}
+		return nil
+	})
+
+	return hashes, err
+}
+
+// Upsert записывает контрольные суммы сохранённых заказов одним запросом.
+//
+//	Параметры:
+//	- hashes: контрольные суммы по order_uid.
+//	- seenAt: время сохранения.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *orderDedupRepository) Upsert(ctx context.Context, hashes map[string]string, seenAt time.Time) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	uids := make([]string, 0, len(hashes))
+	sums := make([]string, 0, len(hashes))
+	for uid, hash := range hashes {
+		uids = append(uids, uid)
+		sums = append(sums, hash)
+	}
+
+	return r.metrics.RecordDBOperation(ctx, "upsert", "order_dedup", true, func(ctx context.Context) error {
+		return r.q.UpsertOrderDedup(ctx, sqlcdb.UpsertOrderDedupParams{OrderUids: uids, PayloadHashes: sums, SeenAt: seenAt})
+	})
+}
+
+// DeleteBefore удаляет записи, вышедшие за окно дедупликации.
+//
+//	Параметры:
+//	- before: записи, сохранённые раньше этого времени, удаляются.
+//	Возвращает:
+//	- int64: число удалённых записей.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *orderDedupRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+
+	err := r.metrics.RecordDBOperation(ctx, "delete", "order_dedup", true, func(ctx context.Context) error {
+		var err error
+		deleted, err = r.q.DeleteOrderDedupBefore(ctx, before)
+		return err
+	})
+	return deleted, err
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"l0_wb/internal/model"
+	sqlcdb "l0_wb/internal/repository/db"
+)
+
+// OrderEventsRepository определяет методы для взаимодействия с журналом изменений 'order_events'.
+type OrderEventsRepository interface {
+	InsertMany(ctx context.Context, changes []model.OrderChange) error
+	ListByOrderID(ctx context.Context, orderUID string) ([]model.OrderChange, error)
+	EraseDeliveryPII(ctx context.Context, orderUIDs []string) (int64, error)
+}
+
+type orderEventsRepository struct {
+	q       *sqlcdb.Queries // Запросы из queries/order_events.sql
+	metrics *MetricsWrapper
+}
+
+// NewOrderEventsRepository создает новый экземпляр OrderEventsRepository.
+//
+//	Параметры:
+//	- db: пул соединений или активная транзакция.
+//	Возвращает:
+//	- OrderEventsRepository: экземпляр интерфейса для взаимодействия с таблицей 'order_events'.
+func NewOrderEventsRepository(db DBTX) OrderEventsRepository {
+	return &orderEventsRepository{
+		q:       sqlcdb.New(db),
+		metrics: NewMetricsWrapper(),
+	}
+}
+
+// InsertMany добавляет записи журнала изменений одной командой COPY.
+//
+//	Записи с нулевым OccurredAt получают текущее время.
+//
+//	Параметры:
+//	- changes: записи журнала изменений.
+//	Возвращает:
+//	- error: ошибка сериализации изменений или выполнения запроса (если возникла).
+func (r *orderEventsRepository) InsertMany(ctx context.Context, changes []model.OrderChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	rows := make([]sqlcdb.CopyOrderEventsParams, len(changes))
+	for i := range changes {
+		c := &changes[i]
+		sealed, err := sealDiff(c.Diff)
+		if err != nil {
+			return fmt.Errorf("encrypt diff of order %s: %w", c.OrderUID, err)
+		}
+		diff, err := json.Marshal(sealed)
+		if err != nil {
+			return fmt.Errorf("marshal diff of order %s: %w", c.OrderUID, err)
+		}
+		occurredAt := c.OccurredAt
+		if occurredAt.IsZero() {
+			occurredAt = now
+		}
+		rows[i] = sqlcdb.CopyOrderEventsParams{
+			OrderUid:   c.OrderUID,
+			Action:     c.Action,
+			Actor:      c.Actor,
+			OccurredAt: occurredAt,
+			Diff:       diff,
+		}
+	}
+	return r.metrics.RecordDBOperation(ctx, "copy", "order_events", true, func(ctx context.Context) error {
+		_, err := r.q.CopyOrderEvents(ctx, rows)
+		return err
+	})
+}
+
+// ListByOrderID возвращает журнал изменений заказа в порядке записи.
+//
+//	Параметры:
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- []model.OrderChange: записи журнала (пустой список, если изменений не было).
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *orderEventsRepository) ListByOrderID(ctx context.Context, orderUID string) ([]model.OrderChange, error) {
+	var changes []model.OrderChange
+
+	err := r.metrics.RecordDBOperation(ctx, "select", "order_events", false, func(ctx context.Context) error {
+		rows, err := r.q.ListOrderEventsByOrderID(ctx, orderUID)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			c := model.OrderChange{ID: row.ID, OrderUID: row.OrderUid, Action: row.Action, Actor: row.Actor, OccurredAt: row.OccurredAt}
+			if err := json.Unmarshal(row.Diff, &c.Diff); err != nil {
+				return fmt.Errorf("unmarshal diff of order %s: %w", c.OrderUID, err)
+			}
+			if c.Diff, err = openDiff(c.Diff); err != nil {
+				return fmt.Errorf("decrypt diff of order %s: %w", c.OrderUID, err)
+			}
+			changes = append(changes, c)
+		}
+		return nil
+	})
+
+	return changes, err
+}
+
+// EraseDeliveryPII удаляет персональные данные получателя из записей журнала изменений заказов.
+//
+//	Из diff удаляются пути delivery.name, delivery.phone, delivery.zip,
+//	delivery.address и delivery.email, а из вложенного объекта delivery
+//	(запись о первом сохранении) — соответствующие поля.
+//
+//	Параметры:
+//	- orderUIDs: идентификаторы заказов.
+//	Возвращает:
+//	- int64: число просмотренных записей журнала.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *orderEventsRepository) EraseDeliveryPII(ctx context.Context, orderUIDs []string) (int64, error) {
+	if len(orderUIDs) == 0 {
+		return 0, nil
+	}
+	var n int64
+
+	err := r.metrics.RecordDBOperation(ctx, "update", "order_events", true, func(ctx context.Context) error {
+		var err error
+		n, err = r.q.EraseOrderEventsDeliveryPII(ctx, orderUIDs)
+		return err
+	})
+	return n, err
+}
@@ -2,36 +2,90 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 	"l0_wb/internal/model"
+	"l0_wb/internal/pagination"
+	sqlcdb "l0_wb/internal/repository/db"
 )
 
 // OrdersRepository определяет методы для взаимодействия с таблицей 'orders'.
 type OrdersRepository interface {
 	Insert(ctx context.Context, order *model.Order) error
+	InsertMany(ctx context.Context, orders []*model.Order) error
+	Update(ctx context.Context, order *model.Order) error
 	GetByID(ctx context.Context, orderUID string) (*model.Order, error)
+	GetFullByID(ctx context.Context, orderUID string) (*model.Order, error)
+	GetByCustomerID(ctx context.Context, customerID string, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor, error)
+	GetAllOrderIDs(ctx context.Context) ([]string, error)
+	GetOrderIDsSince(ctx context.Context, since time.Time) ([]string, error)
+	LockExisting(ctx context.Context, orderUIDs []string) (map[string]bool, error)
+	SoftDelete(ctx context.Context, orderUID string) error
+	Restore(ctx context.Context, orderUID string) error
+	Archive(ctx context.Context, orderUID string) error
+	SetStatus(ctx context.Context, orderUID string, from, to model.OrderStatus) error
+	List(ctx context.Context, filter OrderFilter, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor, error)
+	ListFull(ctx context.Context, filter OrderFilter, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor, error)
 }
 
 type ordersRepository struct {
-	db      *pgxpool.Pool
+	q       *sqlcdb.Queries // Запросы из queries/orders.sql к основной БД
+	readQ   *sqlcdb.Queries // Те же запросы через read
+	read    DBTX            // Подключение для запросов только на чтение (реплика или db)
 	metrics *MetricsWrapper
 }
 
 // NewOrdersRepository создает новый экземпляр OrdersRepository.
 //
 //	Параметры:
-//	- db: подключение к базе данных (sql.DB).
+//	- db: пул соединений или активная транзакция.
 //	Возвращает:
 //	- OrdersRepository: экземпляр интерфейса для взаимодействия с таблицей 'orders'.
-func NewOrdersRepository(db *pgxpool.Pool) OrdersRepository {
+func NewOrdersRepository(db DBTX) OrdersRepository {
+	return NewOrdersRepositoryWithReads(db, db)
+}
+
+// NewOrdersRepositoryWithReads создает OrdersRepository с отдельным подключением для чтения.
+//
+//	Через read выполняются GetByID, GetFullByID, List, ListFull и GetByCustomerID; запись,
+//	блокировки и выгрузка идентификаторов для прогрева кэша идут через db.
+//
+//	Параметры:
+//	- db: подключение к основной БД.
+//	- read: подключение для запросов только на чтение, например NewReadDB.
+//	Возвращает:
+//	- OrdersRepository: экземпляр интерфейса для взаимодействия с таблицей 'orders'.
+func NewOrdersRepositoryWithReads(db, read DBTX) OrdersRepository {
 	return &ordersRepository{
-		db:      db,
+		q:       sqlcdb.New(db),
+		readQ:   sqlcdb.New(read),
+		read:    read,
 		metrics: NewMetricsWrapper(),
 	}
 }
 
+// orderParams возвращает значения столбцов таблицы 'orders' для вставки и обновления заказа.
+func orderParams(o *model.Order) sqlcdb.InsertOrderParams {
+	return sqlcdb.InsertOrderParams{
+		OrderUid:          o.OrderUID,
+		TrackNumber:       o.TrackNumber,
+		Entry:             o.Entry,
+		Locale:            o.Locale,
+		InternalSignature: o.InternalSignature,
+		CustomerID:        o.CustomerID,
+		DeliveryService:   o.DeliveryService,
+		Shardkey:          o.Shardkey,
+		SmID:              o.SmID,
+		DateCreated:       o.DateCreated,
+		OofShard:          o.OofShard,
+		AmountMismatch:    o.AmountMismatch,
+	}
+}
+
 // Insert добавляет новую запись о заказе в таблицу 'orders'.
 //
 //	Параметры:
@@ -40,23 +94,7 @@ func NewOrdersRepository(db *pgxpool.Pool) OrdersRepository {
 //	- error: ошибка при выполнении запроса (если возникла).
 func (r *ordersRepository) Insert(ctx context.Context, order *model.Order) error {
 	return r.metrics.RecordDBOperation(ctx, "insert", "orders", true, func(ctx context.Context) error {
-		query := `INSERT INTO orders (order_uid, track_number, entry, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard)
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
-
-		_, err := r.db.Exec(ctx, query,
-			order.OrderUID,
-			order.TrackNumber,
-			order.Entry,
-			order.Locale,
-			order.InternalSignature,
-			order.CustomerID,
-			order.DeliveryService,
-			order.Shardkey,
-			order.SmID,
-			order.DateCreated,
-			order.OofShard,
-		)
-		return err
+		return r.q.InsertOrder(ctx, orderParams(order))
 	})
 }
 
@@ -71,34 +109,556 @@ func (r *ordersRepository) GetByID(ctx context.Context, orderUID string) (*model
 	var order *model.Order
 
 	err := r.metrics.RecordDBOperation(ctx, "select", "orders", false, func(ctx context.Context) error {
-		query := `SELECT order_uid, track_number, entry, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard
-              FROM orders WHERE order_uid = $1`
-
-		row := r.db.QueryRow(ctx, query, orderUID)
-		var o model.Order
-		var dateCreated time.Time
-
-		err := row.Scan(
-			&o.OrderUID,
-			&o.TrackNumber,
-			&o.Entry,
-			&o.Locale,
-			&o.InternalSignature,
-			&o.CustomerID,
-			&o.DeliveryService,
-			&o.Shardkey,
-			&o.SmID,
-			&dateCreated,
-			&o.OofShard,
-		)
+		row, err := r.readQ.GetOrderByID(ctx, orderUID)
 		if err != nil {
 			return err
 		}
 
-		o.DateCreated = dateCreated
-		order = &o
+		order = &model.Order{
+			OrderUID:          row.OrderUid,
+			TrackNumber:       row.TrackNumber,
+			Entry:             row.Entry,
+			Locale:            row.Locale,
+			InternalSignature: row.InternalSignature,
+			CustomerID:        row.CustomerID,
+			DeliveryService:   row.DeliveryService,
+			Shardkey:          row.Shardkey,
+			SmID:              row.SmID,
+			DateCreated:       row.DateCreated,
+			OofShard:          row.OofShard,
+			DeletedAt:         row.DeletedAt,
+			ArchivedAt:        row.ArchivedAt,
+			CreatedAt:         &row.CreatedAt,
+			UpdatedAt:         &row.UpdatedAt,
+			Status:            model.OrderStatus(row.Status),
+			AmountMismatch:    row.AmountMismatch,
+		}
 		return nil
 	})
 
 	return order, err
 }
+
+// Update заменяет данные заказа в таблице 'orders'.
+//
+//	Параметры:
+//	- order: объект model.Order с новым состоянием заказа.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *ordersRepository) Update(ctx context.Context, order *model.Order) error {
+	return r.metrics.RecordDBOperation(ctx, "update", "orders", true, func(ctx context.Context) error {
+		return r.q.UpdateOrder(ctx, sqlcdb.UpdateOrderParams(orderParams(order)))
+	})
+}
+
+// InsertMany добавляет записи о заказах в таблицу 'orders' одной командой COPY.
+//
+//	created_at и updated_at не передаются и заполняются значением по умолчанию now().
+//
+//	Параметры:
+//	- orders: заказы для вставки.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *ordersRepository) InsertMany(ctx context.Context, orders []*model.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	rows := make([]sqlcdb.CopyOrdersParams, len(orders))
+	for i, o := range orders {
+		rows[i] = sqlcdb.CopyOrdersParams(orderParams(o))
+	}
+	return r.metrics.RecordDBOperation(ctx, "copy", "orders", true, func(ctx context.Context) error {
+		_, err := r.q.CopyOrders(ctx, rows)
+		return err
+	})
+}
+
+// fullOrderColumns — столбцы заказа вместе с доставкой, оплатой и товарами.
+//
+//	Товары собираются в JSON-массив в порядке вставки, поэтому заказ
+//	любого размера возвращается одной строкой. Строки заказов выбираются
+//	под псевдонимом o, см. fullOrderJoins.
+const fullOrderColumns = `o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature, o.customer_id,
+                  o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard, o.deleted_at, o.archived_at,
+                  o.created_at, o.updated_at, o.status, o.amount_mismatch,
+                  d.name, d.phone, d.zip, d.city, d.address, d.region, d.email,
+                  p.transaction, p.request_id, p.currency, p.provider, p.amount, p.payment_dt, p.bank,
+                  p.delivery_cost, p.goods_total, p.custom_fee,
+                  COALESCE((SELECT json_agg(json_build_object(
+                      'chrt_id', i.chrt_id, 'track_number', i.track_number, 'price', i.price, 'rid', i.rid,
+                      'name', i.name, 'sale', i.sale, 'size', i.size, 'total_price', i.total_price,
+                      'nm_id', i.nm_id, 'brand', i.brand, 'status', i.status) ORDER BY i.id)
+                   FROM items i WHERE i.order_uid = o.order_uid), '[]')`
+
+// fullOrderJoins присоединяет к заказам o их доставку и оплату.
+const fullOrderJoins = `
+              JOIN deliveries d ON d.order_uid = o.order_uid
+              JOIN payments p ON p.order_uid = o.order_uid`
+
+// scanFullOrder считывает строку, выбранную по fullOrderColumns.
+func scanFullOrder(row pgx.Row) (*model.Order, error) {
+	var o model.Order
+	var items []byte
+	d, p := &o.Delivery, &o.Payment
+
+	err := row.Scan(
+		&o.OrderUID,
+		&o.TrackNumber,
+		&o.Entry,
+		&o.Locale,
+		&o.InternalSignature,
+		&o.CustomerID,
+		&o.DeliveryService,
+		&o.Shardkey,
+		&o.SmID,
+		&o.DateCreated,
+		&o.OofShard,
+		&o.DeletedAt,
+		&o.ArchivedAt,
+		&o.CreatedAt,
+		&o.UpdatedAt,
+		&o.Status,
+		&o.AmountMismatch,
+		&d.Name,
+		&d.Phone,
+		&d.Zip,
+		&d.City,
+		&d.Address,
+		&d.Region,
+		&d.Email,
+		&p.Transaction,
+		&p.RequestID,
+		&p.Currency,
+		&p.Provider,
+		&p.Amount,
+		&p.PaymentDt,
+		&p.Bank,
+		&p.DeliveryCost,
+		&p.GoodsTotal,
+		&p.CustomFee,
+		&items,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := openDelivery(d); err != nil {
+		return nil, err
+	}
+	var rows []aggregatedItem
+	if err := json.Unmarshal(items, &rows); err != nil {
+		return nil, err
+	}
+	o.Items = make([]model.Item, len(rows))
+	for i := range rows {
+		o.Items[i] = rows[i].toModel()
+	}
+	return &o, nil
+}
+
+// aggregatedItem — товар, собранный json_build_object в fullOrderColumns.
+//
+//	Ключи JSON совпадают с именами колонок таблицы 'items' и не зависят от
+//	JSON-представления model.Item.
+type aggregatedItem struct {
+	ChrtID      int    `json:"chrt_id"`
+	TrackNumber string `json:"track_number"`
+	Price       int    `json:"price"`
+	Rid         string `json:"rid"`
+	Name        string `json:"name"`
+	Sale        int    `json:"sale"`
+	Size        string `json:"size"`
+	TotalPrice  int    `json:"total_price"`
+	NmID        int    `json:"nm_id"`
+	Brand       string `json:"brand"`
+	Status      int    `json:"status"`
+}
+
+// toModel преобразует строку товара в модель.
+func (r *aggregatedItem) toModel() model.Item {
+	return model.Item{
+		ChrtID:      r.ChrtID,
+		TrackNumber: r.TrackNumber,
+		Price:       r.Price,
+		Rid:         r.Rid,
+		Name:        r.Name,
+		Sale:        r.Sale,
+		Size:        r.Size,
+		TotalPrice:  r.TotalPrice,
+		NmID:        r.NmID,
+		Brand:       r.Brand,
+		Status:      r.Status,
+	}
+}
+
+// GetFullByID получает заказ вместе с доставкой, оплатой и товарами одним запросом.
+//
+//	Параметры:
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- *model.Order: полностью заполненный объект заказа.
+//	- error: ошибка при выполнении запроса (если возникла) или pgx.ErrNoRows, если заказ не найден.
+func (r *ordersRepository) GetFullByID(ctx context.Context, orderUID string) (*model.Order, error) {
+	var order *model.Order
+
+	err := r.metrics.RecordDBOperation(ctx, "select", "orders", false, func(ctx context.Context) error {
+		query := `SELECT ` + fullOrderColumns + `
+              FROM orders o` + fullOrderJoins + `
+              WHERE o.order_uid = $1`
+
+		var err error
+		order, err = scanFullOrder(r.read.QueryRow(ctx, query, orderUID))
+		return err
+	})
+
+	return order, err
+}
+
+// GetByCustomerID возвращает страницу истории заказов покупателя, от новых к старым.
+//
+//	Заказы заполняются полностью (доставка, оплата, товары), как в ListFull.
+//
+//	Параметры:
+//	- customerID: идентификатор покупателя.
+//	- after: позиция, после которой начинается страница (nil — с начала истории).
+//	- limit: максимальное число заказов на странице.
+//	Возвращает:
+//	- []*model.Order: заказы страницы.
+//	- *pagination.Cursor: позиция для следующей страницы или nil, если страница последняя.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *ordersRepository) GetByCustomerID(ctx context.Context, customerID string, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor, error) {
+	return r.ListFull(ctx, OrderFilter{CustomerID: customerID}, after, limit)
+}
+
+// ListFull возвращает страницу полностью заполненных заказов, от новых к старым.
+//
+//	Заказы заполняются полностью (доставка, оплата, товары) одним запросом;
+//	пагинация — keyset по (date_created, order_uid), как в List.
+//
+//	Параметры:
+//	- filter: условия отбора заказов.
+//	- after: позиция, после которой начинается страница (nil — с начала выборки).
+//	- limit: максимальное число заказов на странице.
+//	Возвращает:
+//	- []*model.Order: заказы страницы.
+//	- *pagination.Cursor: позиция для следующей страницы или nil, если страница последняя.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *ordersRepository) ListFull(ctx context.Context, filter OrderFilter, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor, error) {
+	var orders []*model.Order
+
+	err := r.metrics.RecordDBOperation(ctx, "select", "orders", false, func(ctx context.Context) error {
+		where, args := filter.where(after)
+		// Запрашиваем на одну строку больше, чтобы узнать, есть ли следующая страница
+		args = append(args, limit+1)
+		query := `SELECT ` + fullOrderColumns + `
+              FROM (SELECT * FROM orders` + where + `
+                    ORDER BY date_created DESC, order_uid DESC
+                    LIMIT $` + strconv.Itoa(len(args)) + `) o` + fullOrderJoins + `
+              ORDER BY o.date_created DESC, o.order_uid DESC`
+
+		rows, err := r.read.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			o, err := scanFullOrder(rows)
+			if err != nil {
+				return err
+			}
+			orders = append(orders, o)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nextPage(orders, limit)
+}
+
+// GetAllOrderIDs возвращает order_uid всех заказов, кроме мягко удалённых, в порядке создания.
+//
+//	Возвращает:
+//	- []string: список order_uid.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *ordersRepository) GetAllOrderIDs(ctx context.Context) ([]string, error) {
+	return r.queryOrderIDs(ctx, r.q.GetAllOrderIDs)
+}
+
+// GetOrderIDsSince возвращает order_uid заказов, созданных не раньше since, кроме мягко удалённых, в порядке создания.
+//
+//	Параметры:
+//	- since: нижняя граница даты создания.
+//	Возвращает:
+//	- []string: список order_uid.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *ordersRepository) GetOrderIDsSince(ctx context.Context, since time.Time) ([]string, error) {
+	return r.queryOrderIDs(ctx, func(ctx context.Context) ([]string, error) {
+		return r.q.GetOrderIDsSince(ctx, since)
+	})
+}
+
+// queryOrderIDs выполняет запрос, возвращающий столбец order_uid.
+func (r *ordersRepository) queryOrderIDs(ctx context.Context, query func(ctx context.Context) ([]string, error)) ([]string, error) {
+	var uids []string
+
+	err := r.metrics.RecordDBOperation(ctx, "select", "orders", false, func(ctx context.Context) error {
+		var err error
+		uids, err = query(ctx)
+		return err
+	})
+
+	return uids, err
+}
+
+// LockExisting находит уже сохранённые заказы и блокирует их строки до конца транзакции.
+//
+//	Вызывается на репозитории, привязанном к транзакции (см. TxManager).
+//
+//	Параметры:
+//	- orderUIDs: идентификаторы проверяемых заказов.
+//	Возвращает:
+//	- map[string]bool: множество order_uid сохранённых заказов.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *ordersRepository) LockExisting(ctx context.Context, orderUIDs []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+
+	err := r.metrics.RecordDBOperation(ctx, "select", "orders", true, func(ctx context.Context) error {
+		uids, err := r.q.LockExistingOrders(ctx, orderUIDs)
+		if err != nil {
+			return err
+		}
+		for _, uid := range uids {
+			existing[uid] = true
+		}
+		return nil
+	})
+
+	return existing, err
+}
+
+// setMark выполняет UPDATE служебной отметки заказа.
+//
+//	Возвращает pgx.ErrNoRows, если ни одна строка не обновлена.
+func (r *ordersRepository) setMark(ctx context.Context, update func(ctx context.Context) (int64, error)) error {
+	return r.metrics.RecordDBOperation(ctx, "update", "orders", true, func(ctx context.Context) error {
+		n, err := update(ctx)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return pgx.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// setMarkByID выполняет UPDATE служебной отметки заказа запросом с единственным параметром order_uid.
+func (r *ordersRepository) setMarkByID(ctx context.Context, update func(ctx context.Context, orderUID string) (int64, error), orderUID string) error {
+	return r.setMark(ctx, func(ctx context.Context) (int64, error) {
+		return update(ctx, orderUID)
+	})
+}
+
+// SoftDelete помечает заказ удалённым, не удаляя его данные.
+//
+//	Повторное удаление сохраняет время первого. Удалённые заказы не попадают
+//	в GetAllOrderIDs, List и GetByCustomerID, но остаются доступны по GetByID
+//	и GetFullByID.
+//
+//	Параметры:
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла) или pgx.ErrNoRows, если заказ не найден.
+func (r *ordersRepository) SoftDelete(ctx context.Context, orderUID string) error {
+	return r.setMarkByID(ctx, r.q.SoftDeleteOrder, orderUID)
+}
+
+// Restore снимает с заказа отметку удаления.
+//
+//	Параметры:
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла) или pgx.ErrNoRows, если заказ не найден.
+func (r *ordersRepository) Restore(ctx context.Context, orderUID string) error {
+	return r.setMarkByID(ctx, r.q.RestoreOrder, orderUID)
+}
+
+// Archive помечает заказ архивным.
+//
+//	Архивные заказы остаются во всех выборках, отметка лишь сообщает клиентам,
+//	что заказ перенесён в архив. Повторная архивация сохраняет время первой.
+//
+//	Параметры:
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла) или pgx.ErrNoRows, если заказ не найден.
+func (r *ordersRepository) Archive(ctx context.Context, orderUID string) error {
+	return r.setMarkByID(ctx, r.q.ArchiveOrder, orderUID)
+}
+
+// SetStatus переводит заказ из статуса from в статус to.
+//
+//	Обновление выполняется, только если текущий статус заказа равен from,
+//	поэтому параллельные переходы не перезаписывают друг друга. Допустимость
+//	перехода проверяет сервис.
+//
+//	Параметры:
+//	- orderUID: уникальный идентификатор заказа.
+//	- from: ожидаемый текущий статус.
+//	- to: новый статус.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла) или pgx.ErrNoRows, если заказ не найден,
+//	  удалён или его статус отличается от from.
+func (r *ordersRepository) SetStatus(ctx context.Context, orderUID string, from, to model.OrderStatus) error {
+	return r.setMark(ctx, func(ctx context.Context) (int64, error) {
+		return r.q.SetOrderStatus(ctx, sqlcdb.SetOrderStatusParams{
+			OrderUid:   orderUID,
+			FromStatus: string(from),
+			ToStatus:   string(to),
+		})
+	})
+}
+
+// OrderFilter содержит условия отбора заказов для List.
+//
+//	Нулевые значения полей не ограничивают выборку; From и To задают
+//	полуинтервал [From, To) по date_created.
+type OrderFilter struct {
+	From            time.Time
+	To              time.Time
+	CustomerID      string
+	DeliveryService string
+	Status          model.OrderStatus
+}
+
+// Match сообщает, удовлетворяет ли заказ условиям отбора, так же как условие WHERE фильтра.
+func (f OrderFilter) Match(o *model.Order) bool {
+	switch {
+	case o.DeletedAt != nil:
+		return false
+	case !f.From.IsZero() && o.DateCreated.Before(f.From):
+		return false
+	case !f.To.IsZero() && !o.DateCreated.Before(f.To):
+		return false
+	case f.CustomerID != "" && o.CustomerID != f.CustomerID:
+		return false
+	case f.DeliveryService != "" && o.DeliveryService != f.DeliveryService:
+		return false
+	case f.Status != "" && o.Status != f.Status:
+		return false
+	}
+	return true
+}
+
+// where возвращает условие WHERE и его аргументы для фильтра и позиции курсора.
+func (f OrderFilter) where(after *pagination.Cursor) (string, []any) {
+	// Мягко удалённые заказы в выборки не попадают
+	conds := []string{"deleted_at IS NULL"}
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, strings.ReplaceAll(cond, "?", "$"+strconv.Itoa(len(args))))
+	}
+
+	if !f.From.IsZero() {
+		add("date_created >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		add("date_created < ?", f.To)
+	}
+	if f.CustomerID != "" {
+		add("customer_id = ?", f.CustomerID)
+	}
+	if f.DeliveryService != "" {
+		add("delivery_service = ?", f.DeliveryService)
+	}
+	if f.Status != "" {
+		add("status = ?", f.Status)
+	}
+	if after != nil {
+		args = append(args, after.DateCreated, after.OrderUID)
+		conds = append(conds, "(date_created, order_uid) < ($"+strconv.Itoa(len(args)-1)+", $"+strconv.Itoa(len(args))+")")
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// List возвращает страницу заказов, упорядоченных по (date_created, order_uid) по убыванию.
+//
+//	Используется keyset-пагинация: следующая страница начинается строго после
+//	позиции курсора, поэтому стоимость запроса не зависит от глубины выборки.
+//	Заполняются только поля таблицы 'orders'; доставка, оплата и товары не загружаются.
+//
+//	Параметры:
+//	- filter: условия отбора заказов.
+//	- after: позиция, после которой начинается страница (nil — с начала выборки).
+//	- limit: максимальное число заказов на странице.
+//	Возвращает:
+//	- []*model.Order: заказы страницы.
+//	- *pagination.Cursor: позиция для следующей страницы или nil, если страница последняя.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *ordersRepository) List(ctx context.Context, filter OrderFilter, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor, error) {
+	var orders []*model.Order
+
+	err := r.metrics.RecordDBOperation(ctx, "select", "orders", false, func(ctx context.Context) error {
+		where, args := filter.where(after)
+		// Запрашиваем на одну строку больше, чтобы узнать, есть ли следующая страница
+		args = append(args, limit+1)
+		query := `SELECT order_uid, track_number, entry, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard,
+                  deleted_at, archived_at, created_at, updated_at, status, amount_mismatch
+              FROM orders` + where + `
+              ORDER BY date_created DESC, order_uid DESC
+              LIMIT $` + strconv.Itoa(len(args))
+
+		rows, err := r.read.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var o model.Order
+			err := rows.Scan(
+				&o.OrderUID,
+				&o.TrackNumber,
+				&o.Entry,
+				&o.Locale,
+				&o.InternalSignature,
+				&o.CustomerID,
+				&o.DeliveryService,
+				&o.Shardkey,
+				&o.SmID,
+				&o.DateCreated,
+				&o.OofShard,
+				&o.DeletedAt,
+				&o.ArchivedAt,
+				&o.CreatedAt,
+				&o.UpdatedAt,
+				&o.Status,
+				&o.AmountMismatch,
+			)
+			if err != nil {
+				return err
+			}
+			orders = append(orders, &o)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nextPage(orders, limit)
+}
+
+// nextPage обрезает выборку из limit+1 заказов до страницы и возвращает курсор следующей.
+func nextPage(orders []*model.Order, limit int) ([]*model.Order, *pagination.Cursor, error) {
+	if len(orders) <= limit {
+		return orders, nil, nil
+	}
+	orders = orders[:limit]
+	last := orders[limit-1]
+	return orders, &pagination.Cursor{DateCreated: last.DateCreated, OrderUID: last.OrderUID}, nil
+}
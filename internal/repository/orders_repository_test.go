@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"l0_wb/internal/model"
+	"l0_wb/internal/pagination"
+)
+
+// TestOrderFilterWhere проверяет нумерацию параметров в условии отбора заказов.
+func TestOrderFilterWhere(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	after := &pagination.Cursor{DateCreated: from.Add(time.Hour), OrderUID: "b563"}
+
+	tests := []struct {
+		name   string
+		filter OrderFilter
+		after  *pagination.Cursor
+		where  string
+		args   int
+	}{
+		{name: "empty", where: " WHERE deleted_at IS NULL", args: 0},
+		{
+			name:   "filters",
+			filter: OrderFilter{From: from, CustomerID: "test"},
+			where:  " WHERE deleted_at IS NULL AND date_created >= $1 AND customer_id = $2",
+			args:   2,
+		},
+		{
+			name:   "filters with cursor",
+			filter: OrderFilter{DeliveryService: "meest"},
+			after:  after,
+			where:  " WHERE deleted_at IS NULL AND delivery_service = $1 AND (date_created, order_uid) < ($2, $3)",
+			args:   3,
+		},
+		{
+			name:   "status",
+			filter: OrderFilter{CustomerID: "test", Status: model.StatusPaid},
+			where:  " WHERE deleted_at IS NULL AND customer_id = $1 AND status = $2",
+			args:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := tt.filter.where(tt.after)
+			if where != tt.where {
+				t.Errorf("where = %q, want %q", where, tt.where)
+			}
+			if len(args) != tt.args {
+				t.Errorf("len(args) = %d, want %d", len(args), tt.args)
+			}
+		})
+	}
+}
+
+// TestOrderFilterMatch проверяет, что отбор заказа в памяти совпадает с условием WHERE.
+func TestOrderFilterMatch(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deleted := from
+	order := &model.Order{CustomerID: "test", DeliveryService: "meest", DateCreated: from, Status: model.StatusPaid}
+
+	tests := []struct {
+		name   string
+		filter OrderFilter
+		order  *model.Order
+		want   bool
+	}{
+		{"empty", OrderFilter{}, order, true},
+		{"period includes from", OrderFilter{From: from, To: from.Add(time.Hour)}, order, true},
+		{"period excludes to", OrderFilter{To: from}, order, false},
+		{"customer", OrderFilter{CustomerID: "other"}, order, false},
+		{"delivery service", OrderFilter{DeliveryService: "meest"}, order, true},
+		{"status", OrderFilter{Status: model.StatusCreated}, order, false},
+		{"deleted", OrderFilter{}, &model.Order{DateCreated: from, DeletedAt: &deleted}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(tt.order); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -3,28 +3,34 @@ package repository
 import (
 	"context"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"l0_wb/internal/model"
+	sqlcdb "l0_wb/internal/repository/db"
 )
 
 // PaymentsRepository определяет методы для взаимодействия с таблицей 'payments'.
 type PaymentsRepository interface {
 	Insert(ctx context.Context, payment *model.Payment, orderUID string) error
+	InsertMany(ctx context.Context, orders []*model.Order) error
+	Update(ctx context.Context, payment *model.Payment, orderUID string) error
 	GetByOrderID(ctx context.Context, orderUID string) (*model.Payment, error)
 }
 
 type paymentsRepository struct {
-	db *pgxpool.Pool
+	q       *sqlcdb.Queries // Запросы из queries/payments.sql
+	metrics *MetricsWrapper
 }
 
 // NewPaymentsRepository создает новый экземпляр PaymentsRepository.
 //
 //	Параметры:
-//	- db: подключение к базе данных (sql.DB).
+//	- db: пул соединений или активная транзакция.
 //	Возвращает:
 //	- PaymentsRepository: экземпляр интерфейса для взаимодействия с таблицей 'payments'.
-func NewPaymentsRepository(db *pgxpool.Pool) PaymentsRepository {
-	return &paymentsRepository{db: db}
+func NewPaymentsRepository(db DBTX) PaymentsRepository {
+	return &paymentsRepository{
+		q:       sqlcdb.New(db),
+		metrics: NewMetricsWrapper(),
+	}
 }
 
 // Insert добавляет новую запись о платеже в таблицу 'payments'.
@@ -35,23 +41,21 @@ func NewPaymentsRepository(db *pgxpool.Pool) PaymentsRepository {
 //	Возвращает:
 //	- error: ошибка при выполнении запроса (если возникла).
 func (r *paymentsRepository) Insert(ctx context.Context, payment *model.Payment, orderUID string) error {
-	query := `INSERT INTO payments (order_uid, transaction, request_id, currency, provider, amount, payment_dt, bank, delivery_cost, goods_total, custom_fee)
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
-
-	_, err := r.db.Exec(ctx, query,
-		orderUID,
-		payment.Transaction,
-		payment.RequestID,
-		payment.Currency,
-		payment.Provider,
-		payment.Amount,
-		payment.PaymentDt,
-		payment.Bank,
-		payment.DeliveryCost,
-		payment.GoodsTotal,
-		payment.CustomFee,
-	)
-	return err
+	return r.metrics.RecordDBOperation(ctx, "insert", "payments", true, func(ctx context.Context) error {
+		return r.q.InsertPayment(ctx, sqlcdb.InsertPaymentParams{
+			OrderUid:     orderUID,
+			Transaction:  payment.Transaction,
+			RequestID:    payment.RequestID,
+			Currency:     payment.Currency,
+			Provider:     payment.Provider,
+			Amount:       payment.Amount,
+			PaymentDt:    payment.PaymentDt,
+			Bank:         payment.Bank,
+			DeliveryCost: payment.DeliveryCost,
+			GoodsTotal:   payment.GoodsTotal,
+			CustomFee:    payment.CustomFee,
+		})
+	})
 }
 
 // GetByOrderID получает запись о платеже по order_uid.
@@ -62,24 +66,84 @@ func (r *paymentsRepository) Insert(ctx context.Context, payment *model.Payment,
 //	- *model.Payment: объект платежа, если запись найдена.
 //	- error: ошибка при выполнении запроса (если возникла) или sql.ErrNoRows, если запись не найдена.
 func (r *paymentsRepository) GetByOrderID(ctx context.Context, orderUID string) (*model.Payment, error) {
-	query := `SELECT transaction, request_id, currency, provider, amount, payment_dt, bank, delivery_cost, goods_total, custom_fee
-              FROM payments WHERE order_uid = $1`
-	row := r.db.QueryRow(ctx, query, orderUID)
-	var p model.Payment
-	err := row.Scan(
-		&p.Transaction,
-		&p.RequestID,
-		&p.Currency,
-		&p.Provider,
-		&p.Amount,
-		&p.PaymentDt,
-		&p.Bank,
-		&p.DeliveryCost,
-		&p.GoodsTotal,
-		&p.CustomFee,
-	)
+	var row sqlcdb.GetPaymentByOrderIDRow
+
+	err := r.metrics.RecordDBOperation(ctx, "select", "payments", false, func(ctx context.Context) error {
+		var err error
+		row, err = r.q.GetPaymentByOrderID(ctx, orderUID)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &p, nil
+	return &model.Payment{
+		Transaction:  row.Transaction,
+		RequestID:    row.RequestID,
+		Currency:     row.Currency,
+		Provider:     row.Provider,
+		Amount:       row.Amount,
+		PaymentDt:    row.PaymentDt,
+		Bank:         row.Bank,
+		DeliveryCost: row.DeliveryCost,
+		GoodsTotal:   row.GoodsTotal,
+		CustomFee:    row.CustomFee,
+	}, nil
+}
+
+// Update заменяет данные платежа заказа в таблице 'payments'.
+//
+//	Параметры:
+//	- payment: объект model.Payment с новыми данными платежа.
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *paymentsRepository) Update(ctx context.Context, payment *model.Payment, orderUID string) error {
+	return r.metrics.RecordDBOperation(ctx, "update", "payments", true, func(ctx context.Context) error {
+		return r.q.UpdatePayment(ctx, sqlcdb.UpdatePaymentParams{
+			OrderUid:     orderUID,
+			Transaction:  payment.Transaction,
+			RequestID:    payment.RequestID,
+			Currency:     payment.Currency,
+			Provider:     payment.Provider,
+			Amount:       payment.Amount,
+			PaymentDt:    payment.PaymentDt,
+			Bank:         payment.Bank,
+			DeliveryCost: payment.DeliveryCost,
+			GoodsTotal:   payment.GoodsTotal,
+			CustomFee:    payment.CustomFee,
+		})
+	})
+}
+
+// InsertMany добавляет записи о платежах заказов в таблицу 'payments' одной командой COPY.
+//
+//	Параметры:
+//	- orders: заказы, данные платежа которых вставляются.
+//	Возвращает:
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *paymentsRepository) InsertMany(ctx context.Context, orders []*model.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	rows := make([]sqlcdb.CopyPaymentsParams, len(orders))
+	for i, o := range orders {
+		p := &o.Payment
+		rows[i] = sqlcdb.CopyPaymentsParams{
+			OrderUid:     o.OrderUID,
+			Transaction:  p.Transaction,
+			RequestID:    p.RequestID,
+			Currency:     p.Currency,
+			Provider:     p.Provider,
+			Amount:       p.Amount,
+			PaymentDt:    p.PaymentDt,
+			Bank:         p.Bank,
+			DeliveryCost: p.DeliveryCost,
+			GoodsTotal:   p.GoodsTotal,
+			CustomFee:    p.CustomFee,
+		}
+	}
+	return r.metrics.RecordDBOperation(ctx, "copy", "payments", true, func(ctx context.Context) error {
+		_, err := r.q.CopyPayments(ctx, rows)
+		return err
+	})
 }
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync/atomic"
+
+	"l0_wb/internal/fieldcrypt"
+	"l0_wb/internal/model"
+	sqlcdb "l0_wb/internal/repository/db"
+)
+
+// fieldCipher — шифратор персональных данных получателя в таблицах 'deliveries' и 'order_events'.
+var fieldCipher atomic.Pointer[fieldcrypt.Cipher]
+
+// SetFieldCipher включает шифрование телефона, email и адреса получателя.
+//
+//	Репозитории шифруют эти поля при записи и расшифровывают при чтении;
+//	значения, записанные открытым текстом до включения шифрования, читаются
+//	как есть до перешифрования (см. ReencryptDeliveries).
+//
+//	Параметры:
+//	- c: шифратор (nil отключает шифрование новых значений).
+func SetFieldCipher(c *fieldcrypt.Cipher) {
+	fieldCipher.Store(c)
+}
+
+// piiDeliveryFields — шифруемые поля доставки по их ключам в JSON-представлении заказа.
+var piiDeliveryFields = map[string]func(d *model.Delivery) *string{
+	"phone":   func(d *model.Delivery) *string { return &d.Phone },
+	"email":   func(d *model.Delivery) *string { return &d.Email },
+	"address": func(d *model.Delivery) *string { return &d.Address },
+}
+
+// sealDelivery возвращает копию доставки с зашифрованными персональными данными.
+func sealDelivery(d *model.Delivery) (model.Delivery, error) {
+	c := fieldCipher.Load()
+	sealed := *d
+	for name, field := range piiDeliveryFields {
+		value, err := c.Encrypt(*field(&sealed))
+		if err != nil {
+			return model.Delivery{}, fmt.Errorf("encrypt delivery %s: %w", name, err)
+		}
+		*field(&sealed) = value
+	}
+	return sealed, nil
+}
+
+// openDelivery расшифровывает персональные данные доставки на месте.
+func openDelivery(d *model.Delivery) error {
+	c := fieldCipher.Load()
+	for name, field := range piiDeliveryFields {
+		value, err := c.Decrypt(*field(d))
+		if err != nil {
+			return fmt.Errorf("decrypt delivery %s: %w", name, err)
+		}
+		*field(d) = value
+	}
+	return nil
+}
+
+// transformDiff применяет fn к персональным данным получателя в записи журнала изменений.
+//
+//	Поля встречаются по путям "delivery.<поле>" (изменение заказа) и внутри
+//	объекта "delivery" (первое сохранение). Исходная запись не меняется.
+func transformDiff(diff map[string]any, fn func(string) (string, error)) (map[string]any, error) {
+	out := maps.Clone(diff)
+	if err := transformFields(out, "delivery.", fn); err != nil {
+		return nil, err
+	}
+	if delivery, ok := out["delivery"].(map[string]any); ok {
+		nested := maps.Clone(delivery)
+		if err := transformFields(nested, "", fn); err != nil {
+			return nil, err
+		}
+		out["delivery"] = nested
+	}
+	return out, nil
+}
+
+// transformFields применяет fn к строковым значениям шифруемых полей доставки с ключами keyPrefix+<поле>.
+func transformFields(m map[string]any, keyPrefix string, fn func(string) (string, error)) error {
+	for name := range piiDeliveryFields {
+		if s, ok := m[keyPrefix+name].(string); ok {
+			value, err := fn(s)
+			if err != nil {
+				return fmt.Errorf("delivery.%s: %w", name, err)
+			}
+			m[keyPrefix+name] = value
+		}
+	}
+	return nil
+}
+
+// sealDiff шифрует персональные данные получателя в записи журнала изменений.
+func sealDiff(diff map[string]any) (map[string]any, error) {
+	return transformDiff(diff, fieldCipher.Load().Encrypt)
+}
+
+// openDiff расшифровывает персональные данные получателя в записи журнала изменений.
+func openDiff(diff map[string]any) (map[string]any, error) {
+	return transformDiff(diff, fieldCipher.Load().Decrypt)
+}
+
+// reencrypt возвращает значение, зашифрованное основным ключом шифратора.
+//
+//	Открытый текст шифруется, значение под прежним ключом перешифровывается.
+func reencrypt(c *fieldcrypt.Cipher, value string) (string, error) {
+	if c.Current(value) {
+		return value, nil
+	}
+	plain, err := c.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return c.Encrypt(plain)
+}
+
+// ReencryptDeliveries приводит персональные данные одной страницы таблицы 'deliveries'
+// к основному ключу шифратора, заданного SetFieldCipher.
+//
+//	Строки перебираются по возрастанию order_uid. Строка обновляется, только
+//	если её значения не изменились с момента чтения, поэтому инструмент можно
+//	запускать на работающем сервисе; пропущенные строки уже записаны
+//	сервисом текущим ключом или будут обработаны повторным запуском.
+//
+//	Параметры:
+//	- db: пул соединений.
+//	- after: order_uid, после которого начинается страница ("" — с начала).
+//	- limit: размер страницы.
+//	Возвращает:
+//	- string: order_uid последней строки страницы ("" — строк больше нет).
+//	- int: число обновлённых строк.
+//	- error: ошибка расшифровки или выполнения запроса.
+func ReencryptDeliveries(ctx context.Context, db DBTX, after string, limit int) (string, int, error) {
+	q := sqlcdb.New(db)
+	page, err := q.ListDeliveriesPII(ctx, sqlcdb.ListDeliveriesPIIParams{After: after, BatchSize: limit})
+	if err != nil {
+		return "", 0, err
+	}
+	if len(page) == 0 {
+		return "", 0, nil
+	}
+
+	c := fieldCipher.Load()
+	updated := 0
+	for _, r := range page {
+		var sealed [3]string
+		for i, value := range []string{r.Phone, r.Email, r.Address} {
+			if sealed[i], err = reencrypt(c, value); err != nil {
+				return "", updated, fmt.Errorf("delivery of order %s: %w", r.OrderUid, err)
+			}
+		}
+		if sealed == [3]string{r.Phone, r.Email, r.Address} {
+			continue
+		}
+		n, err := q.UpdateDeliveryPII(ctx, sqlcdb.UpdateDeliveryPIIParams{
+			OrderUid:   r.OrderUid,
+			Phone:      sealed[0],
+			Email:      sealed[1],
+			Address:    sealed[2],
+			OldPhone:   r.Phone,
+			OldEmail:   r.Email,
+			OldAddress: r.Address,
+		})
+		if err != nil {
+			return "", updated, err
+		}
+		updated += int(n)
+	}
+	return page[len(page)-1].OrderUid, updated, nil
+}
+
+// ReencryptOrderEvents приводит персональные данные одной страницы журнала 'order_events'
+// к основному ключу шифратора, заданного SetFieldCipher.
+//
+//	Записи перебираются по возрастанию id; запись обновляется, только если
+//	её diff не изменился с момента чтения.
+//
+//	Параметры:
+//	- db: пул соединений.
+//	- after: id, после которого начинается страница (0 — с начала).
+//	- limit: размер страницы.
+//	Возвращает:
+//	- int64: id последней записи страницы (0 — записей больше нет).
+//	- int: число обновлённых записей.
+//	- error: ошибка расшифровки или выполнения запроса.
+func ReencryptOrderEvents(ctx context.Context, db DBTX, after int64, limit int) (int64, int, error) {
+	q := sqlcdb.New(db)
+	page, err := q.ListOrderEventDiffs(ctx, sqlcdb.ListOrderEventDiffsParams{After: after, BatchSize: limit})
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(page) == 0 {
+		return 0, 0, nil
+	}
+
+	c := fieldCipher.Load()
+	updated := 0
+	for _, r := range page {
+		var diff map[string]any
+		if err := json.Unmarshal(r.Diff, &diff); err != nil {
+			return 0, updated, fmt.Errorf("order event %d: %w", r.ID, err)
+		}
+		changed := false
+		sealed, err := transformDiff(diff, func(value string) (string, error) {
+			out, err := reencrypt(c, value)
+			changed = changed || out != value
+			return out, err
+		})
+		if err != nil {
+			return 0, updated, fmt.Errorf("order event %d: %w", r.ID, err)
+		}
+		if !changed {
+			continue
+		}
+		data, err := json.Marshal(sealed)
+		if err != nil {
+			return 0, updated, fmt.Errorf("order event %d: %w", r.ID, err)
+		}
+		n, err := q.UpdateOrderEventDiff(ctx, sqlcdb.UpdateOrderEventDiffParams{ID: r.ID, Diff: data, OldDiff: r.Diff})
+		if err != nil {
+			return 0, updated, err
+		}
+		updated += int(n)
+	}
+	return page[len(page)-1].ID, updated, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"l0_wb/internal/fieldcrypt"
+	"l0_wb/internal/model"
+)
+
+// useTestCipher включает шифрование персональных данных на время теста.
+func useTestCipher(t *testing.T) *fieldcrypt.Cipher {
+	t.Helper()
+	c, err := fieldcrypt.New(bytes.Repeat([]byte{7}, fieldcrypt.KeySize))
+	if err != nil {
+		t.Fatalf("fieldcrypt.New: %v", err)
+	}
+	SetFieldCipher(c)
+	t.Cleanup(func() { SetFieldCipher(nil) })
+	return c
+}
+
+// TestSealDelivery проверяет шифрование телефона, email и адреса доставки.
+func TestSealDelivery(t *testing.T) {
+	useTestCipher(t)
+	d := model.Delivery{Name: "Test Testov", Phone: "+9720000000", City: "Kiryat Mozkin", Address: "Ploshad Mira 15", Email: "test@gmail.com"}
+
+	sealed, err := sealDelivery(&d)
+	if err != nil {
+		t.Fatalf("sealDelivery: %v", err)
+	}
+	if sealed.Name != d.Name || sealed.City != d.City {
+		t.Errorf("non-PII fields must stay plain: %+v", sealed)
+	}
+	for _, v := range []string{sealed.Phone, sealed.Email, sealed.Address} {
+		if !strings.HasPrefix(v, "enc:") {
+			t.Errorf("expected encrypted value, got %q", v)
+		}
+	}
+	if d.Phone != "+9720000000" {
+		t.Error("sealDelivery must not modify its argument")
+	}
+	if err := openDelivery(&sealed); err != nil || sealed != d {
+		t.Errorf("openDelivery = %+v, %v", sealed, err)
+	}
+}
+
+// TestSealDiff проверяет шифрование персональных данных в записях журнала изменений.
+func TestSealDiff(t *testing.T) {
+	useTestCipher(t)
+	diff := map[string]any{
+		"locale":         "en",
+		"delivery.phone": "+9720000000",
+		"delivery.city":  "Haifa",
+		"delivery":       map[string]any{"email": "test@gmail.com", "name": "Test Testov"},
+	}
+
+	sealed, err := sealDiff(diff)
+	if err != nil {
+		t.Fatalf("sealDiff: %v", err)
+	}
+	nested := sealed["delivery"].(map[string]any)
+	if !strings.HasPrefix(sealed["delivery.phone"].(string), "enc:") || !strings.HasPrefix(nested["email"].(string), "enc:") {
+		t.Errorf("PII is not encrypted: %v", sealed)
+	}
+	if sealed["delivery.city"] != "Haifa" || nested["name"] != "Test Testov" || sealed["locale"] != "en" {
+		t.Errorf("non-PII fields changed: %v", sealed)
+	}
+	if diff["delivery.phone"] != "+9720000000" || diff["delivery"].(map[string]any)["email"] != "test@gmail.com" {
+		t.Error("sealDiff must not modify its argument")
+	}
+
+	opened, err := openDiff(sealed)
+	if err != nil || opened["delivery.phone"] != "+9720000000" || opened["delivery"].(map[string]any)["email"] != "test@gmail.com" {
+		t.Errorf("openDiff = %v, %v", opened, err)
+	}
+}
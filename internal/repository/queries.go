@@ -0,0 +1,10 @@
+package repository
+
+// Запросы репозиториев описаны в queries/*.sql, по одному файлу на таблицу,
+// и компилируются sqlc по схеме из миграций internal/db/migrations в
+// типизированный пакет internal/repository/db (make generate). Опечатка в
+// запросе или расхождение со схемой обнаруживаются при генерации, а не при
+// выполнении запроса. Запросы, собираемые динамически (фильтры List,
+// выборка полного заказа), остаются в коде репозиториев.
+//
+//go:generate go run github.com/sqlc-dev/sqlc/cmd/sqlc@v1.29.0 generate -f ../../sqlc.yaml
@@ -0,0 +1,3 @@
+-- name: InsertAuditEntry :one
+INSERT INTO admin_audit_log (occurred_at, actor, method, path, params, status, client_ip, request_id)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id;
@@ -0,0 +1,2 @@
+-- name: GetAPIKeyByHash :one
+SELECT key_hash, name, rate_limit, revoked FROM api_keys WHERE key_hash = $1;
@@ -0,0 +1,22 @@
+-- name: InsertDelivery :exec
+INSERT INTO deliveries (order_uid, name, phone, zip, city, address, region, email, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now(), now());
+
+-- name: CopyDeliveries :copyfrom
+INSERT INTO deliveries (order_uid, name, phone, zip, city, address, region, email)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetDeliveryByOrderID :one
+SELECT name, phone, zip, city, address, region, email
+    FROM deliveries WHERE order_uid = $1;
+
+-- name: UpdateDelivery :exec
+UPDATE deliveries SET name = $2, phone = $3, zip = $4, city = $5, address = $6, region = $7, email = $8,
+        updated_at = now()
+    WHERE order_uid = $1;
+
+-- name: EraseDeliveriesByCustomerID :many
+UPDATE deliveries d SET name = '', phone = '', zip = '', address = '', email = '', updated_at = now()
+    FROM orders o
+    WHERE o.order_uid = d.order_uid AND o.customer_id = $1
+    RETURNING d.order_uid;
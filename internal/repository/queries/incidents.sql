@@ -0,0 +1,6 @@
+-- name: InsertIncident :one
+INSERT INTO incidents (dependency, started_at, ended_at, last_error)
+    VALUES ($1, $2, $3, $4) RETURNING id;
+
+-- name: CloseIncident :exec
+UPDATE incidents SET ended_at = $2, last_error = $3 WHERE id = $1;
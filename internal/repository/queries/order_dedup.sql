@@ -0,0 +1,11 @@
+-- name: ListRecentOrderDedup :many
+SELECT order_uid, payload_hash
+    FROM order_dedup WHERE order_uid = ANY(@order_uids::text[]) AND seen_at >= @since;
+
+-- name: UpsertOrderDedup :exec
+INSERT INTO order_dedup (order_uid, payload_hash, seen_at)
+    SELECT unnest(@order_uids::text[]), unnest(@payload_hashes::text[]), @seen_at::timestamptz
+    ON CONFLICT (order_uid) DO UPDATE SET payload_hash = EXCLUDED.payload_hash, seen_at = EXCLUDED.seen_at;
+
+-- name: DeleteOrderDedupBefore :execrows
+DELETE FROM order_dedup WHERE seen_at < $1;
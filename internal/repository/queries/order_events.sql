@@ -0,0 +1,18 @@
+-- name: CopyOrderEvents :copyfrom
+INSERT INTO order_events (order_uid, action, actor, occurred_at, diff)
+    VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListOrderEventsByOrderID :many
+SELECT id, order_uid, action, actor, occurred_at, diff
+    FROM order_events WHERE order_uid = $1
+    ORDER BY id;
+
+-- name: EraseOrderEventsDeliveryPII :execrows
+UPDATE order_events
+    SET diff = CASE
+        WHEN jsonb_typeof(diff -> 'delivery') = 'object'
+            THEN jsonb_set(diff - '{delivery.name,delivery.phone,delivery.zip,delivery.address,delivery.email}'::text[],
+                '{delivery}', (diff -> 'delivery') - '{name,phone,zip,address,email}'::text[])
+        ELSE diff - '{delivery.name,delivery.phone,delivery.zip,delivery.address,delivery.email}'::text[]
+    END
+    WHERE order_uid = ANY(@order_uids::text[]);
@@ -0,0 +1,42 @@
+-- name: InsertOrder :exec
+INSERT INTO orders (order_uid, track_number, entry, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard,
+        amount_mismatch, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, now(), now());
+
+-- name: CopyOrders :copyfrom
+INSERT INTO orders (order_uid, track_number, entry, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard,
+        amount_mismatch)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
+
+-- name: GetOrderByID :one
+SELECT order_uid, track_number, entry, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard,
+        deleted_at, archived_at, created_at, updated_at, status, amount_mismatch
+    FROM orders WHERE order_uid = $1;
+
+-- name: UpdateOrder :exec
+UPDATE orders SET track_number = $2, entry = $3, locale = $4, internal_signature = $5, customer_id = $6,
+        delivery_service = $7, shardkey = $8, sm_id = $9, date_created = $10, oof_shard = $11, amount_mismatch = $12,
+        updated_at = now()
+    WHERE order_uid = $1;
+
+-- name: GetAllOrderIDs :many
+SELECT order_uid FROM orders WHERE deleted_at IS NULL ORDER BY date_created, order_uid;
+
+-- name: GetOrderIDsSince :many
+SELECT order_uid FROM orders WHERE deleted_at IS NULL AND date_created >= @since ORDER BY date_created, order_uid;
+
+-- name: LockExistingOrders :many
+SELECT order_uid FROM orders WHERE order_uid = ANY(@order_uids::text[]) FOR UPDATE;
+
+-- name: SoftDeleteOrder :execrows
+UPDATE orders SET deleted_at = COALESCE(deleted_at, now()), updated_at = now() WHERE order_uid = $1;
+
+-- name: RestoreOrder :execrows
+UPDATE orders SET deleted_at = NULL, updated_at = now() WHERE order_uid = $1;
+
+-- name: ArchiveOrder :execrows
+UPDATE orders SET archived_at = COALESCE(archived_at, now()), updated_at = now() WHERE order_uid = $1;
+
+-- name: SetOrderStatus :execrows
+UPDATE orders SET status = @to_status, updated_at = now()
+    WHERE order_uid = @order_uid AND status = @from_status AND deleted_at IS NULL;
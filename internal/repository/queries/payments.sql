@@ -0,0 +1,18 @@
+-- name: InsertPayment :exec
+INSERT INTO payments (order_uid, transaction, request_id, currency, provider, amount, payment_dt, bank, delivery_cost, goods_total, custom_fee,
+        created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, now(), now());
+
+-- name: CopyPayments :copyfrom
+INSERT INTO payments (order_uid, transaction, request_id, currency, provider, amount, payment_dt, bank, delivery_cost, goods_total, custom_fee)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+
+-- name: GetPaymentByOrderID :one
+SELECT transaction, request_id, currency, provider, amount, payment_dt, bank, delivery_cost, goods_total, custom_fee
+    FROM payments WHERE order_uid = $1;
+
+-- name: UpdatePayment :exec
+UPDATE payments SET transaction = $2, request_id = $3, currency = $4, provider = $5, amount = $6,
+        payment_dt = $7, bank = $8, delivery_cost = $9, goods_total = $10, custom_fee = $11,
+        updated_at = now()
+    WHERE order_uid = $1;
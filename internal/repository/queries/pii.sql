@@ -0,0 +1,17 @@
+-- name: ListDeliveriesPII :many
+SELECT order_uid, phone, email, address
+    FROM deliveries WHERE order_uid > @after
+    ORDER BY order_uid LIMIT @batch_size::int;
+
+-- name: UpdateDeliveryPII :execrows
+UPDATE deliveries SET phone = @phone, email = @email, address = @address
+    WHERE order_uid = @order_uid AND phone IS NOT DISTINCT FROM @old_phone AND email IS NOT DISTINCT FROM @old_email
+        AND address IS NOT DISTINCT FROM @old_address;
+
+-- name: ListOrderEventDiffs :many
+SELECT id, diff
+    FROM order_events WHERE id > @after
+    ORDER BY id LIMIT @batch_size::int;
+
+-- name: UpdateOrderEventDiff :execrows
+UPDATE order_events SET diff = @diff WHERE id = @id AND diff = @old_diff;
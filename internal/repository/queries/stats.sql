@@ -0,0 +1,27 @@
+-- name: OrdersPerDay :many
+SELECT to_char(date_trunc('day', o.date_created AT TIME ZONE 'UTC'), 'YYYY-MM-DD') AS day,
+        coalesce(p.currency, '') AS currency, count(*) AS orders, coalesce(sum(p.amount), 0)::bigint AS amount
+    FROM orders o LEFT JOIN payments p ON p.order_uid = o.order_uid
+    WHERE o.deleted_at IS NULL AND o.date_created >= @since
+    GROUP BY 1, 2 ORDER BY 1, 4 DESC, 2;
+
+-- name: AmountByCurrency :many
+SELECT coalesce(currency, '') AS currency, count(*) AS orders, coalesce(sum(amount), 0)::bigint AS amount
+    FROM payments p JOIN orders o ON o.order_uid = p.order_uid
+    WHERE o.deleted_at IS NULL GROUP BY 1 ORDER BY 3 DESC, 1;
+
+-- name: TopDeliveryServices :many
+WITH top AS (
+    SELECT coalesce(delivery_service, '') AS service, count(*) AS orders
+    FROM orders WHERE deleted_at IS NULL GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT @service_limit::int
+)
+SELECT t.service, t.orders AS service_orders, coalesce(p.currency, '') AS currency, count(*) AS orders,
+        coalesce(sum(p.amount), 0)::bigint AS amount
+    FROM top t
+    JOIN orders o ON coalesce(o.delivery_service, '') = t.service AND o.deleted_at IS NULL
+    LEFT JOIN payments p ON p.order_uid = o.order_uid
+    GROUP BY 1, 2, 3 ORDER BY 2 DESC, 1, 5 DESC, 3;
+
+-- name: AvgItemsPerOrder :one
+SELECT coalesce(avg(cnt), 0)::float8 AS avg_items
+    FROM (SELECT count(i.id) AS cnt FROM orders o LEFT JOIN items i ON i.order_uid = o.order_uid WHERE o.deleted_at IS NULL GROUP BY o.order_uid) t;
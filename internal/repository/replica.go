@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// replicaSet распределяет запросы чтения по репликам по кругу.
+type replicaSet struct {
+	pools []*pgxpool.Pool
+	next  atomic.Uint64
+}
+
+// NewReadDB возвращает подключение для запросов только на чтение.
+//
+//	Если реплики заданы, запросы распределяются между ними по кругу; иначе
+//	чтение идёт через основное подключение. Данные на репликах могут отставать
+//	от основной БД, поэтому чтение собственных записей должно идти через primary.
+//
+//	Параметры:
+//	- primary: подключение к основной БД.
+//	- replicas: пулы соединений к репликам.
+//	Возвращает:
+//	- DBTX: подключение для чтения.
+func NewReadDB(primary DBTX, replicas ...*pgxpool.Pool) DBTX {
+	if len(replicas) == 0 {
+		return primary
+	}
+	return &replicaSet{pools: replicas}
+}
+
+// pick выбирает реплику для очередного запроса.
+func (s *replicaSet) pick() *pgxpool.Pool {
+	return s.pools[(s.next.Add(1)-1)%uint64(len(s.pools))]
+}
+
+// Exec выполняет команду на одной из реплик.
+func (s *replicaSet) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return s.pick().Exec(ctx, sql, args...)
+}
+
+// Query выполняет запрос на одной из реплик.
+func (s *replicaSet) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return s.pick().Query(ctx, sql, args...)
+}
+
+// QueryRow выполняет запрос одной строки на одной из реплик.
+func (s *replicaSet) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return s.pick().QueryRow(ctx, sql, args...)
+}
+
+// CopyFrom выполняет COPY на одной из реплик; реплики в режиме hot standby его отклонят.
+func (s *replicaSet) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return s.pick().CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
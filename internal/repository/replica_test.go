@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TestNewReadDB проверяет выбор подключения для чтения и распределение по репликам.
+func TestNewReadDB(t *testing.T) {
+	primary := &replicaSet{}
+	if got := NewReadDB(primary); got != primary {
+		t.Fatalf("expected primary without replicas, got %T", got)
+	}
+
+	// Пул не подключается к БД до первого запроса
+	var replicas []*pgxpool.Pool
+	for _, host := range []string{"replica-a", "replica-b"} {
+		pool, err := pgxpool.New(context.Background(), "postgres://user@"+host+"/orders")
+		if err != nil {
+			t.Fatalf("failed to create pool: %v", err)
+		}
+		defer pool.Close()
+		replicas = append(replicas, pool)
+	}
+
+	set, ok := NewReadDB(primary, replicas...).(*replicaSet)
+	if !ok {
+		t.Fatal("expected replica set")
+	}
+	for i := 0; i < 4; i++ {
+		if got := set.pick(); got != replicas[i%2] {
+			t.Errorf("pick %d: unexpected replica %s", i, got.Config().ConnConfig.Host)
+		}
+	}
+}
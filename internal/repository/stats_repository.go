@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"l0_wb/internal/model"
+	sqlcdb "l0_wb/internal/repository/db"
+)
+
+// StatsRepository определяет агрегирующие запросы по заказам.
+//
+//	Мягко удалённые заказы в статистике не учитываются.
+type StatsRepository interface {
+	OrdersPerDay(ctx context.Context, since time.Time) ([]model.DayCount, error)
+	AmountByCurrency(ctx context.Context) ([]model.CurrencyAmount, error)
+	TopDeliveryServices(ctx context.Context, limit int) ([]model.DeliveryServiceCount, error)
+	AvgItemsPerOrder(ctx context.Context) (float64, error)
+}
+
+type statsRepository struct {
+	q       *sqlcdb.Queries // Запросы из queries/stats.sql
+	metrics *MetricsWrapper
+}
+
+// NewStatsRepository создает новый экземпляр StatsRepository.
+//
+//	Параметры:
+//	- db: пул соединений или активная транзакция.
+//	Возвращает:
+//	- StatsRepository: экземпляр интерфейса для агрегирующих запросов.
+func NewStatsRepository(db DBTX) StatsRepository {
+	return &statsRepository{
+		q:       sqlcdb.New(db),
+		metrics: NewMetricsWrapper(),
+	}
+}
+
+// OrdersPerDay возвращает число заказов и суммы оплат по дням (UTC), начиная с указанного момента.
+//
+//	Группировка по дню и валюте выполняется в БД; строки одного дня затем
+//	объединяются, поэтому размер результата не зависит от числа заказов.
+//
+//	Параметры:
+//	- since: начало периода.
+//	Возвращает:
+//	- []model.DayCount: заказы и суммы оплат по дням в порядке возрастания даты.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *statsRepository) OrdersPerDay(ctx context.Context, since time.Time) ([]model.DayCount, error) {
+	var result []model.DayCount
+	err := r.metrics.RecordDBOperation(ctx, "select", "orders", false, func(ctx context.Context) error {
+		rows, err := r.q.OrdersPerDay(ctx, since)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if n := len(result); n == 0 || result[n-1].Day != row.Day {
+				result = append(result, model.DayCount{Day: row.Day})
+			}
+			d := &result[len(result)-1]
+			d.Orders += row.Orders
+			d.Amounts = append(d.Amounts, model.CurrencyAmount{Currency: row.Currency, Orders: row.Orders, Amount: row.Amount})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// AmountByCurrency возвращает число заказов и сумму оплат по валютам.
+//
+//	Возвращает:
+//	- []model.CurrencyAmount: заказы и суммы по валютам в порядке убывания суммы.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *statsRepository) AmountByCurrency(ctx context.Context) ([]model.CurrencyAmount, error) {
+	var result []model.CurrencyAmount
+	err := r.metrics.RecordDBOperation(ctx, "select", "payments", false, func(ctx context.Context) error {
+		rows, err := r.q.AmountByCurrency(ctx)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			result = append(result, model.CurrencyAmount{Currency: row.Currency, Orders: row.Orders, Amount: row.Amount})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// TopDeliveryServices возвращает службы доставки с наибольшим числом заказов и суммы их оплат.
+//
+//	Параметры:
+//	- limit: число служб в ответе.
+//	Возвращает:
+//	- []model.DeliveryServiceCount: службы доставки в порядке убывания числа заказов.
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *statsRepository) TopDeliveryServices(ctx context.Context, limit int) ([]model.DeliveryServiceCount, error) {
+	var result []model.DeliveryServiceCount
+	err := r.metrics.RecordDBOperation(ctx, "select", "orders", false, func(ctx context.Context) error {
+		rows, err := r.q.TopDeliveryServices(ctx, limit)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if n := len(result); n == 0 || result[n-1].DeliveryService != row.Service {
+				result = append(result, model.DeliveryServiceCount{DeliveryService: row.Service, Orders: row.ServiceOrders})
+			}
+			d := &result[len(result)-1]
+			d.Amounts = append(d.Amounts, model.CurrencyAmount{Currency: row.Currency, Orders: row.Orders, Amount: row.Amount})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// AvgItemsPerOrder возвращает среднее число товаров в заказе.
+//
+//	Возвращает:
+//	- float64: среднее число товаров (0, если заказов нет).
+//	- error: ошибка при выполнении запроса (если возникла).
+func (r *statsRepository) AvgItemsPerOrder(ctx context.Context) (float64, error) {
+	var avg float64
+	err := r.metrics.RecordDBOperation(ctx, "select", "items", false, func(ctx context.Context) error {
+		var err error
+		avg, err = r.q.AvgItemsPerOrder(ctx)
+		return err
+	})
+	return avg, err
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TxManager выполняет работу с репозиториями в рамках одной транзакции (unit of work).
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context, repos *Repositories) error) error
+	WithinTxOptions(ctx context.Context, opts TxOptions, fn func(ctx context.Context, repos *Repositories) error) error
+}
+
+// TxIsoLevel — уровень изоляции транзакции.
+type TxIsoLevel string
+
+// Уровни изоляции транзакций; значения совпадают с DB_TX_ISOLATION.
+const (
+	ReadCommitted  TxIsoLevel = "read_committed"
+	RepeatableRead TxIsoLevel = "repeatable_read"
+	Serializable   TxIsoLevel = "serializable"
+)
+
+// pgxIsoLevels сопоставляет уровни изоляции с уровнями pgx.
+var pgxIsoLevels = map[TxIsoLevel]pgx.TxIsoLevel{
+	ReadCommitted:  pgx.ReadCommitted,
+	RepeatableRead: pgx.RepeatableRead,
+	Serializable:   pgx.Serializable,
+}
+
+// TxOptions задаёт уровень изоляции и режим доступа транзакции.
+//
+//	Нулевое значение открывает транзакцию с настройками сервера по умолчанию
+//	(обычно read committed, чтение и запись). На уровнях repeatable_read и
+//	serializable фиксация может завершиться ошибкой сериализации (40001),
+//	поэтому такую транзакцию нужно повторять целиком.
+type TxOptions struct {
+	IsoLevel TxIsoLevel // Уровень изоляции (пусто — по умолчанию сервера)
+	ReadOnly bool       // Транзакция только для чтения: запись завершится ошибкой
+}
+
+// pgxOptions возвращает параметры транзакции pgx.
+func (o TxOptions) pgxOptions() (pgx.TxOptions, error) {
+	opts := pgx.TxOptions{}
+	if o.IsoLevel != "" {
+		level, ok := pgxIsoLevels[o.IsoLevel]
+		if !ok {
+			return opts, fmt.Errorf("unknown transaction isolation level %q", o.IsoLevel)
+		}
+		opts.IsoLevel = level
+	}
+	if o.ReadOnly {
+		opts.AccessMode = pgx.ReadOnly
+	}
+	return opts, nil
+}
+
+type txManager struct {
+	db      *pgxpool.Pool
+	metrics *MetricsWrapper
+}
+
+// NewTxManager создает новый экземпляр TxManager.
+//
+//	Параметры:
+//	- db: пул соединений к базе данных.
+//	Возвращает:
+//	- TxManager: менеджер транзакций поверх пула.
+func NewTxManager(db *pgxpool.Pool) TxManager {
+	return &txManager{
+		db:      db,
+		metrics: NewMetricsWrapper(),
+	}
+}
+
+// WithinTx открывает транзакцию и передаёт fn репозитории, привязанные к ней.
+//
+//	Транзакция открывается с настройками сервера по умолчанию, см. WithinTxOptions.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- fn: работа, выполняемая в транзакции.
+//	Возвращает:
+//	- error: ошибку fn без изменений либо ошибку открытия или фиксации транзакции.
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context, repos *Repositories) error) error {
+	return m.WithinTxOptions(ctx, TxOptions{}, fn)
+}
+
+// WithinTxOptions открывает транзакцию с заданными параметрами и передаёт fn репозитории, привязанные к ней.
+//
+//	Транзакция фиксируется, если fn завершилась без ошибки, и откатывается,
+//	если fn вернула ошибку или запаниковала; паника пробрасывается дальше.
+//	Длительность и исход транзакции целиком записываются в метрики как
+//	операция "transaction" над таблицей "tx", запросы внутри fn — отдельно.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- opts: уровень изоляции и режим доступа транзакции.
+//	- fn: работа, выполняемая в транзакции.
+//	Возвращает:
+//	- error: ошибку fn без изменений либо ошибку параметров, открытия или фиксации транзакции.
+func (m *txManager) WithinTxOptions(ctx context.Context, opts TxOptions, fn func(ctx context.Context, repos *Repositories) error) error {
+	txOpts, err := opts.pgxOptions()
+	if err != nil {
+		return err
+	}
+	return m.metrics.RecordDBOperation(ctx, "transaction", "tx", true, func(ctx context.Context) error {
+		return m.run(ctx, txOpts, fn)
+	})
+}
+
+// run выполняет fn в транзакции и фиксирует или откатывает её.
+func (m *txManager) run(ctx context.Context, opts pgx.TxOptions, fn func(ctx context.Context, repos *Repositories) error) (err error) {
+	tx, err := m.db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("begin transaction failed: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		} else if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if err = fn(ctx, NewRepositories(tx)); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction failed: %w", err)
+	}
+	return nil
+}
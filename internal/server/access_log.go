@@ -0,0 +1,92 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+	"l0_wb/internal/util"
+)
+
+// accessLog пишет журнал доступа HTTP.
+type accessLog struct {
+	logger      *zap.Logger
+	sampleEvery uint64
+	counter     atomic.Uint64
+}
+
+// newAccessLog создаёт журнал доступа из конфигурации.
+//
+//	Параметры:
+//	- cfg: конфигурация приложения.
+//	- logger: логгер приложения для сообщения об ошибке открытия журнала.
+//	Возвращает:
+//	- *accessLog: журнал доступа или nil, если он выключен или не открылся.
+func newAccessLog(cfg *config.Config, logger *zap.Logger) *accessLog {
+	if !cfg.AccessLogEnabled {
+		return nil
+	}
+	l, err := util.NewAccessLogger(cfg.AccessLogOutput)
+	if err != nil {
+		logger.Error("Failed to open access log, access logging disabled",
+			zap.String("output", cfg.AccessLogOutput), zap.Error(err))
+		return nil
+	}
+	return &accessLog{logger: l, sampleEvery: uint64(cfg.AccessLogSampleEvery)}
+}
+
+// sampled сообщает, нужно ли записать запрос с данным статусом.
+//
+//	Ошибки (статус >= 400) записываются всегда, успешные ответы — каждый
+//	sampleEvery-й.
+func (a *accessLog) sampled(status int) bool {
+	if status >= http.StatusBadRequest || a.sampleEvery <= 1 {
+		return true
+	}
+	return a.counter.Add(1)%a.sampleEvery == 0
+}
+
+// accessLogMiddleware записывает каждый запрос в журнал доступа.
+//
+//	Запись содержит метод, путь, статус, длительность, размер ответа, IP клиента
+//	и идентификатор запроса, поэтому middleware должен располагаться внутри
+//	requestIDMiddleware.
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	Возвращает:
+//	- http.Handler: обработчик с журналом доступа.
+func (s *Server) accessLogMiddleware(next http.Handler) http.Handler {
+	if s.accessLog == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rw, r)
+
+		if !s.accessLog.sampled(rw.statusCode) {
+			return
+		}
+		s.accessLog.logger.Info("HTTP request",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.Int("status", rw.statusCode),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("bytes", rw.bytesWritten),
+			zap.String("client_ip", clientIP(r)),
+			zap.String("request_id", requestIDFromContext(r.Context())),
+		)
+	})
+}
+
+// clientIP возвращает IP-адрес клиента из адреса соединения.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
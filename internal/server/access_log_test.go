@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestAccessLogSampling проверяет, что ошибки пишутся всегда, а успешные запросы — с выборкой.
+func TestAccessLogSampling(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	s := &Server{accessLog: &accessLog{logger: zap.New(core), sampleEvery: 2}, logger: zap.NewNop()}
+	h := s.requestIDMiddleware(s.accessLogMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})))
+
+	for _, path := range []string{"/ok", "/ok", "/missing", "/ok"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 access log entries, got %d", len(entries))
+	}
+	fields := entries[1].ContextMap()
+	if fields["path"] != "/missing" || fields["status"] != int64(http.StatusNotFound) || fields["request_id"] == "" {
+		t.Errorf("unexpected access log entry %v", fields)
+	}
+	if fields["client_ip"] != "192.0.2.1" {
+		t.Errorf("unexpected client ip %v", fields["client_ip"])
+	}
+}
@@ -0,0 +1,365 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+	"l0_wb/internal/dto"
+	"l0_wb/internal/model"
+	"l0_wb/internal/service"
+	"l0_wb/internal/util"
+)
+
+// adminPrefix — префикс административных маршрутов.
+const adminPrefix = "/api/admin"
+
+// ConsumerControl управляет чтением заказов из Kafka.
+type ConsumerControl interface {
+	Pause()
+	Resume()
+	Paused() bool
+}
+
+// ConfigReloader перечитывает конфигурацию и применяет параметры, изменяемые на лету.
+type ConfigReloader interface {
+	Reload() ([]string, error)
+}
+
+// adminAuth проверяет учётные данные администратора.
+//
+//	Принимается токен в заголовке "Authorization: Bearer <token>" или
+//	Basic-аутентификация. Значения сравниваются по SHA-256 за постоянное время,
+//	чтобы не раскрывать длину секрета.
+type adminAuth struct {
+	token    [sha256.Size]byte
+	user     string
+	password [sha256.Size]byte
+	hasToken bool
+	hasBasic bool
+}
+
+// newAdminAuth создаёт проверку учётных данных администратора.
+//
+//	Параметры:
+//	- cfg: конфигурация приложения.
+//	Возвращает:
+//	- *adminAuth: проверка учётных данных или nil, если ни ADMIN_TOKEN, ни ADMIN_USER не заданы.
+func newAdminAuth(cfg *config.Config) *adminAuth {
+	if cfg.AdminToken == "" && cfg.AdminUser == "" {
+		return nil
+	}
+	return &adminAuth{
+		token:    sha256.Sum256([]byte(cfg.AdminToken)),
+		user:     cfg.AdminUser,
+		password: sha256.Sum256([]byte(cfg.AdminPassword)),
+		hasToken: cfg.AdminToken != "",
+		hasBasic: cfg.AdminUser != "",
+	}
+}
+
+// authenticate проверяет учётные данные запроса.
+//
+//	Возвращает:
+//	- string: имя администратора для журнала аудита.
+//	- bool: true, если учётные данные верны.
+func (a *adminAuth) authenticate(r *http.Request) (string, bool) {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && a.hasToken {
+		sum := sha256.Sum256([]byte(token))
+		return "token", subtle.ConstantTimeCompare(sum[:], a.token[:]) == 1
+	}
+	if user, password, ok := r.BasicAuth(); ok && a.hasBasic {
+		sum := sha256.Sum256([]byte(password))
+		userOK := subtle.ConstantTimeCompare([]byte(user), []byte(a.user)) == 1
+		return user, userOK && subtle.ConstantTimeCompare(sum[:], a.password[:]) == 1
+	}
+	return "", false
+}
+
+// requireAdmin пропускает только запросы администратора и записывает каждый вызов в журнал аудита.
+//
+//	Каждый вызов с параметрами и статусом ответа пишется в лог аудита и, если
+//	подключён репозиторий, в таблицу admin_audit_log. Отклонённые попытки
+//	пишутся только в лог, чтобы перебор учётных данных не заполнял таблицу.
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	Возвращает:
+//	- http.HandlerFunc: обработчик с проверкой прав администратора.
+func (s *Server) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		actor, ok := s.admin.authenticate(r)
+		audit := s.audit.With(
+			zap.String("actor", actor),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.String("client_ip", clientIP(r)),
+			zap.String("request_id", requestIDFromContext(r.Context())),
+		)
+		if !ok {
+			audit.Warn("Admin request rejected")
+			w.Header().Set("WWW-Authenticate", `Basic realm="admin", charset="UTF-8"`)
+			s.writeError(w, r, newAPIError(http.StatusUnauthorized, codeUnauthorized, "admin credentials required"))
+			return
+		}
+
+		params := auditParams(r)
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next(rw, r.WithContext(service.WithActor(r.Context(), "admin:"+actor)))
+		audit.Info("Admin request",
+			zap.Any("params", params),
+			zap.Int("status", rw.statusCode),
+			zap.Duration("latency", time.Since(start)),
+		)
+		s.recordAudit(r, &model.AuditEntry{
+			OccurredAt: start.UTC(),
+			Actor:      actor,
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			Params:     params,
+			Status:     rw.statusCode,
+			ClientIP:   clientIP(r),
+			RequestID:  requestIDFromContext(r.Context()),
+		}, audit)
+	}
+}
+
+// SetConsumerControl подключает управление Kafka-консумером к административным маршрутам.
+//
+//	Параметры:
+//	- c: управление консумером (nil — маршруты паузы отвечают 503).
+func (s *Server) SetConsumerControl(c ConsumerControl) {
+	s.consumer = c
+}
+
+// SetConfigReloader подключает перезагрузку конфигурации к административным маршрутам.
+//
+//	Параметры:
+//	- r: перезагрузка конфигурации (nil — маршрут перезагрузки отвечает 503).
+func (s *Server) SetConfigReloader(r ConfigReloader) {
+	s.reloader = r
+}
+
+// Reconfigure применяет к серверу параметры, изменённые при перезагрузке конфигурации.
+//
+//	Меняются лимит запросов по умолчанию для API-ключей и время кэширования
+//	статистики; ограничители частоты и кэш статистики при этом сбрасываются.
+//	Параметры:
+//	- cfg: новая конфигурация.
+func (s *Server) Reconfigure(cfg *config.Config) {
+	if s.auth != nil {
+		s.auth.setDefaultRateLimit(cfg.APIKeyDefaultRateLimit)
+	}
+	if s.stats != nil {
+		s.stats.setTTL(cfg.StatsCacheTTL)
+	}
+	s.config.Store(cfg.Redacted())
+}
+
+// registerAdminRoutes регистрирует административные маршруты /api/admin.
+//
+//	Маршруты регистрируются, только если задан ADMIN_TOKEN или ADMIN_USER.
+//	Параметры:
+//	- mux: HTTP маршрутизатор (ServeMux).
+func (s *Server) registerAdminRoutes(mux *http.ServeMux) {
+	if s.admin == nil {
+		return
+	}
+	s.route(mux, "GET "+adminPrefix+"/cache/stats", s.handleAdminCacheStats, s.requireAdmin)
+	s.route(mux, "POST "+adminPrefix+"/cache/clear", s.handleAdminCacheClear, s.requireAdmin)
+	s.route(mux, "POST "+adminPrefix+"/consumer/pause", s.handleAdminConsumer(true), s.requireAdmin)
+	s.route(mux, "POST "+adminPrefix+"/consumer/resume", s.handleAdminConsumer(false), s.requireAdmin)
+	s.route(mux, "GET "+adminPrefix+"/config", s.handleAdminConfig, s.requireAdmin)
+	s.route(mux, "GET "+adminPrefix+"/config/sources", s.handleAdminConfigSources, s.requireAdmin)
+	s.route(mux, "POST "+adminPrefix+"/config/reload", s.handleAdminConfigReload, s.requireAdmin)
+	s.route(mux, "GET "+adminPrefix+"/log/level", s.handleAdminLogLevel, s.requireAdmin)
+	s.route(mux, "PUT "+adminPrefix+"/log/level", s.handleAdminSetLogLevel, s.limitBody, s.requireAdmin)
+	s.route(mux, "DELETE "+adminPrefix+"/orders/{id}", s.handleAdminDeleteOrder, s.requireAdmin)
+	s.route(mux, "POST "+adminPrefix+"/orders/{id}/restore", s.handleAdminRestoreOrder, s.requireAdmin)
+	s.route(mux, "POST "+adminPrefix+"/orders/{id}/archive", s.handleAdminArchiveOrder, s.requireAdmin)
+	s.route(mux, "GET "+adminPrefix+"/audit", s.handleAdminAudit, s.requireAdmin)
+	s.logger.Info("Admin routes registered", zap.String("prefix", adminPrefix))
+}
+
+// handleAdminCacheStats возвращает состояние кэша заказов.
+func (s *Server) handleAdminCacheStats(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, r, map[string]any{
+		"orders": s.cache.Len(),
+		"warmed": s.cache.Warmed(),
+	})
+}
+
+// handleAdminCacheClear очищает кэш заказов.
+//
+//	Заказы возвращаются в кэш по мере поступления из Kafka; полная загрузка
+//	из БД выполняется при следующем старте сервиса.
+func (s *Server) handleAdminCacheClear(w http.ResponseWriter, r *http.Request) {
+	removed := s.cache.Clear()
+	s.writeJSON(w, r, map[string]int{"removed": removed})
+}
+
+// handleAdminConsumer возвращает обработчик паузы или возобновления чтения Kafka.
+func (s *Server) handleAdminConsumer(pause bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.consumer == nil {
+			s.writeError(w, r, newAPIError(http.StatusServiceUnavailable, codeUnavailable, "kafka consumer is not running"))
+			return
+		}
+		if pause {
+			s.consumer.Pause()
+		} else {
+			s.consumer.Resume()
+		}
+		s.writeJSON(w, r, map[string]bool{"paused": s.consumer.Paused()})
+	}
+}
+
+// handleAdminConfig возвращает действующую конфигурацию без секретов.
+func (s *Server) handleAdminConfig(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, r, s.config.Load())
+}
+
+// handleAdminConfigSources возвращает файл конфигурации и источник значения каждого параметра.
+//
+//	Значения параметров не раскрываются: только откуда они взяты (flag, env,
+//	env_file, vault, secrets_file, config_file или default).
+func (s *Server) handleAdminConfigSources(w http.ResponseWriter, r *http.Request) {
+	cfg := s.config.Load()
+	s.writeJSON(w, r, map[string]any{
+		"config_file": cfg.ConfigFile(),
+		"sources":     cfg.Sources(),
+	})
+}
+
+// handleAdminConfigReload перечитывает конфигурацию и возвращает имена изменённых параметров.
+//
+//	Некорректная конфигурация не применяется; в ответе перечисляются найденные проблемы.
+func (s *Server) handleAdminConfigReload(w http.ResponseWriter, r *http.Request) {
+	if s.reloader == nil {
+		s.writeError(w, r, newAPIError(http.StatusServiceUnavailable, codeUnavailable, "configuration reload is not available"))
+		return
+	}
+	changed, err := s.reloader.Reload()
+	var cfgErr *config.ValidationError
+	if errors.As(err, &cfgErr) {
+		s.writeError(w, r, newAPIError(http.StatusUnprocessableEntity, codeValidation, "invalid configuration").withDetails(cfgErr.Problems))
+		return
+	}
+	if err != nil {
+		s.log(r).Error("Failed to reload configuration", zap.Error(err))
+		s.writeError(w, r, newAPIError(http.StatusInternalServerError, codeInternal, "failed to reload configuration"))
+		return
+	}
+	if changed == nil {
+		changed = []string{}
+	}
+	s.writeJSON(w, r, map[string][]string{"changed": changed})
+}
+
+// logLevelPayload — тело запроса и ответа /log/level.
+type logLevelPayload struct {
+	Level string `json:"level"`
+}
+
+// handleAdminLogLevel возвращает текущий уровень журналирования.
+func (s *Server) handleAdminLogLevel(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, r, logLevelPayload{Level: util.LogLevel()})
+}
+
+// handleAdminSetLogLevel меняет уровень журналирования без перезапуска.
+//
+//	Тело запроса — {"level": "debug"}. Уровень действует до перезапуска или
+//	до перезагрузки конфигурации с изменённым LOG_LEVEL.
+func (s *Server) handleAdminSetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req logLevelPayload
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if !errors.As(err, new(*http.MaxBytesError)) {
+			err = newAPIErrorf(http.StatusBadRequest, codeBadRequest, "invalid log level payload: %v", err)
+		}
+		s.writeError(w, r, err)
+		return
+	}
+	previous := util.LogLevel()
+	if err := util.SetLogLevel(req.Level); err != nil {
+		s.writeError(w, r, newAPIErrorf(http.StatusBadRequest, codeBadRequest, "invalid log level %q", req.Level))
+		return
+	}
+	s.log(r).Info("Log level changed", zap.String("from", previous), zap.String("to", util.LogLevel()))
+	s.writeJSON(w, r, logLevelPayload{Level: util.LogLevel()})
+}
+
+// requireOrderStorage сообщает клиенту 503, если сервис заказов не подключён.
+func (s *Server) requireOrderStorage(w http.ResponseWriter, r *http.Request) bool {
+	if s.orders == nil {
+		s.writeError(w, r, newAPIError(http.StatusServiceUnavailable, codeUnavailable, "order storage is not available"))
+		return false
+	}
+	return true
+}
+
+// handleAdminDeleteOrder мягко удаляет заказ и убирает его из кэша.
+//
+//	Данные заказа остаются в БД; вернуть заказ можно через /orders/{id}/restore.
+func (s *Server) handleAdminDeleteOrder(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOrderStorage(w, r) {
+		return
+	}
+	orderID := r.PathValue("id")
+	if err := s.orders.DeleteOrder(r.Context(), orderID); err != nil {
+		s.log(r).Warn("Failed to delete order", zap.String("order_uid", orderID), zap.Error(err))
+		s.writeError(w, r, err)
+		return
+	}
+	s.cache.Delete(orderID)
+	s.writeJSON(w, r, map[string]any{"order_uid": orderID, "deleted": true})
+}
+
+// handleAdminRestoreOrder снимает с заказа отметку удаления и возвращает его в кэш.
+func (s *Server) handleAdminRestoreOrder(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOrderStorage(w, r) {
+		return
+	}
+	orderID := r.PathValue("id")
+	order, err := s.orders.RestoreOrder(r.Context(), orderID)
+	if err != nil {
+		s.log(r).Warn("Failed to restore order", zap.String("order_uid", orderID), zap.Error(err))
+		s.writeError(w, r, err)
+		return
+	}
+	s.cache.Set(order)
+	s.writeJSON(w, r, dto.NewOrder(order))
+}
+
+// handleAdminArchiveOrder помечает заказ архивным.
+//
+//	Архивный заказ остаётся доступен; в кэше обновляется только его отметка.
+func (s *Server) handleAdminArchiveOrder(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOrderStorage(w, r) {
+		return
+	}
+	orderID := r.PathValue("id")
+	order, err := s.orders.ArchiveOrder(r.Context(), orderID)
+	if err != nil {
+		s.log(r).Warn("Failed to archive order", zap.String("order_uid", orderID), zap.Error(err))
+		s.writeError(w, r, err)
+		return
+	}
+	if order.DeletedAt == nil {
+		s.cache.Set(order)
+	}
+	s.writeJSON(w, r, dto.NewOrder(order))
+}
+
+// writeJSON записывает ответ в формате JSON.
+func (s *Server) writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.log(r).Error("Failed to encode response", zap.Error(err))
+	}
+}
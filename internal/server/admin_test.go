@@ -0,0 +1,151 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/config"
+	"l0_wb/internal/model"
+	"l0_wb/internal/util"
+)
+
+// fakeConsumer запоминает состояние паузы.
+type fakeConsumer struct{ paused bool }
+
+func (f *fakeConsumer) Pause()       { f.paused = true }
+func (f *fakeConsumer) Resume()      { f.paused = false }
+func (f *fakeConsumer) Paused() bool { return f.paused }
+
+// fakeReloader возвращает заданный результат перезагрузки конфигурации.
+type fakeReloader struct {
+	changed []string
+	err     error
+}
+
+func (f *fakeReloader) Reload() ([]string, error) { return f.changed, f.err }
+
+// TestAdminRoutes проверяет аутентификацию административных маршрутов и их действия.
+func TestAdminRoutes(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	cfg := &config.Config{AdminToken: "admin-token", AdminUser: "ops", AdminPassword: "secret", DBPassword: "db-secret"}
+	orderCache := cache.NewOrderCache()
+	orderCache.Set(&model.Order{OrderUID: "b563feb7b2b84b6test"})
+	consumer := &fakeConsumer{}
+	orders := &fakeOrderService{customerOrders: []*model.Order{{OrderUID: "b563feb7b2b84b6test"}}}
+	s := &Server{
+		cache:    orderCache,
+		orders:   orders,
+		admin:    newAdminAuth(cfg),
+		audit:    zap.NewNop(),
+		consumer: consumer,
+		logger:   zap.NewNop(),
+	}
+	s.config.Store(cfg.Redacted())
+	mux := http.NewServeMux()
+	s.registerRoutes(mux)
+
+	do := func(method, path string, auth func(*http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if auth != nil {
+			auth(req)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	bearer := func(token string) func(*http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+
+	if rec := do(http.MethodGet, "/api/admin/cache/stats", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without credentials, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/api/admin/cache/stats", bearer("wrong")); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for wrong token, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/api/admin/cache/stats", func(r *http.Request) { r.SetBasicAuth("ops", "secret") }); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for basic auth, got %d", rec.Code)
+	}
+
+	if rec := do(http.MethodPost, "/api/admin/consumer/pause", bearer("admin-token")); rec.Code != http.StatusOK || !consumer.paused {
+		t.Errorf("expected consumer to be paused, got %d", rec.Code)
+	}
+
+	rec := do(http.MethodGet, "/api/admin/config", bearer("admin-token"))
+	if strings.Contains(rec.Body.String(), "db-secret") || strings.Contains(rec.Body.String(), "admin-token") {
+		t.Errorf("config dump exposes secrets: %s", rec.Body.String())
+	}
+	if rec := do(http.MethodGet, "/api/admin/config/sources", bearer("admin-token")); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"sources"`) {
+		t.Errorf("expected config sources, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = do(http.MethodPost, "/api/admin/cache/clear", bearer("admin-token"))
+	var resp map[string]int
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp["removed"] != 1 || orderCache.Len() != 0 {
+		t.Errorf("unexpected cache clear response %s", rec.Body.String())
+	}
+
+	if rec := do(http.MethodPost, "/api/admin/orders/b563feb7b2b84b6test/restore", bearer("admin-token")); rec.Code != http.StatusOK || orderCache.Len() != 1 {
+		t.Errorf("expected restored order in cache, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodPost, "/api/admin/orders/b563feb7b2b84b6test/archive", bearer("admin-token")); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "archived_at") {
+		t.Errorf("expected archived order, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodDelete, "/api/admin/orders/b563feb7b2b84b6test", bearer("admin-token")); rec.Code != http.StatusOK || orderCache.Len() != 0 || len(orders.deleted) != 1 {
+		t.Errorf("expected order to be deleted, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodDelete, "/api/admin/orders/missing", bearer("admin-token")); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for missing order, got %d", rec.Code)
+	}
+
+	if rec := do(http.MethodPost, "/api/admin/config/reload", bearer("admin-token")); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without reloader, got %d", rec.Code)
+	}
+	reloader := &fakeReloader{changed: []string{"LOG_LEVEL"}}
+	s.SetConfigReloader(reloader)
+	if rec := do(http.MethodPost, "/api/admin/config/reload", bearer("admin-token")); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "LOG_LEVEL") {
+		t.Errorf("expected changed settings, got %d: %s", rec.Code, rec.Body.String())
+	}
+	reloader.err = &config.ValidationError{Problems: []string{"invalid LOG_LEVEL: \"loud\""}}
+	if rec := do(http.MethodPost, "/api/admin/config/reload", bearer("admin-token")); rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "LOG_LEVEL") {
+		t.Errorf("expected validation problems, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// TestAdminLogLevel проверяет чтение и изменение уровня журналирования.
+func TestAdminLogLevel(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	defer func(level string) { _ = util.SetLogLevel(level) }(util.LogLevel())
+
+	cfg := &config.Config{AdminToken: "admin-token"}
+	s := &Server{admin: newAdminAuth(cfg), audit: zap.NewNop(), logger: zap.NewNop()}
+	mux := http.NewServeMux()
+	s.registerAdminRoutes(mux)
+
+	do := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/admin/log/level", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer admin-token")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodPut, `{"level":"debug"}`); rec.Code != http.StatusOK || util.LogLevel() != "debug" {
+		t.Fatalf("expected level to change to debug, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodGet, ""); !strings.Contains(rec.Body.String(), `"debug"`) {
+		t.Errorf("expected current level in response, got %s", rec.Body.String())
+	}
+	if rec := do(http.MethodPut, `{"level":"loud"}`); rec.Code != http.StatusBadRequest || util.LogLevel() != "debug" {
+		t.Errorf("expected 400 for unknown level, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/metrics"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+)
+
+// Параметры журнала аудита административных вызовов.
+const (
+	// auditMaxBody — размер JSON-тела запроса, который сохраняется в параметрах вызова.
+	auditMaxBody = 4 << 10
+	// auditWriteTimeout — время на запись вызова в журнал аудита.
+	auditWriteTimeout = 2 * time.Second
+	// defaultAuditPageSize — размер страницы журнала аудита по умолчанию.
+	defaultAuditPageSize = 100
+)
+
+// auditPage — ответ GET /api/admin/audit.
+type auditPage struct {
+	Entries []model.AuditEntry `json:"entries"`
+	// NextBefore — значение параметра before для следующей страницы (0 — страница последняя).
+	NextBefore int64 `json:"next_before,omitempty"`
+}
+
+// SetAuditRepository подключает журнал аудита административных вызовов в БД.
+//
+//	Параметры:
+//	- repo: репозиторий журнала аудита (nil — вызовы только пишутся в лог,
+//	  а GET /api/admin/audit отвечает 503).
+func (s *Server) SetAuditRepository(repo repository.AuditRepository) {
+	s.auditRepo = repo
+}
+
+// auditParams собирает параметры административного вызова.
+//
+//	В параметры попадают id из пути, параметры строки запроса и JSON-тело
+//	размером до auditMaxBody. Прочитанное тело возвращается в запрос, чтобы
+//	обработчик получил его целиком.
+//	Параметры:
+//	- r: HTTP-запрос.
+//	Возвращает:
+//	- map[string]any: параметры вызова (nil, если их нет).
+func auditParams(r *http.Request) map[string]any {
+	params := make(map[string]any)
+	if id := r.PathValue("id"); id != "" {
+		params["id"] = id
+	}
+	if q := r.URL.Query(); len(q) > 0 {
+		params["query"] = q
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := io.ReadAll(io.LimitReader(r.Body, auditMaxBody+1))
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		if err == nil && len(body) <= auditMaxBody && json.Valid(body) {
+			params["body"] = json.RawMessage(body)
+		}
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	return params
+}
+
+// recordAudit сохраняет административный вызов в журнал аудита.
+//
+//	Запись выполняется и после отмены запроса клиентом; ошибка записи не
+//	меняет ответ, а попадает в лог аудита и метрику errors_total{type="audit"}.
+//	Параметры:
+//	- r: HTTP-запрос.
+//	- entry: запись журнала.
+//	- audit: лог аудита с полями вызова.
+func (s *Server) recordAudit(r *http.Request, entry *model.AuditEntry, audit *zap.Logger) {
+	if s.auditRepo == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), auditWriteTimeout)
+	defer cancel()
+	if _, err := s.auditRepo.Insert(ctx, entry); err != nil {
+		metrics.RecordError("audit", "insert")
+		audit.Error("Failed to write admin audit entry", zap.Error(err))
+	}
+}
+
+// handleAdminAudit возвращает журнал аудита административных вызовов: GET /api/admin/audit.
+//
+//	Записи отдаются от новых к старым. Параметры отбора: actor — точное имя
+//	администратора, path — префикс пути, from и to — полуинтервал [from, to)
+//	в формате RFC 3339 или YYYY-MM-DD, before — next_before предыдущей
+//	страницы, limit — размер страницы (по умолчанию 100).
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleAdminAudit(w http.ResponseWriter, r *http.Request) {
+	if s.auditRepo == nil {
+		s.writeError(w, r, newAPIError(http.StatusServiceUnavailable, codeUnavailable, "audit log storage is not available"))
+		return
+	}
+	filter, limit, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	entries, err := s.auditRepo.List(r.Context(), filter, limit)
+	if err != nil {
+		s.log(r).Error("Failed to fetch audit log", zap.Error(err))
+		s.writeError(w, r, err)
+		return
+	}
+	resp := auditPage{Entries: entries}
+	if resp.Entries == nil {
+		resp.Entries = []model.AuditEntry{}
+	}
+	if len(entries) == limit {
+		resp.NextBefore = entries[len(entries)-1].ID
+	}
+	s.writeJSON(w, r, resp)
+}
+
+// parseAuditFilter разбирает параметры отбора журнала аудита.
+//
+//	Параметры:
+//	- q: параметры запроса.
+//	Возвращает:
+//	- repository.AuditFilter: условия отбора.
+//	- int: размер страницы.
+//	- error: *apiError для некорректного параметра.
+func parseAuditFilter(q url.Values) (repository.AuditFilter, int, error) {
+	filter := repository.AuditFilter{Actor: q.Get("actor"), Path: q.Get("path")}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		raw := q.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := parseFilterTime(raw)
+		if err != nil {
+			return filter, 0, newAPIErrorf(http.StatusBadRequest, codeBadRequest, "%s must be an RFC 3339 timestamp or YYYY-MM-DD date", p.name).
+				withDetails(map[string]string{"parameter": p.name})
+		}
+		*p.dst = t
+	}
+	if raw := q.Get("before"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id < 1 {
+			return filter, 0, newAPIError(http.StatusBadRequest, codeBadRequest, "before must be a positive audit entry id").
+				withDetails(map[string]string{"parameter": "before"})
+		}
+		filter.BeforeID = id
+	}
+
+	limit := defaultAuditPageSize
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxPageSize {
+			return filter, 0, newAPIErrorf(http.StatusBadRequest, codeBadRequest, "limit must be between 1 and %d", maxPageSize).
+				withDetails(map[string]any{"parameter": "limit", "min": 1, "max": maxPageSize})
+		}
+		limit = n
+	}
+	return filter, limit, nil
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/util"
+)
+
+// fakeAuditRepository хранит записи журнала аудита в памяти.
+type fakeAuditRepository struct {
+	entries []model.AuditEntry
+	filter  repository.AuditFilter
+}
+
+func (f *fakeAuditRepository) Insert(_ context.Context, e *model.AuditEntry) (int64, error) {
+	e.ID = int64(len(f.entries) + 1)
+	f.entries = append(f.entries, *e)
+	return e.ID, nil
+}
+
+func (f *fakeAuditRepository) List(_ context.Context, filter repository.AuditFilter, limit int) ([]model.AuditEntry, error) {
+	f.filter = filter
+	var page []model.AuditEntry
+	for i := len(f.entries) - 1; i >= 0 && len(page) < limit; i-- {
+		page = append(page, f.entries[i])
+	}
+	return page, nil
+}
+
+// TestAdminAudit проверяет запись административных вызовов и выдачу журнала аудита.
+func TestAdminAudit(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	defer func(level string) { _ = util.SetLogLevel(level) }(util.LogLevel())
+
+	cfg := &config.Config{AdminUser: "ops", AdminPassword: "secret"}
+	repo := &fakeAuditRepository{}
+	s := &Server{admin: newAdminAuth(cfg), audit: zap.NewNop(), logger: zap.NewNop()}
+	s.SetAuditRepository(repo)
+	mux := http.NewServeMux()
+	s.registerAdminRoutes(mux)
+
+	do := func(method, path, body string, authorized bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if authorized {
+			req.SetBasicAuth("ops", "secret")
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodPut, "/api/admin/log/level", `{"level":"debug"}`, true); rec.Code != http.StatusOK {
+		t.Fatalf("expected level to change, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodDelete, "/api/admin/orders/b563feb7b2b84b6test", "", true); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without order storage, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/api/admin/cache/clear", "", false); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without credentials, got %d", rec.Code)
+	}
+
+	if len(repo.entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %+v", repo.entries)
+	}
+	level := repo.entries[0]
+	if level.Actor != "ops" || level.Method != http.MethodPut || level.Status != http.StatusOK ||
+		string(level.Params["body"].(json.RawMessage)) != `{"level":"debug"}` {
+		t.Errorf("unexpected log level audit entry %+v", level)
+	}
+	if deleted := repo.entries[1]; deleted.Params["id"] != "b563feb7b2b84b6test" || deleted.Status != http.StatusServiceUnavailable {
+		t.Errorf("unexpected delete audit entry %+v", deleted)
+	}
+
+	rec := do(http.MethodGet, "/api/admin/audit?actor=ops&limit=1", "", true)
+	var page auditPage
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("unexpected audit response %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(page.Entries) != 1 || page.Entries[0].ID != 2 || page.NextBefore != 2 || repo.filter.Actor != "ops" {
+		t.Errorf("unexpected audit page %+v", page)
+	}
+	if rec := do(http.MethodGet, "/api/admin/audit?before=x", "", true); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid before, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	"l0_wb/internal/config"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/service"
+)
+
+// apiKeyHeader — заголовок, в котором машинные клиенты передают ключ.
+const apiKeyHeader = "X-API-Key"
+
+// apiKeyCacheTTL — время, в течение которого ключ из БД не перечитывается.
+// Ограничивает нагрузку на БД и задаёт задержку применения отзыва ключа.
+const apiKeyCacheTTL = time.Minute
+
+// cachedAPIKey хранит ключ, прочитанный из БД, вместе со временем его устаревания.
+type cachedAPIKey struct {
+	key       *model.APIKey
+	expiresAt time.Time
+}
+
+// apiKeyAuth выполняет аутентификацию по заголовку X-API-Key и ограничивает частоту запросов для каждого ключа.
+type apiKeyAuth struct {
+	static     map[string]*model.APIKey // Ключи из конфигурации, индексированные по хешу
+	repo       repository.APIKeysRepository
+	defaultRPS int
+	logger     *zap.Logger
+	now        func() time.Time
+
+	mu       sync.Mutex
+	dbKeys   map[string]cachedAPIKey
+	limiters map[string]*rate.Limiter
+}
+
+// newAPIKeyAuth создаёт аутентификатор по API-ключам.
+//
+//	Параметры:
+//	- cfg: конфигурация приложения.
+//	- repo: репозиторий ключей в БД (nil, если ключи берутся только из конфигурации).
+//	- logger: логгер.
+//	Возвращает:
+//	- *apiKeyAuth: аутентификатор или nil, если ни одного источника ключей не настроено.
+func newAPIKeyAuth(cfg *config.Config, repo repository.APIKeysRepository, logger *zap.Logger) *apiKeyAuth {
+	if !cfg.APIKeysFromDB {
+		repo = nil
+	}
+	if len(cfg.APIKeys) == 0 && repo == nil {
+		return nil
+	}
+
+	static := make(map[string]*model.APIKey, len(cfg.APIKeys))
+	for _, k := range cfg.APIKeys {
+		hash := hashAPIKey(k.Key)
+		static[hash] = &model.APIKey{Name: k.Name, KeyHash: hash, RateLimit: k.RateLimit}
+	}
+
+	return &apiKeyAuth{
+		static:     static,
+		repo:       repo,
+		defaultRPS: cfg.APIKeyDefaultRateLimit,
+		logger:     logger,
+		now:        time.Now,
+		dbKeys:     make(map[string]cachedAPIKey),
+		limiters:   make(map[string]*rate.Limiter),
+	}
+}
+
+// hashAPIKey возвращает hex-представление SHA-256 хеша ключа.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// lookup ищет ключ сначала в конфигурации, затем в БД.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- key: значение ключа из запроса.
+//	Возвращает:
+//	- *model.APIKey: найденный ключ или nil, если ключ неизвестен.
+//	- error: ошибку обращения к БД.
+func (a *apiKeyAuth) lookup(ctx context.Context, key string) (*model.APIKey, error) {
+	hash := hashAPIKey(key)
+	if k, ok := a.static[hash]; ok {
+		return k, nil
+	}
+	if a.repo == nil {
+		return nil, nil
+	}
+
+	a.mu.Lock()
+	cached, ok := a.dbKeys[hash]
+	a.mu.Unlock()
+	if ok && a.now().Before(cached.expiresAt) {
+		return cached.key, nil
+	}
+
+	k, err := a.repo.GetByHash(ctx, hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	a.dbKeys[hash] = cachedAPIKey{key: k, expiresAt: a.now().Add(apiKeyCacheTTL)}
+	a.mu.Unlock()
+	return k, nil
+}
+
+// setDefaultRateLimit меняет лимит запросов по умолчанию.
+//
+//	Ограничители всех ключей пересоздаются при следующем запросе.
+func (a *apiKeyAuth) setDefaultRateLimit(rps int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.defaultRPS = rps
+	clear(a.limiters)
+}
+
+// limiter возвращает ограничитель частоты запросов для ключа.
+func (a *apiKeyAuth) limiter(k *model.APIKey) *rate.Limiter {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	l, ok := a.limiters[k.KeyHash]
+	if ok {
+		return l
+	}
+	rps := k.RateLimit
+	if rps == 0 {
+		rps = a.defaultRPS
+	}
+	if rps <= 0 {
+		l = rate.NewLimiter(rate.Inf, 0)
+	} else {
+		l = rate.NewLimiter(rate.Limit(rps), rps)
+	}
+	a.limiters[k.KeyHash] = l
+	return l
+}
+
+// requireAPIKey оборачивает обработчик проверкой API-ключа и лимита запросов.
+//
+//	Если аутентификация не настроена, обработчик возвращается без изменений.
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	Возвращает:
+//	- http.HandlerFunc: обработчик с проверкой ключа.
+func (s *Server) requireAPIKey(next http.HandlerFunc) http.HandlerFunc {
+	if s.auth == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(apiKeyHeader)
+		if key == "" {
+			s.writeError(w, r, newAPIError(http.StatusUnauthorized, codeUnauthorized, "api key is required"))
+			return
+		}
+
+		k, err := s.auth.lookup(r.Context(), key)
+		if err != nil {
+			s.log(r).Error("Failed to look up API key", zap.Error(err))
+			s.writeError(w, r, newAPIError(http.StatusInternalServerError, codeInternal, "failed to verify api key"))
+			return
+		}
+		if k == nil || k.Revoked {
+			s.log(r).Warn("Rejected request with invalid API key", zap.String("path", r.URL.Path))
+			s.writeError(w, r, newAPIError(http.StatusUnauthorized, codeUnauthorized, "invalid api key"))
+			return
+		}
+
+		reservation := s.auth.limiter(k).Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			reservation.Cancel()
+			retryAfter := int(math.Ceil(delay.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			s.log(r).Warn("API key rate limit exceeded", zap.String("client", k.Name))
+			s.writeError(w, r, newAPIError(http.StatusTooManyRequests, codeRateLimited, "rate limit exceeded").
+				withDetails(map[string]int{"retry_after_seconds": retryAfter}))
+			return
+		}
+
+		// Изменения заказов, сделанные по запросу, записываются в журнал от имени клиента
+		next(w, r.WithContext(service.WithActor(r.Context(), "api_key:"+k.Name)))
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository/mocks"
+	"l0_wb/internal/service"
+)
+
+// TestRequireAPIKey проверяет проверку ключа из конфигурации и БД и ограничение частоты запросов.
+func TestRequireAPIKey(t *testing.T) {
+	repo := &mocks.APIKeysRepositoryMock{
+		GetByHashFunc: func(_ context.Context, hash string) (*model.APIKey, error) {
+			switch hash {
+			case hashAPIKey("db-key"):
+				return &model.APIKey{Name: "db-client", KeyHash: hash}, nil
+			case hashAPIKey("revoked-key"):
+				return &model.APIKey{Name: "old-client", KeyHash: hash, Revoked: true}, nil
+			case hashAPIKey("broken-key"):
+				return nil, errors.New("connection refused")
+			}
+			return nil, pgx.ErrNoRows
+		},
+	}
+	cfg := &config.Config{
+		APIKeys: []config.APIKeyConfig{
+			{Name: "static-client", Key: "static-key"},
+			{Name: "limited-client", Key: "limited-key", RateLimit: 1},
+		},
+		APIKeysFromDB: true,
+	}
+	s := &Server{auth: newAPIKeyAuth(cfg, repo, zap.NewNop()), logger: zap.NewNop()}
+	var actor string
+	h := s.requireAPIKey(func(w http.ResponseWriter, r *http.Request) {
+		actor = service.ActorFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		key        string
+		requests   int // Число запросов подряд; проверяется ответ на последний
+		wantStatus int
+		wantActor  string
+		wantRetry  string
+	}{
+		{name: "missing key", wantStatus: http.StatusUnauthorized},
+		{name: "unknown key", key: "unknown-key", wantStatus: http.StatusUnauthorized},
+		{name: "static key", key: "static-key", wantStatus: http.StatusOK, wantActor: "api_key:static-client"},
+		{name: "db key", key: "db-key", wantStatus: http.StatusOK, wantActor: "api_key:db-client"},
+		{name: "revoked key", key: "revoked-key", wantStatus: http.StatusUnauthorized},
+		{name: "db failure", key: "broken-key", wantStatus: http.StatusInternalServerError},
+		{name: "within rate limit", key: "limited-key", requests: 1, wantStatus: http.StatusOK, wantActor: "api_key:limited-client"},
+		{name: "rate limit exceeded", key: "limited-key", requests: 1, wantStatus: http.StatusTooManyRequests, wantRetry: "1"},
+		// Лимит ведётся для каждого ключа отдельно
+		{name: "other key not limited", key: "static-key", requests: 5, wantStatus: http.StatusOK, wantActor: "api_key:static-client"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rec *httptest.ResponseRecorder
+			actor = ""
+			for range max(tt.requests, 1) {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+				if tt.key != "" {
+					req.Header.Set(apiKeyHeader, tt.key)
+				}
+				rec = httptest.NewRecorder()
+				h(rec, req)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if tt.wantStatus == http.StatusOK && actor != tt.wantActor {
+				t.Errorf("expected actor %q, got %q", tt.wantActor, actor)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetry {
+				t.Errorf("expected Retry-After %q, got %q", tt.wantRetry, got)
+			}
+		})
+	}
+}
+
+// TestAPIKeyCacheExpiry проверяет, что ключ из БД перечитывается только после истечения apiKeyCacheTTL.
+func TestAPIKeyCacheExpiry(t *testing.T) {
+	revoked := false
+	repo := &mocks.APIKeysRepositoryMock{
+		GetByHashFunc: func(_ context.Context, hash string) (*model.APIKey, error) {
+			return &model.APIKey{Name: "db-client", KeyHash: hash, Revoked: revoked}, nil
+		},
+	}
+	auth := newAPIKeyAuth(&config.Config{APIKeysFromDB: true}, repo, zap.NewNop())
+	now := time.Unix(0, 0)
+	auth.now = func() time.Time { return now }
+
+	lookup := func() *model.APIKey {
+		t.Helper()
+		k, err := auth.lookup(context.Background(), "db-key")
+		if err != nil || k == nil {
+			t.Fatalf("lookup failed: key=%v err=%v", k, err)
+		}
+		return k
+	}
+
+	lookup()
+	revoked = true
+	now = now.Add(apiKeyCacheTTL - time.Second)
+	if k := lookup(); k.Revoked || len(repo.GetByHashCalls()) != 1 {
+		t.Fatalf("expected cached key before TTL, got revoked=%v reads=%d", k.Revoked, len(repo.GetByHashCalls()))
+	}
+
+	// После истечения TTL отзыв ключа в БД применяется
+	now = now.Add(time.Second)
+	if k := lookup(); !k.Revoked || len(repo.GetByHashCalls()) != 2 {
+		t.Fatalf("expected key to be re-read after TTL, got revoked=%v reads=%d", k.Revoked, len(repo.GetByHashCalls()))
+	}
+
+	// Репозиторий без API_KEYS_FROM_DB не используется, и без ключей в конфигурации аутентификация не настраивается
+	if newAPIKeyAuth(&config.Config{}, repo, zap.NewNop()) != nil {
+		t.Error("expected nil auth when API_KEYS_FROM_DB is off and API_KEYS is empty")
+	}
+}
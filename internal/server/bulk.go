@@ -0,0 +1,204 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/dto"
+	"l0_wb/internal/i18n"
+	"l0_wb/internal/model"
+	"l0_wb/internal/service"
+)
+
+// bulkChunkSize — число заказов, сохраняемых одной транзакцией SaveBatch.
+const bulkChunkSize = 100
+
+// Статусы обработки заказа при массовой загрузке.
+const (
+	bulkStatusSaved      = string(service.SaveStatusSaved)
+	bulkStatusDuplicate  = string(service.SaveStatusDuplicate)
+	bulkStatusSuperseded = string(service.SaveStatusSuperseded)
+	bulkStatusFailed     = string(service.SaveStatusFailed)
+)
+
+// bulkResult — результат обработки одного заказа массовой загрузки.
+type bulkResult struct {
+	Index    int                  `json:"index"`
+	OrderUID string               `json:"order_uid,omitempty"`
+	Status   string               `json:"status"`
+	Error    string               `json:"error,omitempty"`
+	Fields   []service.FieldError `json:"fields,omitempty"` // Нарушения правил валидации по полям
+}
+
+// bulkResponse — ответ на массовую загрузку заказов.
+type bulkResponse struct {
+	Total     int          `json:"total"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []bulkResult `json:"results"`
+}
+
+// errBulkTooMany возвращается, если в запросе больше BULK_MAX_ORDERS заказов.
+var errBulkTooMany = errors.New("too many orders in request")
+
+// handleBulkOrders сохраняет пакет заказов: POST /api/v1/orders/bulk.
+//
+//	Тело — JSON-массив заказов или NDJSON (Content-Type application/x-ndjson),
+//	по одному заказу в строке. Каждый заказ декодируется и проверяется отдельно;
+//	корректные сохраняются через SaveBatch порциями по bulkChunkSize. Ошибка
+//	одного заказа или порции не мешает сохранению остальных; уже сохранённые
+//	без изменений заказы получают статус skipped_duplicate, а ранние версии
+//	заказа, повторяющегося в порции, — superseded. В ответе — результат
+//	по каждому заказу в порядке следования во входных данных.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleBulkOrders(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOrderStorage(w, r) {
+		return
+	}
+
+	raw, err := readBulkOrders(r, s.bulkMaxOrders)
+	if err != nil {
+		s.log(r).Warn("Failed to read bulk orders", zap.Error(err))
+		if errors.Is(err, errBulkTooMany) {
+			err = newAPIError(http.StatusRequestEntityTooLarge, codePayloadTooLarge, err.Error()).
+				withDetails(map[string]int{"max_orders": s.bulkMaxOrders})
+		} else if !errors.As(err, new(*http.MaxBytesError)) {
+			err = newAPIErrorf(http.StatusBadRequest, codeBadRequest, "invalid request body: %v", err)
+		}
+		s.writeError(w, r, err)
+		return
+	}
+
+	resp := bulkResponse{Total: len(raw), Results: make([]bulkResult, len(raw))}
+	var pending []*model.Order
+	var pendingIdx []int
+	for i, msg := range raw {
+		resp.Results[i] = bulkResult{Index: i, Status: bulkStatusFailed}
+		var in dto.Order
+		if err := json.Unmarshal(msg, &in); err != nil {
+			resp.Results[i].Error = err.Error()
+			continue
+		}
+		order := in.ToModel()
+		resp.Results[i].OrderUID = order.OrderUID
+		if err := service.ValidateOrder(order); err != nil {
+			var validationErr *service.ValidationError
+			if errors.As(err, &validationErr) {
+				validationErr = validationErr.Localize(messageLang(r, order.Locale))
+				resp.Results[i].Fields = validationErr.Fields
+				err = validationErr
+			}
+			resp.Results[i].Error = err.Error()
+			continue
+		}
+		pending = append(pending, order)
+		pendingIdx = append(pendingIdx, i)
+	}
+
+	for start := 0; start < len(pending); start += bulkChunkSize {
+		end := min(start+bulkChunkSize, len(pending))
+		chunk := pending[start:end]
+		saved, err := s.orders.SaveBatch(r.Context(), chunk)
+		if err != nil {
+			s.log(r).Error("Failed to save bulk orders chunk", zap.Int("size", len(chunk)), zap.Error(err))
+			for j, order := range chunk {
+				resp.Results[pendingIdx[start+j]].Error = i18n.Sprintf(messageLang(r, order.Locale), "failed to save order")
+			}
+			continue
+		}
+		for j, order := range chunk {
+			res := &resp.Results[pendingIdx[start+j]]
+			if !saved[j].Stored() {
+				res.Error = i18n.Sprintf(messageLang(r, order.Locale), "failed to save order")
+				continue
+			}
+			res.Status = string(saved[j].Status)
+			if s.feed != nil && saved[j].Status == service.SaveStatusSaved {
+				s.feed.Publish(order)
+			}
+		}
+	}
+
+	for _, res := range resp.Results {
+		if res.Status == bulkStatusSaved || res.Status == bulkStatusDuplicate || res.Status == bulkStatusSuperseded {
+			resp.Succeeded++
+		}
+	}
+	resp.Failed = resp.Total - resp.Succeeded
+	s.log(r).Info("Bulk orders processed",
+		zap.Int("total", resp.Total),
+		zap.Int("succeeded", resp.Succeeded),
+		zap.Int("failed", resp.Failed),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.log(r).Error("Failed to encode bulk response", zap.Error(err))
+	}
+}
+
+// readBulkOrders читает заказы из тела запроса без их декодирования.
+//
+//	Параметры:
+//	- r: HTTP-запрос.
+//	- maxOrders: максимальное число заказов (0 — без ограничения).
+//	Возвращает:
+//	- []json.RawMessage: заказы в исходном виде.
+//	- error: ошибку чтения, синтаксиса или errBulkTooMany.
+func readBulkOrders(r *http.Request, maxOrders int) ([]json.RawMessage, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/x-ndjson" || mediaType == "application/jsonl" {
+		return readNDJSON(r.Body, maxOrders)
+	}
+
+	dec := json.NewDecoder(r.Body)
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('[') {
+		return nil, errors.New("expected a JSON array of orders")
+	}
+	var raw []json.RawMessage
+	for dec.More() {
+		if maxOrders > 0 && len(raw) >= maxOrders {
+			return nil, errBulkTooMany
+		}
+		var msg json.RawMessage
+		if err := dec.Decode(&msg); err != nil {
+			return nil, err
+		}
+		raw = append(raw, msg)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// readNDJSON читает непустые строки NDJSON; каждая строка — отдельный заказ.
+//
+//	Строки с некорректным JSON не прерывают чтение: ошибка будет получена при
+//	декодировании и попадёт в результат этого заказа.
+func readNDJSON(body io.Reader, maxOrders int) ([]json.RawMessage, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var raw []json.RawMessage
+	for scanner.Scan() {
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		if maxOrders > 0 && len(raw) >= maxOrders {
+			return nil, errBulkTooMany
+		}
+		raw = append(raw, append(json.RawMessage(nil), b...))
+	}
+	return raw, scanner.Err()
+}
@@ -0,0 +1,279 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/model"
+	"l0_wb/internal/pagination"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/service"
+	"l0_wb/internal/util"
+)
+
+// fakeOrderService сохраняет заказы в памяти и отклоняет заказы с заданным order_uid.
+//
+//	customerOrders задаёт историю заказов, упорядоченную по убыванию order_uid;
+//	history — журналы изменений заказов из customerOrders.
+type fakeOrderService struct {
+	saved          []string
+	deleted        []string
+	reject         string
+	customerOrders []*model.Order
+	history        map[string][]model.OrderChange
+}
+
+func (f *fakeOrderService) SaveOrder(ctx context.Context, order *model.Order) error {
+	results, err := f.SaveBatch(ctx, []*model.Order{order})
+	if err != nil {
+		return err
+	}
+	return results[0].Err
+}
+
+func (f *fakeOrderService) SaveBatch(_ context.Context, orders []*model.Order) ([]service.SaveResult, error) {
+	results := make([]service.SaveResult, len(orders))
+	for i, o := range orders {
+		results[i] = service.SaveResult{OrderUID: o.OrderUID, Status: service.SaveStatusSaved}
+		switch {
+		case o.OrderUID == f.reject:
+			results[i].Status, results[i].Err = service.SaveStatusFailed, errors.New("constraint violation")
+		case slices.Contains(f.saved, o.OrderUID):
+			results[i].Status = service.SaveStatusDuplicate
+		default:
+			f.saved = append(f.saved, o.OrderUID)
+		}
+	}
+	return results, nil
+}
+
+func (f *fakeOrderService) GetOrderByID(context.Context, string) (*model.Order, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeOrderService) DeleteOrder(_ context.Context, orderUID string) error {
+	if _, err := f.find(orderUID); err != nil {
+		return err
+	}
+	f.deleted = append(f.deleted, orderUID)
+	return nil
+}
+
+func (f *fakeOrderService) RestoreOrder(_ context.Context, orderUID string) (*model.Order, error) {
+	return f.find(orderUID)
+}
+
+func (f *fakeOrderService) ArchiveOrder(_ context.Context, orderUID string) (*model.Order, error) {
+	o, err := f.find(orderUID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	o.ArchivedAt = &now
+	return o, nil
+}
+
+func (f *fakeOrderService) GetOrderHistory(_ context.Context, orderUID string) ([]model.OrderChange, error) {
+	if _, err := f.find(orderUID); err != nil {
+		return nil, err
+	}
+	if changes := f.history[orderUID]; changes != nil {
+		return changes, nil
+	}
+	return []model.OrderChange{}, nil
+}
+
+func (f *fakeOrderService) UpdateStatus(_ context.Context, orderUID string, status model.OrderStatus) (*model.Order, error) {
+	if !status.Valid() {
+		return nil, service.ErrUnknownStatus
+	}
+	o, err := f.find(orderUID)
+	if err != nil {
+		return nil, err
+	}
+	if !o.Status.CanTransitionTo(status) {
+		return nil, &service.TransitionError{From: o.Status, To: status}
+	}
+	o.Status = status
+	return o, nil
+}
+
+func (f *fakeOrderService) EraseCustomerData(_ context.Context, customerID string) ([]*model.Order, error) {
+	var orders []*model.Order
+	for _, o := range f.customerOrders {
+		if o.CustomerID == customerID {
+			o.Delivery.Name, o.Delivery.Phone, o.Delivery.Zip, o.Delivery.Address, o.Delivery.Email = "", "", "", "", ""
+			orders = append(orders, o)
+		}
+	}
+	if len(orders) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return orders, nil
+}
+
+// find ищет заказ в customerOrders.
+func (f *fakeOrderService) find(orderUID string) (*model.Order, error) {
+	for _, o := range f.customerOrders {
+		if o.OrderUID == orderUID {
+			return o, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (f *fakeOrderService) GetOrdersByCustomerID(ctx context.Context, customerID string, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor, error) {
+	return f.ListOrders(ctx, repository.OrderFilter{CustomerID: customerID}, after, limit)
+}
+
+func (f *fakeOrderService) ListOrders(_ context.Context, filter repository.OrderFilter, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor, error) {
+	var orders []*model.Order
+	for _, o := range f.customerOrders {
+		if filter.Match(o) && (after == nil || o.OrderUID < after.OrderUID) {
+			orders = append(orders, o)
+		}
+	}
+	if len(orders) <= limit {
+		return orders, nil, nil
+	}
+	last := orders[limit-1]
+	return orders[:limit], &pagination.Cursor{DateCreated: last.DateCreated, OrderUID: last.OrderUID}, nil
+}
+
+// TestBulkOrders проверяет результаты по каждому заказу для JSON-массива и NDJSON.
+func TestBulkOrders(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	validOrder := func(uid string) string {
+		return `{"order_uid":"` + uid + `","delivery":{"name":"Test","phone":"+9720000000","address":"Street 1"},"items":[{"chrt_id":1}]}`
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		statuses    []string
+		saved       int
+	}{
+		{
+			name:        "json array",
+			contentType: "application/json",
+			body:        "[" + validOrder("a") + `,{"order_uid":"no-items"},` + validOrder("b") + "]",
+			statuses:    []string{bulkStatusSaved, bulkStatusFailed, bulkStatusSaved},
+			saved:       2,
+		},
+		{
+			name:        "ndjson",
+			contentType: "application/x-ndjson",
+			body:        validOrder("c") + "\n{broken\n\n" + validOrder("d") + "\n",
+			statuses:    []string{bulkStatusSaved, bulkStatusFailed, bulkStatusSaved},
+			saved:       2,
+		},
+	}
+
+	for _, tt := range tests {
+		svc := &fakeOrderService{}
+		s := &Server{cache: cache.NewOrderCache(), orders: svc, logger: zap.NewNop()}
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/bulk", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", tt.contentType)
+		rec := httptest.NewRecorder()
+		s.handleBulkOrders(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", tt.name, rec.Code, rec.Body.String())
+		}
+		var resp bulkResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: invalid response: %v", tt.name, err)
+		}
+		if resp.Total != len(tt.statuses) || resp.Succeeded != tt.saved || len(svc.saved) != tt.saved {
+			t.Errorf("%s: unexpected summary %+v, saved %v", tt.name, resp, svc.saved)
+		}
+		for i, status := range tt.statuses {
+			if resp.Results[i].Status != status {
+				t.Errorf("%s: order %d: expected %s, got %+v", tt.name, i, status, resp.Results[i])
+			}
+		}
+	}
+}
+
+// TestBulkOrdersFieldErrors проверяет, что нарушения правил валидации возвращаются по полям.
+func TestBulkOrdersFieldErrors(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	s := &Server{cache: cache.NewOrderCache(), orders: &fakeOrderService{}, logger: zap.NewNop()}
+	body := `[{"order_uid":"a","delivery":{"name":"Test","phone":"phone"},"items":[{"chrt_id":1}]}]`
+	rec := httptest.NewRecorder()
+	s.handleBulkOrders(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/bulk", strings.NewReader(body)))
+
+	var resp bulkResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	fields := resp.Results[0].Fields
+	if resp.Failed != 1 || len(fields) != 1 || fields[0].Field != "delivery.phone" || fields[0].Rule != service.RuleFormat {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+// TestBulkOrdersPerOrderResults проверяет, что несохранённый заказ не мешает остальным, а повтор не считается ошибкой.
+func TestBulkOrdersPerOrderResults(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	validOrder := func(uid string) string {
+		return `{"order_uid":"` + uid + `","delivery":{"name":"Test","phone":"+9720000000","address":"Street 1"},"items":[{"chrt_id":1}]}`
+	}
+	svc := &fakeOrderService{reject: "b", saved: []string{"c"}}
+	s := &Server{cache: cache.NewOrderCache(), orders: svc, logger: zap.NewNop()}
+	body := "[" + validOrder("a") + "," + validOrder("b") + "," + validOrder("c") + "]"
+	rec := httptest.NewRecorder()
+	s.handleBulkOrders(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/bulk", strings.NewReader(body)))
+
+	var resp bulkResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	want := []string{bulkStatusSaved, bulkStatusFailed, bulkStatusDuplicate}
+	for i, status := range want {
+		if resp.Results[i].Status != status {
+			t.Errorf("order %d: expected %s, got %+v", i, status, resp.Results[i])
+		}
+	}
+	if resp.Succeeded != 2 || resp.Failed != 1 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+// TestBulkOrdersChunkFailure проверяет, что ошибка сохранения отмечает заказы порции как неудачные.
+func TestBulkOrdersChunkFailure(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	svc := &fakeOrderService{reject: "a"}
+	s := &Server{cache: cache.NewOrderCache(), orders: svc, logger: zap.NewNop()}
+	body := `[{"order_uid":"a","delivery":{"name":"Test","phone":"+9720000000","address":"Street 1"},"items":[{"chrt_id":1}]}]`
+	rec := httptest.NewRecorder()
+	s.handleBulkOrders(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/bulk", strings.NewReader(body)))
+
+	var resp bulkResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if resp.Failed != 1 || resp.Results[0].Error == "" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
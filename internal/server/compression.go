@@ -0,0 +1,229 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"l0_wb/internal/metrics"
+)
+
+// gzipWriterPool переиспользует gzip.Writer между запросами.
+var gzipWriterPool sync.Pool
+
+// acquireGzipWriter возвращает gzip.Writer из пула, настроенный на запись в w.
+func acquireGzipWriter(w *countingWriter, level int) *gzip.Writer {
+	if gz, ok := gzipWriterPool.Get().(*gzip.Writer); ok {
+		gz.Reset(w)
+		return gz
+	}
+	gz, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		gz = gzip.NewWriter(w)
+	}
+	return gz
+}
+
+// countingWriter считает байты, записанные в исходный http.ResponseWriter.
+type countingWriter struct {
+	w http.ResponseWriter
+	n int
+}
+
+// Write записывает данные и увеличивает счётчик.
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += n
+	return n, err
+}
+
+// gzipResponseWriter сжимает ответ, если он достаточно велик.
+//
+//	Первые minSize байт буферизуются: если обработчик завершился раньше,
+//	ответ отправляется без сжатия, поскольку для маленьких ответов gzip
+//	только добавляет накладные расходы.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize int
+	level   int
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	raw         int
+	out         *countingWriter
+	gz          *gzip.Writer
+}
+
+// WriteHeader откладывает отправку заголовков до решения о сжатии.
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+	g.status = status
+	// Ответы без тела, уже закодированные ответы и потоки SSE не сжимаем.
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified ||
+		g.Header().Get("Content-Encoding") != "" || g.Header().Get("Content-Type") == "text/event-stream" {
+		g.decide(false)
+	}
+}
+
+// Write буферизует начало ответа и затем пишет данные в gzip-поток или напрямую.
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !g.wroteHeader {
+		g.WriteHeader(http.StatusOK)
+	}
+	g.raw += len(b)
+	if !g.decided {
+		g.buf = append(g.buf, b...)
+		if len(g.buf) < g.minSize {
+			return len(b), nil
+		}
+		if err := g.decide(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if g.gz != nil {
+		return g.gz.Write(b)
+	}
+	return g.out.Write(b)
+}
+
+// decide фиксирует решение о сжатии, отправляет заголовки и накопленный буфер.
+func (g *gzipResponseWriter) decide(compress bool) error {
+	if g.decided {
+		return nil
+	}
+	g.decided = true
+	if !g.wroteHeader {
+		g.wroteHeader = true
+		g.status = http.StatusOK
+	}
+
+	if compress {
+		h := g.Header()
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		g.gz = acquireGzipWriter(g.out, g.level)
+	}
+	g.ResponseWriter.WriteHeader(g.status)
+
+	if len(g.buf) == 0 {
+		return nil
+	}
+	buf := g.buf
+	g.buf = nil
+	var err error
+	if g.gz != nil {
+		_, err = g.gz.Write(buf)
+	} else {
+		_, err = g.out.Write(buf)
+	}
+	return err
+}
+
+// Flush отправляет накопленные данные клиенту.
+//
+//	Сброс до накопления minSize байт означает потоковый ответ, поэтому
+//	такой ответ сжимается сразу.
+func (g *gzipResponseWriter) Flush() {
+	if !g.decided {
+		_ = g.decide(true)
+	}
+	if g.gz != nil {
+		_ = g.gz.Flush()
+	}
+	_ = http.NewResponseController(g.ResponseWriter).Flush()
+}
+
+// Hijack передаёт соединение обработчику (например, для WebSocket) без сжатия.
+func (g *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if g.decided {
+		return nil, nil, errors.New("response already started")
+	}
+	g.decided = true
+	return http.NewResponseController(g.ResponseWriter).Hijack()
+}
+
+// Unwrap возвращает исходный http.ResponseWriter для http.ResponseController.
+func (g *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// finish завершает ответ: отправляет остаток буфера и закрывает gzip-поток.
+func (g *gzipResponseWriter) finish() {
+	if !g.decided {
+		if !g.wroteHeader {
+			// Обработчик ничего не записал: net/http сам отправит 200 без тела.
+			return
+		}
+		_ = g.decide(false)
+	}
+	if g.gz == nil {
+		return
+	}
+	_ = g.gz.Close()
+	gzipWriterPool.Put(g.gz)
+	g.gz = nil
+	metrics.RecordCompression("gzip", g.raw, g.out.n)
+}
+
+// acceptsGzip сообщает, принимает ли клиент ответы в gzip.
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		// "gzip;q=0" означает явный отказ клиента от gzip.
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			weight, err := strconv.ParseFloat(q, 64)
+			return err == nil && weight > 0
+		}
+		return true
+	}
+	return false
+}
+
+// compressionMiddleware сжимает ответы в gzip в соответствии с Accept-Encoding.
+//
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	Возвращает:
+//	- http.Handler: обработчик со сжатием ответов.
+func (s *Server) compressionMiddleware(next http.Handler) http.Handler {
+	if !s.compression.enabled {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{
+			ResponseWriter: w,
+			minSize:        s.compression.minSize,
+			level:          s.compression.level,
+			out:            &countingWriter{w: w},
+		}
+		defer gw.finish()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// compressionSettings содержит параметры сжатия ответов.
+type compressionSettings struct {
+	enabled bool
+	minSize int
+	level   int
+}
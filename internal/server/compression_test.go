@@ -0,0 +1,66 @@
+package server
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCompressionMiddleware проверяет сжатие больших ответов и пропуск маленьких.
+func TestCompressionMiddleware(t *testing.T) {
+	s := &Server{compression: compressionSettings{enabled: true, minSize: 1024, level: gzip.DefaultCompression}}
+	large := strings.Repeat(`{"order_uid":"b563feb7b2b84b6test"}`, 100)
+
+	handler := s.compressionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Has("small") {
+			_, _ = io.WriteString(w, "{}")
+			return
+		}
+		_, _ = io.WriteString(w, large)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	if rec.Body.Len() >= len(large) {
+		t.Errorf("expected compressed body smaller than %d bytes, got %d", len(large), rec.Body.Len())
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("failed to create gzip reader: %v", err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("failed to decompress body: %v", err)
+	}
+	if string(body) != large {
+		t.Errorf("decompressed body does not match original")
+	}
+
+	// Маленький ответ отправляется без сжатия.
+	req = httptest.NewRequest(http.MethodGet, "/api/orders?small", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != "{}" {
+		t.Errorf("expected uncompressed small body, got encoding %q body %q", rec.Header().Get("Content-Encoding"), rec.Body.String())
+	}
+
+	// Клиент, отказавшийся от gzip, получает исходный ответ.
+	req = httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != large {
+		t.Errorf("expected uncompressed body for gzip;q=0")
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"l0_wb/internal/events"
+	"l0_wb/internal/model"
+)
+
+// orderValidators возвращает ETag и время изменения заказа для условных запросов.
+//
+//	ETag строится по контрольной сумме содержимого заказа, поэтому меняется
+//	при любом обновлении заказа. Время изменения — дата создания заказа.
+//	Параметры:
+//	- order: заказ.
+//	Возвращает:
+//	- string: сильный ETag в кавычках.
+//	- time.Time: значение для Last-Modified.
+//	- error: ошибку вычисления контрольной суммы.
+func orderValidators(order *model.Order) (string, time.Time, error) {
+	sum, err := events.Checksum(order)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return `"` + sum + `"`, order.DateCreated.UTC().Truncate(time.Second), nil
+}
+
+// setCacheHeaders выставляет заголовки кэширования ответа.
+//
+//	Клиент может хранить ответ, но обязан перепроверять его условным запросом.
+func setCacheHeaders(h http.Header, etag string, modified time.Time) {
+	h.Set("ETag", etag)
+	if !modified.IsZero() {
+		h.Set("Last-Modified", modified.Format(http.TimeFormat))
+	}
+	h.Set("Cache-Control", "private, no-cache")
+}
+
+// notModified проверяет условия If-None-Match и If-Modified-Since.
+//
+//	Если задан If-None-Match, If-Modified-Since игнорируется (RFC 9110, 13.2.2).
+//	Параметры:
+//	- r: HTTP-запрос.
+//	- etag: текущий ETag ресурса.
+//	- modified: время изменения ресурса.
+//	Возвращает:
+//	- bool: true, если клиент может использовать свою копию (ответ 304).
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || modified.IsZero() {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	return !modified.After(t)
+}
+
+// etagMatches выполняет слабое сравнение ETag со списком из If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
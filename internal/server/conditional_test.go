@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/model"
+	"l0_wb/internal/util"
+)
+
+// TestConditionalGetOrder проверяет ответ 304 на запрос с актуальным ETag или датой.
+func TestConditionalGetOrder(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	orderCache := cache.NewOrderCache()
+	orderCache.Set(&model.Order{OrderUID: "b563feb7b2b84b6test", DateCreated: time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)})
+	s := &Server{cache: orderCache, logger: zap.NewNop()}
+	mux := http.NewServeMux()
+	s.registerRoutes(mux)
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/b563feb7b2b84b6test", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Header().Get("Last-Modified") == "" {
+		t.Fatalf("expected 200 with validators, got %d %v", first.Code, first.Header())
+	}
+
+	tests := []struct {
+		header string
+		value  string
+		status int
+	}{
+		{"If-None-Match", etag, http.StatusNotModified},
+		{"If-None-Match", `W/` + etag, http.StatusNotModified},
+		{"If-None-Match", `"stale"`, http.StatusOK},
+		{"If-Modified-Since", "Fri, 26 Nov 2021 06:22:19 GMT", http.StatusNotModified},
+		{"If-Modified-Since", "Thu, 25 Nov 2021 06:22:19 GMT", http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := get(tt.header, tt.value)
+		if rec.Code != tt.status {
+			t.Errorf("%s: %s: expected %d, got %d", tt.header, tt.value, tt.status, rec.Code)
+		}
+		if tt.status == http.StatusNotModified && rec.Body.Len() != 0 {
+			t.Errorf("%s: 304 response must not have a body", tt.header)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/dto"
+	"l0_wb/internal/model"
+)
+
+// handleGetCustomerOrders возвращает историю заказов покупателя: GET /api/v1/customers/{id}/orders.
+//
+//	Заказы читаются из БД от новых к старым постранично: limit задаёт размер
+//	страницы (по умолчанию 100), cursor — позицию из next_cursor предыдущего
+//	ответа. Параметр fields ограничивает каждый заказ выбранными полями.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleGetCustomerOrders(w http.ResponseWriter, r *http.Request) {
+	customerID := r.PathValue("id")
+	if !s.requireOrderStorage(w, r) {
+		return
+	}
+
+	q := r.URL.Query()
+	fields, err := parseFields(q.Get("fields"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	req, err := parsePageRequest(q, s.cursors)
+	if err != nil {
+		s.log(r).Warn("Invalid pagination parameters", zap.Error(err))
+		s.writeError(w, r, err)
+		return
+	}
+
+	orders, next, err := s.orders.GetOrdersByCustomerID(r.Context(), customerID, req.after, req.limit)
+	if err != nil {
+		s.log(r).Error("Failed to fetch customer orders", zap.String("customer_id", customerID), zap.Error(err))
+		s.writeError(w, r, err)
+		return
+	}
+	if orders == nil {
+		orders = []*model.Order{}
+	}
+
+	resp := orderPage{Orders: dto.NewOrders(orders)}
+	if next != nil {
+		resp.NextCursor = s.cursors.Encode(*next)
+	}
+	if fields != nil {
+		if resp.Orders, err = selectOrdersFields(orders, fields); err != nil {
+			s.log(r).Error("Failed to select order fields", zap.Error(err))
+			s.writeError(w, r, newAPIError(http.StatusInternalServerError, codeEncodeResponse, "failed to encode response"))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.log(r).Error("Failed to encode customer orders", zap.Error(err))
+	}
+}
+
+// customerErasure — ответ на удаление персональных данных покупателя.
+type customerErasure struct {
+	CustomerID string   `json:"customer_id"`
+	Orders     []string `json:"orders"` // order_uid заказов, из которых удалены данные
+}
+
+// handleEraseCustomerData удаляет персональные данные покупателя: DELETE /api/v1/customers/{id}/data.
+//
+//	Имя, телефон, индекс, адрес и email получателя удаляются из всех заказов
+//	покупателя и из их журнала изменений; сами заказы остаются. Заказы
+//	обновляются в кэше. Покупатель без заказов — 404.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleEraseCustomerData(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOrderStorage(w, r) {
+		return
+	}
+	customerID := r.PathValue("id")
+
+	orders, err := s.orders.EraseCustomerData(r.Context(), customerID)
+	if err != nil {
+		s.log(r).Warn("Failed to erase customer data", zap.String("customer_id", customerID), zap.Error(err))
+		s.writeError(w, r, err)
+		return
+	}
+
+	resp := customerErasure{CustomerID: customerID, Orders: make([]string, 0, len(orders))}
+	for _, order := range orders {
+		if order.DeletedAt == nil {
+			s.cache.Set(order)
+		}
+		resp.Orders = append(resp.Orders, order.OrderUID)
+	}
+	s.writeJSON(w, r, resp)
+}
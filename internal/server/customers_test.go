@@ -0,0 +1,101 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/model"
+	"l0_wb/internal/pagination"
+	"l0_wb/internal/util"
+)
+
+// TestGetCustomerOrders проверяет постраничную выдачу истории заказов покупателя.
+func TestGetCustomerOrders(t *testing.T) {
+	svc := &fakeOrderService{customerOrders: []*model.Order{
+		{OrderUID: "c", CustomerID: "alice"},
+		{OrderUID: "b", CustomerID: "bob"},
+		{OrderUID: "a", CustomerID: "alice"},
+	}}
+	s := &Server{orders: svc, cursors: pagination.NewSigner([]byte("secret"), time.Hour), logger: zap.NewNop()}
+
+	get := func(customerID, query string) (int, orderPage, []map[string]any) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/customers/"+customerID+"/orders?"+query, nil)
+		req.SetPathValue("id", customerID)
+		rec := httptest.NewRecorder()
+		s.handleGetCustomerOrders(rec, req)
+
+		var page orderPage
+		var orders []map[string]any
+		page.Orders = &orders
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+		}
+		return rec.Code, page, orders
+	}
+
+	code, page, orders := get("alice", "limit=1")
+	if code != http.StatusOK || len(orders) != 1 || orders[0]["order_uid"] != "c" || page.NextCursor == "" {
+		t.Fatalf("unexpected first page: %d %+v %v", code, page, orders)
+	}
+	code, page, orders = get("alice", "limit=1&cursor="+page.NextCursor)
+	if code != http.StatusOK || len(orders) != 1 || orders[0]["order_uid"] != "a" {
+		t.Fatalf("unexpected second page: %d %+v %v", code, page, orders)
+	}
+
+	if code, _, orders = get("carol", ""); code != http.StatusOK || orders == nil || len(orders) != 0 {
+		t.Errorf("expected empty history, got %d %v", code, orders)
+	}
+	if code, _, _ = get("alice", "limit=0"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid limit, got %d", code)
+	}
+}
+
+// TestEraseCustomerData проверяет ответ на удаление персональных данных и обновление кэша.
+func TestEraseCustomerData(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	deleted := time.Now()
+	svc := &fakeOrderService{customerOrders: []*model.Order{
+		{OrderUID: "c", CustomerID: "alice", Delivery: model.Delivery{Name: "Alice", Phone: "+79990000000", City: "Moscow"}},
+		{OrderUID: "b", CustomerID: "bob", Delivery: model.Delivery{Name: "Bob"}},
+		{OrderUID: "a", CustomerID: "alice", Delivery: model.Delivery{Name: "Alice"}, DeletedAt: &deleted},
+	}}
+	s := &Server{cache: cache.NewOrderCache(), orders: svc, logger: zap.NewNop()}
+
+	erase := func(customerID string) (int, customerErasure) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/customers/"+customerID+"/data", nil)
+		req.SetPathValue("id", customerID)
+		rec := httptest.NewRecorder()
+		s.handleEraseCustomerData(rec, req)
+
+		var resp customerErasure
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+		}
+		return rec.Code, resp
+	}
+
+	code, resp := erase("alice")
+	if code != http.StatusOK || resp.CustomerID != "alice" || len(resp.Orders) != 2 || resp.Orders[0] != "c" || resp.Orders[1] != "a" {
+		t.Fatalf("unexpected erasure response %d %+v", code, resp)
+	}
+	if o := s.cache.Get("c"); o == nil || o.Delivery.Name != "" || o.Delivery.City != "Moscow" {
+		t.Errorf("cache not updated: %+v", o)
+	}
+	if s.cache.Contains("a") {
+		t.Error("deleted order must not be added to cache")
+	}
+	if code, _ := erase("nobody"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for customer without orders, got %d", code)
+	}
+}
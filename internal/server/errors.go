@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"l0_wb/internal/breaker"
+	"l0_wb/internal/i18n"
+	"l0_wb/internal/model"
+	"l0_wb/internal/pagination"
+	"l0_wb/internal/service"
+)
+
+// Коды ошибок API, на которые могут опираться клиенты.
+const (
+	codeBadRequest       = "bad_request"
+	codeInvalidCursor    = "invalid_cursor"
+	codeExpiredCursor    = "cursor_expired"
+	codeUnauthorized     = "unauthorized"
+	codeForbidden        = "forbidden"
+	codeRequestTimeout   = "request_timeout"
+	codePayloadTooLarge  = "payload_too_large"
+	codeNotFound         = "not_found"
+	codeMethodNotAllowed = "method_not_allowed"
+	codeInvalidStatus    = "invalid_status_transition"
+	codeValidation       = "validation_failed"
+	codeRateLimited      = "rate_limited"
+	codeOverloaded       = "overloaded"
+	codeTimeout          = "timeout"
+	codeUnavailable      = "unavailable"
+	codeInternal         = "internal_error"
+	codeKafkaPublish     = "kafka_publish_failed"
+	codeEncodeResponse   = "encode_failed"
+)
+
+// apiError — единый формат ошибки API.
+//
+//	Status задаёт HTTP-статус ответа и в тело не попадает; RequestID
+//	заполняется при записи ответа из контекста запроса. Message содержит
+//	сообщение на английском и переводится при записи ответа на язык клиента.
+type apiError struct {
+	Status    int    `json:"-"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+
+	format string // Сообщение в формате fmt.Sprintf, ключ перевода (см. i18n.Sprintf)
+	args   []any  // Параметры сообщения
+}
+
+// Error реализует интерфейс error.
+func (e *apiError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// newAPIError создаёт ошибку API.
+//
+//	Параметры:
+//	- status: HTTP-статус ответа.
+//	- code: машиночитаемый код ошибки.
+//	- message: описание ошибки для человека.
+//	Возвращает:
+//	- *apiError: ошибка API.
+func newAPIError(status int, code, message string) *apiError {
+	return newAPIErrorf(status, code, message)
+}
+
+// newAPIErrorf создаёт ошибку API с сообщением, содержащим параметры.
+//
+//	Параметры:
+//	- status: HTTP-статус ответа.
+//	- code: машиночитаемый код ошибки.
+//	- format: сообщение на английском в формате fmt.Sprintf.
+//	- args: параметры сообщения.
+//	Возвращает:
+//	- *apiError: ошибка API.
+func newAPIErrorf(status int, code, format string, args ...any) *apiError {
+	return &apiError{
+		Status:  status,
+		Code:    code,
+		Message: i18n.Sprintf(i18n.Default, format, args...),
+		format:  format,
+		args:    args,
+	}
+}
+
+// withDetails возвращает копию ошибки с дополнительными сведениями.
+func (e *apiError) withDetails(details any) *apiError {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// mapError сопоставляет ошибку с HTTP-статусом и кодом ошибки API.
+//
+//	Ошибки, не известные слою HTTP, превращаются в internal_error без
+//	подробностей, чтобы не раскрывать клиенту внутреннее устройство сервиса.
+//
+//	Параметры:
+//	- err: ошибка обработки запроса.
+//	Возвращает:
+//	- *apiError: ошибка API для ответа клиенту.
+func mapError(err error) *apiError {
+	var apiErr *apiError
+	var transitionErr *service.TransitionError
+	var validationErr *service.ValidationError
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr
+	case errors.As(err, &validationErr):
+		return newAPIError(http.StatusBadRequest, codeValidation, validationErr.Error()).
+			withDetails(map[string]any{"fields": validationErr.Fields})
+	case errors.Is(err, service.ErrUnknownStatus):
+		return newAPIError(http.StatusBadRequest, codeBadRequest, err.Error())
+	case errors.As(err, &transitionErr):
+		allowed := transitionErr.From.Next()
+		if allowed == nil {
+			allowed = []model.OrderStatus{}
+		}
+		return newAPIErrorf(http.StatusConflict, codeInvalidStatus, "order status cannot change from %s to %s",
+			transitionErr.From, transitionErr.To).withDetails(map[string]any{
+			"from":    transitionErr.From,
+			"to":      transitionErr.To,
+			"allowed": allowed,
+		})
+	case errors.Is(err, pagination.ErrExpiredCursor):
+		return newAPIError(http.StatusBadRequest, codeExpiredCursor, "cursor has expired, restart pagination")
+	case errors.Is(err, pagination.ErrInvalidCursor), errors.Is(err, pagination.ErrUnsupportedVersion):
+		return newAPIError(http.StatusBadRequest, codeInvalidCursor, err.Error())
+	case errors.As(err, new(*http.MaxBytesError)):
+		return newAPIError(http.StatusRequestEntityTooLarge, codePayloadTooLarge, "request body is too large")
+	case errors.Is(err, pgx.ErrNoRows):
+		return newAPIError(http.StatusNotFound, codeNotFound, "resource not found")
+	case errors.Is(err, breaker.ErrOpen):
+		// Автомат защиты БД разомкнут: доступны только ответы из кэша
+		return newAPIError(http.StatusServiceUnavailable, codeUnavailable, "database is temporarily unavailable")
+	case errors.Is(err, context.DeadlineExceeded):
+		return newAPIError(http.StatusGatewayTimeout, codeTimeout, "request timed out")
+	default:
+		return newAPIError(http.StatusInternalServerError, codeInternal, "internal server error")
+	}
+}
+
+// writeError записывает ошибку в формате JSON.
+//
+//	Сообщение ошибки переводится на язык из заголовка Accept-Language, а
+//	нарушения валидации заказа — на язык локали заказа (см. messageLang).
+//
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+//	- err: ошибка; *apiError записывается как есть, остальные сопоставляются через mapError.
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	lang := requestLang(r)
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		lang = messageLang(r, validationErr.Locale)
+		err = validationErr.Localize(lang)
+	}
+	apiErr := *mapError(err)
+	if apiErr.format != "" {
+		apiErr.Message = i18n.Sprintf(lang, apiErr.format, apiErr.args...)
+	}
+	apiErr.RequestID = requestIDFromContext(r.Context())
+
+	h := w.Header()
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Content-Language", lang)
+	h.Add("Vary", "Accept-Language")
+	w.WriteHeader(apiErr.Status)
+	if encErr := json.NewEncoder(w).Encode(apiErr); encErr != nil {
+		s.log(r).Error("Failed to encode error response", zap.Error(encErr))
+	}
+}
+
+// requestLang возвращает язык сообщений об ошибках по заголовку Accept-Language.
+//
+//	Параметры:
+//	- r: HTTP-запрос.
+//	Возвращает:
+//	- string: поддерживаемый язык или i18n.Default.
+func requestLang(r *http.Request) string {
+	if lang, ok := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language")); ok {
+		return lang
+	}
+	return i18n.Default
+}
+
+// messageLang возвращает язык сообщений об ошибках в заказе: язык его локали,
+// если он поддерживается, иначе язык запроса (см. requestLang).
+//
+//	Параметры:
+//	- r: HTTP-запрос.
+//	- locale: локаль заказа.
+//	Возвращает:
+//	- string: поддерживаемый язык.
+func messageLang(r *http.Request, locale string) string {
+	if lang, ok := i18n.Parse(locale); ok {
+		return lang
+	}
+	return requestLang(r)
+}
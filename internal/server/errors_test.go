@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/breaker"
+	"l0_wb/internal/model"
+	"l0_wb/internal/pagination"
+	"l0_wb/internal/service"
+)
+
+// TestWriteError проверяет формат ответа с ошибкой и сопоставление ошибок со статусами.
+func TestWriteError(t *testing.T) {
+	s := &Server{logger: zap.NewNop()}
+
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{newAPIError(http.StatusNotFound, codeNotFound, "order not found"), http.StatusNotFound, codeNotFound},
+		{fmt.Errorf("decode: %w", pagination.ErrExpiredCursor), http.StatusBadRequest, codeExpiredCursor},
+		{pagination.ErrInvalidCursor, http.StatusBadRequest, codeInvalidCursor},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout, codeTimeout},
+		{&service.ValidationError{Fields: []service.FieldError{{Field: "order_uid", Rule: service.RuleRequired, Message: "is required"}}}, http.StatusBadRequest, codeValidation},
+		{fmt.Errorf("get order: %w", breaker.ErrOpen), http.StatusServiceUnavailable, codeUnavailable},
+		{errors.New("pq: connection reset"), http.StatusInternalServerError, codeInternal},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+		req = req.WithContext(context.WithValue(req.Context(), requestIDKey{}, "req-1"))
+		rec := httptest.NewRecorder()
+		s.writeError(rec, req, tt.err)
+
+		if rec.Code != tt.status {
+			t.Errorf("%v: expected status %d, got %d", tt.err, tt.status, rec.Code)
+		}
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%v: response is not valid JSON: %v", tt.err, err)
+		}
+		if body["code"] != tt.code || body["request_id"] != "req-1" || body["message"] == "" {
+			t.Errorf("%v: unexpected body %v", tt.err, body)
+		}
+	}
+}
+
+// TestWriteErrorLocalized проверяет перевод сообщений об ошибках по Accept-Language и локали заказа.
+func TestWriteErrorLocalized(t *testing.T) {
+	s := &Server{logger: zap.NewNop()}
+
+	write := func(err error, acceptLanguage string) (*httptest.ResponseRecorder, map[string]any) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/bulk", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		rec := httptest.NewRecorder()
+		s.writeError(rec, req, err)
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("response is not valid JSON: %v", err)
+		}
+		return rec, body
+	}
+
+	rec, body := write(newAPIErrorf(http.StatusRequestEntityTooLarge, codePayloadTooLarge, "request body exceeds %d bytes", 1024), "ru-RU,en;q=0.8")
+	if body["message"] != "тело запроса превышает 1024 байт" || rec.Header().Get("Content-Language") != "ru" {
+		t.Errorf("expected russian message, got %v (%s)", body["message"], rec.Header().Get("Content-Language"))
+	}
+	if _, body := write(errors.New("boom"), "de"); body["message"] != "internal server error" {
+		t.Errorf("unsupported language must fall back to english, got %v", body["message"])
+	}
+
+	// Нарушения валидации показываются на языке локали заказа
+	order := &model.Order{
+		OrderUID: "b563feb7b2b84b6test",
+		Locale:   "ru",
+		Delivery: model.Delivery{Name: "Test Testov", Phone: "+9720000000"},
+	}
+	rec, body = write(service.ValidateOrder(order), "en")
+	details, _ := body["details"].(map[string]any)
+	fields, _ := details["fields"].([]any)
+	if len(fields) != 1 || rec.Header().Get("Content-Language") != "ru" {
+		t.Fatalf("unexpected validation response %v", body)
+	}
+	if msg := fields[0].(map[string]any)["message"]; msg != "в заказе нет товаров" {
+		t.Errorf("expected russian field message, got %v", msg)
+	}
+	if body["message"] != "некорректный заказ: items: в заказе нет товаров" {
+		t.Errorf("unexpected message %v", body["message"])
+	}
+}
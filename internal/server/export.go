@@ -0,0 +1,145 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/export"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+)
+
+// orderFilter содержит условия отбора заказов для выгрузки.
+type orderFilter struct {
+	from            time.Time
+	to              time.Time
+	customerID      string
+	deliveryService string
+}
+
+// parseOrderFilter разбирает параметры отбора заказов.
+//
+//	from и to задают полуинтервал [from, to) по date_created в формате
+//	RFC 3339 или YYYY-MM-DD; customer_id и delivery_service сравниваются точно.
+//	Параметры:
+//	- q: параметры запроса.
+//	Возвращает:
+//	- orderFilter: условия отбора.
+//	- error: *apiError для некорректной даты.
+func parseOrderFilter(q url.Values) (orderFilter, error) {
+	f := orderFilter{customerID: q.Get("customer_id"), deliveryService: q.Get("delivery_service")}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &f.from}, {"to", &f.to}} {
+		raw := q.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		t, err := parseFilterTime(raw)
+		if err != nil {
+			return f, newAPIErrorf(http.StatusBadRequest, codeBadRequest, "%s must be an RFC 3339 timestamp or YYYY-MM-DD date", p.name).
+				withDetails(map[string]string{"parameter": p.name})
+		}
+		*p.dst = t
+	}
+	return f, nil
+}
+
+// parseFilterTime разбирает время в формате RFC 3339 или дату YYYY-MM-DD (UTC).
+func parseFilterTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, raw)
+}
+
+// match сообщает, удовлетворяет ли заказ условиям отбора.
+func (f orderFilter) match(o *model.Order) bool {
+	switch {
+	case !f.from.IsZero() && o.DateCreated.Before(f.from):
+		return false
+	case !f.to.IsZero() && !o.DateCreated.Before(f.to):
+		return false
+	case f.customerID != "" && o.CustomerID != f.customerID:
+		return false
+	case f.deliveryService != "" && o.DeliveryService != f.deliveryService:
+		return false
+	}
+	return true
+}
+
+// repository возвращает условия отбора в виде фильтра репозитория для чтения из БД.
+func (f orderFilter) repository() repository.OrderFilter {
+	return repository.OrderFilter{From: f.from, To: f.to, CustomerID: f.customerID, DeliveryService: f.deliveryService}
+}
+
+// apply возвращает заказы, удовлетворяющие условиям, от новых к старым.
+func (f orderFilter) apply(orders []*model.Order) []*model.Order {
+	matched := make([]*model.Order, 0, len(orders))
+	for _, o := range orders {
+		if f.match(o) {
+			matched = append(matched, o)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return orderAfter(matched[i].DateCreated.UnixNano(), matched[i].OrderUID, matched[j].DateCreated.UnixNano(), matched[j].OrderUID)
+	})
+	return matched
+}
+
+// handleExportOrders выгружает заказы файлом: GET /api/v1/orders/export?format=csv|xlsx.
+//
+//	Заказы отбираются по параметрам from, to, customer_id и delivery_service
+//	из кэша или, если он содержит не все заказы БД, из БД;
+//	каждый товар заказа выгружается отдельной строкой. Файл пишется в ответ
+//	потоково, поэтому ошибку записи после начала ответа можно только залогировать.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleExportOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	format := export.Format(q.Get("format"))
+	if format == "" {
+		format = export.FormatCSV
+	}
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		s.writeError(w, r, newAPIError(http.StatusBadRequest, codeBadRequest, "format must be csv or xlsx").
+			withDetails(map[string]string{"parameter": "format"}))
+		return
+	}
+	filter, err := parseOrderFilter(q)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	var orders []*model.Order
+	if s.partialCache() {
+		if orders, err = s.listAllOrders(r.Context(), filter.repository()); err != nil {
+			s.log(r).Error("Failed to list orders for export", zap.Error(err))
+			s.writeError(w, r, err)
+			return
+		}
+	} else {
+		orders = filter.apply(s.cache.GetAll())
+	}
+	s.log(r).Info("Exporting orders", zap.String("format", string(format)), zap.Int("count", len(orders)))
+
+	h := w.Header()
+	h.Set("Content-Type", format.ContentType())
+	h.Set("Content-Disposition", `attachment; filename="orders.`+string(format)+`"`)
+	ew, err := export.NewWriter(format, w)
+	if err == nil {
+		err = export.WriteOrders(ew, orders)
+	}
+	if err == nil {
+		err = ew.Close()
+	}
+	if err != nil {
+		s.log(r).Error("Failed to export orders", zap.String("format", string(format)), zap.Error(err))
+	}
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/model"
+	"l0_wb/internal/util"
+)
+
+// TestExportOrdersPartialCache проверяет, что при ограниченном кэше выгружаются и вытесненные из него заказы.
+func TestExportOrdersPartialCache(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	orders := []*model.Order{
+		{OrderUID: "order-c", CustomerID: "alice", DateCreated: base.Add(2 * time.Hour), Items: []model.Item{{ChrtID: 3}}},
+		{OrderUID: "order-b", CustomerID: "bob", DateCreated: base.Add(time.Hour), Items: []model.Item{{ChrtID: 2}}},
+		{OrderUID: "order-a", CustomerID: "alice", DateCreated: base, Items: []model.Item{{ChrtID: 1}}},
+	}
+	orderCache := cache.NewOrderCache(cache.WithMaxEntries(1))
+	for _, o := range orders {
+		orderCache.Set(o)
+	}
+	s := &Server{cache: orderCache, orders: &fakeOrderService{customerOrders: orders}, logger: zap.NewNop()}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"format=csv", []string{"order-c", "order-b", "order-a"}},
+		{"format=csv&customer_id=alice", []string{"order-c", "order-a"}},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.handleExportOrders(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/export?"+tt.query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", tt.query, rec.Code)
+		}
+		records, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+		if err != nil {
+			t.Fatalf("%s: invalid CSV: %v", tt.query, err)
+		}
+		if len(records) != len(tt.want)+1 {
+			t.Fatalf("%s: expected header and %d rows, got %d records", tt.query, len(tt.want), len(records))
+		}
+		for i, uid := range tt.want {
+			if records[i+1][0] != uid {
+				t.Errorf("%s: row %d: expected %s, got %s", tt.query, i+1, uid, records[i+1][0])
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"l0_wb/internal/dto"
+	"l0_wb/internal/model"
+)
+
+// fieldSelection — дерево полей, выбранных параметром fields.
+//
+//	Ключ — имя поля JSON; nil-поддерево означает поле целиком. Для массивов
+//	(items) выбор применяется к каждому элементу.
+type fieldSelection map[string]fieldSelection
+
+// parseFields разбирает параметр fields вида "order_uid,payment.amount,items.name".
+//
+//	Параметры:
+//	- raw: значение параметра.
+//	Возвращает:
+//	- fieldSelection: выбранные поля или nil, если параметр пуст (нужен заказ целиком).
+//	- error: *apiError, если указано поле, которого нет в заказе.
+func parseFields(raw string) (fieldSelection, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	sel := fieldSelection{}
+	for _, path := range strings.Split(raw, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if !validFieldPath(reflect.TypeOf(dto.Order{}), strings.Split(path, ".")) {
+			return nil, newAPIErrorf(http.StatusBadRequest, codeBadRequest, "unknown field: %s", path).
+				withDetails(map[string]string{"parameter": "fields", "field": path})
+		}
+		sel.add(strings.Split(path, "."))
+	}
+	return sel, nil
+}
+
+// add добавляет путь к дереву выбора.
+func (sel fieldSelection) add(path []string) {
+	name := path[0]
+	sub, seen := sel[name]
+	if len(path) == 1 {
+		// Поле выбрано целиком: вложенный выбор больше не нужен
+		sel[name] = nil
+		return
+	}
+	if seen && sub == nil {
+		return
+	}
+	if sub == nil {
+		sub = fieldSelection{}
+		sel[name] = sub
+	}
+	sub.add(path[1:])
+}
+
+// validFieldPath проверяет, что путь соответствует полям JSON типа t.
+func validFieldPath(t reflect.Type, path []string) bool {
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if len(path) == 0 {
+		return true
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == path[0] {
+			return validFieldPath(f.Type, path[1:])
+		}
+	}
+	return false
+}
+
+// selectOrderFields возвращает только выбранные поля заказа.
+//
+//	Параметры:
+//	- order: заказ.
+//	- sel: выбранные поля.
+//	Возвращает:
+//	- map[string]any: документ заказа с выбранными полями.
+//	- error: ошибку сериализации заказа.
+func selectOrderFields(order *model.Order, sel fieldSelection) (map[string]any, error) {
+	b, err := json.Marshal(dto.NewOrder(order))
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return sel.apply(doc).(map[string]any), nil
+}
+
+// selectOrdersFields применяет выбор полей к списку заказов.
+func selectOrdersFields(orders []*model.Order, sel fieldSelection) ([]map[string]any, error) {
+	result := make([]map[string]any, len(orders))
+	for i, order := range orders {
+		doc, err := selectOrderFields(order, sel)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = doc
+	}
+	return result, nil
+}
+
+// apply оставляет в значении только выбранные поля.
+func (sel fieldSelection) apply(v any) any {
+	if sel == nil {
+		return v
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(sel))
+		for name, sub := range sel {
+			if fv, ok := v[name]; ok {
+				out[name] = sub.apply(fv)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, el := range v {
+			out[i] = sel.apply(el)
+		}
+		return out
+	default:
+		return v
+	}
+}
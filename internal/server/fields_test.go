@@ -0,0 +1,63 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/model"
+	"l0_wb/internal/util"
+)
+
+// TestFieldSelection проверяет ответ с выбранными полями заказа.
+func TestFieldSelection(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	orderCache := cache.NewOrderCache()
+	orderCache.Set(&model.Order{
+		OrderUID:    "b563feb7b2b84b6test",
+		TrackNumber: "WBILMTESTTRACK",
+		Payment:     model.Payment{Amount: 1817, Currency: "USD"},
+		Items:       []model.Item{{ChrtID: 9934930, Name: "Mascaras"}, {ChrtID: 9934931, Name: "Brush"}},
+	})
+	s := &Server{cache: orderCache, logger: zap.NewNop()}
+	mux := http.NewServeMux()
+	s.registerRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/b563feb7b2b84b6test?fields=order_uid,payment.amount,items.name", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(doc) != 3 || doc["order_uid"] != "b563feb7b2b84b6test" {
+		t.Errorf("unexpected document %v", doc)
+	}
+	if payment := doc["payment"].(map[string]any); len(payment) != 1 || payment["amount"] != float64(1817) {
+		t.Errorf("unexpected payment %v", payment)
+	}
+	items := doc["items"].([]any)
+	if len(items) != 2 || len(items[0].(map[string]any)) != 1 || items[1].(map[string]any)["name"] != "Brush" {
+		t.Errorf("unexpected items %v", items)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders?fields=order_uid,payment.unknown", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown field, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders?fields=track_number", nil))
+	var list []map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list) != 1 || len(list[0]) != 1 {
+		t.Errorf("unexpected list response %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/sli"
+)
+
+// readinessCheckTimeout ограничивает время одной проверки готовности.
+const readinessCheckTimeout = 2 * time.Second
+
+// ReadinessCheck проверяет готовность компонента обслуживать трафик.
+type ReadinessCheck func(ctx context.Context) error
+
+// readinessCheck — именованная проверка готовности.
+type readinessCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+// componentStatus — состояние компонента в ответе /health/ready.
+type componentStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// readinessResponse — тело ответа /health/ready.
+type readinessResponse struct {
+	Status     string                     `json:"status"`
+	Components map[string]componentStatus `json:"components"`
+	SLI        map[string]sli.Snapshot    `json:"sli"`
+}
+
+// AddReadinessCheck регистрирует проверку готовности компонента.
+//
+//	Проверки регистрируются до запуска сервера и выполняются при каждом
+//	запросе /health/ready.
+//	Параметры:
+//	- name: имя компонента в ответе.
+//	- check: проверка; nil-ошибка означает, что компонент готов.
+func (s *Server) AddReadinessCheck(name string, check ReadinessCheck) {
+	s.readiness = append(s.readiness, readinessCheck{name: name, check: check})
+}
+
+// handleLive обрабатывает запросы к эндпоинту /health/live.
+//
+//	Отвечает 200, пока процесс способен обрабатывать HTTP-запросы; зависимости не проверяются.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleLive(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"status": "alive"}); err != nil {
+		s.log(r).Error("Failed to write liveness response", zap.Error(err))
+	}
+}
+
+// handleReady обрабатывает запросы к эндпоинту /health/ready.
+//
+//	Возвращает 503, если не пройдена хотя бы одна проверка компонентов (БД,
+//	Kafka-консьюмер, прогрев кэша) или доля ошибок операций с БД превышает порог
+//	SLI: в этом случае балансировщику следует направлять трафик на другие экземпляры.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	resp := readinessResponse{
+		Status:     "ready",
+		Components: make(map[string]componentStatus, len(s.readiness)),
+		SLI: map[string]sli.Snapshot{
+			sli.SourceHTTP: sli.Get(sli.SourceHTTP),
+			sli.SourceDB:   sli.Get(sli.SourceDB),
+		},
+	}
+	status := http.StatusOK
+
+	for _, rc := range s.readiness {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+		err := rc.check(ctx)
+		cancel()
+		if err != nil {
+			resp.Components[rc.name] = componentStatus{Status: "down", Error: err.Error()}
+			resp.Status = "not_ready"
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		resp.Components[rc.name] = componentStatus{Status: "up"}
+	}
+
+	if status == http.StatusOK && s.sliThresholds.exceeded(resp.SLI[sli.SourceDB]) {
+		resp.Status = "degraded"
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.log(r).Error("Failed to encode readiness response", zap.Error(err))
+	}
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/model"
+)
+
+// orderHistory — ответ с журналом изменений заказа.
+type orderHistory struct {
+	OrderUID string              `json:"order_uid"`
+	Changes  []model.OrderChange `json:"changes"`
+}
+
+// handleGetOrderHistory возвращает журнал изменений заказа: GET /api/v1/orders/{id}/history.
+//
+//	Журнал читается из БД в порядке записи; каждая запись содержит действие,
+//	исполнителя, время и изменённые поля. Мягко удалённые заказы тоже имеют историю.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleGetOrderHistory(w http.ResponseWriter, r *http.Request) {
+	orderID := r.PathValue("id")
+	if !s.requireOrderStorage(w, r) {
+		return
+	}
+
+	changes, err := s.orders.GetOrderHistory(r.Context(), orderID)
+	if err != nil {
+		s.log(r).Warn("Failed to fetch order history", zap.String("order_uid", orderID), zap.Error(err))
+		s.writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(orderHistory{OrderUID: orderID, Changes: changes}); err != nil {
+		s.log(r).Error("Failed to encode order history", zap.Error(err))
+	}
+}
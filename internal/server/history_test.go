@@ -0,0 +1,49 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/model"
+)
+
+// TestGetOrderHistory проверяет выдачу журнала изменений заказа.
+func TestGetOrderHistory(t *testing.T) {
+	svc := &fakeOrderService{
+		customerOrders: []*model.Order{{OrderUID: "a"}, {OrderUID: "b"}},
+		history: map[string][]model.OrderChange{"a": {
+			{ID: 1, OrderUID: "a", Action: model.ChangeInsert, Actor: "kafka:orders", Diff: map[string]any{"locale": "en"}},
+			{ID: 2, OrderUID: "a", Action: model.ChangeUpdate, Actor: "api_key:partner", Diff: map[string]any{"locale": "ru"}},
+		}},
+	}
+	s := &Server{orders: svc, logger: zap.NewNop()}
+
+	get := func(orderUID string) (int, orderHistory) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+orderUID+"/history", nil)
+		req.SetPathValue("id", orderUID)
+		rec := httptest.NewRecorder()
+		s.handleGetOrderHistory(rec, req)
+
+		var resp orderHistory
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+		}
+		return rec.Code, resp
+	}
+
+	code, resp := get("a")
+	if code != http.StatusOK || len(resp.Changes) != 2 || resp.Changes[1].Actor != "api_key:partner" || resp.Changes[1].Diff["locale"] != "ru" {
+		t.Fatalf("unexpected history: %d %+v", code, resp)
+	}
+	if code, resp = get("b"); code != http.StatusOK || resp.Changes == nil || len(resp.Changes) != 0 {
+		t.Errorf("expected empty history, got %d %+v", code, resp)
+	}
+	if code, _ = get("missing"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown order, got %d", code)
+	}
+}
@@ -2,18 +2,29 @@
 package server
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"io/fs"
+	"net"
 	"net/http"
-	"path"
-	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/gorilla/websocket"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 	"l0_wb/internal/cache"
+	"l0_wb/internal/config"
+	"l0_wb/internal/dto"
+	"l0_wb/internal/feed"
 	"l0_wb/internal/kafka"
 	"l0_wb/internal/metrics"
+	"l0_wb/internal/model"
+	"l0_wb/internal/pagination"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/service"
 	"l0_wb/internal/util"
 )
 
@@ -21,25 +32,104 @@ import (
 type Server struct {
 	httpServer *http.Server
 	cache      *cache.OrderCache
-	staticDir  string
-	logger     *zap.Logger
+	orders     service.OrderService
+	stats      *statsCache
+	static     http.Handler
+	// streamThreshold — число товаров, начиная с которого заказ отдаётся потоково (0 — отключено).
+	streamThreshold int
+	profiler        *endpointProfiler
+	auth            *apiKeyAuth
+	cors            *corsPolicy
+	compression     compressionSettings
+	cursors         *pagination.Signer
+	sliThresholds   sliThresholds
+	loadShedding    bool
+	metricsOnMain   bool
+	readiness       []readinessCheck
+	feed            *feed.Hub
+	sseHeartbeat    time.Duration
+	requestTimeout  time.Duration
+	maxBodyBytes    int64
+	bulkMaxBytes    int64
+	bulkMaxOrders   int
+	accessLog       *accessLog
+	admin           *adminAuth
+	audit           *zap.Logger
+	auditRepo       repository.AuditRepository
+	consumer        ConsumerControl
+	testOrders      bool
+	config          atomic.Pointer[config.Config] // Действующая конфигурация без секретов
+	reloader        ConfigReloader
+	shutdownTimeout time.Duration
+	upgrader        *websocket.Upgrader
+	tls             *tlsSettings
+	redirectServer  *http.Server
+	logger          *zap.Logger
 }
 
 // NewServer создаёт новый экземпляр Server.
 //
 //	Параметры:
-//	- port: порт, на котором будет работать сервер.
+//	- cfg: конфигурация приложения.
 //	- orderCache: кэш для доступа к заказам.
-//	- staticDir: директория для статических файлов (например, index.html).
+//	- orderService: сервис сохранения заказов (используется массовой загрузкой).
+//	- orderFeed: поток обработанных заказов для WebSocket- и SSE-клиентов.
+//	- static: файловая система со статическими файлами (например, index.html); nil — не раздавать.
+//	- apiKeysRepo: репозиторий API-ключей (используется при API_KEYS_FROM_DB=true).
+//	- statsRepo: репозиторий агрегирующих запросов для статистики заказов.
 //	Возвращает:
 //	- *Server: экземпляр HTTP-сервера.
-func NewServer(port string, orderCache *cache.OrderCache, staticDir string) *Server {
+func NewServer(
+	cfg *config.Config,
+	orderCache *cache.OrderCache,
+	orderService service.OrderService,
+	orderFeed *feed.Hub,
+	static fs.FS,
+	apiKeysRepo repository.APIKeysRepository,
+	statsRepo repository.StatsRepository,
+) *Server {
 	logger := util.GetLogger()
+	port := cfg.HTTPPort
 
 	s := &Server{
-		cache:     orderCache,
-		staticDir: staticDir,
-		logger:    logger,
+		cache:           orderCache,
+		orders:          orderService,
+		stats:           newStatsCache(statsRepo, cfg.StatsCacheTTL),
+		feed:            orderFeed,
+		sseHeartbeat:    cfg.SSEHeartbeatInterval,
+		requestTimeout:  cfg.RequestTimeout,
+		maxBodyBytes:    cfg.MaxRequestBodyBytes,
+		bulkMaxBytes:    cfg.BulkMaxBodyBytes,
+		bulkMaxOrders:   cfg.BulkMaxOrders,
+		shutdownTimeout: cfg.ShutdownTimeout,
+		streamThreshold: cfg.OrderStreamThreshold,
+		auth:            newAPIKeyAuth(cfg, apiKeysRepo, logger),
+		cors:            newCORSPolicy(cfg),
+		compression: compressionSettings{
+			enabled: cfg.CompressionEnabled,
+			minSize: cfg.CompressionMinSize,
+			level:   cfg.CompressionLevel,
+		},
+		cursors: newCursorSigner(cfg, logger),
+		sliThresholds: sliThresholds{
+			maxErrorRate: cfg.SLIMaxErrorRate,
+			minEvents:    uint64(cfg.SLIMinEvents),
+		},
+		loadShedding:  cfg.LoadSheddingEnabled,
+		metricsOnMain: cfg.MetricsOnMainServer,
+		accessLog:     newAccessLog(cfg, logger),
+		admin:         newAdminAuth(cfg),
+		testOrders:    cfg.AppEnv == config.AppEnvDev,
+		audit:         logger.Named("audit"),
+		logger:        logger,
+	}
+	s.config.Store(cfg.Redacted())
+	s.upgrader = s.newUpgrader()
+	if static != nil {
+		s.static = http.FileServerFS(static)
+	}
+	if cfg.ProfilingEnabled {
+		s.profiler = newEndpointProfiler(cfg.ProfilingSampleEvery)
 	}
 
 	mux := http.NewServeMux()
@@ -47,11 +137,12 @@ func NewServer(port string, orderCache *cache.OrderCache, staticDir string) *Ser
 
 	s.httpServer = &http.Server{
 		Addr:         ":" + port,
-		Handler:      mux,
+		Handler:      s.requestIDMiddleware(s.accessLogMiddleware(s.corsMiddleware(s.compressionMiddleware(s.methodNotAllowedMiddleware(mux))))),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  10 * time.Second,
 	}
+	s.configureTLS(cfg)
 
 	logger.Info("HTTP server initialized", zap.String("port", port))
 	return s
@@ -79,7 +170,7 @@ func (s *Server) metricsMiddleware(next http.HandlerFunc, endpoint string) http.
 
 		// Записываем метрики
 		duration := time.Since(startTime)
-		metrics.RecordHTTPRequest(r.Method, endpoint, rw.statusCode, duration)
+		metrics.RecordHTTPRequest(r.Context(), r.Method, endpoint, rw.statusCode, duration)
 
 		// Если произошла ошибка (статус >= 400), записываем ее
 		if rw.statusCode >= 400 {
@@ -97,143 +188,304 @@ func (s *Server) metricsMiddleware(next http.HandlerFunc, endpoint string) http.
 	}
 }
 
+// instrument оборачивает HTTP-обработчик трассировкой, сбором метрик и, если включено, профилированием.
+//
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	- endpoint: имя эндпоинта для метрик, спана и профиля.
+//	Возвращает:
+//	- http.HandlerFunc: инструментированный обработчик.
+func (s *Server) instrument(next http.HandlerFunc, endpoint string) http.HandlerFunc {
+	if s.profiler != nil {
+		next = s.profiler.wrap(next, endpoint)
+	}
+	return s.traceMiddleware(s.metricsMiddleware(next, endpoint), endpoint)
+}
+
 // responseWriter оборачивает http.ResponseWriter для отслеживания статуса ответа и размера.
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode   int
 	bytesWritten int
+	wroteHeader  bool
 }
 
 // WriteHeader переопределяет метод для отслеживания статуса ответа.
 func (rw *responseWriter) WriteHeader(statusCode int) {
 	rw.statusCode = statusCode
+	rw.wroteHeader = true
 	rw.ResponseWriter.WriteHeader(statusCode)
 }
 
 // Write переопределяет метод для отслеживания размера ответа.
 func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
 	n, err := rw.ResponseWriter.Write(b)
 	rw.bytesWritten += n
 	return n, err
 }
 
-// registerRoutes регистрирует маршруты HTTP для обработки запросов.
-//
-//	Параметры:
-//	- mux: HTTP маршрутизатор (ServeMux).
-func (s *Server) registerRoutes(mux *http.ServeMux) {
-	// Маршрут для получения заказа по ID
-	mux.HandleFunc("/order/", s.metricsMiddleware(s.handleGetOrderByID, "/order/{id}"))
-	mux.HandleFunc("/api/orders", s.metricsMiddleware(s.handleGetOrders, "/api/orders"))
-	mux.HandleFunc("/api/send-test-order", s.metricsMiddleware(s.handleSendTestOrder, "/api/send-test-order"))
-
-	// Health check endpoint
-	mux.HandleFunc("/health", s.metricsMiddleware(s.handleHealth, "/health"))
-	s.logger.Info("Health check endpoint registered")
-
-	// Статический контент (index.html)
-	if s.staticDir != "" {
-		mux.HandleFunc("/", s.metricsMiddleware(s.handleStatic, "/static"))
-		s.logger.Info("Static content route registered", zap.String("staticDir", s.staticDir))
+// Unwrap возвращает исходный http.ResponseWriter для http.ResponseController.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Hijack передаёт соединение обработчику (WebSocket); библиотеки проверяют
+// http.Hijacker приведением типа, поэтому одного Unwrap недостаточно.
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(rw.ResponseWriter).Hijack()
+	if err == nil {
+		rw.statusCode = http.StatusSwitchingProtocols
+		rw.wroteHeader = true
 	}
+	return conn, brw, err
 }
 
-// handleGetOrderByID обрабатывает запросы вида: GET /order/{id}.
+// handleGetOrderByID обрабатывает запросы вида: GET /api/v1/orders/{id}.
 //
 //	Возвращает заказ с указанным ID, если он есть в кэше.
 //	Если ID отсутствует или не найден, возвращается ошибка 404 или 400.
+//	Ответ содержит ETag и Last-Modified; на условный запрос с актуальной
+//	копией клиента возвращается 304 без тела. Параметр fields ограничивает
+//	ответ выбранными полями (например, fields=order_uid,payment.amount).
 //	Параметры:
 //	- w: HTTP-ответ.
 //	- r: HTTP-запрос.
 func (s *Server) handleGetOrderByID(w http.ResponseWriter, r *http.Request) {
-	// Удаляем префикс "/order/" чтобы получить {id}
-	orderID := strings.TrimPrefix(r.URL.Path, "/order/")
-	s.logger.Info("Received order request", zap.String("orderID", orderID))
+	orderID := r.PathValue("id")
+	s.log(r).Info("Received order request", zap.String("order_uid", orderID))
 
 	if orderID == "" {
-		http.Error(w, "order id is required", http.StatusBadRequest)
-		s.logger.Warn("Order ID is missing in request")
+		s.writeError(w, r, newAPIError(http.StatusBadRequest, codeBadRequest, "order id is required"))
+		s.log(r).Warn("Order ID is missing in request")
+		return
+	}
+
+	fields, err := parseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		s.writeError(w, r, err)
 		return
 	}
 
 	order := s.cache.Get(orderID)
+	if order == nil && !s.cache.Complete() && s.orders != nil {
+		// Кэш ограничен по размеру или времени жизни: промах не означает, что заказа нет
+		order, err = s.orders.GetOrderByID(r.Context(), orderID)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			order = nil
+		case err != nil:
+			s.log(r).Error("Failed to load order from database", zap.String("order_uid", orderID), zap.Error(err))
+			s.writeError(w, r, err)
+			return
+		case order != nil:
+			s.cache.Set(order)
+		}
+	}
 	if order == nil {
-		http.Error(w, "order not found", http.StatusNotFound)
-		s.logger.Warn("Order not found", zap.String("orderID", orderID))
+		s.writeError(w, r, newAPIError(http.StatusNotFound, codeNotFound, "order not found").withDetails(map[string]string{"order_uid": orderID}))
+		s.log(r).Warn("Order not found", zap.String("order_uid", orderID))
+		return
+	}
+
+	etag, modified, err := orderValidators(order)
+	if err != nil {
+		s.log(r).Error("Failed to compute order ETag", zap.String("order_uid", orderID), zap.Error(err))
+		s.writeError(w, r, err)
+		return
+	}
+	setCacheHeaders(w.Header(), etag, modified)
+	if notModified(r, etag, modified) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(order); err != nil {
-		s.logger.Error("Failed to encode response", zap.Error(err))
-		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+	if fields != nil {
+		doc, err := selectOrderFields(order, fields)
+		if err == nil {
+			err = json.NewEncoder(w).Encode(doc)
+		}
+		if err != nil {
+			s.log(r).Error("Failed to encode response", zap.Error(err))
+			s.writeError(w, r, newAPIError(http.StatusInternalServerError, codeEncodeResponse, "failed to encode response"))
+		}
+		return
+	}
+	if s.streamThreshold > 0 && len(order.Items) >= s.streamThreshold {
+		// Заголовки уже могли уйти клиенту, поэтому ошибку можно только залогировать.
+		if err := writeOrderStream(w, order); err != nil {
+			s.log(r).Error("Failed to stream order response", zap.String("order_uid", orderID), zap.Error(err))
+		}
+		return
+	}
+	if err := json.NewEncoder(w).Encode(dto.NewOrder(order)); err != nil {
+		s.log(r).Error("Failed to encode response", zap.Error(err))
+		s.writeError(w, r, newAPIError(http.StatusInternalServerError, codeEncodeResponse, "failed to encode response"))
 	}
 }
 
-// handleGetOrders возвращает список всех заказов из кэша.
-func (s *Server) handleGetOrders(w http.ResponseWriter, _ *http.Request) {
-	s.logger.Info("Received request to fetch all orders")
+// handleGetOrders возвращает список заказов.
+//
+//	Без параметров возвращается массив всех заказов. При указании limit и/или
+//	cursor возвращается страница заказов, упорядоченных по дате создания
+//	(сначала новые), и подписанный курсор следующей страницы. Параметр fields
+//	ограничивает каждый заказ выбранными полями. Заказы берутся из кэша, если
+//	он содержит все заказы БД, иначе — через OrderService.ListOrders из БД.
+func (s *Server) handleGetOrders(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("Received request to fetch all orders")
+
+	q := r.URL.Query()
+	fields, err := parseFields(q.Get("fields"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	if isPageRequest(q) {
+		req, err := parsePageRequest(q, s.cursors)
+		if err != nil {
+			s.log(r).Warn("Invalid pagination parameters", zap.Error(err))
+			s.writeError(w, r, err)
+			return
+		}
+
+		var page []*model.Order
+		var next *pagination.Cursor
+		if s.partialCache() {
+			page, next, err = s.orders.ListOrders(r.Context(), repository.OrderFilter{}, req.after, req.limit)
+			if err != nil {
+				s.log(r).Error("Failed to list orders", zap.Error(err))
+				s.writeError(w, r, err)
+				return
+			}
+		} else {
+			page, next = paginateOrders(s.cache.GetAll(), req)
+		}
+		if page == nil {
+			page = []*model.Order{}
+		}
+		resp := orderPage{Orders: dto.NewOrders(page)}
+		if next != nil {
+			resp.NextCursor = s.cursors.Encode(*next)
+		}
+		if fields != nil {
+			if resp.Orders, err = selectOrdersFields(page, fields); err != nil {
+				s.log(r).Error("Failed to select order fields", zap.Error(err))
+				s.writeError(w, r, newAPIError(http.StatusInternalServerError, codeEncodeResponse, "failed to encode response"))
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			s.log(r).Error("Failed to encode orders page", zap.Error(err))
+			s.writeError(w, r, newAPIError(http.StatusInternalServerError, codeEncodeResponse, "failed to encode response"))
+		}
+		return
+	}
 
 	orders := s.cache.GetAll()
+	if s.partialCache() {
+		if orders, err = s.listAllOrders(r.Context(), repository.OrderFilter{}); err != nil {
+			s.log(r).Error("Failed to list orders", zap.Error(err))
+			s.writeError(w, r, err)
+			return
+		}
+	}
 	if len(orders) == 0 {
-		http.Error(w, "no orders available", http.StatusNotFound)
-		s.logger.Warn("No orders found in cache")
+		s.writeError(w, r, newAPIError(http.StatusNotFound, codeNotFound, "no orders available"))
+		s.log(r).Warn("No orders found")
 		return
 	}
 
+	var resp any = dto.NewOrders(orders)
+	if fields != nil {
+		if resp, err = selectOrdersFields(orders, fields); err != nil {
+			s.log(r).Error("Failed to select order fields", zap.Error(err))
+			s.writeError(w, r, newAPIError(http.StatusInternalServerError, codeEncodeResponse, "failed to encode response"))
+			return
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(orders); err != nil {
-		s.logger.Error("Failed to encode orders response", zap.Error(err))
-		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.log(r).Error("Failed to encode orders response", zap.Error(err))
+		s.writeError(w, r, newAPIError(http.StatusInternalServerError, codeEncodeResponse, "failed to encode response"))
+	}
+}
+
+// partialCache сообщает, что кэш содержит не все заказы БД и списки нужно читать через OrderService.
+//
+//	Кэш неполон при CACHE_TTL, CACHE_PRELOAD_WINDOW, после вытеснения по
+//	CACHE_MAX_ENTRIES и после очистки.
+func (s *Server) partialCache() bool {
+	return !s.cache.Complete() && s.orders != nil
+}
+
+// listAllOrders возвращает все заказы, удовлетворяющие фильтру, от новых к старым.
+//
+//	Заказы читаются страницами через OrderService.ListOrders, который при
+//	неполном кэше обращается к БД.
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- filter: условия отбора.
+//	Возвращает:
+//	- []*model.Order: заказы выборки.
+//	- error: ошибка чтения заказов.
+func (s *Server) listAllOrders(ctx context.Context, filter repository.OrderFilter) ([]*model.Order, error) {
+	var orders []*model.Order
+	var after *pagination.Cursor
+	for {
+		page, next, err := s.orders.ListOrders(ctx, filter, after, maxPageSize)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, page...)
+		if next == nil {
+			return orders, nil
+		}
+		after = next
 	}
 }
 
 // handleSendTestOrder отправляет тестовый заказ в Kafka.
-func (s *Server) handleSendTestOrder(w http.ResponseWriter, _ *http.Request) {
-	s.logger.Info("Received request to send test order")
+//
+//	Тело запроса необязательно: если передан заказ в JSON, отправляется он,
+//	иначе генерируется случайный заказ.
+func (s *Server) handleSendTestOrder(w http.ResponseWriter, r *http.Request) {
+	s.log(r).Info("Received request to send test order")
 
-	orderUID, err := kafka.ProduceTestMessage()
+	order, err := decodeTestOrder(r)
 	if err != nil {
-		s.logger.Error("Failed to send test order", zap.Error(err))
-		http.Error(w, "failed to send test order", http.StatusInternalServerError)
+		s.log(r).Warn("Invalid test order payload", zap.Error(err))
+		s.writeError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write([]byte("Test order sent successfully! Order UID: " + orderUID)); err != nil {
-		log.Printf("failed to write response: %v", err)
+	orderUID, err := kafka.ProduceTestMessage(r.Context(), order)
+	if err != nil {
+		s.log(r).Error("Failed to send test order", zap.Error(err))
+		s.writeError(w, r, newAPIError(http.StatusInternalServerError, codeKafkaPublish, "failed to send test order"))
+		return
 	}
-}
 
-// handleHealth обрабатывает запросы к эндпоинту /health.
-//
-//	Возвращает 200 OK, если сервер работает нормально.
-//	Параметры:
-//	- w: HTTP-ответ.
-//	- r: HTTP-запрос.
-func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
-	s.logger.Info("Health check requested")
 	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write([]byte("OK")); err != nil {
-		s.logger.Error("Failed to write health check response", zap.Error(err))
+	if _, err := w.Write([]byte("Test order sent successfully! Order UID: " + orderUID)); err != nil {
+		s.log(r).Warn("Failed to write response", zap.Error(err))
 	}
 }
 
-// handleStatic раздаёт статические файлы из s.staticDir.
+// handleStatic раздаёт статические файлы веб-интерфейса.
 //
-//	Если запрашивается "/", возвращается "index.html".
+//	Если запрашивается "/", возвращается "index.html". Пути разрешаются внутри
+//	файловой системы s.static, поэтому выйти за её пределы через ".." нельзя.
 //	Параметры:
 //	- w: HTTP-ответ.
 //	- r: HTTP-запрос.
 func (s *Server) handleStatic(w http.ResponseWriter, r *http.Request) {
-	filePath := r.URL.Path
-	if filePath == "/" {
-		filePath = "/index.html"
-	}
-	fp := path.Join(s.staticDir, filePath)
-
-	s.logger.Info("Serving static file", zap.String("filePath", fp))
-	http.ServeFile(w, r, fp)
+	s.log(r).Info("Serving static file", zap.String("path", r.URL.Path))
+	s.static.ServeHTTP(w, r)
 }
 
 // Start запускает сервер и блокируется до завершения работы.
@@ -244,18 +496,36 @@ func (s *Server) handleStatic(w http.ResponseWriter, r *http.Request) {
 //	Возвращает:
 //	- error: ошибку, если сервер не удалось запустить или корректно завершить.
 func (s *Server) Start(ctx context.Context) error {
-	errCh := make(chan error, 1)
+	errCh := make(chan error, 2)
 	go func() {
-		s.logger.Info("HTTP server is starting")
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		s.logger.Info("HTTP server is starting", zap.Bool("tls", s.tls != nil))
+		if err := s.listenAndServe(); err != nil && err != http.ErrServerClosed {
 			errCh <- err
 		}
 	}()
+	if s.redirectServer != nil {
+		go func() {
+			s.logger.Info("HTTP redirect server is starting", zap.String("addr", s.redirectServer.Addr))
+			if err := s.redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errCh <- err
+			}
+		}()
+	}
 
 	select {
 	case <-ctx.Done():
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		// Отключаем потоковых клиентов: Shutdown не ждёт перехваченные соединения
+		// WebSocket и не завершает долгие ответы сам.
+		if s.feed != nil {
+			s.feed.Close()
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
 		defer cancel()
+		if s.redirectServer != nil {
+			if err := s.redirectServer.Shutdown(shutdownCtx); err != nil {
+				s.logger.Error("Failed to shut down redirect server", zap.Error(err))
+			}
+		}
 		if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
 			s.logger.Error("Failed to shut down server", zap.Error(err))
 			return err
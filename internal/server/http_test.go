@@ -0,0 +1,79 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/model"
+	"l0_wb/internal/pagination"
+	"l0_wb/internal/util"
+)
+
+// TestGetOrdersPartialCache проверяет, что при ограниченном кэше список и страницы заказов читаются через сервис.
+func TestGetOrdersPartialCache(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	orders := []*model.Order{
+		{OrderUID: "c", DateCreated: base.Add(2 * time.Hour)},
+		{OrderUID: "b", DateCreated: base.Add(time.Hour)},
+		{OrderUID: "a", DateCreated: base},
+	}
+	// В кэш помещается один заказ: остальные вытеснены и есть только в БД
+	orderCache := cache.NewOrderCache(cache.WithMaxEntries(1))
+	for _, o := range orders {
+		orderCache.Set(o)
+	}
+	s := &Server{
+		cache:   orderCache,
+		orders:  &fakeOrderService{customerOrders: orders},
+		cursors: pagination.NewSigner([]byte("secret"), time.Hour),
+		logger:  zap.NewNop(),
+	}
+
+	get := func(query string) (int, orderPage, []map[string]any) {
+		rec := httptest.NewRecorder()
+		s.handleGetOrders(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders?"+query, nil))
+
+		var page orderPage
+		var list []map[string]any
+		if rec.Code == http.StatusOK {
+			body := any(&list)
+			if query != "" {
+				page.Orders = &list
+				body = &page
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), body); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+		}
+		return rec.Code, page, list
+	}
+	uids := func(list []map[string]any) []any {
+		var out []any
+		for _, o := range list {
+			out = append(out, o["order_uid"])
+		}
+		return out
+	}
+
+	code, _, list := get("")
+	if code != http.StatusOK || len(list) != len(orders) {
+		t.Fatalf("expected all %d orders, got %d %v", len(orders), code, uids(list))
+	}
+
+	code, page, list := get("limit=2")
+	if code != http.StatusOK || len(list) != 2 || list[0]["order_uid"] != "c" || page.NextCursor == "" {
+		t.Fatalf("unexpected first page: %d %v %q", code, uids(list), page.NextCursor)
+	}
+	code, page, list = get("limit=2&cursor=" + page.NextCursor)
+	if code != http.StatusOK || len(list) != 1 || list[0]["order_uid"] != "a" || page.NextCursor != "" {
+		t.Fatalf("unexpected second page: %d %v %q", code, uids(list), page.NextCursor)
+	}
+}
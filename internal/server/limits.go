@@ -0,0 +1,194 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// errRequestTimeout — ошибка API для запросов, не уложившихся в REQUEST_TIMEOUT.
+var errRequestTimeout = newAPIError(http.StatusRequestTimeout, codeRequestTimeout, "request processing timed out")
+
+// limitBody ограничивает размер тела запроса значением MAX_REQUEST_BODY_BYTES.
+//
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	Возвращает:
+//	- http.HandlerFunc: обработчик с ограничением размера тела.
+func (s *Server) limitBody(next http.HandlerFunc) http.HandlerFunc {
+	return s.limitBodyTo(s.maxBodyBytes)(next)
+}
+
+// limitBodyTo возвращает middleware, ограничивающий размер тела запроса.
+//
+//	Запросы с заранее известной длиной больше лимита отклоняются сразу с 413;
+//	для остальных чтение тела сверх лимита завершается ошибкой *http.MaxBytesError,
+//	которую mapError сопоставляет с тем же статусом.
+//	Параметры:
+//	- limit: максимальный размер тела в байтах (0 — без ограничения).
+//	Возвращает:
+//	- middleware: middleware с ограничением размера тела.
+func (s *Server) limitBodyTo(limit int64) middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		if limit <= 0 {
+			return next
+		}
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				s.writeError(w, r, newAPIErrorf(http.StatusRequestEntityTooLarge, codePayloadTooLarge,
+					"request body exceeds %d bytes", limit))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next(w, r)
+		}
+	}
+}
+
+// withTimeout ограничивает время обработки запроса значением REQUEST_TIMEOUT.
+//
+//	Дедлайн передаётся обработчику через контекст запроса, поэтому обращения к
+//	кэшу и БД прерываются вместе с ним. Если к дедлайну обработчик ещё не начал
+//	отвечать, клиент получает 408, а дальнейшие записи обработчика отбрасываются.
+//	Уже начатый ответ (например, потоковая выдача большого заказа) не прерывается.
+//	Потоковые маршруты (WebSocket, SSE) этим middleware не оборачиваются.
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	Возвращает:
+//	- http.HandlerFunc: обработчик с ограничением времени.
+func (s *Server) withTimeout(next http.HandlerFunc) http.HandlerFunc {
+	if s.requestTimeout <= 0 {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		tw := &timeoutWriter{w: w, h: make(http.Header), ctx: ctx}
+		done := make(chan struct{})
+		panicked := make(chan any, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+					return
+				}
+				close(done)
+			}()
+			next(tw, r)
+		}()
+
+		select {
+		case <-done:
+		case p := <-panicked:
+			panic(p)
+		case <-ctx.Done():
+		}
+
+		tw.mu.Lock()
+		if !tw.wroteHeader && ctx.Err() != nil {
+			tw.timedOut = true
+			tw.mu.Unlock()
+			s.log(r).Warn("Request timed out")
+			s.writeError(w, r, errRequestTimeout)
+			return
+		}
+		tw.mu.Unlock()
+		// Ответ уже начат: дожидаемся обработчика, чтобы не оборвать его на середине
+		select {
+		case <-done:
+		case p := <-panicked:
+			panic(p)
+		}
+	}
+}
+
+// timeoutWriter защищает http.ResponseWriter от записи после истечения таймаута.
+//
+//	Обработчик пишет заголовки в собственную карту h, которая копируется в
+//	исходный ответ только при его начале. Поэтому ответ 408 не содержит
+//	заголовков обработчика, а обработчик, продолжающий работу после дедлайна,
+//	не меняет заголовки одновременно с writeError. После дедлайна ещё не
+//	начатый ответ не начинается, даже если обработчик успел записать раньше,
+//	чем withTimeout заметил истечение контекста.
+type timeoutWriter struct {
+	w           http.ResponseWriter
+	h           http.Header
+	ctx         context.Context
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+}
+
+// Header возвращает заголовки ответа обработчика.
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.h
+}
+
+// WriteHeader записывает статус, если таймаут ещё не истёк.
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.wroteHeader || tw.expired() {
+		return
+	}
+	tw.copyHeader()
+	tw.w.WriteHeader(status)
+}
+
+// expired сообщает, что ответ уже нельзя начать: таймаут обработан или дедлайн истёк.
+//
+//	Вызывается под mu.
+func (tw *timeoutWriter) expired() bool {
+	return tw.timedOut || tw.ctx.Err() != nil
+}
+
+// copyHeader переносит заголовки обработчика в исходный ответ при его начале.
+//
+//	Вызывается под mu.
+func (tw *timeoutWriter) copyHeader() {
+	if tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	dst := tw.w.Header()
+	for k, v := range tw.h {
+		dst[k] = v
+	}
+}
+
+// Write записывает тело ответа или возвращает http.ErrHandlerTimeout после таймаута.
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.wroteHeader && tw.expired() {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.copyHeader()
+	return tw.w.Write(b)
+}
+
+// Flush отправляет буферизованные данные клиенту.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.wroteHeader && tw.expired() {
+		return
+	}
+	tw.copyHeader()
+	_ = http.NewResponseController(tw.w).Flush()
+}
+
+// Unwrap возвращает исходный http.ResponseWriter для http.ResponseController.
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.w
+}
+
+// Hijack запрещает перехват соединения под таймаутом.
+func (tw *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("hijack is not supported under request timeout")
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TestWithTimeout проверяет, что медленный обработчик получает отменённый контекст, а клиент — 408.
+func TestWithTimeout(t *testing.T) {
+	s := &Server{requestTimeout: 20 * time.Millisecond, logger: zap.NewNop()}
+	canceled := make(chan struct{})
+	h := s.withTimeout(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(canceled)
+		_, _ = w.Write([]byte("late"))
+	})
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil))
+	if rec.Code != http.StatusRequestTimeout {
+		t.Fatalf("expected 408, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), codeRequestTimeout) {
+		t.Errorf("unexpected body %q", rec.Body.String())
+	}
+	<-canceled
+}
+
+// TestWithTimeoutHeaders проверяет, что заголовки обработчика не попадают в ответ 408,
+// а их изменение после дедлайна не гонится с записью ошибки (запуск с -race).
+func TestWithTimeoutHeaders(t *testing.T) {
+	s := &Server{requestTimeout: 20 * time.Millisecond, logger: zap.NewNop()}
+	finished := make(chan struct{})
+	h := s.withTimeout(func(w http.ResponseWriter, r *http.Request) {
+		defer close(finished)
+		w.Header().Set("ETag", `"early"`)
+		<-r.Context().Done()
+		for i := range 100 {
+			w.Header().Set("ETag", fmt.Sprintf(`"late-%d"`, i))
+			w.Header().Set("Content-Type", "application/octet-stream")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/1", nil))
+	<-finished
+	if rec.Code != http.StatusRequestTimeout {
+		t.Fatalf("expected 408, got %d", rec.Code)
+	}
+	if etag := rec.Header().Get("ETag"); etag != "" {
+		t.Errorf("expected no handler ETag in 408 response, got %q", etag)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("expected JSON error Content-Type, got %q", ct)
+	}
+
+	// Без таймаута заголовки обработчика доходят до клиента
+	s.requestTimeout = time.Second
+	h = s.withTimeout(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("ok"))
+	})
+	rec = httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/1", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"v1"` {
+		t.Errorf("expected 200 with ETag, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+// TestLimitBody проверяет отказ 413 для тела больше MAX_REQUEST_BODY_BYTES.
+func TestLimitBody(t *testing.T) {
+	s := &Server{maxBodyBytes: 8, logger: zap.NewNop()}
+	h := s.limitBody(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			s.writeError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// Длина тела неизвестна заранее: лимит срабатывает при чтении
+	chunked := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader("123456789")))
+	chunked.ContentLength = -1
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"small body", httptest.NewRequest(http.MethodPost, "/", strings.NewReader("1234")), http.StatusOK},
+		{"declared length", httptest.NewRequest(http.MethodPost, "/", strings.NewReader("123456789")), http.StatusRequestEntityTooLarge},
+		{"unknown length", chunked, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h(rec, tt.req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.status, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// statusRecorder запоминает статус и заголовки ответа, не передавая их клиенту.
+type statusRecorder struct {
+	header http.Header
+	status int
+}
+
+// Header возвращает заголовки ответа.
+func (r *statusRecorder) Header() http.Header { return r.header }
+
+// WriteHeader запоминает статус ответа.
+func (r *statusRecorder) WriteHeader(status int) { r.status = status }
+
+// Write отбрасывает тело ответа.
+func (r *statusRecorder) Write(b []byte) (int, error) { return len(b), nil }
+
+// methodNotAllowedMiddleware отвечает на запросы с неразрешённым методом ошибкой API.
+//
+//	Маршруты регистрируются с методом ("GET /api/v1/orders"), и ServeMux сам
+//	отвечает 405 с заголовком Allow, но текстовым телом. Middleware заранее
+//	определяет такие запросы через mux.Handler и отвечает 405 в формате ошибки
+//	API с тем же заголовком Allow.
+//	Параметры:
+//	- mux: HTTP маршрутизатор (ServeMux).
+//	Возвращает:
+//	- http.Handler: обработчик с единым форматом ответа 405.
+func (s *Server) methodNotAllowedMiddleware(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, pattern := mux.Handler(r)
+		if pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		// Маршрут не найден: встроенный обработчик ServeMux сообщает 404 или 405
+		rec := &statusRecorder{header: http.Header{}}
+		h.ServeHTTP(rec, r)
+		if rec.status != http.StatusMethodNotAllowed {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		allow := rec.header.Get("Allow")
+		w.Header().Set("Allow", allow)
+		s.writeError(w, r, newAPIErrorf(http.StatusMethodNotAllowed, codeMethodNotAllowed, "method %s is not allowed", r.Method).
+			withDetails(map[string][]string{"allowed": strings.Split(allow, ", ")}))
+	})
+}
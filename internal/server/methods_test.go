@@ -0,0 +1,47 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/util"
+)
+
+// TestMethodNotAllowed проверяет ответ 405 с заголовком Allow в формате ошибки API.
+func TestMethodNotAllowed(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	s := &Server{logger: zap.NewNop()}
+	mux := http.NewServeMux()
+	s.registerRoutes(mux)
+	h := s.methodNotAllowedMiddleware(mux)
+
+	tests := []struct {
+		method string
+		path   string
+		allow  string
+	}{
+		{http.MethodDelete, "/api/v1/orders", "GET, HEAD"},
+		{http.MethodGet, "/api/v1/send-test-order", "POST"},
+		{http.MethodPut, "/api/send-test-order", "POST"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s %s: expected 405, got %d", tt.method, tt.path, rec.Code)
+			continue
+		}
+		if got := rec.Header().Get("Allow"); got != tt.allow {
+			t.Errorf("%s %s: expected Allow %q, got %q", tt.method, tt.path, tt.allow, got)
+		}
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["code"] != codeMethodNotAllowed {
+			t.Errorf("%s %s: unexpected body %s", tt.method, tt.path, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"l0_wb/internal/config"
+)
+
+// corsPolicy содержит правила CORS, применяемые ко всем маршрутам сервера.
+type corsPolicy struct {
+	allowAll    bool
+	origins     map[string]struct{}
+	methods     string
+	headers     string
+	maxAge      string
+	credentials bool
+}
+
+// newCORSPolicy создаёт политику CORS из конфигурации.
+//
+//	Возвращает nil, если список разрешённых источников пуст: в этом случае
+//	заголовки CORS не выставляются и браузер применяет политику same-origin.
+func newCORSPolicy(cfg *config.Config) *corsPolicy {
+	if len(cfg.CORSAllowedOrigins) == 0 {
+		return nil
+	}
+	p := &corsPolicy{
+		origins:     make(map[string]struct{}, len(cfg.CORSAllowedOrigins)),
+		methods:     strings.Join(cfg.CORSAllowedMethods, ", "),
+		headers:     strings.Join(cfg.CORSAllowedHeaders, ", "),
+		maxAge:      strconv.Itoa(int(cfg.CORSMaxAge.Seconds())),
+		credentials: cfg.CORSAllowCredentials,
+	}
+	for _, origin := range cfg.CORSAllowedOrigins {
+		if origin == "*" {
+			p.allowAll = true
+			continue
+		}
+		p.origins[strings.TrimSuffix(origin, "/")] = struct{}{}
+	}
+	return p
+}
+
+// allowed сообщает, разрешён ли источник запроса.
+func (p *corsPolicy) allowed(origin string) bool {
+	if p.allowAll {
+		return true
+	}
+	_, ok := p.origins[origin]
+	return ok
+}
+
+// corsMiddleware добавляет заголовки CORS и отвечает на preflight-запросы.
+//
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	Возвращает:
+//	- http.Handler: обработчик с поддержкой CORS.
+func (s *Server) corsMiddleware(next http.Handler) http.Handler {
+	if s.cors == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		if !s.cors.allowed(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Expose-Headers", requestIDHeader+", ETag, Last-Modified")
+		if s.cors.credentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		// Preflight-запрос браузера: отвечаем сами, не передавая его обработчику маршрута.
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			h.Set("Access-Control-Allow-Methods", s.cors.methods)
+			h.Set("Access-Control-Allow-Headers", s.cors.headers)
+			h.Set("Access-Control-Max-Age", s.cors.maxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
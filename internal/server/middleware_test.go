@@ -0,0 +1,96 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"l0_wb/internal/config"
+)
+
+// TestCORSMiddleware проверяет заголовки CORS для разрешённых и чужих источников и ответ на preflight-запрос.
+func TestCORSMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		credentials bool
+		method      string
+		origin      string
+		preflight   bool
+		wantStatus  int
+		wantOrigin  string // Ожидаемый Access-Control-Allow-Origin (пусто — заголовка нет)
+		wantCreds   bool
+	}{
+		{name: "exact origin", origins: []string{"https://ui.example/"}, method: http.MethodGet, origin: "https://ui.example", wantStatus: http.StatusOK, wantOrigin: "https://ui.example"},
+		{name: "origin not on the list", origins: []string{"https://ui.example"}, method: http.MethodGet, origin: "https://evil.example", wantStatus: http.StatusOK},
+		{name: "exact origin differs by scheme", origins: []string{"https://ui.example"}, method: http.MethodGet, origin: "http://ui.example", wantStatus: http.StatusOK},
+		{name: "wildcard", origins: []string{"*"}, method: http.MethodGet, origin: "https://any.example", wantStatus: http.StatusOK, wantOrigin: "https://any.example"},
+		{name: "same-origin request", origins: []string{"https://ui.example"}, method: http.MethodGet, wantStatus: http.StatusOK},
+		{name: "credentials allowed", origins: []string{"https://ui.example"}, credentials: true, method: http.MethodGet, origin: "https://ui.example", wantStatus: http.StatusOK, wantOrigin: "https://ui.example", wantCreds: true},
+		{name: "credentials for rejected origin", origins: []string{"https://ui.example"}, credentials: true, method: http.MethodGet, origin: "https://evil.example", wantStatus: http.StatusOK},
+		{name: "preflight", origins: []string{"https://ui.example"}, method: http.MethodOptions, origin: "https://ui.example", preflight: true, wantStatus: http.StatusNoContent, wantOrigin: "https://ui.example"},
+		// Preflight с чужого источника передаётся маршруту и не получает разрешающих заголовков
+		{name: "preflight from rejected origin", origins: []string{"https://ui.example"}, method: http.MethodOptions, origin: "https://evil.example", preflight: true, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				CORSAllowedOrigins:   tt.origins,
+				CORSAllowedMethods:   []string{"GET", "POST"},
+				CORSAllowedHeaders:   []string{"Content-Type", "X-API-Key"},
+				CORSMaxAge:           10 * time.Minute,
+				CORSAllowCredentials: tt.credentials,
+			}
+			s := &Server{cors: newCORSPolicy(cfg)}
+			reached := false
+			h := s.corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				reached = true
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(tt.method, "/api/v1/orders", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			hdr := rec.Header()
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if got := hdr.Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("expected Access-Control-Allow-Origin %q, got %q", tt.wantOrigin, got)
+			}
+			if got := hdr.Get("Access-Control-Allow-Credentials") == "true"; got != tt.wantCreds {
+				t.Errorf("expected credentials allowed=%v, got %v", tt.wantCreds, got)
+			}
+			if tt.origin != "" && !slices.Contains(hdr.Values("Vary"), "Origin") {
+				t.Errorf("expected Vary: Origin, got %v", hdr.Values("Vary"))
+			}
+			allowedPreflight := tt.preflight && tt.wantOrigin != ""
+			if reached == allowedPreflight {
+				t.Errorf("expected route handler reached=%v, got %v", !allowedPreflight, reached)
+			}
+			if allowedPreflight {
+				if hdr.Get("Access-Control-Allow-Methods") != "GET, POST" ||
+					hdr.Get("Access-Control-Allow-Headers") != "Content-Type, X-API-Key" ||
+					hdr.Get("Access-Control-Max-Age") != "600" {
+					t.Errorf("unexpected preflight headers: %v", hdr)
+				}
+			} else if hdr.Get("Access-Control-Allow-Methods") != "" {
+				t.Errorf("unexpected Access-Control-Allow-Methods on a non-preflight response: %v", hdr)
+			}
+		})
+	}
+
+	// Без разрешённых источников политика не создаётся и заголовки CORS не выставляются
+	if newCORSPolicy(&config.Config{}) != nil {
+		t.Error("expected nil policy without allowed origins")
+	}
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"l0_wb/internal/dto"
+	"l0_wb/internal/model"
+)
+
+const (
+	// streamChunkSize — количество товаров, после записи которых буфер сбрасывается клиенту.
+	streamChunkSize = 100
+	// streamWriteTimeout — дедлайн записи, продлеваемый после каждого отправленного чанка.
+	streamWriteTimeout = 10 * time.Second
+)
+
+// orderHeader представляет заказ без массива товаров.
+//
+//	Поле Items затеняет одноимённое поле встроенной структуры и при nil
+//	не попадает в JSON, поэтому заголовок заказа сериализуется без товаров.
+type orderHeader struct {
+	*dto.Order
+	Items *struct{} `json:"items,omitempty"`
+}
+
+// writeOrderStream записывает заказ в ответ потоково (chunked encoding).
+//
+//	Сначала отправляются поля заказа, затем товары порциями по streamChunkSize
+//	с промежуточным сбросом буфера. После каждой порции дедлайн записи
+//	продлевается, поэтому большие заказы не упираются в WriteTimeout сервера.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- order: объект заказа.
+//	Возвращает:
+//	- error: ошибку сериализации или записи в соединение.
+func writeOrderStream(w http.ResponseWriter, o *model.Order) error {
+	order := dto.NewOrder(o)
+	header, err := json.Marshal(orderHeader{Order: order})
+	if err != nil {
+		return fmt.Errorf("failed to encode order header: %w", err)
+	}
+
+	rc := http.NewResponseController(w)
+	// Ошибка http.ErrNotSupported означает, что обёртка не даёт управлять дедлайном; тогда действует WriteTimeout сервера.
+	_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+
+	bw := bufio.NewWriterSize(w, 32<<10)
+	// Заголовок заканчивается на '}', вместо неё дописываем массив товаров.
+	if _, err := bw.Write(header[:len(header)-1]); err != nil {
+		return err
+	}
+	if len(header) > 2 {
+		if err := bw.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	if _, err := bw.WriteString(`"items":[`); err != nil {
+		return err
+	}
+
+	for i := range order.Items {
+		if i > 0 {
+			if err := bw.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		data, err := json.Marshal(&order.Items[i])
+		if err != nil {
+			return fmt.Errorf("failed to encode item %d: %w", i, err)
+		}
+		if _, err := bw.Write(data); err != nil {
+			return err
+		}
+
+		if (i+1)%streamChunkSize == 0 {
+			if err := bw.Flush(); err != nil {
+				return err
+			}
+			_ = rc.Flush()
+			_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		}
+	}
+
+	if _, err := bw.WriteString("]}\n"); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
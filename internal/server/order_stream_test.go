@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"l0_wb/internal/model"
+)
+
+// TestWriteOrderStream проверяет, что потоковая запись даёт тот же JSON, что и обычная сериализация.
+func TestWriteOrderStream(t *testing.T) {
+	order := &model.Order{
+		OrderUID:    "stream_uid",
+		TrackNumber: "WBILMTESTTRACK",
+		Delivery:    model.Delivery{Name: "Test Testov", Phone: "+9720000000"},
+		Payment:     model.Payment{Currency: "USD", Amount: 1817},
+		DateCreated: time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+	}
+	for i := 0; i < streamChunkSize*2+7; i++ {
+		order.Items = append(order.Items, model.Item{ChrtID: i, Name: "item", TotalPrice: i * 10})
+	}
+
+	rec := httptest.NewRecorder()
+	if err := writeOrderStream(rec, order); err != nil {
+		t.Fatalf("writeOrderStream returned error: %v", err)
+	}
+
+	var got, want map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("streamed body is not valid JSON: %v", err)
+	}
+	expected, err := json.Marshal(order)
+	if err != nil {
+		t.Fatalf("failed to marshal order: %v", err)
+	}
+	if err := json.Unmarshal(expected, &want); err != nil {
+		t.Fatalf("failed to unmarshal expected order: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("streamed order differs from buffered encoding")
+	}
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"crypto/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+	"l0_wb/internal/model"
+	"l0_wb/internal/pagination"
+)
+
+// Размеры страницы для постраничной выдачи заказов.
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// orderPage — ответ постраничной выдачи заказов.
+//
+//	Orders содержит []*model.Order или, при выборе полей, []map[string]any.
+type orderPage struct {
+	Orders     any    `json:"orders"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// newCursorSigner создаёт подписчик курсоров пагинации.
+//
+//	Если CURSOR_SECRET не задан, генерируется случайный секрет: курсоры
+//	остаются рабочими, но не переживают рестарт и не принимаются другими
+//	экземплярами сервиса.
+func newCursorSigner(cfg *config.Config, logger *zap.Logger) *pagination.Signer {
+	secret := []byte(cfg.CursorSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			logger.Fatal("Failed to generate cursor secret", zap.Error(err))
+		}
+		logger.Warn("CURSOR_SECRET is not set, pagination cursors will not survive restarts")
+	}
+	return pagination.NewSigner(secret, cfg.CursorTTL)
+}
+
+// pageRequest содержит разобранные параметры постраничного запроса.
+type pageRequest struct {
+	limit int
+	after *pagination.Cursor
+}
+
+// isPageRequest сообщает, запрошена ли постраничная выдача.
+func isPageRequest(q url.Values) bool {
+	return q.Has("limit") || q.Has("cursor")
+}
+
+// parsePageRequest разбирает параметры limit и cursor.
+//
+//	Параметры:
+//	- q: параметры запроса.
+//	- signer: подписчик курсоров.
+//	Возвращает:
+//	- pageRequest: параметры страницы.
+//	- error: *apiError для некорректного limit или ошибку pagination для некорректного cursor.
+func parsePageRequest(q url.Values, signer *pagination.Signer) (pageRequest, error) {
+	req := pageRequest{limit: defaultPageSize}
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return req, newAPIErrorf(http.StatusBadRequest, codeBadRequest, "limit must be between 1 and %d", maxPageSize).
+				withDetails(map[string]any{"parameter": "limit", "min": 1, "max": maxPageSize})
+		}
+		req.limit = limit
+	}
+	if raw := q.Get("cursor"); raw != "" {
+		c, err := signer.Decode(raw)
+		if err != nil {
+			return req, err
+		}
+		req.after = &c
+	}
+	return req, nil
+}
+
+// paginateOrders возвращает страницу заказов, упорядоченных по (date_created, order_uid) по убыванию.
+//
+//	Параметры:
+//	- orders: все заказы выборки.
+//	- req: параметры страницы.
+//	Возвращает:
+//	- []*model.Order: заказы страницы.
+//	- *pagination.Cursor: позиция для следующей страницы или nil, если страница последняя.
+func paginateOrders(orders []*model.Order, req pageRequest) ([]*model.Order, *pagination.Cursor) {
+	sorted := make([]*model.Order, len(orders))
+	copy(sorted, orders)
+	sort.Slice(sorted, func(i, j int) bool {
+		return orderAfter(sorted[i].DateCreated.UnixNano(), sorted[i].OrderUID, sorted[j].DateCreated.UnixNano(), sorted[j].OrderUID)
+	})
+
+	start := 0
+	if req.after != nil {
+		afterTS := req.after.DateCreated.UnixNano()
+		start = sort.Search(len(sorted), func(i int) bool {
+			return orderAfter(afterTS, req.after.OrderUID, sorted[i].DateCreated.UnixNano(), sorted[i].OrderUID)
+		})
+	}
+
+	end := start + req.limit
+	if end >= len(sorted) {
+		return sorted[start:], nil
+	}
+	last := sorted[end-1]
+	return sorted[start:end], &pagination.Cursor{DateCreated: last.DateCreated, OrderUID: last.OrderUID}
+}
+
+// orderAfter сообщает, идёт ли заказ (ts1, uid1) раньше заказа (ts2, uid2) при сортировке по убыванию.
+func orderAfter(ts1 int64, uid1 string, ts2 int64, uid2 string) bool {
+	if ts1 != ts2 {
+		return ts1 > ts2
+	}
+	return uid1 > uid2
+}
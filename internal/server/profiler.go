@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime/metrics"
+	runtimepprof "runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Имена метрик runtime/metrics, используемых для оценки аллокаций и CPU.
+const (
+	metricAllocBytes   = "/gc/heap/allocs:bytes"
+	metricAllocObjects = "/gc/heap/allocs:objects"
+	metricUserCPU      = "/cpu/classes/user:cpu-seconds"
+)
+
+// endpointStats хранит накопленную статистику по одному эндпоинту.
+type endpointStats struct {
+	requests      uint64
+	sampled       uint64
+	totalDuration time.Duration
+	maxDuration   time.Duration
+	allocBytes    uint64
+	allocObjects  uint64
+	cpuSeconds    float64
+}
+
+// endpointReport представляет строку отчёта профилировщика.
+type endpointReport struct {
+	Endpoint           string  `json:"endpoint"`
+	Requests           uint64  `json:"requests"`
+	Sampled            uint64  `json:"sampled"`
+	AvgLatencyMs       float64 `json:"avg_latency_ms"`
+	MaxLatencyMs       float64 `json:"max_latency_ms"`
+	AllocBytesPerReq   float64 `json:"alloc_bytes_per_req"`
+	AllocObjectsPerReq float64 `json:"alloc_objects_per_req"`
+	CPUSecondsPerReq   float64 `json:"cpu_seconds_per_req"`
+}
+
+// endpointProfiler собирает профиль аллокаций, CPU и задержек в разрезе эндпоинтов.
+//
+//	Аллокации и CPU снимаются через runtime/metrics до и после обработки запроса.
+//	Эти счётчики общие для процесса, поэтому под конкурентной нагрузкой значения
+//	являются оценкой; для точной атрибуции CPU запросы помечаются pprof-лейблом
+//	"endpoint", который виден в профилях /api/admin/profile/pprof/.
+type endpointProfiler struct {
+	mu          sync.Mutex
+	stats       map[string]*endpointStats
+	sampleEvery uint64
+	counter     atomic.Uint64
+}
+
+// newEndpointProfiler создаёт профилировщик, замеряющий аллокации каждого sampleEvery-го запроса.
+func newEndpointProfiler(sampleEvery int) *endpointProfiler {
+	if sampleEvery < 1 {
+		sampleEvery = 1
+	}
+	return &endpointProfiler{
+		stats:       make(map[string]*endpointStats),
+		sampleEvery: uint64(sampleEvery),
+	}
+}
+
+// wrap оборачивает обработчик сбором профиля для указанного эндпоинта.
+//
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	- endpoint: имя эндпоинта в отчёте.
+//	Возвращает:
+//	- http.HandlerFunc: обработчик с профилированием.
+func (p *endpointProfiler) wrap(next http.HandlerFunc, endpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sampled := p.counter.Add(1)%p.sampleEvery == 0
+
+		var before []metrics.Sample
+		if sampled {
+			before = readRuntimeSamples()
+		}
+		startTime := time.Now()
+
+		runtimepprof.Do(r.Context(), runtimepprof.Labels("endpoint", endpoint), func(ctx context.Context) {
+			next(w, r.WithContext(ctx))
+		})
+
+		duration := time.Since(startTime)
+		var after []metrics.Sample
+		if sampled {
+			after = readRuntimeSamples()
+		}
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		st, ok := p.stats[endpoint]
+		if !ok {
+			st = &endpointStats{}
+			p.stats[endpoint] = st
+		}
+		st.requests++
+		st.totalDuration += duration
+		if duration > st.maxDuration {
+			st.maxDuration = duration
+		}
+		if sampled {
+			st.sampled++
+			st.allocBytes += after[0].Value.Uint64() - before[0].Value.Uint64()
+			st.allocObjects += after[1].Value.Uint64() - before[1].Value.Uint64()
+			st.cpuSeconds += after[2].Value.Float64() - before[2].Value.Float64()
+		}
+	}
+}
+
+// report формирует отчёт, отсортированный по убыванию выбранного показателя.
+//
+//	Параметры:
+//	- sortBy: "alloc" (по умолчанию), "objects", "cpu" или "latency".
+//	Возвращает:
+//	- []endpointReport: строки отчёта.
+func (p *endpointProfiler) report(sortBy string) []endpointReport {
+	p.mu.Lock()
+	rows := make([]endpointReport, 0, len(p.stats))
+	for endpoint, st := range p.stats {
+		row := endpointReport{
+			Endpoint: endpoint,
+			Requests: st.requests,
+			Sampled:  st.sampled,
+		}
+		if st.requests > 0 {
+			row.AvgLatencyMs = float64(st.totalDuration.Microseconds()) / 1000 / float64(st.requests)
+			row.MaxLatencyMs = float64(st.maxDuration.Microseconds()) / 1000
+		}
+		if st.sampled > 0 {
+			row.AllocBytesPerReq = float64(st.allocBytes) / float64(st.sampled)
+			row.AllocObjectsPerReq = float64(st.allocObjects) / float64(st.sampled)
+			row.CPUSecondsPerReq = st.cpuSeconds / float64(st.sampled)
+		}
+		rows = append(rows, row)
+	}
+	p.mu.Unlock()
+
+	key := func(r endpointReport) float64 { return r.AllocBytesPerReq }
+	switch sortBy {
+	case "objects":
+		key = func(r endpointReport) float64 { return r.AllocObjectsPerReq }
+	case "cpu":
+		key = func(r endpointReport) float64 { return r.CPUSecondsPerReq }
+	case "latency":
+		key = func(r endpointReport) float64 { return r.AvgLatencyMs }
+	}
+	sort.Slice(rows, func(i, j int) bool { return key(rows[i]) > key(rows[j]) })
+	return rows
+}
+
+// readRuntimeSamples считывает текущие значения счётчиков аллокаций и CPU.
+func readRuntimeSamples() []metrics.Sample {
+	samples := []metrics.Sample{
+		{Name: metricAllocBytes},
+		{Name: metricAllocObjects},
+		{Name: metricUserCPU},
+	}
+	metrics.Read(samples)
+	return samples
+}
+
+// profilePrefix — префикс маршрутов профилирования в административной группе.
+const profilePrefix = adminPrefix + "/profile"
+
+// registerProfilingRoutes регистрирует эндпоинты профилирования, если профилирование включено.
+//
+//	Профили раскрывают внутреннее устройство процесса, поэтому маршруты
+//	входят в административную группу: доступны с учётными данными
+//	администратора и записываются в журнал аудита. Без ADMIN_TOKEN и
+//	ADMIN_USER маршруты не регистрируются.
+//	Параметры:
+//	- mux: HTTP маршрутизатор (ServeMux).
+func (s *Server) registerProfilingRoutes(mux *http.ServeMux) {
+	if s.profiler == nil {
+		return
+	}
+	if s.admin == nil {
+		s.logger.Warn("Profiling endpoints not registered: admin credentials are not configured")
+		return
+	}
+	s.route(mux, "GET "+profilePrefix+"/endpoints", s.handleProfileReport, s.requireAdmin)
+	s.route(mux, "GET "+profilePrefix+"/pprof/", handlePprofIndex, s.requireAdmin)
+	s.route(mux, "GET "+profilePrefix+"/pprof/cmdline", pprof.Cmdline, s.requireAdmin)
+	s.route(mux, "GET "+profilePrefix+"/pprof/profile", pprof.Profile, s.requireAdmin)
+	// symbol принимает адреса как в строке запроса (GET), так и в теле (POST)
+	s.route(mux, "GET "+profilePrefix+"/pprof/symbol", pprof.Symbol, s.requireAdmin)
+	s.route(mux, "POST "+profilePrefix+"/pprof/symbol", pprof.Symbol, s.requireAdmin)
+	s.route(mux, "GET "+profilePrefix+"/pprof/trace", pprof.Trace, s.requireAdmin)
+	s.logger.Info("Profiling endpoints registered", zap.String("prefix", profilePrefix))
+}
+
+// handlePprofIndex раздаёт именованные профили pprof (heap, goroutine, allocs и т.д.).
+//
+//	pprof.Index ищет имя профиля только под префиксом /debug/pprof/, поэтому
+//	для профилей под profilePrefix/pprof/ имя извлекается вручную.
+func handlePprofIndex(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, profilePrefix+"/pprof/")
+	if name == "" {
+		pprof.Index(w, r)
+		return
+	}
+	pprof.Handler(name).ServeHTTP(w, r)
+}
+
+// handleProfileReport возвращает ранжированный отчёт профилировщика.
+//
+//	Параметр запроса sort задаёт показатель ранжирования: alloc, objects, cpu или latency.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleProfileReport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.profiler.report(r.URL.Query().Get("sort"))); err != nil {
+		s.log(r).Error("Failed to encode profile report", zap.Error(err))
+		s.writeError(w, r, newAPIError(http.StatusInternalServerError, codeEncodeResponse, "failed to encode response"))
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+)
+
+// TestProfilingRoutesRequireAdmin проверяет, что эндпоинты профилирования доступны только администратору и попадают в журнал аудита.
+func TestProfilingRoutesRequireAdmin(t *testing.T) {
+	cfg := &config.Config{AdminToken: "admin-token"}
+	repo := &fakeAuditRepository{}
+	s := &Server{admin: newAdminAuth(cfg), audit: zap.NewNop(), profiler: newEndpointProfiler(1), logger: zap.NewNop()}
+	s.SetAuditRepository(repo)
+	mux := http.NewServeMux()
+	s.registerProfilingRoutes(mux)
+
+	do := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	paths := []string{
+		profilePrefix + "/endpoints",
+		profilePrefix + "/pprof/",
+		profilePrefix + "/pprof/heap",
+		profilePrefix + "/pprof/cmdline",
+		profilePrefix + "/pprof/profile",
+		profilePrefix + "/pprof/symbol",
+		profilePrefix + "/pprof/trace",
+	}
+	for _, path := range paths {
+		if rec := do(http.MethodGet, path, ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("GET %s: expected 401 without credentials, got %d", path, rec.Code)
+		}
+		if rec := do(http.MethodGet, path, "wrong"); rec.Code != http.StatusUnauthorized {
+			t.Errorf("GET %s: expected 401 for wrong token, got %d", path, rec.Code)
+		}
+	}
+	if rec := do(http.MethodPost, profilePrefix+"/pprof/symbol", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("POST symbol: expected 401 without credentials, got %d", rec.Code)
+	}
+	if len(repo.entries) != 0 {
+		t.Errorf("rejected requests must not be stored in the audit table, got %d entries", len(repo.entries))
+	}
+
+	// Запрос администратора обслуживается и записывается в журнал аудита
+	rec := do(http.MethodGet, profilePrefix+"/endpoints?sort=latency", "admin-token")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for endpoint report, got %d", rec.Code)
+	}
+	var report []endpointReport
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if rec := do(http.MethodGet, profilePrefix+"/pprof/cmdline", "admin-token"); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for cmdline, got %d", rec.Code)
+	}
+	if len(repo.entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(repo.entries))
+	}
+	if e := repo.entries[1]; e.Actor != "token" || e.Path != profilePrefix+"/pprof/cmdline" || e.Status != http.StatusOK {
+		t.Errorf("unexpected audit entry %+v", e)
+	}
+
+	// Без учётных данных администратора маршруты не регистрируются
+	s = &Server{profiler: newEndpointProfiler(1), logger: zap.NewNop()}
+	mux = http.NewServeMux()
+	s.registerProfilingRoutes(mux)
+	if rec := do(http.MethodGet, profilePrefix+"/endpoints", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without admin credentials configured, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/errtrack"
+	"l0_wb/internal/metrics"
+)
+
+// recoverPanic перехватывает панику в обработчике.
+//
+//	Паника логируется со стеком и идентификатором запроса, отправляется в
+//	Sentry, учитывается в метрике errors_total{type="http",operation="panic"}, а клиент получает
+//	500 в формате ошибки API. Если ответ уже начат, дописать ошибку нельзя:
+//	соединение обрывается через http.ErrAbortHandler, чтобы клиент не принял
+//	обрезанный ответ за полный.
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	Возвращает:
+//	- http.HandlerFunc: обработчик с восстановлением после паники.
+func (s *Server) recoverPanic(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+
+			metrics.RecordError("http", "panic")
+			errtrack.CapturePanic(r.Context(), p, map[string]string{
+				"component":  "http",
+				"method":     r.Method,
+				"path":       r.URL.Path,
+				"request_id": requestIDFromContext(r.Context()),
+			})
+			s.log(r).Error("Panic in HTTP handler",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("panic", fmt.Sprint(p)),
+				zap.ByteString("stack", debug.Stack()),
+			)
+
+			if rw, ok := w.(*responseWriter); ok && rw.wroteHeader {
+				panic(http.ErrAbortHandler)
+			}
+			s.writeError(w, r, newAPIError(http.StatusInternalServerError, codeInternal, "internal server error"))
+		}()
+		next(w, r)
+	}
+}
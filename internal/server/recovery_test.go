@@ -0,0 +1,32 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// TestRecoverPanic проверяет, что паника в обработчике превращается в ответ 500 в формате ошибки API.
+func TestRecoverPanic(t *testing.T) {
+	s := &Server{logger: zap.NewNop()}
+	mux := http.NewServeMux()
+	s.route(mux, "GET /boom", func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/boom", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not valid JSON: %v", err)
+	}
+	if body["code"] != codeInternal {
+		t.Errorf("unexpected body %v", body)
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/util"
+)
+
+// requestIDHeader — заголовок с идентификатором запроса.
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength ограничивает длину идентификатора, принятого от клиента.
+const maxRequestIDLength = 128
+
+// requestIDKey — ключ контекста для идентификатора запроса.
+type requestIDKey struct{}
+
+// requestIDMiddleware присваивает запросу идентификатор и логгер с этим идентификатором.
+//
+//	Идентификатор берётся из заголовка X-Request-ID, если клиент его передал и
+//	он корректен, иначе генерируется. Он возвращается в заголовке ответа и
+//	добавляется полем request_id ко всем записям логгера запроса.
+//
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	Возвращает:
+//	- http.Handler: обработчик с идентификацией запросов.
+func (s *Server) requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		ctx = util.ContextWithLogger(ctx, s.logger.With(zap.String("request_id", id)))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requestIDFromContext возвращает идентификатор запроса из контекста или пустую строку.
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// log возвращает логгер запроса с полем request_id.
+func (s *Server) log(r *http.Request) *zap.Logger {
+	return util.LoggerFromContext(r.Context(), s.logger)
+}
+
+// newRequestID генерирует случайный идентификатор запроса.
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID проверяет идентификатор, полученный от клиента.
+//
+//	Допускаются только печатные ASCII-символы, чтобы идентификатор нельзя было
+//	использовать для подделки записей в логах.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// TestRequestIDMiddleware проверяет генерацию и передачу X-Request-ID.
+func TestRequestIDMiddleware(t *testing.T) {
+	s := &Server{logger: zap.NewNop()}
+	var seen string
+	handler := s.requestIDMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = requestIDFromContext(r.Context())
+	}))
+
+	// Корректный идентификатор клиента сохраняется.
+	req := httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+	req.Header.Set(requestIDHeader, "client-id-42")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if seen != "client-id-42" || rec.Header().Get(requestIDHeader) != "client-id-42" {
+		t.Errorf("expected propagated request id, got context %q header %q", seen, rec.Header().Get(requestIDHeader))
+	}
+
+	// Некорректный идентификатор заменяется сгенерированным.
+	req = httptest.NewRequest(http.MethodGet, "/api/orders", nil)
+	req.Header.Set(requestIDHeader, "bad id\nwith newline")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if len(seen) != 32 || rec.Header().Get(requestIDHeader) != seen {
+		t.Errorf("expected generated request id, got context %q header %q", seen, rec.Header().Get(requestIDHeader))
+	}
+}
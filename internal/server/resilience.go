@@ -0,0 +1,55 @@
+package server
+
+import (
+	"math/rand/v2"
+	"net/http"
+
+	"l0_wb/internal/metrics"
+	"l0_wb/internal/sli"
+)
+
+// sliThresholds задаёт пороги SLI, общие для проверки готовности и сброса нагрузки.
+type sliThresholds struct {
+	maxErrorRate float64
+	minEvents    uint64
+}
+
+// exceeded сообщает, превышен ли порог для снимка SLI.
+func (t sliThresholds) exceeded(s sli.Snapshot) bool {
+	return s.Exceeds(t.maxErrorRate, t.minEvents)
+}
+
+// shedLoad оборачивает обработчик учётом HTTP SLI и сбросом части нагрузки.
+//
+//	Результат каждого обработанного запроса записывается в общий калькулятор SLI
+//	(ошибкой считается ответ 5xx). При включённом LOAD_SHEDDING_ENABLED и превышении
+//	доли ошибок часть запросов отклоняется с 503: вероятность отказа растёт
+//	пропорционально превышению порога. Отклонённые запросы в SLI не учитываются,
+//	иначе сброс нагрузки поддерживал бы сам себя.
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	Возвращает:
+//	- http.HandlerFunc: обработчик с учётом SLI.
+func (s *Server) shedLoad(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.loadShedding && s.shouldShed(sli.Get(sli.SourceHTTP)) {
+			metrics.RecordError("http", "load_shedding")
+			w.Header().Set("Retry-After", "1")
+			s.writeError(w, r, newAPIError(http.StatusServiceUnavailable, codeOverloaded, "service is overloaded, retry later"))
+			return
+		}
+
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next(rw, r)
+		sli.Record(sli.SourceHTTP, rw.statusCode < http.StatusInternalServerError)
+	}
+}
+
+// shouldShed решает, нужно ли отклонить запрос при текущем SLI.
+func (s *Server) shouldShed(snapshot sli.Snapshot) bool {
+	if !s.sliThresholds.exceeded(snapshot) {
+		return false
+	}
+	excess := (snapshot.ErrorRate - s.sliThresholds.maxErrorRate) / (1 - s.sliThresholds.maxErrorRate)
+	return rand.Float64() < excess //nolint:gosec // Криптостойкость для выборки запросов не требуется
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"l0_wb/internal/metrics"
+)
+
+// middleware оборачивает обработчик дополнительной логикой.
+type middleware func(http.HandlerFunc) http.HandlerFunc
+
+// route регистрирует обработчик для шаблона маршрута Go 1.22 ("GET /path/{param}").
+//
+//	Middleware применяются в порядке перечисления: первый оборачивает все
+//	остальные. Снаружи цепочки всегда добавляются восстановление после паники,
+//	метрики и профилирование; имя эндпоинта для них — путь шаблона без метода.
+//	Параметры:
+//	- mux: HTTP маршрутизатор (ServeMux).
+//	- pattern: шаблон маршрута с методом и параметрами пути.
+//	- h: обработчик.
+//	- mws: middleware маршрута.
+func (s *Server) route(mux *http.ServeMux, pattern string, h http.HandlerFunc, mws ...middleware) {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	endpoint := pattern
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		endpoint = path
+	}
+	mux.HandleFunc(pattern, s.instrument(s.recoverPanic(h), endpoint))
+}
+
+// registerRoutes регистрирует маршруты HTTP для обработки запросов.
+//
+//	Параметры:
+//	- mux: HTTP маршрутизатор (ServeMux).
+func (s *Server) registerRoutes(mux *http.ServeMux) {
+	// API версии v1
+	s.route(mux, "GET "+apiV1+"/orders/{id}", s.handleGetOrderByID, s.withTimeout, s.limitBody, s.shedLoad, s.requireAPIKey)
+	s.route(mux, "GET "+apiV1+"/orders/{id}/history", s.handleGetOrderHistory, s.withTimeout, s.limitBody, s.shedLoad, s.requireAPIKey)
+	s.route(mux, "GET "+apiV1+"/orders", s.handleGetOrders, s.withTimeout, s.limitBody, s.shedLoad, s.requireAPIKey)
+	s.route(mux, "GET "+apiV1+"/customers/{id}/orders", s.handleGetCustomerOrders, s.withTimeout, s.limitBody, s.shedLoad, s.requireAPIKey)
+	s.route(mux, "DELETE "+apiV1+"/customers/{id}/data", s.handleEraseCustomerData, s.withTimeout, s.limitBody, s.shedLoad, s.requireAPIKey)
+	s.route(mux, "GET "+apiV1+"/stats", s.handleStats, s.withTimeout, s.limitBody, s.shedLoad, s.requireAPIKey)
+	// Выгрузка пишется потоково и может быть долгой, поэтому таймаут запроса к ней не применяется
+	s.route(mux, "GET "+apiV1+"/orders/export", s.handleExportOrders, s.limitBody, s.shedLoad, s.requireAPIKey)
+	s.route(mux, "PATCH "+apiV1+"/orders/{id}/status", s.handleUpdateOrderStatus, s.withTimeout, s.limitBody, s.shedLoad, s.requireAPIKey)
+	s.route(mux, "POST "+apiV1+"/orders/bulk", s.handleBulkOrders, s.limitBodyTo(s.bulkMaxBytes), s.shedLoad, s.requireAPIKey)
+	s.route(mux, "POST "+apiV1+"/send-test-order", s.handleSendTestOrder, s.allowTestOrders, s.withTimeout, s.limitBody, s.shedLoad, s.requireAPIKey)
+
+	// Поток обработанных заказов: WebSocket и SSE для клиентов без WebSocket.
+	// Соединения долгоживущие, поэтому таймаут запроса к ним не применяется.
+	s.route(mux, "GET "+apiV1+"/ws/orders", s.handleOrdersWebSocket, s.requireAPIKey)
+	s.route(mux, "GET "+apiV1+"/orders/stream", s.handleOrdersStream, s.requireAPIKey)
+
+	// Маршруты без версии сохранены для совместимости и помечаются как устаревшие
+	s.route(mux, "GET /order/{id...}", s.handleGetOrderByID, deprecated(apiV1+"/orders/{id}"), s.withTimeout, s.limitBody, s.shedLoad, s.requireAPIKey)
+	s.route(mux, "GET /order/{id}/history", s.handleGetOrderHistory, deprecated(apiV1+"/orders/{id}/history"), s.withTimeout, s.limitBody, s.shedLoad, s.requireAPIKey)
+	s.route(mux, "GET /api/orders", s.handleGetOrders, deprecated(apiV1+"/orders"), s.withTimeout, s.limitBody, s.shedLoad, s.requireAPIKey)
+	s.route(mux, "POST /api/send-test-order", s.handleSendTestOrder, deprecated(apiV1+"/send-test-order"), s.allowTestOrders, s.withTimeout, s.limitBody, s.shedLoad, s.requireAPIKey)
+	s.route(mux, "GET /ws/orders", s.handleOrdersWebSocket, deprecated(apiV1+"/ws/orders"), s.requireAPIKey)
+	s.route(mux, "GET /api/orders/stream", s.handleOrdersStream, deprecated(apiV1+"/orders/stream"), s.requireAPIKey)
+
+	// Проверки живости и готовности; /health и /readyz сохранены для совместимости
+	s.route(mux, "GET /health/live", s.handleLive)
+	s.route(mux, "GET /health/ready", s.handleReady)
+	s.route(mux, "GET /health", s.handleLive)
+	s.route(mux, "GET /readyz", s.handleReady)
+	s.logger.Info("Health check endpoint registered")
+
+	// Сведения о сборке
+	s.route(mux, "GET /version", s.handleVersion)
+
+	// Метрики Prometheus (при METRICS_ON_MAIN_SERVER=true вместо отдельного сервера)
+	if s.metricsOnMain {
+		mux.Handle("GET /metrics", metrics.Handler())
+		s.logger.Info("Metrics endpoint registered")
+	}
+
+	// Операционные маршруты с отдельной аутентификацией
+	s.registerAdminRoutes(mux)
+
+	// Профилирование эндпоинтов (только при PROFILING_ENABLED=true)
+	s.registerProfilingRoutes(mux)
+
+	// Статический контент (index.html)
+	if s.static != nil {
+		s.route(mux, "GET /", s.handleStatic)
+		s.logger.Info("Static content route registered")
+	}
+}
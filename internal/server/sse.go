@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/dto"
+)
+
+// sseWriteTimeout — дедлайн записи одного события SSE.
+const sseWriteTimeout = 10 * time.Second
+
+// sseRetry — интервал переподключения EventSource после обрыва, в миллисекундах.
+const sseRetry = 3000
+
+// handleOrdersStream обрабатывает запросы к эндпоинту GET /api/orders/stream.
+//
+//	Отдаёт тот же поток обработанных заказов, что и /ws/orders, в формате
+//	Server-Sent Events для клиентов без поддержки WebSocket. Каждый заказ
+//	отправляется событием "order"; в паузах отправляются комментарии-heartbeat,
+//	чтобы прокси не закрывали простаивающее соединение.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleOrdersStream(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	// Отключаем буферизацию ответа в nginx.
+	h.Set("X-Accel-Buffering", "no")
+
+	// send записывает событие и сразу отправляет его клиенту.
+	send := func(event string) error {
+		// Ошибка http.ErrNotSupported означает, что обёртка не даёт управлять дедлайном; тогда действует WriteTimeout сервера.
+		_ = rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+		if _, err := fmt.Fprint(w, event); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	sub := s.feed.Subscribe()
+	defer s.feed.Unsubscribe(sub)
+
+	if err := send(fmt.Sprintf("retry: %d\n\n", sseRetry)); err != nil {
+		s.log(r).Warn("Failed to start SSE stream", zap.Error(err))
+		return
+	}
+	s.log(r).Info("SSE client connected", zap.String("remote_addr", r.RemoteAddr))
+
+	heartbeat := time.NewTicker(s.sseHeartbeat)
+	defer heartbeat.Stop()
+
+	var seq uint64
+	for {
+		select {
+		case order, ok := <-sub.Orders():
+			if !ok {
+				// Сервер останавливается: клиент переподключится через retry.
+				return
+			}
+			data, err := json.Marshal(dto.NewOrder(order))
+			if err != nil {
+				s.log(r).Error("Failed to encode order for SSE", zap.Error(err))
+				continue
+			}
+			seq++
+			if err := send(fmt.Sprintf("id: %d\nevent: order\ndata: %s\n\n", seq, data)); err != nil {
+				s.log(r).Warn("Failed to write SSE event", zap.Error(err))
+				return
+			}
+		case <-heartbeat.C:
+			if err := send(": keepalive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			s.log(r).Info("SSE client disconnected", zap.String("remote_addr", r.RemoteAddr))
+			return
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/feed"
+	"l0_wb/internal/model"
+)
+
+// TestOrdersStream проверяет отправку заказов и heartbeat в потоке SSE.
+func TestOrdersStream(t *testing.T) {
+	s := &Server{feed: feed.NewHub(8), sseHeartbeat: 50 * time.Millisecond, logger: zap.NewNop()}
+	ts := httptest.NewServer(s.instrument(s.handleOrdersStream, "/api/orders/stream"))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", ct)
+	}
+
+	// Заголовки получены после подписки, поэтому заказ уже попадёт в поток.
+	s.feed.Publish(&model.Order{OrderUID: "sse_uid"})
+
+	lines := bufio.NewScanner(resp.Body)
+	var gotOrder, gotHeartbeat bool
+	for lines.Scan() && !(gotOrder && gotHeartbeat) {
+		line := lines.Text()
+		if strings.HasPrefix(line, "data: ") && strings.Contains(line, `"order_uid":"sse_uid"`) {
+			gotOrder = true
+		}
+		if line == ": keepalive" {
+			gotHeartbeat = true
+		}
+	}
+	if !gotOrder || !gotHeartbeat {
+		t.Errorf("expected order event and heartbeat, got order=%v heartbeat=%v", gotOrder, gotHeartbeat)
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+)
+
+// Параметры запроса статистики по умолчанию и их пределы.
+const (
+	defaultStatsDays = 30
+	maxStatsDays     = 365
+	defaultStatsTop  = 5
+	maxStatsTop      = 50
+)
+
+// statsKey — параметры запроса статистики, по которым кэшируется результат.
+type statsKey struct {
+	days int
+	top  int
+}
+
+// statsEntry — закэшированный результат запроса статистики.
+type statsEntry struct {
+	stats   *model.OrderStats
+	expires time.Time
+}
+
+// statsCache кэширует результаты агрегирующих запросов на короткое время.
+//
+//	Запросы выполняются под общей блокировкой, поэтому одновременные
+//	обращения при пустом кэше не приводят к параллельным агрегациям в БД.
+type statsCache struct {
+	repo    repository.StatsRepository
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[statsKey]statsEntry
+	now     func() time.Time
+}
+
+// newStatsCache создаёт statsCache.
+//
+//	Параметры:
+//	- repo: репозиторий агрегирующих запросов.
+//	- ttl: время жизни результата (0 — не кэшировать).
+//	Возвращает:
+//	- *statsCache: кэш статистики или nil, если репозиторий не задан.
+func newStatsCache(repo repository.StatsRepository, ttl time.Duration) *statsCache {
+	if repo == nil {
+		return nil
+	}
+	return &statsCache{repo: repo, ttl: ttl, entries: make(map[statsKey]statsEntry), now: time.Now}
+}
+
+// setTTL меняет время жизни результата и сбрасывает закэшированную статистику.
+func (c *statsCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+	clear(c.entries)
+}
+
+// get возвращает статистику из кэша или вычисляет её заново.
+func (c *statsCache) get(ctx context.Context, key statsKey) (*model.OrderStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if e, ok := c.entries[key]; ok && now.Before(e.expires) {
+		return e.stats, nil
+	}
+	stats, err := c.compute(ctx, key, now)
+	if err != nil {
+		return nil, err
+	}
+	if c.ttl > 0 {
+		c.entries[key] = statsEntry{stats: stats, expires: now.Add(c.ttl)}
+	}
+	return stats, nil
+}
+
+// compute выполняет агрегирующие запросы.
+func (c *statsCache) compute(ctx context.Context, key statsKey, now time.Time) (*model.OrderStats, error) {
+	stats := &model.OrderStats{GeneratedAt: now.UTC()}
+	var err error
+	since := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -(key.days - 1))
+	if stats.OrdersPerDay, err = c.repo.OrdersPerDay(ctx, since); err != nil {
+		return nil, fmt.Errorf("orders per day: %w", err)
+	}
+	if stats.AmountByCurrency, err = c.repo.AmountByCurrency(ctx); err != nil {
+		return nil, fmt.Errorf("amount by currency: %w", err)
+	}
+	if stats.TopDeliveryServices, err = c.repo.TopDeliveryServices(ctx, key.top); err != nil {
+		return nil, fmt.Errorf("top delivery services: %w", err)
+	}
+	if stats.AvgItemsPerOrder, err = c.repo.AvgItemsPerOrder(ctx); err != nil {
+		return nil, fmt.Errorf("average items per order: %w", err)
+	}
+	return stats, nil
+}
+
+// handleStats возвращает сводную статистику по заказам: GET /api/v1/stats.
+//
+//	Параметры запроса: days — глубина ряда заказов по дням (1..365, по умолчанию 30),
+//	top — число служб доставки в рейтинге (1..50, по умолчанию 5).
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if s.stats == nil {
+		s.writeError(w, r, newAPIError(http.StatusServiceUnavailable, codeUnavailable, "statistics are not available"))
+		return
+	}
+	q := r.URL.Query()
+	days, err := intParam(q.Get("days"), "days", defaultStatsDays, 1, maxStatsDays)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	top, err := intParam(q.Get("top"), "top", defaultStatsTop, 1, maxStatsTop)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	stats, err := s.stats.get(r.Context(), statsKey{days: days, top: top})
+	if err != nil {
+		s.log(r).Error("Failed to compute order statistics", zap.Error(err))
+		s.writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		s.log(r).Error("Failed to encode statistics response", zap.Error(err))
+	}
+}
+
+// intParam разбирает целочисленный параметр запроса.
+//
+//	Параметры:
+//	- raw: значение параметра.
+//	- name: имя параметра для сообщения об ошибке.
+//	- def: значение по умолчанию для пустого параметра.
+//	- minVal, maxVal: допустимые границы.
+//	Возвращает:
+//	- int: значение параметра.
+//	- error: *apiError, если значение некорректно.
+func intParam(raw, name string, def, minVal, maxVal int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < minVal || v > maxVal {
+		return 0, newAPIErrorf(http.StatusBadRequest, codeBadRequest, "%s must be between %d and %d", name, minVal, maxVal).
+			withDetails(map[string]any{"parameter": name, "min": minVal, "max": maxVal})
+	}
+	return v, nil
+}
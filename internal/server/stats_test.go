@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/model"
+)
+
+// fakeStatsRepository считает обращения к агрегирующим запросам.
+type fakeStatsRepository struct {
+	calls int
+	since time.Time
+}
+
+func (f *fakeStatsRepository) OrdersPerDay(_ context.Context, since time.Time) ([]model.DayCount, error) {
+	f.calls++
+	f.since = since
+	return []model.DayCount{{Day: since.Format(time.DateOnly), Orders: 3}}, nil
+}
+
+func (f *fakeStatsRepository) AmountByCurrency(context.Context) ([]model.CurrencyAmount, error) {
+	return []model.CurrencyAmount{{Currency: "USD", Amount: 1817}}, nil
+}
+
+func (f *fakeStatsRepository) TopDeliveryServices(_ context.Context, limit int) ([]model.DeliveryServiceCount, error) {
+	return []model.DeliveryServiceCount{{DeliveryService: "meest", Orders: 3}}[:min(limit, 1)], nil
+}
+
+func (f *fakeStatsRepository) AvgItemsPerOrder(context.Context) (float64, error) {
+	return 1.5, nil
+}
+
+// TestStatsCache проверяет, что статистика кэшируется до истечения TTL.
+func TestStatsCache(t *testing.T) {
+	repo := &fakeStatsRepository{}
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	c := newStatsCache(repo, time.Minute)
+	c.now = func() time.Time { return now }
+	key := statsKey{days: 7, top: 5}
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.get(context.Background(), key); err != nil {
+			t.Fatalf("get failed: %v", err)
+		}
+	}
+	if repo.calls != 1 {
+		t.Errorf("expected 1 aggregation, got %d", repo.calls)
+	}
+	if want := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC); !repo.since.Equal(want) {
+		t.Errorf("expected period start %v, got %v", want, repo.since)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := c.get(context.Background(), key); err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if repo.calls != 2 {
+		t.Errorf("expected recomputation after TTL, got %d aggregations", repo.calls)
+	}
+}
+
+// TestHandleStatsValidation проверяет отказ для параметров вне допустимых границ.
+func TestHandleStatsValidation(t *testing.T) {
+	s := &Server{stats: newStatsCache(&fakeStatsRepository{}, time.Minute), logger: zap.NewNop()}
+	for _, query := range []string{"?days=0", "?days=1000", "?top=abc"} {
+		rec := httptest.NewRecorder()
+		s.handleStats(rec, httptest.NewRequest(http.MethodGet, "/api/v1/stats"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rec.Code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	s.handleStats(rec, httptest.NewRequest(http.MethodGet, "/api/v1/stats", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/dto"
+	"l0_wb/internal/model"
+)
+
+// statusRequest — тело запроса на изменение статуса заказа.
+type statusRequest struct {
+	Status model.OrderStatus `json:"status"`
+}
+
+// handleUpdateOrderStatus переводит заказ в новый статус: PATCH /api/v1/orders/{id}/status.
+//
+//	Тело запроса — {"status": "<статус>"}. Недопустимый переход отклоняется
+//	с кодом 409 и списком статусов, доступных из текущего. В ответе — заказ
+//	в новом статусе; он же обновляется в кэше.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleUpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	if !s.requireOrderStorage(w, r) {
+		return
+	}
+	orderID := r.PathValue("id")
+
+	var req statusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if !errors.As(err, new(*http.MaxBytesError)) {
+			err = newAPIErrorf(http.StatusBadRequest, codeBadRequest, "invalid status payload: %v", err)
+		}
+		s.writeError(w, r, err)
+		return
+	}
+
+	order, err := s.orders.UpdateStatus(r.Context(), orderID, req.Status)
+	if err != nil {
+		s.log(r).Warn("Failed to update order status",
+			zap.String("order_uid", orderID),
+			zap.String("status", string(req.Status)),
+			zap.Error(err),
+		)
+		s.writeError(w, r, err)
+		return
+	}
+	s.cache.Set(order)
+	s.writeJSON(w, r, dto.NewOrder(order))
+}
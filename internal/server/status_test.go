@@ -0,0 +1,62 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/model"
+	"l0_wb/internal/util"
+)
+
+// TestUpdateOrderStatus проверяет переход между статусами и отклонение недопустимых переходов.
+func TestUpdateOrderStatus(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	svc := &fakeOrderService{customerOrders: []*model.Order{{OrderUID: "a", Status: model.StatusCreated}}}
+	s := &Server{cache: cache.NewOrderCache(), orders: svc, logger: zap.NewNop()}
+
+	patch := func(orderUID, body string) (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/orders/"+orderUID+"/status", strings.NewReader(body))
+		req.SetPathValue("id", orderUID)
+		rec := httptest.NewRecorder()
+		s.handleUpdateOrderStatus(rec, req)
+
+		var resp map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response: %v", err)
+		}
+		return rec.Code, resp
+	}
+
+	if code, resp := patch("a", `{"status":"paid"}`); code != http.StatusOK || resp["status"] != "paid" {
+		t.Fatalf("expected order to be paid, got %d %v", code, resp)
+	}
+	if o := s.cache.Get("a"); o == nil || o.Status != model.StatusPaid {
+		t.Errorf("cache not updated: %+v", o)
+	}
+
+	code, resp := patch("a", `{"status":"delivered"}`)
+	if code != http.StatusConflict || resp["code"] != codeInvalidStatus {
+		t.Fatalf("expected 409 for paid -> delivered, got %d %v", code, resp)
+	}
+	details, _ := resp["details"].(map[string]any)
+	if allowed, _ := details["allowed"].([]any); len(allowed) != 2 || allowed[0] != "shipped" {
+		t.Errorf("unexpected details %v", details)
+	}
+
+	if code, _ := patch("a", `{"status":"lost"}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown status, got %d", code)
+	}
+	if code, _ := patch("a", `{`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid body, got %d", code)
+	}
+	if code, _ := patch("missing", `{"status":"paid"}`); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown order, got %d", code)
+	}
+}
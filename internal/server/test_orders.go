@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"l0_wb/internal/dto"
+	"l0_wb/internal/model"
+	"l0_wb/internal/service"
+)
+
+// allowTestOrders ограничивает доступ к отправке тестовых заказов.
+//
+//	Тестовые заказы создают в Kafka и БД фиктивные данные, поэтому вне
+//	окружения разработки (APP_ENV=dev) их может отправлять только администратор.
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	Возвращает:
+//	- http.HandlerFunc: обработчик с проверкой окружения или прав администратора.
+func (s *Server) allowTestOrders(next http.HandlerFunc) http.HandlerFunc {
+	if s.testOrders {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.admin == nil {
+			s.writeError(w, r, newAPIError(http.StatusForbidden, codeForbidden, "test orders are disabled outside the dev environment"))
+			return
+		}
+		s.requireAdmin(next)(w, r)
+	}
+}
+
+// decodeTestOrder читает заказ из тела запроса.
+//
+//	Параметры:
+//	- r: HTTP-запрос.
+//	Возвращает:
+//	- *model.Order: заказ или nil, если тело пустое.
+//	- error: *apiError для некорректного JSON, *service.ValidationError для
+//	  некорректного заказа или ошибку чтения тела.
+func decodeTestOrder(r *http.Request) (*model.Order, error) {
+	var in dto.Order
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if errors.As(err, new(*http.MaxBytesError)) {
+			return nil, err
+		}
+		return nil, newAPIErrorf(http.StatusBadRequest, codeBadRequest, "invalid order payload: %v", err)
+	}
+	order := in.ToModel()
+	if err := service.ValidateOrder(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+)
+
+// TestAllowTestOrders проверяет, что вне окружения dev тестовые заказы доступны только администратору.
+func TestAllowTestOrders(t *testing.T) {
+	ok := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	tests := []struct {
+		name   string
+		server *Server
+		auth   string
+		status int
+	}{
+		{"dev", &Server{testOrders: true}, "", http.StatusOK},
+		{"production without admin", &Server{}, "", http.StatusForbidden},
+		{"production anonymous", &Server{admin: newAdminAuth(&config.Config{AdminToken: "t"})}, "", http.StatusUnauthorized},
+		{"production admin", &Server{admin: newAdminAuth(&config.Config{AdminToken: "t"})}, "Bearer t", http.StatusOK},
+	}
+	for _, tt := range tests {
+		tt.server.logger = zap.NewNop()
+		tt.server.audit = zap.NewNop()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/send-test-order", nil)
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		rec := httptest.NewRecorder()
+		tt.server.allowTestOrders(ok)(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.status, rec.Code)
+		}
+	}
+}
+
+// TestDecodeTestOrder проверяет разбор необязательного заказа из тела запроса.
+func TestDecodeTestOrder(t *testing.T) {
+	order, err := decodeTestOrder(httptest.NewRequest(http.MethodPost, "/", nil))
+	if order != nil || err != nil {
+		t.Errorf("expected random order for empty body, got %v, %v", order, err)
+	}
+
+	body := `{"order_uid":"custom","delivery":{"name":"Test","phone":"+9720000000","address":"Street 1"},"items":[{"chrt_id":1}]}`
+	order, err = decodeTestOrder(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	if err != nil || order == nil || order.OrderUID != "custom" {
+		t.Errorf("expected custom order, got %v, %v", order, err)
+	}
+
+	if _, err := decodeTestOrder(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"order_uid":""}`))); mapError(err).Status != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid order, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme/autocert"
+	"l0_wb/internal/config"
+)
+
+// tlsSettings описывает, как сервер получает сертификат для HTTPS.
+type tlsSettings struct {
+	certFile string
+	keyFile  string
+	manager  *autocert.Manager
+}
+
+// newTLSSettings создаёт параметры TLS из конфигурации.
+//
+//	Параметры:
+//	- cfg: конфигурация приложения.
+//	Возвращает:
+//	- *tlsSettings: параметры TLS или nil, если сервер работает по HTTP.
+func newTLSSettings(cfg *config.Config) *tlsSettings {
+	switch {
+	case cfg.TLSCertFile != "":
+		return &tlsSettings{certFile: cfg.TLSCertFile, keyFile: cfg.TLSKeyFile}
+	case len(cfg.TLSAutocertDomains) > 0:
+		return &tlsSettings{manager: &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(cfg.TLSAutocertCacheDir),
+			HostPolicy: autocert.HostWhitelist(cfg.TLSAutocertDomains...),
+			Email:      cfg.TLSAutocertEmail,
+		}}
+	default:
+		return nil
+	}
+}
+
+// tlsConfig возвращает конфигурацию TLS для http.Server.
+func (t *tlsSettings) tlsConfig() *tls.Config {
+	if t.manager != nil {
+		return t.manager.TLSConfig()
+	}
+	return &tls.Config{MinVersion: tls.VersionTLS12}
+}
+
+// listenAndServe запускает сервер по HTTP или HTTPS в зависимости от конфигурации.
+func (s *Server) listenAndServe() error {
+	if s.tls == nil {
+		return s.httpServer.ListenAndServe()
+	}
+	// Для autocert сертификат выдаётся через TLSConfig.GetCertificate, пути к файлам пустые
+	return s.httpServer.ListenAndServeTLS(s.tls.certFile, s.tls.keyFile)
+}
+
+// newRedirectServer создаёт HTTP-сервер, перенаправляющий запросы на HTTPS.
+//
+//	При использовании autocert этот же сервер отвечает на проверки ACME HTTP-01.
+//
+//	Параметры:
+//	- redirectPort: порт HTTP, который слушает сервер перенаправлений.
+//	- httpsPort: порт HTTPS, на который перенаправляются запросы.
+//	Возвращает:
+//	- *http.Server: сервер перенаправлений.
+func (t *tlsSettings) newRedirectServer(redirectPort, httpsPort string) *http.Server {
+	var handler http.Handler = redirectToHTTPS(httpsPort)
+	if t.manager != nil {
+		handler = t.manager.HTTPHandler(handler)
+	}
+	return &http.Server{
+		Addr:         ":" + redirectPort,
+		Handler:      handler,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 5 * time.Second,
+		IdleTimeout:  10 * time.Second,
+	}
+}
+
+// redirectToHTTPS возвращает обработчик, перенаправляющий запрос на тот же адрес по HTTPS.
+func redirectToHTTPS(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
+
+// configureTLS включает TLS на основном сервере и создаёт сервер перенаправлений.
+func (s *Server) configureTLS(cfg *config.Config) {
+	s.tls = newTLSSettings(cfg)
+	if s.tls == nil {
+		if cfg.HTTPRedirectPort != "" {
+			s.logger.Warn("HTTP_REDIRECT_PORT is ignored because TLS is not configured")
+		}
+		return
+	}
+	s.httpServer.TLSConfig = s.tls.tlsConfig()
+	if cfg.HTTPRedirectPort != "" {
+		s.redirectServer = s.tls.newRedirectServer(cfg.HTTPRedirectPort, cfg.HTTPPort)
+	}
+	s.logger.Info("HTTPS enabled",
+		zap.Bool("autocert", s.tls.manager != nil),
+		zap.String("redirect_port", cfg.HTTPRedirectPort),
+	)
+}
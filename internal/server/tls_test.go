@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+)
+
+// TestRedirectToHTTPS проверяет, что перенаправление сохраняет путь и строку запроса и подставляет порт HTTPS.
+func TestRedirectToHTTPS(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort string
+		host      string
+		target    string
+		want      string
+	}{
+		{name: "non-default port", httpsPort: "8443", host: "orders.example:8080", target: "/api/v1/orders?limit=10&status=paid",
+			want: "https://orders.example:8443/api/v1/orders?limit=10&status=paid"},
+		{name: "default port omitted", httpsPort: "443", host: "orders.example:80", target: "/order/b563feb7b2b84b6test",
+			want: "https://orders.example/order/b563feb7b2b84b6test"},
+		{name: "host without port", httpsPort: "8443", host: "orders.example", target: "/",
+			want: "https://orders.example:8443/"},
+		{name: "IPv6 host", httpsPort: "8443", host: "[::1]:8080", target: "/health/live",
+			want: "https://[::1]:8443/health/live"},
+		{name: "escaped path", httpsPort: "443", host: "orders.example", target: "/api/v1/orders/a%2Fb?q=%20x",
+			want: "https://orders.example/api/v1/orders/a%2Fb?q=%20x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+			redirectToHTTPS(tt.httpsPort).ServeHTTP(rec, req)
+
+			// 308 сохраняет метод и тело запроса
+			if rec.Code != http.StatusPermanentRedirect {
+				t.Fatalf("expected 308, got %d", rec.Code)
+			}
+			if got := rec.Header().Get("Location"); got != tt.want {
+				t.Errorf("expected Location %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestConfigureTLS проверяет выбор источника сертификата и создание сервера перенаправлений.
+func TestConfigureTLS(t *testing.T) {
+	newServer := func() *Server {
+		return &Server{httpServer: &http.Server{}, logger: zap.NewNop()}
+	}
+
+	// Без TLS порт перенаправлений игнорируется
+	s := newServer()
+	s.configureTLS(&config.Config{HTTPPort: "8081", HTTPRedirectPort: "8080"})
+	if s.tls != nil || s.redirectServer != nil || s.httpServer.TLSConfig != nil {
+		t.Error("expected plain HTTP without TLS settings")
+	}
+
+	// Сертификат из файлов
+	s = newServer()
+	s.configureTLS(&config.Config{HTTPPort: "8443", HTTPRedirectPort: "8080", TLSCertFile: "tls.crt", TLSKeyFile: "tls.key"})
+	if s.tls == nil || s.tls.certFile != "tls.crt" || s.tls.keyFile != "tls.key" || s.tls.manager != nil {
+		t.Fatalf("unexpected TLS settings: %+v", s.tls)
+	}
+	if s.httpServer.TLSConfig == nil || s.httpServer.TLSConfig.MinVersion == 0 {
+		t.Error("expected TLS config with minimum version")
+	}
+	if s.redirectServer == nil || s.redirectServer.Addr != ":8080" {
+		t.Fatalf("expected redirect server on :8080, got %+v", s.redirectServer)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/orders?id=1", nil)
+	req.Host = "orders.example:8080"
+	rec := httptest.NewRecorder()
+	s.redirectServer.Handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Location"); got != "https://orders.example:8443/orders?id=1" {
+		t.Errorf("unexpected redirect %q", got)
+	}
+
+	// Autocert: сервер перенаправлений отвечает на проверки ACME HTTP-01
+	s = newServer()
+	s.configureTLS(&config.Config{HTTPPort: "443", HTTPRedirectPort: "80", TLSAutocertDomains: []string{"orders.example.com"}, TLSAutocertCacheDir: t.TempDir()})
+	if s.tls == nil || s.tls.manager == nil || s.httpServer.TLSConfig.GetCertificate == nil {
+		t.Fatalf("expected autocert TLS settings, got %+v", s.tls)
+	}
+	if err := s.tls.manager.HostPolicy(context.Background(), "other.example.com"); err == nil {
+		t.Error("expected autocert to reject hosts outside TLS_AUTOCERT_DOMAINS")
+	}
+	req = httptest.NewRequest(http.MethodGet, "/.well-known/acme-challenge/token", nil)
+	req.Host = "orders.example.com"
+	rec = httptest.NewRecorder()
+	s.redirectServer.Handler.ServeHTTP(rec, req)
+	if rec.Code == http.StatusPermanentRedirect {
+		t.Error("expected ACME challenge to be served instead of redirected")
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"l0_wb/internal/tracing"
+	"l0_wb/internal/util"
+)
+
+// traceMiddleware начинает серверный спан запроса.
+//
+//	Родительский контекст трассировки берётся из заголовков запроса
+//	(traceparent), имя спана — метод и шаблон маршрута, чтобы число имён
+//	оставалось ограниченным. Если спан записывается, его trace_id добавляется
+//	к записям логгера запроса. Ответы 5xx отмечают спан ошибкой.
+//
+//	Параметры:
+//	- next: следующий обработчик в цепочке.
+//	- endpoint: шаблон маршрута.
+//	Возвращает:
+//	- http.HandlerFunc: обработчик с трассировкой.
+func (s *Server) traceMiddleware(next http.HandlerFunc, endpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Start(ctx, r.Method+" "+endpoint,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.HTTPRoute(endpoint),
+				semconv.URLPath(r.URL.Path),
+			),
+		)
+		defer span.End()
+		if sc := span.SpanContext(); sc.IsValid() && span.IsRecording() {
+			ctx = util.ContextWithLogger(ctx, util.LoggerFromContext(ctx, s.logger).With(zap.String("trace_id", sc.TraceID().String())))
+		}
+
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next(rw, r.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPResponseStatusCode(rw.statusCode))
+		if rw.statusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rw.statusCode))
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/buildinfo"
+)
+
+// handleVersion обрабатывает запросы к эндпоинту /version.
+//
+//	Возвращает версию, коммит и время сборки запущенного экземпляра, чтобы
+//	можно было определить, какая сборка развёрнута в окружении.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(buildinfo.Get()); err != nil {
+		s.log(r).Error("Failed to encode version response", zap.Error(err))
+	}
+}
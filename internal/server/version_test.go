@@ -0,0 +1,34 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/buildinfo"
+)
+
+// TestHandleVersion проверяет, что /version отдаёт сведения о сборке.
+func TestHandleVersion(t *testing.T) {
+	defer func(v string) { buildinfo.Version = v }(buildinfo.Version)
+	buildinfo.Version = "v1.2.3"
+
+	s := &Server{logger: zap.NewNop()}
+	mux := http.NewServeMux()
+	s.route(mux, "GET /version", s.handleVersion)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var info buildinfo.Info
+	if err := json.NewDecoder(rec.Body).Decode(&info); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if info.Version != "v1.2.3" || info.Commit == "" || info.GoVersion == "" {
+		t.Errorf("unexpected build info %+v", info)
+	}
+}
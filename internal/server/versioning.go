@@ -0,0 +1,33 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// apiV1 — префикс первой версии API.
+//
+//	Форма ответов внутри версии не меняется; несовместимые изменения
+//	(например, представление денежных сумм) выпускаются под новым префиксом.
+const apiV1 = "/api/v1"
+
+// deprecated помечает маршрут без версии как устаревший.
+//
+//	Обработчик не меняется, но в ответ добавляются заголовки Deprecation
+//	(RFC 9745) и Link с адресом того же ресурса в актуальной версии API, чтобы
+//	клиенты могли найти замену до удаления маршрута.
+//	Параметры:
+//	- successor: путь в актуальной версии; {id} заменяется значением из запроса.
+//	Возвращает:
+//	- middleware: middleware с заголовками устаревания.
+func deprecated(successor string) middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			link := strings.ReplaceAll(successor, "{id}", r.PathValue("id"))
+			h := w.Header()
+			h.Set("Deprecation", "true")
+			h.Set("Link", "<"+link+`>; rel="successor-version"`)
+			next(w, r)
+		}
+	}
+}
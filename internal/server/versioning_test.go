@@ -0,0 +1,49 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/model"
+	"l0_wb/internal/util"
+)
+
+// TestVersionedRoutes проверяет, что старые маршруты отдают тот же ресурс с заголовками устаревания.
+func TestVersionedRoutes(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	orderCache := cache.NewOrderCache()
+	orderCache.Set(&model.Order{OrderUID: "b563feb7b2b84b6test"})
+
+	s := &Server{cache: orderCache, logger: zap.NewNop()}
+	mux := http.NewServeMux()
+	s.registerRoutes(mux)
+
+	tests := []struct {
+		path       string
+		deprecated bool
+	}{
+		{"/api/v1/orders/b563feb7b2b84b6test", false},
+		{"/order/b563feb7b2b84b6test", true},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", tt.path, rec.Code)
+		}
+		if got := rec.Header().Get("Deprecation") != ""; got != tt.deprecated {
+			t.Errorf("%s: expected deprecated=%v, got %v", tt.path, tt.deprecated, got)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/order/b563feb7b2b84b6test", nil))
+	if link := rec.Header().Get("Link"); link != `</api/v1/orders/b563feb7b2b84b6test>; rel="successor-version"` {
+		t.Errorf("unexpected successor link %q", link)
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"l0_wb/internal/dto"
+)
+
+// Параметры соединения WebSocket потока заказов.
+const (
+	wsWriteTimeout = 10 * time.Second
+	wsPongTimeout  = 60 * time.Second
+	wsPingInterval = wsPongTimeout * 9 / 10
+)
+
+// newUpgrader создаёт Upgrader, принимающий соединения с того же источника
+// и с источников, разрешённых политикой CORS.
+func (s *Server) newUpgrader() *websocket.Upgrader {
+	return &websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 4096,
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return true
+			}
+			if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+				return true
+			}
+			return s.cors != nil && s.cors.allowed(origin)
+		},
+	}
+}
+
+// handleOrdersWebSocket обрабатывает запросы к эндпоинту /ws/orders.
+//
+//	После установки соединения клиент получает каждый заказ, обработанный
+//	Kafka-консьюмером, отдельным текстовым сообщением JSON. Сервер периодически
+//	отправляет ping и закрывает соединение, если клиент перестал отвечать.
+//	Параметры:
+//	- w: HTTP-ответ.
+//	- r: HTTP-запрос.
+func (s *Server) handleOrdersWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade уже отправил клиенту ответ с ошибкой.
+		s.log(r).Warn("Failed to upgrade websocket connection", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	sub := s.feed.Subscribe()
+	defer s.feed.Unsubscribe(sub)
+	s.log(r).Info("Websocket client connected", zap.String("remote_addr", r.RemoteAddr))
+
+	// Читаем входящие сообщения только для обработки pong и закрытия соединения клиентом.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		_ = conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case order, ok := <-sub.Orders():
+			_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if !ok {
+				// Сервер останавливается.
+				_ = conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+				return
+			}
+			if err := conn.WriteJSON(dto.NewOrder(order)); err != nil {
+				s.log(r).Warn("Failed to write order to websocket", zap.Error(err))
+				return
+			}
+		case <-ping.C:
+			_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			s.log(r).Info("Websocket client disconnected", zap.String("remote_addr", r.RemoteAddr))
+			return
+		}
+	}
+}
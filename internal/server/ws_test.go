@@ -0,0 +1,93 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+	"l0_wb/internal/feed"
+	"l0_wb/internal/model"
+)
+
+// TestOrdersWebSocket проверяет доставку заказов из потока клиенту WebSocket.
+func TestOrdersWebSocket(t *testing.T) {
+	s := &Server{feed: feed.NewHub(8), logger: zap.NewNop()}
+	s.upgrader = s.newUpgrader()
+	ts := httptest.NewServer(s.instrument(s.handleOrdersWebSocket, "/ws/orders"))
+	defer ts.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to dial websocket: %v", err)
+	}
+	defer conn.Close()
+
+	// Подписка оформляется после установки соединения, поэтому ждём её появления.
+	deadline := time.Now().Add(2 * time.Second)
+	var got model.Order
+	_ = conn.SetReadDeadline(deadline)
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				s.feed.Publish(&model.Order{OrderUID: "live_uid"})
+			}
+		}
+	}()
+	err = conn.ReadJSON(&got)
+	close(stop)
+	if err != nil {
+		t.Fatalf("failed to read order: %v", err)
+	}
+	if got.OrderUID != "live_uid" {
+		t.Errorf("expected live_uid, got %q", got.OrderUID)
+	}
+
+	// При закрытии потока сервер закрывает соединение.
+	s.feed.Close()
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+				t.Errorf("expected going away close, got %v", err)
+			}
+			break
+		}
+	}
+}
+
+// TestOrdersWebSocketRequiresAPIKey проверяет, что поток заказов по WebSocket доступен только с API-ключом.
+func TestOrdersWebSocketRequiresAPIKey(t *testing.T) {
+	cfg := &config.Config{APIKeys: []config.APIKeyConfig{{Name: "client", Key: "secret"}}}
+	s := &Server{feed: feed.NewHub(8), auth: newAPIKeyAuth(cfg, nil, zap.NewNop()), logger: zap.NewNop()}
+	s.upgrader = s.newUpgrader()
+	mux := http.NewServeMux()
+	s.registerRoutes(mux)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	defer s.feed.Close()
+
+	for _, path := range []string{"/api/v1/ws/orders", "/ws/orders"} {
+		url := "ws" + strings.TrimPrefix(ts.URL, "http") + path
+		if _, resp, err := websocket.DefaultDialer.Dial(url, nil); err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 without api key, got %v", path, err)
+		}
+		if _, resp, err := websocket.DefaultDialer.Dial(url, http.Header{apiKeyHeader: {"wrong"}}); err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 for unknown api key, got %v", path, err)
+		}
+		conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{apiKeyHeader: {"secret"}})
+		if err != nil {
+			t.Fatalf("%s: failed to dial with api key: %v", path, err)
+		}
+		conn.Close()
+	}
+}
@@ -0,0 +1,28 @@
+package service
+
+import "context"
+
+// SystemActor — исполнитель изменений, для которых в контексте не указан иной.
+const SystemActor = "system"
+
+// actorKey — ключ контекста для исполнителя изменений.
+type actorKey struct{}
+
+// WithActor возвращает контекст, в котором изменения заказов записываются в журнал от имени actor.
+//
+//	Параметры:
+//	- ctx: исходный контекст.
+//	- actor: исполнитель, например kafka:orders, api_key:partner или admin:alice.
+//	Возвращает:
+//	- context.Context: контекст с исполнителем.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает исполнителя изменений из контекста или SystemActor.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
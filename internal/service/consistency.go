@@ -0,0 +1,27 @@
+package service
+
+import "l0_wb/internal/model"
+
+// AmountMismatch сверяет сумму оплаты заказа с товарами и доставкой.
+//
+//	Ожидаемая сумма — sum(items.total_price) + payment.delivery_cost.
+//	Заказ с расхождением не отклоняется: сервис сохраняет его с отметкой
+//	model.Order.AmountMismatch и учитывает в метрике order_amount_mismatches_total.
+//
+// Параметры:
+// - order: заказ.
+// - tolerance: допустимое расхождение в единицах суммы оплаты.
+//
+// Возвращает:
+// - int: payment.amount минус ожидаемая сумма или 0, если расхождение не превышает tolerance.
+func AmountMismatch(order *model.Order, tolerance int) int {
+	expected := order.Payment.DeliveryCost
+	for _, item := range order.Items {
+		expected += item.TotalPrice
+	}
+	diff := order.Payment.Amount - expected
+	if diff <= tolerance && diff >= -tolerance {
+		return 0
+	}
+	return diff
+}
@@ -0,0 +1,27 @@
+package service
+
+import "testing"
+
+// TestAmountMismatch проверяет сверку суммы оплаты с товарами и доставкой с учётом допуска.
+func TestAmountMismatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    int
+		tolerance int
+		want      int
+	}{
+		{"consistent", 1817, 0, 0},
+		{"overpaid", 1820, 0, 3},
+		{"underpaid", 1800, 0, -17},
+		{"within tolerance", 1820, 5, 0},
+		{"beyond tolerance", 1800, 5, -17},
+	}
+
+	for _, tt := range tests {
+		order := validOrder()
+		order.Payment.Amount = tt.amount
+		if got := AmountMismatch(order, tt.tolerance); got != tt.want {
+			t.Errorf("%s: expected mismatch %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+)
+
+// erasedDeliveryFields — поля доставки с персональными данными получателя, которые
+// удаляет EraseCustomerData (см. DeliveriesRepository.EraseByCustomerID).
+var erasedDeliveryFields = []string{"delivery.name", "delivery.phone", "delivery.zip", "delivery.address", "delivery.email"}
+
+// EraseCustomerData удаляет персональные данные получателя из всех заказов покупателя.
+//
+//	В одной транзакции имя, телефон, индекс, адрес и email получателя
+//	заменяются пустыми строками во всех заказах покупателя, включая мягко
+//	удалённые, те же поля удаляются из журнала изменений этих заказов, а в
+//	журнал каждого заказа добавляется запись model.ChangeErase с исполнителем
+//	из контекста. Уже опубликованные события об изменении заказов не отзываются.
+//
+//	Параметры:
+//	- ctx: контекст выполнения; исполнитель изменения берётся из него.
+//	- customerID: идентификатор покупателя.
+//	Возвращает:
+//	- []*model.Order: заказы покупателя после удаления данных.
+//	- error: pgx.ErrNoRows, если у покупателя нет заказов, или ошибку запроса.
+func (s *orderService) EraseCustomerData(ctx context.Context, customerID string) ([]*model.Order, error) {
+	var orders []*model.Order
+	err := s.tx.WithinTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		uids, err := repos.Deliveries.EraseByCustomerID(ctx, customerID)
+		if err != nil {
+			return err
+		}
+		if len(uids) == 0 {
+			return pgx.ErrNoRows
+		}
+		if _, err := repos.Events.EraseDeliveryPII(ctx, uids); err != nil {
+			return err
+		}
+
+		diff := make(map[string]any, len(erasedDeliveryFields))
+		for _, field := range erasedDeliveryFields {
+			diff[field] = ""
+		}
+		actor, now := ActorFromContext(ctx), time.Now().UTC()
+		changes := make([]model.OrderChange, 0, len(uids))
+		for _, uid := range uids {
+			changes = append(changes, model.OrderChange{
+				OrderUID:   uid,
+				Action:     model.ChangeErase,
+				Actor:      actor,
+				OccurredAt: now,
+				Diff:       diff,
+			})
+		}
+		if err := repos.Events.InsertMany(ctx, changes); err != nil {
+			return err
+		}
+
+		orders = make([]*model.Order, 0, len(uids))
+		for _, uid := range uids {
+			order, err := loadOrder(ctx, repos, uid)
+			if err != nil {
+				return err
+			}
+			orders = append(orders, order)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.log(ctx).Info("Customer personal data erased",
+		zap.String("customer_id", customerID),
+		zap.Int("orders", len(orders)),
+		zap.String("actor", ActorFromContext(ctx)),
+	)
+	return orders, nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/events"
+	"l0_wb/internal/metrics"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/util"
+)
+
+// Deduplicator отсеивает повторно полученные заказы, содержимое которых не изменилось.
+//
+//	Для каждого сохранённого заказа запоминается контрольная сумма его
+//	содержимого (events.Checksum). Если заказ с той же суммой приходит снова в
+//	пределах окна, SaveBatch отмечает его как SaveStatusDuplicate, не открывая
+//	транзакцию и не читая заказ из БД. Заказ с другой суммой или вне окна
+//	сохраняется обычным путём и при изменении содержимого обновляется.
+type Deduplicator struct {
+	store  repository.OrderDedupRepository
+	window time.Duration
+	now    func() time.Time
+	logger *zap.Logger
+}
+
+// NewDeduplicator создает дедупликатор заказов.
+//
+//	Параметры:
+//	- store: хранилище контрольных сумм сохранённых заказов.
+//	- window: время, в течение которого повтор заказа пропускается.
+//	Возвращает:
+//	- *Deduplicator: экземпляр дедупликатора.
+func NewDeduplicator(store repository.OrderDedupRepository, window time.Duration) *Deduplicator {
+	return &Deduplicator{
+		store:  store,
+		window: window,
+		now:    time.Now,
+		logger: util.GetLogger(),
+	}
+}
+
+// WithDeduplicator задаёт дедупликатор, с которым сверяются заказы перед сохранением.
+func WithDeduplicator(d *Deduplicator) Option {
+	return func(s *orderService) {
+		s.dedup = d
+	}
+}
+
+// check вычисляет контрольные суммы заказов и находит среди них уже сохранённые в пределах окна.
+//
+//	Сбой хранилища не мешает сохранению: заказы просто проходят обычным путём.
+//
+//	Параметры:
+//	- orders: корректные заказы без повторов order_uid.
+//	Возвращает:
+//	- map[string]string: контрольные суммы заказов по order_uid.
+//	- map[string]bool: order_uid заказов, которые можно пропустить.
+func (d *Deduplicator) check(ctx context.Context, orders []*model.Order) (map[string]string, map[string]bool) {
+	hashes := make(map[string]string, len(orders))
+	uids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		sum, err := events.Checksum(order)
+		if err != nil {
+			continue
+		}
+		hashes[order.OrderUID] = sum
+		uids = append(uids, order.OrderUID)
+	}
+
+	recent, err := d.store.ListRecent(ctx, uids, d.now().Add(-d.window))
+	if err != nil {
+		util.LoggerFromContext(ctx, d.logger).Warn("Dedup: lookup failed, saving orders without deduplication", zap.Error(err))
+		return hashes, nil
+	}
+	skip := make(map[string]bool, len(recent))
+	for uid, sum := range recent {
+		if hashes[uid] == sum {
+			skip[uid] = true
+		}
+	}
+	return hashes, skip
+}
+
+// remember запоминает контрольные суммы заказов, состояние которых зафиксировано в БД.
+//
+//	Сбой записи только лишает следующий повтор быстрого пути, поэтому он
+//	записывается в журнал и не возвращается.
+//
+//	Параметры:
+//	- hashes: контрольные суммы заказов по order_uid.
+func (d *Deduplicator) remember(ctx context.Context, hashes map[string]string) {
+	if err := d.store.Upsert(ctx, hashes, d.now().UTC()); err != nil {
+		util.LoggerFromContext(ctx, d.logger).Warn("Dedup: failed to remember saved orders", zap.Int("count", len(hashes)), zap.Error(err))
+	}
+}
+
+// Run периодически удаляет из хранилища записи, вышедшие за окно, пока не отменён ctx.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- interval: интервал очистки.
+func (d *Deduplicator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := d.store.DeleteBefore(ctx, d.now().Add(-d.window))
+			if err != nil {
+				d.logger.Warn("Dedup: failed to delete expired entries", zap.Error(err))
+				continue
+			}
+			if deleted > 0 {
+				d.logger.Info("Dedup: expired entries deleted", zap.Int64("deleted", deleted))
+			}
+		}
+	}
+}
+
+// skipDuplicates отмечает заказы, уже сохранённые с тем же содержимым, и возвращает остальные.
+//
+// Параметры:
+// - orders: корректные заказы без повторов order_uid.
+// - results: результаты пакета.
+// - idx: позиции orders в results.
+//
+// Возвращает:
+// - []*model.Order: заказы, которые нужно сохранить.
+// - []int: их позиции в results.
+// - map[string]string: контрольные суммы заказов для remember.
+func (s *orderService) skipDuplicates(ctx context.Context, orders []*model.Order, results []SaveResult, idx []int) ([]*model.Order, []int, map[string]string) {
+	if s.dedup == nil || len(orders) == 0 {
+		return orders, idx, nil
+	}
+	hashes, skip := s.dedup.check(ctx, orders)
+	if len(skip) == 0 {
+		return orders, idx, hashes
+	}
+
+	rest := make([]*model.Order, 0, len(orders)-len(skip))
+	restIdx := make([]int, 0, len(orders)-len(skip))
+	for j, order := range orders {
+		if skip[order.OrderUID] {
+			results[idx[j]] = SaveResult{OrderUID: order.OrderUID, Status: SaveStatusDuplicate}
+			continue
+		}
+		rest = append(rest, order)
+		restIdx = append(restIdx, idx[j])
+	}
+	metrics.OrdersDeduplicated.Add(float64(len(skip)))
+	return rest, restIdx, hashes
+}
+
+// rememberSaved запоминает контрольные суммы сохранённых заказов пакета.
+//
+// Параметры:
+// - orders: сохранявшиеся заказы.
+// - results: результаты пакета.
+// - idx: позиции orders в results.
+// - hashes: контрольные суммы из skipDuplicates.
+func (s *orderService) rememberSaved(ctx context.Context, orders []*model.Order, results []SaveResult, idx []int, hashes map[string]string) {
+	if s.dedup == nil || len(hashes) == 0 {
+		return
+	}
+	saved := make(map[string]string, len(orders))
+	for j, order := range orders {
+		if sum, ok := hashes[order.OrderUID]; ok && results[idx[j]].Stored() {
+			saved[order.OrderUID] = sum
+		}
+	}
+	if len(saved) > 0 {
+		s.dedup.remember(ctx, saved)
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"l0_wb/internal/model"
+	"l0_wb/internal/util"
+)
+
+// memDedupStore хранит контрольные суммы заказов в памяти.
+type memDedupStore struct {
+	hashes  map[string]string
+	seenAt  map[string]time.Time
+	listErr error
+}
+
+func newMemDedupStore() *memDedupStore {
+	return &memDedupStore{hashes: map[string]string{}, seenAt: map[string]time.Time{}}
+}
+
+func (m *memDedupStore) ListRecent(_ context.Context, orderUIDs []string, since time.Time) (map[string]string, error) {
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+	recent := map[string]string{}
+	for _, uid := range orderUIDs {
+		if at, ok := m.seenAt[uid]; ok && !at.Before(since) {
+			recent[uid] = m.hashes[uid]
+		}
+	}
+	return recent, nil
+}
+
+func (m *memDedupStore) Upsert(_ context.Context, hashes map[string]string, seenAt time.Time) error {
+	for uid, hash := range hashes {
+		m.hashes[uid], m.seenAt[uid] = hash, seenAt
+	}
+	return nil
+}
+
+func (m *memDedupStore) DeleteBefore(_ context.Context, before time.Time) (int64, error) {
+	var n int64
+	for uid, at := range m.seenAt {
+		if at.Before(before) {
+			delete(m.hashes, uid)
+			delete(m.seenAt, uid)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// TestSaveBatchSkipsRecentDuplicates проверяет, что повтор неизменённого заказа в пределах окна
+// не открывает транзакцию, а изменённый заказ, повтор вне окна и сбой хранилища — открывают.
+func TestSaveBatchSkipsRecentDuplicates(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	ctx := context.Background()
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	order := func() *model.Order {
+		o := validOrder()
+		o.DateCreated = created
+		return o
+	}
+
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	store := newMemDedupStore()
+	dedup := NewDeduplicator(store, time.Hour)
+	dedup.now = func() time.Time { return now }
+	tx := &flakyTxManager{}
+	svc := NewOrderService(tx, nil, nil, WithDeduplicator(dedup))
+
+	save := func(o *model.Order) SaveStatus {
+		t.Helper()
+		results, err := svc.SaveBatch(ctx, []*model.Order{o})
+		if err != nil {
+			t.Fatalf("SaveBatch returned error: %v", err)
+		}
+		return results[0].Status
+	}
+
+	if status := save(order()); status != SaveStatusSaved || tx.calls != 1 {
+		t.Fatalf("expected first save in a transaction, got %s after %d transactions", status, tx.calls)
+	}
+	if _, ok := store.hashes[order().OrderUID]; !ok {
+		t.Fatal("saved order checksum is not remembered")
+	}
+
+	if status := save(order()); status != SaveStatusDuplicate || tx.calls != 1 {
+		t.Errorf("expected replay skipped without a transaction, got %s after %d transactions", status, tx.calls)
+	}
+
+	changed := order()
+	changed.Delivery.City = "Kiryat Mozkin"
+	if status := save(changed); status != SaveStatusSaved || tx.calls != 2 {
+		t.Errorf("expected changed order saved in a transaction, got %s after %d transactions", status, tx.calls)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if save(changed); tx.calls != 3 {
+		t.Errorf("expected replay outside the window saved in a transaction, got %d transactions", tx.calls)
+	}
+
+	store.listErr = errors.New("store is down")
+	if status := save(changed); status != SaveStatusSaved || tx.calls != 4 {
+		t.Errorf("expected save without deduplication on store failure, got %s after %d transactions", status, tx.calls)
+	}
+}
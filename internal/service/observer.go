@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"l0_wb/internal/cache"
+	"l0_wb/internal/metrics"
+	"l0_wb/internal/model"
+)
+
+// OrderObserver получает уведомления об итогах сохранения заказов.
+//
+//	Сервис вызывает наблюдателей синхронно после завершения SaveBatch, поэтому
+//	реализация не должна надолго блокироваться; длительную работу (вебхуки,
+//	outbox) следует передавать в собственную очередь наблюдателя.
+type OrderObserver interface {
+	// OnOrderSaved вызывается для заказа, состояние которого зафиксировано в БД:
+	// status — SaveStatusSaved или SaveStatusDuplicate.
+	OnOrderSaved(ctx context.Context, order *model.Order, status SaveStatus)
+	// OnOrderFailed вызывается для заказа, не прошедшего валидацию или отклонённого БД.
+	// Если БД недоступна и SaveBatch возвращает ошибку, уведомление не отправляется:
+	// вызывающая сторона повторит сохранение.
+	OnOrderFailed(ctx context.Context, order *model.Order, err error)
+}
+
+// WithObservers добавляет наблюдателей за сохранением заказов.
+//
+//	Наблюдатели вызываются в порядке добавления.
+func WithObservers(observers ...OrderObserver) Option {
+	return func(s *orderService) {
+		s.observers = append(s.observers, observers...)
+	}
+}
+
+// WithCache задаёт кэш заказов: сервис записывает в него сохранённые заказы
+// (через наблюдателя) и читает из него страницы ListOrders.
+func WithCache(orderCache *cache.OrderCache) Option {
+	return func(s *orderService) {
+		s.cache = orderCache
+		s.observers = append(s.observers, cacheObserver{cache: orderCache})
+	}
+}
+
+// cacheObserver записывает в кэш заказы после фиксации их сохранения.
+type cacheObserver struct {
+	cache *cache.OrderCache
+}
+
+// OnOrderSaved реализует OrderObserver.
+//
+//	Содержимое повтора совпадает с сохранённым, а служебные поля (статус,
+//	время аудита) отсеянный дедупликатором повтор не содержит, поэтому уже
+//	закэшированный заказ не перезаписывается.
+func (o cacheObserver) OnOrderSaved(ctx context.Context, order *model.Order, status SaveStatus) {
+	if status == SaveStatusDuplicate && o.cache.Contains(order.OrderUID) {
+		return
+	}
+	start := time.Now()
+	o.cache.Set(order)
+	metrics.RecordOrderStage(ctx, metrics.StageCacheSet, time.Since(start))
+}
+
+// OnOrderFailed реализует OrderObserver.
+func (o cacheObserver) OnOrderFailed(context.Context, *model.Order, error) {}
+
+// notifyObservers уведомляет наблюдателей об итогах сохранения пакета.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- orders: заказы пакета.
+//	- results: результаты сохранения в том же порядке; заказы без итога
+//	  (пакет прерван недоступностью БД) пропускаются.
+//	- withFailures: уведомлять ли о несохранённых заказах.
+func (s *orderService) notifyObservers(ctx context.Context, orders []*model.Order, results []SaveResult, withFailures bool) {
+	if len(s.observers) == 0 {
+		return
+	}
+	// Из версий одного order_uid в БД находится последняя: уведомляем только о ней
+	last := make(map[string]int, len(orders))
+	for i, order := range orders {
+		if order != nil && (results[i].Status == SaveStatusSaved || results[i].Status == SaveStatusDuplicate) {
+			last[order.OrderUID] = i
+		}
+	}
+	for i, order := range orders {
+		if order == nil {
+			continue
+		}
+		res := results[i]
+		switch res.Status {
+		case SaveStatusSaved, SaveStatusDuplicate:
+			if last[order.OrderUID] != i {
+				continue
+			}
+			for _, obs := range s.observers {
+				obs.OnOrderSaved(ctx, order, res.Status)
+			}
+		case SaveStatusInvalid, SaveStatusFailed:
+			if !withFailures {
+				continue
+			}
+			for _, obs := range s.observers {
+				obs.OnOrderFailed(ctx, order, res.Err)
+			}
+		}
+	}
+}
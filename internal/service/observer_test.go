@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"l0_wb/internal/model"
+	"l0_wb/internal/util"
+)
+
+// recordingObserver запоминает полученные уведомления.
+type recordingObserver struct {
+	saved  []string
+	failed []string
+}
+
+func (o *recordingObserver) OnOrderSaved(_ context.Context, order *model.Order, status SaveStatus) {
+	o.saved = append(o.saved, order.OrderUID+":"+string(status))
+}
+
+func (o *recordingObserver) OnOrderFailed(_ context.Context, order *model.Order, err error) {
+	if err == nil {
+		panic("OnOrderFailed without error")
+	}
+	o.failed = append(o.failed, order.OrderUID)
+}
+
+// TestSaveBatchNotifiesObservers проверяет уведомления наблюдателей об итогах пакета.
+func TestSaveBatchNotifiesObservers(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	obs := &recordingObserver{}
+	svc := NewOrderService(&fakeTxManager{}, nil, nil, WithObservers(obs))
+
+	invalid := validOrder()
+	invalid.OrderUID = "bad uid"
+	if _, err := svc.SaveBatch(context.Background(), []*model.Order{validOrder(), invalid, validOrder()}); err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	if len(obs.saved) != 1 || obs.saved[0] != validOrder().OrderUID+":saved" {
+		t.Errorf("unexpected saved notifications %v", obs.saved)
+	}
+	if len(obs.failed) != 1 || obs.failed[0] != "bad uid" {
+		t.Errorf("unexpected failed notifications %v", obs.failed)
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"l0_wb/internal/model"
+	"l0_wb/internal/pagination"
+	"l0_wb/internal/repository"
+)
+
+// ListOrders возвращает страницу заказов, удовлетворяющих фильтру, от новых к старым.
+//
+//	Если кэш заказов задан и содержит все заказы БД, страница собирается из
+//	кэша; иначе (кэш не прогрет или очищен) заказы читаются из БД. В обоих
+//	случаях заказы заполнены полностью, а курсоры совместимы между собой:
+//	пагинация — keyset по (date_created, order_uid).
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- filter: условия отбора (покупатель, период, служба доставки, статус).
+//	- after: позиция, после которой начинается страница (nil — с начала выборки).
+//	- limit: максимальное число заказов на странице.
+//	Возвращает:
+//	- []*model.Order: заказы страницы.
+//	- *pagination.Cursor: позиция для следующей страницы или nil, если страница последняя.
+//	- error: ошибка, если запрос к БД завершился сбоем.
+func (s *orderService) ListOrders(ctx context.Context, filter repository.OrderFilter, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor, error) {
+	if s.cache != nil && s.cache.Complete() {
+		page, next := pageOrders(s.cache.GetAll(), filter, after, limit)
+		return page, next, nil
+	}
+	return s.repos.Orders.ListFull(ctx, filter, after, limit)
+}
+
+// pageOrders отбирает заказы по фильтру и возвращает страницу в порядке ListFull.
+//
+//	Параметры:
+//	- orders: заказы кэша.
+//	- filter: условия отбора.
+//	- after: позиция, после которой начинается страница (nil — с начала выборки).
+//	- limit: максимальное число заказов на странице.
+//	Возвращает:
+//	- []*model.Order: заказы страницы.
+//	- *pagination.Cursor: позиция для следующей страницы или nil, если страница последняя.
+func pageOrders(orders []*model.Order, filter repository.OrderFilter, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor) {
+	matched := make([]*model.Order, 0, len(orders))
+	for _, o := range orders {
+		if filter.Match(o) && (after == nil || before(after.DateCreated, after.OrderUID, o)) {
+			matched = append(matched, o)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return before(matched[i].DateCreated, matched[i].OrderUID, matched[j])
+	})
+
+	if len(matched) <= limit {
+		return matched, nil
+	}
+	last := matched[limit-1]
+	return matched[:limit], &pagination.Cursor{DateCreated: last.DateCreated, OrderUID: last.OrderUID}
+}
+
+// before сообщает, идёт ли позиция (ts, uid) раньше заказа o при сортировке по убыванию (date_created, order_uid).
+func before(ts time.Time, uid string, o *model.Order) bool {
+	if !ts.Equal(o.DateCreated) {
+		return ts.After(o.DateCreated)
+	}
+	return uid > o.OrderUID
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"l0_wb/internal/cache"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/util"
+)
+
+// cachedOrdersRepository отдаёт заказы для прогрева кэша; остальные методы не используются.
+type cachedOrdersRepository struct {
+	repository.OrdersRepository
+	orders []*model.Order
+}
+
+func (r *cachedOrdersRepository) GetAllOrderIDs(context.Context) ([]string, error) {
+	ids := make([]string, 0, len(r.orders))
+	for _, o := range r.orders {
+		ids = append(ids, o.OrderUID)
+	}
+	return ids, nil
+}
+
+func (r *cachedOrdersRepository) GetFullByID(_ context.Context, orderUID string) (*model.Order, error) {
+	for _, o := range r.orders {
+		if o.OrderUID == orderUID {
+			return o, nil
+		}
+	}
+	return nil, nil
+}
+
+// TestListOrdersFromCache проверяет отбор и keyset-пагинацию заказов из полного кэша.
+func TestListOrdersFromCache(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &cachedOrdersRepository{orders: []*model.Order{
+		{OrderUID: "a", CustomerID: "alice", DateCreated: base, Status: model.StatusPaid},
+		{OrderUID: "b", CustomerID: "bob", DateCreated: base.Add(time.Hour), Status: model.StatusPaid},
+		{OrderUID: "c", CustomerID: "alice", DateCreated: base.Add(2 * time.Hour), Status: model.StatusCreated},
+		{OrderUID: "d", CustomerID: "alice", DateCreated: base.Add(2 * time.Hour), Status: model.StatusPaid},
+	}}
+	orderCache := cache.NewOrderCache()
+	if err := orderCache.LoadFromDB(ctx, repo); err != nil {
+		t.Fatalf("LoadFromDB failed: %v", err)
+	}
+	// Репозитории не заданы: обращение к БД вместо кэша завершится паникой
+	svc := NewOrderService(&fakeTxManager{}, nil, nil, WithCache(orderCache))
+
+	page, next, err := svc.ListOrders(ctx, repository.OrderFilter{CustomerID: "alice"}, nil, 2)
+	if err != nil || len(page) != 2 || page[0].OrderUID != "d" || page[1].OrderUID != "c" || next == nil {
+		t.Fatalf("first page = %v, %v, %v", page, next, err)
+	}
+	page, next, err = svc.ListOrders(ctx, repository.OrderFilter{CustomerID: "alice"}, next, 2)
+	if err != nil || len(page) != 1 || page[0].OrderUID != "a" || next != nil {
+		t.Fatalf("second page = %v, %v, %v", page, next, err)
+	}
+
+	page, _, err = svc.ListOrders(ctx, repository.OrderFilter{Status: model.StatusPaid, To: base.Add(2 * time.Hour)}, nil, 10)
+	if err != nil || len(page) != 2 || page[0].OrderUID != "b" || page[1].OrderUID != "a" {
+		t.Errorf("filtered page = %v, %v", page, err)
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/jackc/pgx/v5"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
+	"l0_wb/internal/breaker"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/events"
+	"l0_wb/internal/metrics"
 	"l0_wb/internal/model"
+	"l0_wb/internal/pagination"
 	"l0_wb/internal/repository"
 	"l0_wb/internal/util"
 )
@@ -19,92 +22,165 @@ import (
 type OrderService interface {
 	SaveOrder(ctx context.Context, order *model.Order) error
 
-	SaveBatch(ctx context.Context, orders []*model.Order) error
+	SaveBatch(ctx context.Context, orders []*model.Order) ([]SaveResult, error)
 
 	GetOrderByID(ctx context.Context, orderUID string) (*model.Order, error)
+
+	GetOrdersByCustomerID(ctx context.Context, customerID string, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor, error)
+
+	ListOrders(ctx context.Context, filter repository.OrderFilter, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor, error)
+
+	DeleteOrder(ctx context.Context, orderUID string) error
+
+	RestoreOrder(ctx context.Context, orderUID string) (*model.Order, error)
+
+	ArchiveOrder(ctx context.Context, orderUID string) (*model.Order, error)
+
+	GetOrderHistory(ctx context.Context, orderUID string) ([]model.OrderChange, error)
+
+	UpdateStatus(ctx context.Context, orderUID string, status model.OrderStatus) (*model.Order, error)
+
+	EraseCustomerData(ctx context.Context, customerID string) ([]*model.Order, error)
 }
 
 // orderService является конкретной реализацией интерфейса OrderService.
 type orderService struct {
-	db             *pgxpool.Pool
-	ordersRepo     repository.OrdersRepository
-	deliveriesRepo repository.DeliveriesRepository
-	paymentsRepo   repository.PaymentsRepository
-	itemsRepo      repository.ItemsRepository
-	logger         *zap.Logger
+	tx              repository.TxManager
+	repos           *repository.Repositories
+	publisher       events.Publisher
+	amountTolerance int                  // Допустимое расхождение суммы оплаты, см. AmountMismatch
+	cache           *cache.OrderCache    // Кэш заказов для ListOrders (nil — только БД)
+	observers       []OrderObserver      // Наблюдатели за итогами сохранения заказов
+	retry           RetryPolicy          // Повтор сохранения при временных ошибках БД
+	txOptions       repository.TxOptions // Параметры транзакций сохранения заказов
+	dedup           *Deduplicator        // Пропуск повторов неизменённых заказов (nil — без дедупликации)
+	logger          *zap.Logger
+}
+
+// Option настраивает OrderService.
+type Option func(*orderService)
+
+// WithAmountTolerance задаёт допустимое расхождение payment.amount с суммой товаров и доставки.
+func WithAmountTolerance(tolerance int) Option {
+	return func(s *orderService) {
+		s.amountTolerance = tolerance
+	}
+}
+
+// WithTxOptions задаёт уровень изоляции и режим доступа транзакций сохранения заказов.
+//
+//	Ошибки сериализации на уровнях repeatable_read и serializable повторяются
+//	согласно WithRetryPolicy.
+func WithTxOptions(opts repository.TxOptions) Option {
+	return func(s *orderService) {
+		s.txOptions = opts
+	}
 }
 
 // NewOrderService создает новый экземпляр orderService.
 //
 //	Параметры:
-//	- db: подключение к базе данных.
-//	- ordersRepo: репозиторий для работы с таблицей заказов.
-//	- deliveriesRepo: репозиторий для работы с таблицей доставок.
-//	- paymentsRepo: репозиторий для работы с таблицей оплат.
-//	- itemsRepo: репозиторий для работы с таблицей товаров.
+//	- tx: менеджер транзакций для сохранения заказов.
+//	- repos: репозитории для чтения заказов вне транзакции.
+//	- publisher: публикатор событий об изменении заказов (nil — события не публикуются).
+//	- opts: дополнительные настройки сервиса.
 //	Возвращает:
-//	- OrderService: экземпляр сервиса для работы с заказами.
-func NewOrderService(
-	db *pgxpool.Pool,
-	ordersRepo repository.OrdersRepository,
-	deliveriesRepo repository.DeliveriesRepository,
-	paymentsRepo repository.PaymentsRepository,
-	itemsRepo repository.ItemsRepository,
-) OrderService {
-	logger := util.GetLogger()
-	return &orderService{
-		db:             db,
-		ordersRepo:     ordersRepo,
-		deliveriesRepo: deliveriesRepo,
-		paymentsRepo:   paymentsRepo,
-		itemsRepo:      itemsRepo,
-		logger:         logger,
+//	- OrderService: экземпляр сервиса для работы с заказами; каждый вызов записывается спаном трассировки.
+func NewOrderService(tx repository.TxManager, repos *repository.Repositories, publisher events.Publisher, opts ...Option) OrderService {
+	s := &orderService{
+		tx:        tx,
+		repos:     repos,
+		publisher: publisher,
+		logger:    util.GetLogger(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return tracedOrderService{next: s}
+}
+
+// log возвращает логгер с полями корреляции из контекста (request_id, partition/offset сообщения и т. п.).
+func (s *orderService) log(ctx context.Context) *zap.Logger {
+	return util.LoggerFromContext(ctx, s.logger)
 }
 
 // SaveOrder сохраняет заказ в рамках одной транзакции базы данных.
 //
 //	Этапы:
 //	1. Валидация структуры заказа (проверка order_uid, списка товаров и данных доставки).
-//	2. Вставка данных в таблицы orders, deliveries, payments, items.
+//	2. Вставка данных в таблицы orders, deliveries, payments, items или обновление уже сохранённого заказа.
 //	3. Завершение транзакции (commit) при успешной вставке всех данных.
+//	4. Публикация событий об изменённых заказах.
+//	5. Уведомление наблюдателей (запись в кэш и т.п.).
+//	Повторное сохранение того же заказа ничего не меняет и ошибкой не считается.
 //	Параметры:
 //	- ctx: контекст выполнения.
 //	- order: объект заказа.
 //	Возвращает:
-//	- error: ошибка, если произошел сбой на любом этапе.
+//	- error: ошибка валидации или сбой на любом этапе.
 func (s *orderService) SaveOrder(ctx context.Context, order *model.Order) error {
-	return s.SaveBatch(ctx, []*model.Order{order})
+	results, err := s.SaveBatch(ctx, []*model.Order{order})
+	if err != nil {
+		return err
+	}
+	return results[0].Err
 }
 
 // SaveBatch выполняет пакетную вставку заказов в базу данных.
-func (s *orderService) SaveBatch(ctx context.Context, orders []*model.Order) error {
+//
+//	Заказы, которые уже есть в БД, обновляются, если их содержимое изменилось;
+//	после фиксации транзакции для них публикуются события об изменении.
+//	Неизменившиеся заказы пропускаются; с дедупликатором (WithDeduplicator)
+//	повторы недавно сохранённых заказов отсеиваются ещё до транзакции.
+//	Из нескольких версий одного order_uid в пакете сохраняется последняя:
+//	более ранние получают итог SaveStatusDuplicate, если их содержимое
+//	совпадает с ней, и SaveStatusSuperseded, если отличается.
+//	Наблюдатели (OrderObserver) уведомляются об итогах после фиксации
+//	транзакций, поэтому, например, кэш не содержит заказов, которых нет в БД.
+//
+//	Сначала весь пакет сохраняется одной транзакцией. Если она отклонена
+//	базой (например, нарушением ограничения в одном из заказов), каждый
+//	заказ сохраняется в отдельной транзакции, чтобы ошибочный заказ не
+//	отменял сохранение остальных.
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- orders: заказы пакета.
+//	Возвращает:
+//	- []SaveResult: результат по каждому заказу в порядке следования в пакете.
+//	- error: ошибку, если БД недоступна и пакет не сохранён целиком.
+func (s *orderService) SaveBatch(ctx context.Context, orders []*model.Order) ([]SaveResult, error) {
 	if len(orders) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	// Открываем транзакцию
-	tx, err := s.db.Begin(ctx)
-	if err != nil {
-		s.logger.Error("SaveBatch: begin transaction failed", zap.Error(err))
-		return fmt.Errorf("begin transaction failed: %w", err)
+	// Отбираем корректные заказы
+	results := make([]SaveResult, len(orders))
+	valid := make([]*model.Order, 0, len(orders))
+	validIdx := make([]int, 0, len(orders))
+	// Из версий одного заказа сохраняется последняя корректная
+	last := make(map[string]int, len(orders))
+	stageStart := time.Now()
+	for i, order := range orders {
+		// Валидация заказа
+		if err := ValidateOrder(order); err != nil {
+			s.log(ctx).Warn("Invalid order", zap.String("order_uid", orderUID(order)), zap.Error(err))
+			results[i] = SaveResult{OrderUID: orderUID(order), Status: SaveStatusInvalid, Err: err}
+			continue
+		}
+		results[i].OrderUID = order.OrderUID
+		last[order.OrderUID] = i
 	}
-
-	// Откат транзакции в случае ошибки
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback(ctx)
-			panic(p)
-		} else if err != nil {
-			_ = tx.Rollback(ctx)
+	var repeats map[int]bool
+	for i, order := range orders {
+		if results[i].Status == SaveStatusInvalid {
+			continue
 		}
-	}()
-
-	// Вставляем заказы в базу данных
-	for _, order := range orders {
-		// Валидация заказа
-		if err := s.validateOrder(order); err != nil {
-			s.logger.Warn("Invalid order", zap.String("order_uid", order.OrderUID), zap.Error(err))
+		if k := last[order.OrderUID]; k != i {
+			// Последняя версия ещё не изменена подстановкой значений по умолчанию
+			if repeats == nil {
+				repeats = make(map[int]bool)
+			}
+			repeats[i] = sameContent(order, orders[k])
 			continue
 		}
 
@@ -112,200 +188,512 @@ func (s *orderService) SaveBatch(ctx context.Context, orders []*model.Order) err
 		if order.DateCreated.IsZero() {
 			order.DateCreated = time.Now().UTC()
 		}
+		// Несходящиеся суммы не отклоняют заказ, а отмечаются в нём
+		order.AmountMismatch = AmountMismatch(order, s.amountTolerance)
+		valid = append(valid, order)
+		validIdx = append(validIdx, i)
+	}
+	metrics.RecordOrderStage(ctx, metrics.StageValidate, time.Since(stageStart))
 
-		// Вставка данных заказа
-		if err = s.insertOrderData(ctx, tx, order); err != nil {
-			s.logger.Error("Failed to insert order data", zap.String("order_uid", order.OrderUID), zap.Error(err))
-			return err
+	// Повторы уже сохранённых заказов отсеиваем без транзакции
+	valid, validIdx, hashes := s.skipDuplicates(ctx, valid, results, validIdx)
+
+	stageStart = time.Now()
+	updates, err := s.saveWithinTx(ctx, valid, results, validIdx)
+	if err != nil && len(valid) > 1 && !isUnavailable(ctx, err) {
+		s.log(ctx).Warn("SaveBatch: batch transaction failed, saving orders one by one", zap.Error(err))
+		updates, err = s.saveOneByOne(ctx, valid, results, validIdx)
+	}
+	metrics.RecordOrderStage(ctx, metrics.StageDBSave, time.Since(stageStart))
+	if err != nil && !isUnavailable(ctx, err) {
+		// Единственный корректный заказ отклонён базой
+		s.log(ctx).Error("SaveBatch: failed to save order", zap.String("order_uid", valid[0].OrderUID), zap.Error(err))
+		results[validIdx[0]] = SaveResult{OrderUID: valid[0].OrderUID, Status: SaveStatusFailed, Err: err}
+		err = nil
+	}
+
+	resolveRepeats(orders, results, last, repeats)
+	s.rememberSaved(ctx, valid, results, validIdx, hashes)
+
+	// Данные уже зафиксированы, поэтому ошибка публикации не отменяет сохранение
+	if s.publisher != nil && len(updates) > 0 {
+		if pubErr := s.publisher.PublishUpdates(ctx, updates); pubErr != nil {
+			s.log(ctx).Error("SaveBatch: failed to publish order events", zap.Error(pubErr))
 		}
 	}
 
-	// Фиксируем транзакцию
-	if err = tx.Commit(ctx); err != nil {
-		s.logger.Error("SaveBatch: commit transaction failed", zap.Error(err))
-		return fmt.Errorf("commit transaction failed: %w", err)
+	if err != nil {
+		s.log(ctx).Error("SaveBatch: transaction failed", zap.Error(err))
+		// О заказах, зафиксированных до сбоя, сообщаем сразу; об остальных —
+		// когда вызывающая сторона повторит пакет
+		s.notifyObservers(ctx, orders, results, false)
+		return nil, err
 	}
+	s.notifyObservers(ctx, orders, results, true)
+	s.log(ctx).Info("SaveBatch: orders processed",
+		zap.Int("batch_size", len(orders)),
+		zap.Int("updated", len(updates)),
+	)
+	return results, nil
+}
 
-	s.logger.Info("SaveBatch: orders saved successfully", zap.Int("batch_size", len(orders)))
-	return nil
+// saveOneByOne сохраняет каждый заказ в отдельной транзакции.
+//
+// Параметры:
+// - orders: корректные заказы без повторов order_uid.
+// - results: результаты пакета.
+// - idx: позиции orders в results.
+//
+// Возвращает:
+// - []events.Update: изменения сохранённых ранее заказов.
+// - error: если БД стала недоступна; уже сохранённые заказы при повторе пакета будут пропущены как дубликаты.
+func (s *orderService) saveOneByOne(ctx context.Context, orders []*model.Order, results []SaveResult, idx []int) ([]events.Update, error) {
+	var updates []events.Update
+	for j, order := range orders {
+		u, err := s.saveWithinTx(ctx, orders[j:j+1], results, idx[j:j+1])
+		if err != nil {
+			if isUnavailable(ctx, err) {
+				return updates, err
+			}
+			s.log(ctx).Error("SaveBatch: failed to save order", zap.String("order_uid", order.OrderUID), zap.Error(err))
+			results[idx[j]] = SaveResult{OrderUID: order.OrderUID, Status: SaveStatusFailed, Err: err}
+			continue
+		}
+		updates = append(updates, u...)
+	}
+	return updates, nil
 }
 
-// validateOrder выполняет базовую валидацию заказа.
+// saveWithinTx сохраняет заказы одной транзакцией и заполняет их результаты.
+//
+//	Транзакция открывается с параметрами WithTxOptions. При конфликте
+//	сериализации, взаимоблокировке или обрыве соединения транзакция
+//	повторяется целиком согласно RetryPolicy сервиса.
 //
 // Параметры:
-// - order: объект заказа.
+// - orders: корректные заказы без повторов order_uid.
+// - results: результаты пакета; заполняются только после фиксации транзакции.
+// - idx: позиции orders в results.
 //
 // Возвращает:
-// - error: если заказ некорректен.
-func (s *orderService) validateOrder(order *model.Order) error {
+// - []events.Update: изменения сохранённых ранее заказов.
+// - error: если транзакция не зафиксирована.
+func (s *orderService) saveWithinTx(ctx context.Context, orders []*model.Order, results []SaveResult, idx []int) ([]events.Update, error) {
+	if len(orders) == 0 {
+		return nil, nil
+	}
+	var updates []events.Update
+	var unchanged map[string]bool
+	err := s.withRetry(ctx, "save_batch", func() error {
+		return s.tx.WithinTxOptions(ctx, s.txOptions, func(ctx context.Context, repos *repository.Repositories) error {
+			updates = nil
+			var err error
+			unchanged, err = s.saveOrders(ctx, repos, orders, &updates)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	for j, order := range orders {
+		status := SaveStatusSaved
+		if unchanged[order.OrderUID] {
+			status = SaveStatusDuplicate
+		}
+		results[idx[j]] = SaveResult{OrderUID: order.OrderUID, Status: status}
+		if status == SaveStatusSaved && order.AmountMismatch != 0 {
+			metrics.OrderAmountMismatches.Inc()
+			s.log(ctx).Warn("Order payment amount does not match items and delivery",
+				zap.String("order_uid", order.OrderUID),
+				zap.Int("amount", order.Payment.Amount),
+				zap.Int("mismatch", order.AmountMismatch),
+			)
+		}
+	}
+	return updates, nil
+}
+
+// sameContent сообщает, совпадает ли содержимое двух версий заказа.
+func sameContent(a, b *model.Order) bool {
+	sumA, errA := events.Checksum(a)
+	sumB, errB := events.Checksum(b)
+	return errA == nil && errB == nil && sumA == sumB
+}
+
+// resolveRepeats заполняет результаты более ранних версий заказов, повторяющихся в пакете.
+//
+//	Ранняя версия разделяет итог последней: если последняя не сохранена,
+//	ранняя получает тот же итог; иначе — SaveStatusDuplicate при совпадающем
+//	содержимом и SaveStatusSuperseded при отличающемся.
+//
+// Параметры:
+// - orders: заказы пакета.
+// - results: результаты пакета.
+// - last: позиция последней корректной версии каждого order_uid.
+// - repeats: позиции ранних версий и совпадение их содержимого с последней.
+func resolveRepeats(orders []*model.Order, results []SaveResult, last map[string]int, repeats map[int]bool) {
+	for i, same := range repeats {
+		uid := orders[i].OrderUID
+		kept := results[last[uid]]
+		switch {
+		case kept.Status == "":
+			// Пакет прерван недоступностью БД, итога нет
+		case !kept.Stored():
+			results[i] = SaveResult{OrderUID: uid, Status: kept.Status, Err: kept.Err}
+		case same:
+			results[i] = SaveResult{OrderUID: uid, Status: SaveStatusDuplicate}
+		default:
+			results[i] = SaveResult{OrderUID: uid, Status: SaveStatusSuperseded}
+		}
+	}
+}
+
+// isUnavailable сообщает, что ошибка вызвана недоступностью БД или отменой запроса,
+// и сохранение заказов по одному заведомо не поможет.
+func isUnavailable(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || errors.Is(err, breaker.ErrOpen) || repository.IsDBFailure(err)
+}
+
+// orderUID возвращает order_uid заказа или пустую строку для nil.
+func orderUID(order *model.Order) string {
 	if order == nil {
-		return errors.New("order is nil")
+		return ""
 	}
-	if order.OrderUID == "" {
-		return errors.New("order_uid is empty")
+	return order.OrderUID
+}
+
+// saveOrders вставляет новые и обновляет изменившиеся заказы в рамках транзакции.
+//
+// Параметры:
+// - repos: репозитории, привязанные к транзакции.
+// - orders: корректные заказы пакета.
+// - updates: сюда добавляются состояния изменившихся заказов до и после сохранения.
+//
+// Возвращает:
+// - map[string]bool: order_uid уже сохранённых заказов, содержимое которых не изменилось.
+// - error: если произошла ошибка чтения, вставки или обновления.
+func (s *orderService) saveOrders(ctx context.Context, repos *repository.Repositories, orders []*model.Order, updates *[]events.Update) (map[string]bool, error) {
+	uids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		uids = append(uids, order.OrderUID)
+	}
+
+	// Блокируем уже сохранённые заказы до конца транзакции
+	existing, err := repos.Orders.LockExisting(ctx, uids)
+	if err != nil {
+		return nil, fmt.Errorf("select existing orders failed: %w", err)
+	}
+
+	// Обновляем изменившиеся заказы, новые вставляем одним пакетом
+	inserted := make([]*model.Order, 0, len(orders))
+	unchanged := make(map[string]bool)
+	for _, order := range orders {
+		if !existing[order.OrderUID] {
+			// Новый заказ получает статус по умолчанию; статус из сообщения не сохраняется
+			order.Status = model.StatusCreated
+			inserted = append(inserted, order)
+			continue
+		}
+
+		update, err := s.updateOrderData(ctx, repos, order)
+		if err != nil {
+			s.log(ctx).Error("Failed to update order data", zap.String("order_uid", order.OrderUID), zap.Error(err))
+			return nil, err
+		}
+		if update == nil {
+			unchanged[order.OrderUID] = true
+			continue
+		}
+		*updates = append(*updates, *update)
+	}
+
+	if err := s.insertOrdersData(ctx, repos, inserted); err != nil {
+		s.log(ctx).Error("Failed to insert orders data", zap.Int("count", len(inserted)), zap.Error(err))
+		return nil, err
+	}
+
+	// Журнал изменений пишется в той же транзакции, что и сами изменения
+	changes := make([]model.OrderChange, 0, len(inserted)+len(*updates))
+	for _, order := range inserted {
+		change, err := newOrderChange(ctx, model.ChangeInsert, nil, order)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
 	}
-	if len(order.Items) == 0 {
-		return errors.New("order has no items")
+	for _, u := range *updates {
+		change, err := newOrderChange(ctx, model.ChangeUpdate, u.Previous, u.Current)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
 	}
-	if order.Delivery.Name == "" || order.Delivery.Phone == "" {
-		return errors.New("invalid delivery data")
+	if err := repos.Events.InsertMany(ctx, changes); err != nil {
+		return nil, fmt.Errorf("insert order events failed: %w", err)
+	}
+	return unchanged, nil
+}
+
+// newOrderChange формирует запись журнала изменений заказа от имени исполнителя из контекста.
+//
+// Параметры:
+// - action: действие, одна из констант model.Change*.
+// - prev: предыдущее состояние заказа или nil для нового заказа.
+// - curr: новое состояние заказа.
+//
+// Возвращает:
+// - model.OrderChange: запись журнала с изменёнными полями.
+// - error: если заказ не удалось сериализовать.
+func newOrderChange(ctx context.Context, action string, prev, curr *model.Order) (model.OrderChange, error) {
+	diff, err := events.Diff(prev, curr)
+	if err != nil {
+		return model.OrderChange{}, fmt.Errorf("diff order %s: %w", curr.OrderUID, err)
+	}
+	return model.OrderChange{
+		OrderUID:   curr.OrderUID,
+		Action:     action,
+		Actor:      ActorFromContext(ctx),
+		OccurredAt: time.Now().UTC(),
+		Diff:       diff,
+	}, nil
+}
+
+// recordMark записывает в журнал изменение служебной отметки заказа.
+func recordMark(ctx context.Context, repos *repository.Repositories, orderUID, action, field string, value any) error {
+	change := model.OrderChange{
+		OrderUID:   orderUID,
+		Action:     action,
+		Actor:      ActorFromContext(ctx),
+		OccurredAt: time.Now().UTC(),
+		Diff:       map[string]any{field: value},
+	}
+	if err := repos.Events.InsertMany(ctx, []model.OrderChange{change}); err != nil {
+		return fmt.Errorf("insert order event failed: %w", err)
 	}
 	return nil
 }
 
-// insertOrderData выполняет вставку данных заказа в базу данных в рамках транзакции.
+// insertOrdersData выполняет вставку данных новых заказов в базу данных в рамках транзакции.
+//
+// Каждая таблица заполняется одной командой COPY, поэтому число обращений к БД
+// не зависит ни от размера пакета, ни от количества товаров в заказах.
 //
 // Параметры:
-// - tx: активная транзакция базы данных.
-// - order: объект заказа.
+// - repos: репозитории, привязанные к транзакции.
+// - orders: новые заказы пакета.
 //
 // Возвращает:
 // - error: если произошла ошибка при вставке.
-func (s *orderService) insertOrderData(ctx context.Context, tx pgx.Tx, order *model.Order) error {
-	if err := s.ordersRepoInsertTx(ctx, tx, order); err != nil {
-		return err
+func (s *orderService) insertOrdersData(ctx context.Context, repos *repository.Repositories, orders []*model.Order) error {
+	if len(orders) == 0 {
+		return nil
 	}
 
-	if err := s.deliveriesRepoInsertTx(ctx, tx, &order.Delivery, order.OrderUID); err != nil {
-		return err
+	if err := repos.Orders.InsertMany(ctx, orders); err != nil {
+		return fmt.Errorf("insert orders failed: %w", err)
 	}
 
-	if err := s.paymentsRepoInsertTx(ctx, tx, &order.Payment, order.OrderUID); err != nil {
-		return err
+	if err := repos.Deliveries.InsertMany(ctx, orders); err != nil {
+		return fmt.Errorf("insert deliveries failed: %w", err)
 	}
 
-	if err := s.itemsRepoInsertTx(ctx, tx, order.Items, order.OrderUID); err != nil {
-		return err
+	if err := repos.Payments.InsertMany(ctx, orders); err != nil {
+		return fmt.Errorf("insert payments failed: %w", err)
+	}
+
+	if err := repos.Items.InsertMany(ctx, orders); err != nil {
+		return fmt.Errorf("insert items failed: %w", err)
 	}
 
 	return nil
 }
 
-// GetOrderByID получает заказ и сопутствующие данные из базы данных и возвращает заполненную структуру Order.
+// updateOrderData заменяет данные сохранённого заказа, если его содержимое изменилось.
 //
-//	Параметры:
-//	- ctx: контекст выполнения.
-//	- orderUID: уникальный идентификатор заказа.
-//	Возвращает:
-//	- *model.Order: объект заказа.
-//	- error: ошибка, если произошел сбой на любом этапе.
-func (s *orderService) GetOrderByID(ctx context.Context, orderUID string) (*model.Order, error) {
-	order, err := s.ordersRepo.GetByID(ctx, orderUID)
+// Параметры:
+// - repos: репозитории, привязанные к транзакции.
+// - order: новое состояние заказа.
+//
+// Возвращает:
+// - *events.Update: предыдущее и новое состояние заказа или nil, если заказ не изменился.
+// - error: если произошла ошибка при чтении или обновлении.
+func (s *orderService) updateOrderData(ctx context.Context, repos *repository.Repositories, order *model.Order) (*events.Update, error) {
+	previous, err := loadOrder(ctx, repos, order.OrderUID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("load previous order failed: %w", err)
 	}
+	// Статус меняется только через UpdateStatus
+	order.Status = previous.Status
 
-	delivery, err := s.deliveriesRepo.GetByOrderID(ctx, orderUID)
+	prevSum, err := events.Checksum(previous)
 	if err != nil {
 		return nil, err
 	}
-
-	payment, err := s.paymentsRepo.GetByOrderID(ctx, orderUID)
+	currSum, err := events.Checksum(order)
 	if err != nil {
 		return nil, err
 	}
+	if prevSum == currSum {
+		return nil, nil
+	}
 
-	items, err := s.itemsRepo.GetByOrderID(ctx, orderUID)
-	if err != nil {
-		return nil, err
+	if err := repos.Orders.Update(ctx, order); err != nil {
+		return nil, fmt.Errorf("update order failed: %w", err)
+	}
+	if err := repos.Deliveries.Update(ctx, &order.Delivery, order.OrderUID); err != nil {
+		return nil, fmt.Errorf("update delivery failed: %w", err)
+	}
+	if err := repos.Payments.Update(ctx, &order.Payment, order.OrderUID); err != nil {
+		return nil, fmt.Errorf("update payment failed: %w", err)
+	}
+	if err := repos.Items.DeleteByOrderID(ctx, order.OrderUID); err != nil {
+		return nil, fmt.Errorf("delete items failed: %w", err)
+	}
+	if err := repos.Items.Insert(ctx, order.Items, order.OrderUID); err != nil {
+		return nil, fmt.Errorf("insert item failed: %w", err)
 	}
 
-	order.Delivery = *delivery
-	order.Payment = *payment
-	order.Items = items
+	return &events.Update{Previous: previous, Current: order}, nil
+}
 
-	return order, nil
+// GetOrderByID получает заказ и сопутствующие данные из базы данных и возвращает заполненную структуру Order.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- *model.Order: объект заказа.
+//	- error: ошибка, если произошел сбой на любом этапе.
+func (s *orderService) GetOrderByID(ctx context.Context, orderUID string) (*model.Order, error) {
+	return loadOrder(ctx, s.repos, orderUID)
 }
 
-// ordersRepoInsertTx вставляет заказ в таблицу orders с использованием транзакции (tx).
-func (s *orderService) ordersRepoInsertTx(ctx context.Context, tx pgx.Tx, order *model.Order) error {
-	query := `INSERT INTO orders (order_uid, track_number, entry, locale, internal_signature, customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard)
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
-	_, err := tx.Exec(ctx, query,
-		order.OrderUID,
-		order.TrackNumber,
-		order.Entry,
-		order.Locale,
-		order.InternalSignature,
-		order.CustomerID,
-		order.DeliveryService,
-		order.Shardkey,
-		order.SmID,
-		order.DateCreated,
-		order.OofShard,
-	)
+// GetOrdersByCustomerID возвращает страницу истории заказов покупателя, от новых к старым.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- customerID: идентификатор покупателя.
+//	- after: позиция, после которой начинается страница (nil — с начала истории).
+//	- limit: максимальное число заказов на странице.
+//	Возвращает:
+//	- []*model.Order: заказы страницы.
+//	- *pagination.Cursor: позиция для следующей страницы или nil, если страница последняя.
+//	- error: ошибка, если запрос завершился сбоем.
+func (s *orderService) GetOrdersByCustomerID(ctx context.Context, customerID string, after *pagination.Cursor, limit int) ([]*model.Order, *pagination.Cursor, error) {
+	return s.repos.Orders.GetByCustomerID(ctx, customerID, after, limit)
+}
+
+// DeleteOrder мягко удаляет заказ: данные остаются в БД, но заказ скрывается из выборок.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- error: pgx.ErrNoRows, если заказ не найден, или ошибку запроса.
+func (s *orderService) DeleteOrder(ctx context.Context, orderUID string) error {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		if err := repos.Orders.SoftDelete(ctx, orderUID); err != nil {
+			return err
+		}
+		order, err := repos.Orders.GetByID(ctx, orderUID)
+		if err != nil {
+			return err
+		}
+		return recordMark(ctx, repos, orderUID, model.ChangeDelete, "deleted_at", order.DeletedAt)
+	})
 	if err != nil {
-		return fmt.Errorf("insert order failed: %w", err)
+		return err
 	}
+	s.log(ctx).Info("Order soft-deleted", zap.String("order_uid", orderUID))
 	return nil
 }
 
-// deliveriesRepoInsertTx вставляет данные доставки в таблицу deliveries с использованием транзакции (tx).
-func (s *orderService) deliveriesRepoInsertTx(ctx context.Context, tx pgx.Tx, delivery *model.Delivery, orderUID string) error {
-	query := `INSERT INTO deliveries (order_uid, name, phone, zip, city, address, region, email)
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
-	_, err := tx.Exec(ctx, query,
-		orderUID,
-		delivery.Name,
-		delivery.Phone,
-		delivery.Zip,
-		delivery.City,
-		delivery.Address,
-		delivery.Region,
-		delivery.Email,
-	)
+// RestoreOrder снимает с заказа отметку удаления.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- *model.Order: восстановленный заказ.
+//	- error: pgx.ErrNoRows, если заказ не найден, или ошибку запроса.
+func (s *orderService) RestoreOrder(ctx context.Context, orderUID string) (*model.Order, error) {
+	var order *model.Order
+	err := s.tx.WithinTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		if err := repos.Orders.Restore(ctx, orderUID); err != nil {
+			return err
+		}
+		if err := recordMark(ctx, repos, orderUID, model.ChangeRestore, "deleted_at", nil); err != nil {
+			return err
+		}
+		var err error
+		order, err = loadOrder(ctx, repos, orderUID)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("insert delivery failed: %w", err)
+		return nil, err
 	}
-	return nil
+	s.log(ctx).Info("Order restored", zap.String("order_uid", orderUID))
+	return order, nil
 }
 
-// paymentsRepoInsertTx вставляет данные оплаты в таблицу payments с использованием транзакции (tx).
-func (s *orderService) paymentsRepoInsertTx(ctx context.Context, tx pgx.Tx, payment *model.Payment, orderUID string) error {
-	query := `INSERT INTO payments (order_uid, transaction, request_id, currency, provider, amount, payment_dt, bank, delivery_cost, goods_total, custom_fee)
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
-	_, err := tx.Exec(ctx, query,
-		orderUID,
-		payment.Transaction,
-		payment.RequestID,
-		payment.Currency,
-		payment.Provider,
-		payment.Amount,
-		payment.PaymentDt,
-		payment.Bank,
-		payment.DeliveryCost,
-		payment.GoodsTotal,
-		payment.CustomFee,
-	)
+// ArchiveOrder помечает заказ архивным.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- *model.Order: заказ с отметкой архивации.
+//	- error: pgx.ErrNoRows, если заказ не найден, или ошибку запроса.
+func (s *orderService) ArchiveOrder(ctx context.Context, orderUID string) (*model.Order, error) {
+	var order *model.Order
+	err := s.tx.WithinTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		if err := repos.Orders.Archive(ctx, orderUID); err != nil {
+			return err
+		}
+		var err error
+		if order, err = loadOrder(ctx, repos, orderUID); err != nil {
+			return err
+		}
+		return recordMark(ctx, repos, orderUID, model.ChangeArchive, "archived_at", order.ArchivedAt)
+	})
 	if err != nil {
-		return fmt.Errorf("insert payment failed: %w", err)
+		return nil, err
 	}
-	return nil
+	s.log(ctx).Info("Order archived", zap.String("order_uid", orderUID))
+	return order, nil
 }
 
-// itemsRepoInsertTx вставляет товары в таблицу items с использованием транзакции (tx).
-func (s *orderService) itemsRepoInsertTx(ctx context.Context, tx pgx.Tx, items []model.Item, orderUID string) error {
-	query := `INSERT INTO items (order_uid, chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status)
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
-
-	for _, it := range items {
-		_, err := tx.Exec(ctx, query,
-			orderUID,
-			it.ChrtID,
-			it.TrackNumber,
-			it.Price,
-			it.Rid,
-			it.Name,
-			it.Sale,
-			it.Size,
-			it.TotalPrice,
-			it.NmID,
-			it.Brand,
-			it.Status,
-		)
-		if err != nil {
-			return fmt.Errorf("insert item failed: %w", err)
-		}
+// GetOrderHistory возвращает журнал изменений заказа в порядке записи.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- []model.OrderChange: записи журнала; пустой список для заказа, сохранённого до появления журнала.
+//	- error: pgx.ErrNoRows, если заказ не найден, или ошибку запроса.
+func (s *orderService) GetOrderHistory(ctx context.Context, orderUID string) ([]model.OrderChange, error) {
+	changes, err := s.repos.Events.ListByOrderID(ctx, orderUID)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	if len(changes) > 0 {
+		return changes, nil
+	}
+	// Пустой журнал отличаем от несуществующего заказа
+	if _, err := s.repos.Orders.GetByID(ctx, orderUID); err != nil {
+		return nil, err
+	}
+	return []model.OrderChange{}, nil
+}
+
+// loadOrder загружает заказ со всеми связанными данными одним запросом.
+//
+//	Параметры:
+//	- repos: репозитории поверх пула или активной транзакции.
+//	- orderUID: уникальный идентификатор заказа.
+//	Возвращает:
+//	- *model.Order: объект заказа.
+//	- error: ошибка, если заказ не найден или запрос завершился сбоем.
+func loadOrder(ctx context.Context, repos *repository.Repositories, orderUID string) (*model.Order, error) {
+	return repos.Orders.GetFullByID(ctx, orderUID)
 }
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"l0_wb/internal/breaker"
+	"l0_wb/internal/cache"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/repository/mocks"
+	"l0_wb/internal/util"
+)
+
+// fakeTxManager завершает транзакцию с заданной ошибкой, не обращаясь к БД.
+type fakeTxManager struct {
+	err error
+}
+
+func (m *fakeTxManager) WithinTx(context.Context, func(context.Context, *repository.Repositories) error) error {
+	return m.err
+}
+
+func (m *fakeTxManager) WithinTxOptions(context.Context, repository.TxOptions, func(context.Context, *repository.Repositories) error) error {
+	return m.err
+}
+
+// TestSaveBatchWritesCache проверяет, что в кэш попадают только заказы зафиксированной транзакции.
+func TestSaveBatchWritesCache(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	ctx := context.Background()
+
+	orderCache := cache.NewOrderCache()
+	tx := &fakeTxManager{err: context.DeadlineExceeded}
+	svc := NewOrderService(tx, nil, nil, WithCache(orderCache))
+	if _, err := svc.SaveBatch(ctx, []*model.Order{validOrder()}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected unavailable error, got %v", err)
+	}
+	if orderCache.Len() != 0 {
+		t.Fatalf("failed save must not populate cache, got %d orders", orderCache.Len())
+	}
+
+	tx.err = nil
+	invalid := validOrder()
+	invalid.OrderUID = ""
+	results, err := svc.SaveBatch(ctx, []*model.Order{validOrder(), invalid})
+	if err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	if results[0].Status != SaveStatusSaved || results[1].Status != SaveStatusInvalid {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if orderCache.Len() != 1 || orderCache.Get(validOrder().OrderUID) == nil {
+		t.Errorf("expected saved order in cache, got %d orders", orderCache.Len())
+	}
+}
+
+// mockStore — репозитории на моках поверх заказов в памяти.
+type mockStore struct {
+	repos     *repository.Repositories
+	orders    *mocks.OrdersRepositoryMock
+	events    *mocks.OrderEventsRepositoryMock
+	saved     map[string]*model.Order // Заказы, вставленные или обновлённые в транзакциях
+	committed int                     // Число зафиксированных транзакций
+}
+
+// newMockStore создает репозитории на моках.
+//
+//	Параметры:
+//	- existing: ранее сохранённые заказы.
+//	- reject: order_uid заказов, вставку которых база отклоняет нарушением ограничения.
+func newMockStore(existing map[string]*model.Order, reject ...string) *mockStore {
+	m := &mockStore{saved: make(map[string]*model.Order)}
+	m.orders = &mocks.OrdersRepositoryMock{
+		LockExistingFunc: func(_ context.Context, uids []string) (map[string]bool, error) {
+			found := make(map[string]bool)
+			for _, uid := range uids {
+				found[uid] = existing[uid] != nil
+			}
+			return found, nil
+		},
+		GetFullByIDFunc: func(_ context.Context, uid string) (*model.Order, error) {
+			if order := existing[uid]; order != nil {
+				return order, nil
+			}
+			return nil, pgx.ErrNoRows
+		},
+		InsertManyFunc: func(_ context.Context, orders []*model.Order) error {
+			for _, order := range orders {
+				if slices.Contains(reject, order.OrderUID) {
+					return &pgconn.PgError{Code: "23514", Message: "check constraint violated"}
+				}
+			}
+			for _, order := range orders {
+				m.saved[order.OrderUID] = order
+			}
+			return nil
+		},
+		UpdateFunc: func(_ context.Context, order *model.Order) error {
+			m.saved[order.OrderUID] = order
+			return nil
+		},
+	}
+	m.events = &mocks.OrderEventsRepositoryMock{
+		InsertManyFunc: func(context.Context, []model.OrderChange) error { return nil },
+	}
+	m.repos = &repository.Repositories{
+		Orders: m.orders,
+		Deliveries: &mocks.DeliveriesRepositoryMock{
+			InsertManyFunc: func(context.Context, []*model.Order) error { return nil },
+			UpdateFunc:     func(context.Context, *model.Delivery, string) error { return nil },
+		},
+		Payments: &mocks.PaymentsRepositoryMock{
+			InsertManyFunc: func(context.Context, []*model.Order) error { return nil },
+			UpdateFunc:     func(context.Context, *model.Payment, string) error { return nil },
+		},
+		Items: &mocks.ItemsRepositoryMock{
+			InsertManyFunc:      func(context.Context, []*model.Order) error { return nil },
+			InsertFunc:          func(context.Context, []model.Item, string) error { return nil },
+			DeleteByOrderIDFunc: func(context.Context, string) error { return nil },
+		},
+		Events: m.events,
+	}
+	return m
+}
+
+// txManager возвращает менеджер транзакций, передающий fn репозитории на моках.
+//
+//	Транзакция считается откатанной, если fn вернула ошибку: заказы,
+//	записанные в ней, удаляются из saved.
+func (m *mockStore) txManager() *mocks.TxManagerMock {
+	withinTx := func(ctx context.Context, fn func(context.Context, *repository.Repositories) error) error {
+		before := make(map[string]*model.Order, len(m.saved))
+		for uid, order := range m.saved {
+			before[uid] = order
+		}
+		if err := fn(ctx, m.repos); err != nil {
+			m.saved = before
+			return err
+		}
+		m.committed++
+		return nil
+	}
+	return &mocks.TxManagerMock{
+		WithinTxFunc: withinTx,
+		WithinTxOptionsFunc: func(ctx context.Context, _ repository.TxOptions, fn func(context.Context, *repository.Repositories) error) error {
+			return withinTx(ctx, fn)
+		},
+	}
+}
+
+// orderWithUID возвращает корректный заказ с заданным order_uid.
+func orderWithUID(uid string) *model.Order {
+	order := validOrder()
+	order.OrderUID = uid
+	return order
+}
+
+// statuses возвращает статусы результатов сохранения.
+func statuses(results []SaveResult) []SaveStatus {
+	out := make([]SaveStatus, 0, len(results))
+	for _, r := range results {
+		out = append(out, r.Status)
+	}
+	return out
+}
+
+// TestSaveBatchValidationFailures проверяет, что некорректные заказы не доходят до БД и не мешают сохранению остальных.
+func TestSaveBatchValidationFailures(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	ctx := context.Background()
+
+	store := newMockStore(nil)
+	tx := store.txManager()
+	svc := NewOrderService(tx, store.repos, nil)
+
+	noItems := orderWithUID("order2")
+	noItems.Items = nil
+	badPhone := orderWithUID("order3")
+	badPhone.Delivery.Phone = "call me"
+	results, err := svc.SaveBatch(ctx, []*model.Order{nil, noItems, badPhone})
+	if err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	for i, r := range results {
+		if r.Status != SaveStatusInvalid || r.Err == nil {
+			t.Errorf("order %d: expected invalid result, got %+v", i, r)
+		}
+	}
+	if len(tx.WithinTxOptionsCalls()) != 0 {
+		t.Errorf("batch without valid orders must not open a transaction, got %d", len(tx.WithinTxOptionsCalls()))
+	}
+
+	results, err = svc.SaveBatch(ctx, []*model.Order{orderWithUID("order1"), badPhone, orderWithUID("order1")})
+	if err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	if got, want := statuses(results), []SaveStatus{SaveStatusDuplicate, SaveStatusInvalid, SaveStatusSaved}; !slices.Equal(got, want) {
+		t.Fatalf("expected statuses %v, got %v", want, got)
+	}
+	inserts := store.orders.InsertManyCalls()
+	if len(inserts) != 1 || len(inserts[0].Orders) != 1 || inserts[0].Orders[0].OrderUID != "order1" {
+		t.Errorf("expected single insert of order1, got %+v", inserts)
+	}
+	if order := store.saved["order1"]; order == nil || order.Status != model.StatusCreated || order.DateCreated.IsZero() {
+		t.Errorf("expected new order with default status and creation date, got %+v", order)
+	}
+	if len(store.events.InsertManyCalls()) != 1 {
+		t.Errorf("expected order event to be written in the same transaction")
+	}
+}
+
+// TestSaveBatchRepeatedOrder проверяет, что из версий одного заказа в пакете сохраняется последняя.
+func TestSaveBatchRepeatedOrder(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	ctx := context.Background()
+
+	store := newMockStore(nil)
+	orderCache := cache.NewOrderCache()
+	svc := NewOrderService(store.txManager(), store.repos, nil, WithCache(orderCache))
+
+	first := orderWithUID("order1")
+	second := orderWithUID("order1")
+	second.Delivery.City = "Haifa"
+	resent := orderWithUID("order1")
+	resent.Delivery.City = "Haifa"
+	results, err := svc.SaveBatch(ctx, []*model.Order{first, second, orderWithUID("order2"), resent})
+	if err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	want := []SaveStatus{SaveStatusSuperseded, SaveStatusDuplicate, SaveStatusSaved, SaveStatusSaved}
+	if got := statuses(results); !slices.Equal(got, want) {
+		t.Fatalf("expected statuses %v, got %v", want, got)
+	}
+	inserts := store.orders.InsertManyCalls()
+	if len(inserts) != 1 || len(inserts[0].Orders) != 2 {
+		t.Fatalf("expected one insert of two orders, got %+v", inserts)
+	}
+	if store.saved["order1"] != resent {
+		t.Errorf("expected the last version of order1 to be saved")
+	}
+	if cached := orderCache.Get("order1"); cached == nil || cached.Delivery.City != "Haifa" {
+		t.Errorf("expected the last version of order1 in cache, got %+v", cached)
+	}
+
+	// Ранняя версия разделяет итог отклонённой последней
+	store = newMockStore(nil, "order3")
+	svc = NewOrderService(store.txManager(), store.repos, nil)
+	changed := orderWithUID("order3")
+	changed.Delivery.City = "Haifa"
+	results, err = svc.SaveBatch(ctx, []*model.Order{orderWithUID("order3"), changed})
+	if err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	if got, want := statuses(results), []SaveStatus{SaveStatusFailed, SaveStatusFailed}; !slices.Equal(got, want) {
+		t.Errorf("expected statuses %v, got %v", want, got)
+	}
+}
+
+// TestSaveBatchRollback проверяет исход отклонённой транзакции: ошибка в заказе
+// отмечается в его результате, а недоступность БД возвращается вызывающей стороне.
+func TestSaveBatchRollback(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	ctx := context.Background()
+
+	store := newMockStore(nil, "order1")
+	orderCache := cache.NewOrderCache()
+	svc := NewOrderService(store.txManager(), store.repos, nil, WithCache(orderCache))
+	results, err := svc.SaveBatch(ctx, []*model.Order{orderWithUID("order1")})
+	if err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	var pgErr *pgconn.PgError
+	if results[0].Status != SaveStatusFailed || !errors.As(results[0].Err, &pgErr) {
+		t.Fatalf("expected failed result with constraint error, got %+v", results[0])
+	}
+	if len(store.saved) != 0 || store.committed != 0 || orderCache.Len() != 0 {
+		t.Errorf("rolled back order must not be saved or cached")
+	}
+	if len(store.events.InsertManyCalls()) != 0 {
+		t.Errorf("order event must not be written after failed insert")
+	}
+
+	// Ошибка при записи журнала откатывает уже вставленный заказ
+	store = newMockStore(nil)
+	store.events.InsertManyFunc = func(context.Context, []model.OrderChange) error { return errors.New("events table is missing") }
+	svc = NewOrderService(store.txManager(), store.repos, nil)
+	results, err = svc.SaveBatch(ctx, []*model.Order{orderWithUID("order2")})
+	if err != nil || results[0].Status != SaveStatusFailed {
+		t.Fatalf("expected failed result, got %+v, %v", results, err)
+	}
+	if len(store.saved) != 0 {
+		t.Errorf("order must be rolled back, got %d saved orders", len(store.saved))
+	}
+
+	// Недоступная БД: пакет не сохранён целиком и будет повторён
+	store = newMockStore(nil)
+	store.orders.LockExistingFunc = func(context.Context, []string) (map[string]bool, error) { return nil, breaker.ErrOpen }
+	tx := store.txManager()
+	svc = NewOrderService(tx, store.repos, nil)
+	if _, err := svc.SaveBatch(ctx, []*model.Order{orderWithUID("order3"), orderWithUID("order4")}); !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("expected breaker.ErrOpen, got %v", err)
+	}
+	if len(tx.WithinTxOptionsCalls()) != 1 {
+		t.Errorf("unavailable database must not trigger one-by-one saving, got %d transactions", len(tx.WithinTxOptionsCalls()))
+	}
+}
+
+// TestSaveBatchPartial проверяет, что отклонённый заказ не отменяет сохранение остальных заказов пакета.
+func TestSaveBatchPartial(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	ctx := context.Background()
+
+	unchanged := orderWithUID("order3")
+	unchanged.DateCreated = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	stored := *unchanged
+	previous := orderWithUID("order4")
+	previous.Status = model.StatusShipped
+	changed := orderWithUID("order4")
+	changed.Delivery.City = "Haifa"
+	store := newMockStore(map[string]*model.Order{"order3": &stored, "order4": previous}, "order2")
+	tx := store.txManager()
+	svc := NewOrderService(tx, store.repos, nil)
+
+	batch := []*model.Order{orderWithUID("order1"), orderWithUID("order2"), unchanged, changed}
+	results, err := svc.SaveBatch(ctx, batch)
+	if err != nil {
+		t.Fatalf("SaveBatch failed: %v", err)
+	}
+	want := []SaveStatus{SaveStatusSaved, SaveStatusFailed, SaveStatusDuplicate, SaveStatusSaved}
+	if got := statuses(results); !slices.Equal(got, want) {
+		t.Fatalf("expected statuses %v, got %v", want, got)
+	}
+	// Пакетная транзакция и по одной на каждый заказ
+	if len(tx.WithinTxOptionsCalls()) != 1+len(batch) || store.committed != len(batch)-1 {
+		t.Errorf("expected %d transactions with %d commits, got %d with %d",
+			1+len(batch), len(batch)-1, len(tx.WithinTxOptionsCalls()), store.committed)
+	}
+	if store.saved["order1"] == nil || store.saved["order2"] != nil {
+		t.Errorf("expected only order1 inserted, got %v", store.saved)
+	}
+	// order4 обновляется в пакетной транзакции и повторно после её отката
+	for _, call := range store.orders.UpdateCalls() {
+		if call.Order.OrderUID != "order4" || call.Order.Status != model.StatusShipped {
+			t.Errorf("expected order4 update keeping its status, got %+v", call.Order)
+		}
+	}
+	if store.saved["order4"] != changed {
+		t.Errorf("expected changed order4 to be saved")
+	}
+}
+
+// TestGetOrderByID проверяет, что заказ читается целиком через репозиторий заказов.
+func TestGetOrderByID(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	ctx := context.Background()
+
+	store := newMockStore(map[string]*model.Order{"order1": orderWithUID("order1")})
+	svc := NewOrderService(store.txManager(), store.repos, nil)
+
+	order, err := svc.GetOrderByID(ctx, "order1")
+	if err != nil || order.OrderUID != "order1" {
+		t.Fatalf("expected order1, got %+v, %v", order, err)
+	}
+	if _, err := svc.GetOrderByID(ctx, "missing"); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows, got %v", err)
+	}
+	calls := store.orders.GetFullByIDCalls()
+	if len(calls) != 2 || calls[1].OrderUID != "missing" {
+		t.Errorf("unexpected repository calls %+v", calls)
+	}
+}
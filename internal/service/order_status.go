@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"l0_wb/internal/events"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+)
+
+// ErrUnknownStatus возвращается, если запрошен статус, которого нет в жизненном цикле заказа.
+var ErrUnknownStatus = errors.New("unknown order status")
+
+// TransitionError сообщает о недопустимом переходе заказа между статусами.
+type TransitionError struct {
+	From model.OrderStatus
+	To   model.OrderStatus
+}
+
+// Error реализует интерфейс error.
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("order status cannot change from %s to %s", e.From, e.To)
+}
+
+// UpdateStatus переводит заказ в новый статус.
+//
+//	Переход проверяется по жизненному циклу заказа (см. model.OrderStatus) под
+//	блокировкой строки заказа и записывается в журнал изменений в той же
+//	транзакции. После фиксации публикуется событие order.status_changed;
+//	ошибка публикации изменение не отменяет.
+//
+//	Параметры:
+//	- ctx: контекст выполнения; исполнитель изменения берётся из него.
+//	- orderUID: уникальный идентификатор заказа.
+//	- status: новый статус.
+//	Возвращает:
+//	- *model.Order: заказ в новом статусе.
+//	- error: ErrUnknownStatus, *TransitionError, pgx.ErrNoRows, если заказ не
+//	  найден или удалён, или ошибку запроса.
+func (s *orderService) UpdateStatus(ctx context.Context, orderUID string, status model.OrderStatus) (*model.Order, error) {
+	if !status.Valid() {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStatus, status)
+	}
+
+	var order *model.Order
+	var from model.OrderStatus
+	err := s.tx.WithinTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		existing, err := repos.Orders.LockExisting(ctx, []string{orderUID})
+		if err != nil {
+			return err
+		}
+		if !existing[orderUID] {
+			return pgx.ErrNoRows
+		}
+		current, err := repos.Orders.GetByID(ctx, orderUID)
+		if err != nil {
+			return err
+		}
+		if current.DeletedAt != nil {
+			return pgx.ErrNoRows
+		}
+		from = current.Status
+		if !from.CanTransitionTo(status) {
+			return &TransitionError{From: from, To: status}
+		}
+
+		if err := repos.Orders.SetStatus(ctx, orderUID, from, status); err != nil {
+			return err
+		}
+		if err := recordMark(ctx, repos, orderUID, model.ChangeStatus, "status", status); err != nil {
+			return err
+		}
+		order, err = loadOrder(ctx, repos, orderUID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.log(ctx).Info("Order status changed",
+		zap.String("order_uid", orderUID),
+		zap.String("from", string(from)),
+		zap.String("to", string(status)),
+	)
+
+	if s.publisher != nil {
+		change := events.StatusChange{
+			OrderUID:   orderUID,
+			From:       from,
+			To:         status,
+			Actor:      ActorFromContext(ctx),
+			OccurredAt: time.Now().UTC(),
+		}
+		if pubErr := s.publisher.PublishStatusChange(ctx, change); pubErr != nil {
+			s.log(ctx).Error("UpdateStatus: failed to publish status event", zap.String("order_uid", orderUID), zap.Error(pubErr))
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+	"l0_wb/internal/breaker"
+	"l0_wb/internal/errtrack"
+	"l0_wb/internal/metrics"
+	"l0_wb/internal/repository"
+)
+
+// Причины повтора транзакции, метка reason метрики database_retries_total.
+const (
+	retryReasonSerialization = "serialization_failure"
+	retryReasonDeadlock      = "deadlock"
+	retryReasonConnection    = "connection"
+)
+
+// RetryPolicy задаёт повтор транзакций при временных ошибках БД.
+type RetryPolicy struct {
+	MaxAttempts int           // Максимум попыток, включая первую (меньше 2 — без повторов)
+	BaseDelay   time.Duration // Задержка перед первым повтором; удваивается с каждой попыткой
+	MaxDelay    time.Duration // Верхняя граница задержки
+}
+
+// WithRetryPolicy задаёт повтор сохранения заказов при временных ошибках БД.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(s *orderService) {
+		s.retry = policy
+	}
+}
+
+// delay возвращает случайную задержку перед повтором после попытки attempt.
+//
+//	Верхняя граница растёт экспоненциально от BaseDelay до MaxDelay, а сама
+//	задержка выбирается равномерно от половины границы до границы, чтобы
+//	конкурирующие транзакции не повторялись одновременно.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	limit := p.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if d := p.BaseDelay << shift; d > 0 && (limit <= 0 || d < limit) {
+			limit = d
+		}
+	}
+	if limit <= 1 {
+		return limit
+	}
+	return limit/2 + rand.N(limit/2+1) //nolint:gosec // Криптостойкость для джиттера не требуется
+}
+
+// transientReason определяет, вызвана ли ошибка временным сбоем, после которого транзакцию стоит повторить.
+//
+//	Параметры:
+//	- err: ошибка транзакции.
+//	Возвращает:
+//	- string: причина повтора или пустую строку, если повтор не поможет.
+func transientReason(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "40001":
+			return retryReasonSerialization
+		case pgErr.Code == "40P01":
+			return retryReasonDeadlock
+		case strings.HasPrefix(pgErr.Code, "08"):
+			return retryReasonConnection
+		}
+		return ""
+	}
+	// Разомкнутый автомат и истёкший срок запроса повтором не исправить
+	if errors.Is(err, breaker.ErrOpen) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ""
+	}
+	if repository.IsDBFailure(err) {
+		return retryReasonConnection
+	}
+	return ""
+}
+
+// withRetry выполняет fn и повторяет её после временных ошибок БД согласно политике сервиса.
+//
+//	fn должна выполнять транзакцию целиком: повтор части транзакции после
+//	конфликта сериализации или взаимоблокировки невозможен.
+//	Параметры:
+//	- ctx: контекст выполнения; его отмена прекращает ожидание повтора.
+//	- operation: название операции для метрик и журналов.
+//	- fn: повторяемая операция.
+//	Возвращает:
+//	- error: ошибку последней попытки.
+func (s *orderService) withRetry(ctx context.Context, operation string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		reason := transientReason(err)
+		if reason == "" {
+			return err
+		}
+		if attempt >= s.retry.MaxAttempts {
+			if s.retry.MaxAttempts > 1 {
+				metrics.DBRetriesExhausted.WithLabelValues(operation).Inc()
+				// Ошибка повторилась во всех попытках — о ней нужно знать, а не только видеть в журнале
+				errtrack.CaptureError(ctx, err, map[string]string{"component": "db", "operation": operation, "reason": reason})
+			}
+			return err
+		}
+
+		metrics.DBRetries.WithLabelValues(operation, reason).Inc()
+		delay := s.retry.delay(attempt)
+		s.log(ctx).Warn("Transient database error, retrying",
+			zap.String("operation", operation),
+			zap.String("reason", reason),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"l0_wb/internal/breaker"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/util"
+)
+
+// flakyTxManager возвращает ошибки из errs по одной на транзакцию, затем фиксирует транзакции.
+type flakyTxManager struct {
+	errs  []error
+	calls int
+	opts  []repository.TxOptions
+}
+
+func (m *flakyTxManager) WithinTx(ctx context.Context, fn func(context.Context, *repository.Repositories) error) error {
+	return m.WithinTxOptions(ctx, repository.TxOptions{}, fn)
+}
+
+func (m *flakyTxManager) WithinTxOptions(_ context.Context, opts repository.TxOptions, _ func(context.Context, *repository.Repositories) error) error {
+	m.calls++
+	m.opts = append(m.opts, opts)
+	if len(m.errs) == 0 {
+		return nil
+	}
+	err := m.errs[0]
+	m.errs = m.errs[1:]
+	return err
+}
+
+// TestTransientReason проверяет классификацию ошибок БД.
+func TestTransientReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&pgconn.PgError{Code: "40001"}, retryReasonSerialization},
+		{fmt.Errorf("commit: %w", &pgconn.PgError{Code: "40P01"}), retryReasonDeadlock},
+		{&pgconn.PgError{Code: "08006"}, retryReasonConnection},
+		{io.ErrUnexpectedEOF, retryReasonConnection},
+		{&pgconn.PgError{Code: "23505"}, ""},
+		{breaker.ErrOpen, ""},
+		{context.DeadlineExceeded, ""},
+		{errors.New("boom"), ""},
+	}
+	for _, tt := range tests {
+		if got := transientReason(tt.err); got != tt.want {
+			t.Errorf("%v: expected %q, got %q", tt.err, tt.want, got)
+		}
+	}
+}
+
+// TestSaveBatchRetriesTransientErrors проверяет повтор транзакции с параметрами WithTxOptions после временных ошибок и отказ от повтора после исчерпания попыток.
+func TestSaveBatchRetriesTransientErrors(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	ctx := context.Background()
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+	tx := &flakyTxManager{errs: []error{&pgconn.PgError{Code: "40001"}, &pgconn.PgError{Code: "40P01"}}}
+	txOpts := repository.TxOptions{IsoLevel: repository.RepeatableRead}
+	svc := NewOrderService(tx, nil, nil, WithRetryPolicy(policy), WithTxOptions(txOpts))
+	results, err := svc.SaveBatch(ctx, []*model.Order{validOrder()})
+	if err != nil || results[0].Status != SaveStatusSaved {
+		t.Fatalf("expected order saved after retries, got %+v, %v", results, err)
+	}
+	if tx.calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", tx.calls)
+	}
+	for i, opts := range tx.opts {
+		if opts != txOpts {
+			t.Errorf("attempt %d: transaction options %+v, want %+v", i+1, opts, txOpts)
+		}
+	}
+
+	conflict := &pgconn.PgError{Code: "40001"}
+	tx = &flakyTxManager{errs: []error{conflict, conflict, conflict}}
+	svc = NewOrderService(tx, nil, nil, WithRetryPolicy(policy))
+	results, err = svc.SaveBatch(ctx, []*model.Order{validOrder()})
+	if err != nil || results[0].Status != SaveStatusFailed || !errors.Is(results[0].Err, conflict) {
+		t.Fatalf("expected failed order after exhausted retries, got %+v, %v", results, err)
+	}
+	if tx.calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", tx.calls)
+	}
+}
+
+// TestRetryPolicyDelay проверяет границы задержки между попытками.
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	for attempt, limit := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 5: 50 * time.Millisecond, 40: 50 * time.Millisecond} {
+		for range 20 {
+			if d := policy.delay(attempt); d < limit/2 || d > limit {
+				t.Fatalf("attempt %d: delay %v outside [%v, %v]", attempt, d, limit/2, limit)
+			}
+		}
+	}
+}
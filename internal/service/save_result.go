@@ -0,0 +1,26 @@
+package service
+
+// SaveStatus — итог сохранения одного заказа пакета.
+type SaveStatus string
+
+// Итоги сохранения заказа.
+const (
+	SaveStatusSaved      SaveStatus = "saved"             // Заказ вставлен или его изменения сохранены
+	SaveStatusDuplicate  SaveStatus = "skipped_duplicate" // Такой же заказ уже сохранён или повторяется в пакете
+	SaveStatusSuperseded SaveStatus = "superseded"        // Вместо заказа сохранена его более поздняя версия из того же пакета
+	SaveStatusInvalid    SaveStatus = "invalid"           // Заказ не прошёл валидацию
+	SaveStatusFailed     SaveStatus = "failed"            // Сохранение заказа завершилось ошибкой
+)
+
+// SaveResult — результат сохранения одного заказа пакета.
+type SaveResult struct {
+	OrderUID string
+	Status   SaveStatus
+	Err      error // Причина для SaveStatusInvalid и SaveStatusFailed
+}
+
+// Stored сообщает, находится ли заказ в БД в переданном состоянии или в
+// состоянии более поздней версии из того же пакета.
+func (r SaveResult) Stored() bool {
+	return r.Status == SaveStatusSaved || r.Status == SaveStatusDuplicate || r.Status == SaveStatusSuperseded
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"l0_wb/internal/model"
+	"l0_wb/internal/pagination"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/tracing"
+)
+
+// Атрибуты спанов сервиса.
+const (
+	orderUIDKey  = attribute.Key("order.uid")
+	batchSizeKey = attribute.Key("order.batch_size")
+)
+
+// tracedOrderService оборачивает каждый метод OrderService спаном трассировки.
+//
+//	Идентификаторы покупателей в спаны не записываются, так как относятся к
+//	персональным данным; заказы помечаются order_uid.
+type tracedOrderService struct {
+	next OrderService
+}
+
+var _ OrderService = tracedOrderService{}
+
+// start начинает спан метода сервиса.
+func (t tracedOrderService) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "OrderService."+method, trace.WithAttributes(attrs...))
+}
+
+// SaveOrder реализует OrderService.
+func (t tracedOrderService) SaveOrder(ctx context.Context, order *model.Order) (err error) {
+	ctx, span := t.start(ctx, "SaveOrder", orderUIDKey.String(orderUID(order)))
+	defer func() { tracing.End(span, err) }()
+	return t.next.SaveOrder(ctx, order)
+}
+
+// SaveBatch реализует OrderService.
+func (t tracedOrderService) SaveBatch(ctx context.Context, orders []*model.Order) (_ []SaveResult, err error) {
+	ctx, span := t.start(ctx, "SaveBatch", batchSizeKey.Int(len(orders)))
+	defer func() { tracing.End(span, err) }()
+	return t.next.SaveBatch(ctx, orders)
+}
+
+// GetOrderByID реализует OrderService.
+func (t tracedOrderService) GetOrderByID(ctx context.Context, orderUID string) (_ *model.Order, err error) {
+	ctx, span := t.start(ctx, "GetOrderByID", orderUIDKey.String(orderUID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetOrderByID(ctx, orderUID)
+}
+
+// GetOrdersByCustomerID реализует OrderService.
+func (t tracedOrderService) GetOrdersByCustomerID(ctx context.Context, customerID string, after *pagination.Cursor, limit int) (_ []*model.Order, _ *pagination.Cursor, err error) {
+	ctx, span := t.start(ctx, "GetOrdersByCustomerID")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetOrdersByCustomerID(ctx, customerID, after, limit)
+}
+
+// ListOrders реализует OrderService.
+func (t tracedOrderService) ListOrders(ctx context.Context, filter repository.OrderFilter, after *pagination.Cursor, limit int) (_ []*model.Order, _ *pagination.Cursor, err error) {
+	ctx, span := t.start(ctx, "ListOrders")
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListOrders(ctx, filter, after, limit)
+}
+
+// DeleteOrder реализует OrderService.
+func (t tracedOrderService) DeleteOrder(ctx context.Context, orderUID string) (err error) {
+	ctx, span := t.start(ctx, "DeleteOrder", orderUIDKey.String(orderUID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.DeleteOrder(ctx, orderUID)
+}
+
+// RestoreOrder реализует OrderService.
+func (t tracedOrderService) RestoreOrder(ctx context.Context, orderUID string) (_ *model.Order, err error) {
+	ctx, span := t.start(ctx, "RestoreOrder", orderUIDKey.String(orderUID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.RestoreOrder(ctx, orderUID)
+}
+
+// ArchiveOrder реализует OrderService.
+func (t tracedOrderService) ArchiveOrder(ctx context.Context, orderUID string) (_ *model.Order, err error) {
+	ctx, span := t.start(ctx, "ArchiveOrder", orderUIDKey.String(orderUID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ArchiveOrder(ctx, orderUID)
+}
+
+// GetOrderHistory реализует OrderService.
+func (t tracedOrderService) GetOrderHistory(ctx context.Context, orderUID string) (_ []model.OrderChange, err error) {
+	ctx, span := t.start(ctx, "GetOrderHistory", orderUIDKey.String(orderUID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetOrderHistory(ctx, orderUID)
+}
+
+// UpdateStatus реализует OrderService.
+func (t tracedOrderService) UpdateStatus(ctx context.Context, orderUID string, status model.OrderStatus) (_ *model.Order, err error) {
+	ctx, span := t.start(ctx, "UpdateStatus", orderUIDKey.String(orderUID), attribute.String("order.status", string(status)))
+	defer func() { tracing.End(span, err) }()
+	return t.next.UpdateStatus(ctx, orderUID, status)
+}
+
+// EraseCustomerData реализует OrderService.
+func (t tracedOrderService) EraseCustomerData(ctx context.Context, customerID string) (_ []*model.Order, err error) {
+	ctx, span := t.start(ctx, "EraseCustomerData")
+	defer func() { tracing.End(span, err) }()
+	return t.next.EraseCustomerData(ctx, customerID)
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"l0_wb/internal/i18n"
+	"l0_wb/internal/model"
+)
+
+// Правила валидации, нарушение которых указывается в FieldError.Rule.
+const (
+	RuleRequired = "required" // Поле обязательно
+	RuleFormat   = "format"   // Значение не соответствует формату
+	RuleRange    = "range"    // Значение вне допустимого диапазона
+	RuleMismatch = "mismatch" // Значение не согласуется с другими полями заказа
+)
+
+// maxOrderUIDLength ограничивает длину order_uid.
+const maxOrderUIDLength = 64
+
+// priceTolerance — допустимое расхождение total_price товара с ценой за вычетом скидки
+// из-за округления до целых.
+const priceTolerance = 1
+
+var (
+	orderUIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	phonePattern    = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+	zipPattern      = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 -]{1,9}$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// phoneSeparators удаляет из телефона допустимые разделители перед проверкой формата.
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "")
+
+// FieldError описывает нарушение правила валидации одного поля заказа.
+type FieldError struct {
+	Field   string `json:"field"`   // Путь к полю в JSON-представлении заказа, например items[0].price
+	Rule    string `json:"rule"`    // Нарушенное правило: одна из констант Rule*
+	Message string `json:"message"` // Описание нарушения для человека
+
+	format string // Сообщение на английском в формате fmt.Sprintf, ключ перевода
+	args   []any  // Параметры сообщения
+}
+
+// ValidationError содержит все нарушения правил валидации заказа.
+type ValidationError struct {
+	Fields []FieldError
+	Locale string // Локаль заказа (поле locale), на языке которой клиенту показываются сообщения
+
+	lang string // Язык сообщений (см. Localize)
+}
+
+// Error реализует интерфейс error.
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return i18n.Sprintf(e.lang, "invalid order: %s", strings.Join(parts, "; "))
+}
+
+// Localize возвращает копию ошибки с сообщениями на языке lang.
+//
+//	Сообщения, заданные вне ValidateOrder, остаются без изменений.
+//
+//	Параметры:
+//	- lang: язык сообщений (см. i18n.Parse).
+//	Возвращает:
+//	- *ValidationError: ошибка с переведёнными сообщениями.
+func (e *ValidationError) Localize(lang string) *ValidationError {
+	fields := make([]FieldError, len(e.Fields))
+	for i, f := range e.Fields {
+		if f.format != "" {
+			f.Message = i18n.Sprintf(lang, f.format, f.args...)
+		}
+		fields[i] = f
+	}
+	return &ValidationError{Fields: fields, Locale: e.Locale, lang: lang}
+}
+
+// validator накапливает нарушения правил валидации.
+type validator struct {
+	fields []FieldError
+}
+
+// add добавляет нарушение правила с сообщением на английском.
+func (v *validator) add(field, rule, format string, args ...any) {
+	v.fields = append(v.fields, FieldError{
+		Field:   field,
+		Rule:    rule,
+		Message: i18n.Sprintf(i18n.Default, format, args...),
+		format:  format,
+		args:    args,
+	})
+}
+
+// required проверяет, что строковое поле заполнено.
+func (v *validator) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, RuleRequired, "is required")
+		return false
+	}
+	return true
+}
+
+// nonNegative проверяет, что числовое поле не отрицательно.
+func (v *validator) nonNegative(field string, value int) {
+	if value < 0 {
+		v.add(field, RuleRange, "must not be negative")
+	}
+}
+
+// ValidateOrder проверяет заказ перед сохранением.
+//
+//	Проверяются обязательные поля, формат order_uid, телефона, email, индекса
+//	и валюты, диапазоны сумм, согласованность total_price товаров с ценой и
+//	скидкой и соответствие goods_total сумме товаров. Необязательные поля
+//	проверяются, только если заполнены. Проверка не прерывается на первом
+//	нарушении: в ошибке перечисляются все.
+//
+// Параметры:
+// - order: объект заказа.
+//
+// Возвращает:
+// - error: *ValidationError, если заказ некорректен.
+func ValidateOrder(order *model.Order) error {
+	var v validator
+	if order == nil {
+		v.add("order", RuleRequired, "order is nil")
+		return &ValidationError{Fields: v.fields}
+	}
+
+	if v.required("order_uid", order.OrderUID) {
+		if len(order.OrderUID) > maxOrderUIDLength || !orderUIDPattern.MatchString(order.OrderUID) {
+			v.add("order_uid", RuleFormat, "must contain only letters, digits, '-' and '_' and be at most %d characters", maxOrderUIDLength)
+		}
+	}
+
+	validateDelivery(&v, &order.Delivery)
+	validatePayment(&v, &order.Payment)
+
+	if len(order.Items) == 0 {
+		v.add("items", RuleRequired, "order has no items")
+	}
+	goodsTotal := 0
+	for i, item := range order.Items {
+		validateItem(&v, fmt.Sprintf("items[%d]", i), &item)
+		goodsTotal += item.TotalPrice
+	}
+	if order.Payment.GoodsTotal > 0 && len(order.Items) > 0 && order.Payment.GoodsTotal != goodsTotal {
+		v.add("payment.goods_total", RuleMismatch, "must equal the sum of items total_price (%d)", goodsTotal)
+	}
+
+	if len(v.fields) > 0 {
+		return &ValidationError{Fields: v.fields, Locale: order.Locale}
+	}
+	return nil
+}
+
+// validateDelivery проверяет данные доставки.
+func validateDelivery(v *validator, d *model.Delivery) {
+	v.required("delivery.name", d.Name)
+	if v.required("delivery.phone", d.Phone) && !phonePattern.MatchString(phoneSeparators.Replace(d.Phone)) {
+		v.add("delivery.phone", RuleFormat, "must contain 7 to 15 digits with an optional leading '+'")
+	}
+	if d.Email != "" {
+		if addr, err := mail.ParseAddress(d.Email); err != nil || addr.Address != d.Email {
+			v.add("delivery.email", RuleFormat, "must be a valid email address")
+		}
+	}
+	if d.Zip != "" && !zipPattern.MatchString(d.Zip) {
+		v.add("delivery.zip", RuleFormat, "must be 2 to 10 letters, digits, spaces or '-'")
+	}
+}
+
+// validatePayment проверяет данные оплаты.
+func validatePayment(v *validator, p *model.Payment) {
+	if p.Currency != "" && !currencyPattern.MatchString(p.Currency) {
+		v.add("payment.currency", RuleFormat, "must be a three-letter ISO 4217 code")
+	}
+	v.nonNegative("payment.amount", p.Amount)
+	v.nonNegative("payment.delivery_cost", p.DeliveryCost)
+	v.nonNegative("payment.goods_total", p.GoodsTotal)
+	v.nonNegative("payment.custom_fee", p.CustomFee)
+}
+
+// validateItem проверяет товар заказа.
+func validateItem(v *validator, field string, item *model.Item) {
+	v.nonNegative(field+".price", item.Price)
+	v.nonNegative(field+".total_price", item.TotalPrice)
+	if item.Sale < 0 || item.Sale > 100 {
+		v.add(field+".sale", RuleRange, "must be between 0 and 100")
+		return
+	}
+	if item.Price > 0 {
+		expected := item.Price * (100 - item.Sale) / 100
+		if diff := item.TotalPrice - expected; diff > priceTolerance || diff < -priceTolerance {
+			v.add(field+".total_price", RuleMismatch, "must equal price minus sale (%d)", expected)
+		}
+	}
+}
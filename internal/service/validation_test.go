@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"l0_wb/internal/model"
+)
+
+// validOrder возвращает заказ, проходящий все правила валидации.
+func validOrder() *model.Order {
+	return &model.Order{
+		OrderUID: "b563feb7b2b84b6test",
+		Delivery: model.Delivery{
+			Name:  "Test Testov",
+			Phone: "+9720000000",
+			Zip:   "2639809",
+			Email: "test@gmail.com",
+		},
+		Payment: model.Payment{Currency: "USD", Amount: 1817, DeliveryCost: 1500, GoodsTotal: 317},
+		Items:   []model.Item{{ChrtID: 9934930, Price: 453, Sale: 30, TotalPrice: 317}},
+	}
+}
+
+// TestValidateOrder проверяет правила валидации и перечисление всех нарушений по полям.
+func TestValidateOrder(t *testing.T) {
+	if err := ValidateOrder(validOrder()); err != nil {
+		t.Fatalf("expected valid order, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(o *model.Order)
+		fields []string
+	}{
+		{"uid format", func(o *model.Order) { o.OrderUID = "bad uid!" }, []string{"order_uid"}},
+		{"missing fields", func(o *model.Order) { o.OrderUID, o.Delivery.Name, o.Items = "", "", nil },
+			[]string{"order_uid", "delivery.name", "items"}},
+		{"phone", func(o *model.Order) { o.Delivery.Phone = "call me" }, []string{"delivery.phone"}},
+		{"phone separators", func(o *model.Order) { o.Delivery.Phone = "+7 (999) 123-45-67" }, nil},
+		{"email", func(o *model.Order) { o.Delivery.Email = "Test <test@gmail.com>" }, []string{"delivery.email"}},
+		{"zip", func(o *model.Order) { o.Delivery.Zip = "#1" }, []string{"delivery.zip"}},
+		{"currency", func(o *model.Order) { o.Payment.Currency = "usd" }, []string{"payment.currency"}},
+		{"negative amount", func(o *model.Order) { o.Payment.Amount = -1 }, []string{"payment.amount"}},
+		{"item total", func(o *model.Order) { o.Items[0].TotalPrice, o.Payment.GoodsTotal = 453, 453 },
+			[]string{"items[0].total_price"}},
+		{"sale range", func(o *model.Order) { o.Items[0].Sale = 120 }, []string{"items[0].sale"}},
+		{"goods total", func(o *model.Order) { o.Payment.GoodsTotal = 300 }, []string{"payment.goods_total"}},
+	}
+
+	for _, tt := range tests {
+		order := validOrder()
+		tt.modify(order)
+		err := ValidateOrder(order)
+		if tt.fields == nil {
+			if err != nil {
+				t.Errorf("%s: expected valid order, got %v", tt.name, err)
+			}
+			continue
+		}
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("%s: expected *ValidationError, got %v", tt.name, err)
+		}
+		if len(validationErr.Fields) != len(tt.fields) {
+			t.Errorf("%s: expected fields %v, got %+v", tt.name, tt.fields, validationErr.Fields)
+			continue
+		}
+		for i, field := range tt.fields {
+			if validationErr.Fields[i].Field != field || validationErr.Fields[i].Rule == "" {
+				t.Errorf("%s: expected field %s, got %+v", tt.name, field, validationErr.Fields[i])
+			}
+		}
+	}
+}
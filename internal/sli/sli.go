@@ -0,0 +1,186 @@
+// Package sli provides sliding-window error-rate calculation shared by resilience features.
+package sli
+
+import (
+	"sync"
+	"time"
+)
+
+// Источники событий, для которых ведётся расчёт SLI.
+const (
+	SourceHTTP = "http" // HTTP-запросы к API (ошибка — ответ 5xx)
+	SourceDB   = "db"   // Операции с базой данных
+)
+
+// Параметры окна по умолчанию.
+const (
+	defaultWindow  = time.Minute
+	defaultBuckets = 12
+)
+
+// Snapshot содержит значения SLI за текущее окно.
+type Snapshot struct {
+	Total     uint64  `json:"total"`      // Количество событий в окне
+	Errors    uint64  `json:"errors"`     // Количество неуспешных событий в окне
+	ErrorRate float64 `json:"error_rate"` // Доля неуспешных событий (0..1)
+}
+
+// Exceeds сообщает, превышена ли допустимая доля ошибок.
+//
+//	Порог не считается превышенным, пока в окне меньше minEvents событий,
+//	чтобы единичные ошибки при низком трафике не переключали поведение сервиса.
+//	Параметры:
+//	- maxErrorRate: допустимая доля ошибок.
+//	- minEvents: минимальное количество событий для принятия решения.
+//	Возвращает:
+//	- bool: true, если порог превышен.
+func (s Snapshot) Exceeds(maxErrorRate float64, minEvents uint64) bool {
+	return s.Total >= minEvents && s.ErrorRate > maxErrorRate
+}
+
+// bucket хранит счётчики событий за один интервал окна.
+type bucket struct {
+	start  int64 // Начало интервала (UnixNano), к которому относятся счётчики
+	total  uint64
+	errors uint64
+}
+
+// Window — скользящее окно успешных и неуспешных событий, разбитое на корзины.
+type Window struct {
+	mu      sync.Mutex
+	buckets []bucket
+	width   int64 // Ширина корзины в наносекундах
+	now     func() time.Time
+}
+
+// NewWindow создаёт скользящее окно.
+//
+//	Параметры:
+//	- size: длительность окна.
+//	- buckets: количество корзин, на которые делится окно.
+//	Возвращает:
+//	- *Window: экземпляр окна.
+func NewWindow(size time.Duration, buckets int) *Window {
+	if buckets < 1 {
+		buckets = 1
+	}
+	if size <= 0 {
+		size = defaultWindow
+	}
+	width := int64(size) / int64(buckets)
+	if width < 1 {
+		width = 1
+	}
+	return &Window{
+		buckets: make([]bucket, buckets),
+		width:   width,
+		now:     time.Now,
+	}
+}
+
+// Record учитывает одно событие.
+//
+//	Параметры:
+//	- success: true, если событие завершилось успешно.
+func (w *Window) Record(success bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	start := w.now().UnixNano() / w.width * w.width
+	b := &w.buckets[(start/w.width)%int64(len(w.buckets))]
+	if b.start != start {
+		*b = bucket{start: start}
+	}
+	b.total++
+	if !success {
+		b.errors++
+	}
+}
+
+// Snapshot возвращает значения SLI за окно, отсчитываемое от текущего момента.
+func (w *Window) Snapshot() Snapshot {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	oldest := w.now().UnixNano() - w.width*int64(len(w.buckets))
+	var s Snapshot
+	for _, b := range w.buckets {
+		if b.start > oldest {
+			s.Total += b.total
+			s.Errors += b.errors
+		}
+	}
+	if s.Total > 0 {
+		s.ErrorRate = float64(s.Errors) / float64(s.Total)
+	}
+	return s
+}
+
+// Calculator хранит окна SLI для нескольких источников событий.
+type Calculator struct {
+	mu      sync.Mutex
+	size    time.Duration
+	buckets int
+	windows map[string]*Window
+}
+
+// NewCalculator создаёт калькулятор с окнами заданной длительности.
+func NewCalculator(size time.Duration, buckets int) *Calculator {
+	return &Calculator{
+		size:    size,
+		buckets: buckets,
+		windows: make(map[string]*Window),
+	}
+}
+
+// window возвращает окно источника, создавая его при первом обращении.
+func (c *Calculator) window(source string) *Window {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w, ok := c.windows[source]
+	if !ok {
+		w = NewWindow(c.size, c.buckets)
+		c.windows[source] = w
+	}
+	return w
+}
+
+// Record учитывает событие источника.
+func (c *Calculator) Record(source string, success bool) {
+	c.window(source).Record(success)
+}
+
+// Snapshot возвращает SLI источника за текущее окно.
+func (c *Calculator) Snapshot(source string) Snapshot {
+	return c.window(source).Snapshot()
+}
+
+var (
+	defaultMu         sync.RWMutex
+	defaultCalculator = NewCalculator(defaultWindow, defaultBuckets)
+)
+
+// Configure задаёт длительность окна общего калькулятора. Накопленные данные сбрасываются.
+//
+//	Вызывается один раз при старте приложения, до начала обработки запросов.
+func Configure(window time.Duration) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultCalculator = NewCalculator(window, defaultBuckets)
+}
+
+// Record учитывает событие источника в общем калькуляторе.
+func Record(source string, success bool) {
+	defaultMu.RLock()
+	c := defaultCalculator
+	defaultMu.RUnlock()
+	c.Record(source, success)
+}
+
+// Get возвращает SLI источника из общего калькулятора.
+func Get(source string) Snapshot {
+	defaultMu.RLock()
+	c := defaultCalculator
+	defaultMu.RUnlock()
+	return c.Snapshot(source)
+}
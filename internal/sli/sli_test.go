@@ -0,0 +1,47 @@
+package sli
+
+import (
+	"testing"
+	"time"
+)
+
+// TestWindow проверяет подсчёт доли ошибок и вытеснение устаревших корзин.
+func TestWindow(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	w := NewWindow(10*time.Second, 10)
+	w.now = func() time.Time { return now }
+
+	for i := 0; i < 6; i++ {
+		w.Record(true)
+	}
+	for i := 0; i < 4; i++ {
+		w.Record(false)
+	}
+
+	s := w.Snapshot()
+	if s.Total != 10 || s.Errors != 4 {
+		t.Fatalf("expected 10 events with 4 errors, got %d/%d", s.Total, s.Errors)
+	}
+	if s.ErrorRate != 0.4 {
+		t.Errorf("expected error rate 0.4, got %v", s.ErrorRate)
+	}
+	if !s.Exceeds(0.3, 10) {
+		t.Errorf("expected threshold 0.3 to be exceeded")
+	}
+	if s.Exceeds(0.3, 11) {
+		t.Errorf("expected threshold to be ignored below minimum events")
+	}
+
+	// Через 5 секунд события ещё в окне, добавляем успешное.
+	now = now.Add(5 * time.Second)
+	w.Record(true)
+	if s := w.Snapshot(); s.Total != 11 {
+		t.Errorf("expected 11 events in window, got %d", s.Total)
+	}
+
+	// Через 11 секунд после первых событий в окне остаётся только последнее.
+	now = now.Add(6 * time.Second)
+	if s := w.Snapshot(); s.Total != 1 || s.Errors != 0 {
+		t.Errorf("expected only 1 successful event in window, got %d/%d", s.Total, s.Errors)
+	}
+}
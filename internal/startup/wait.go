@@ -0,0 +1,83 @@
+// Package startup waits for external dependencies to become reachable during service startup.
+package startup
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/util"
+)
+
+// attemptTimeout ограничивает время одной попытки подключения.
+const attemptTimeout = 5 * time.Second
+
+// Policy задаёт повтор подключения к зависимости при запуске.
+type Policy struct {
+	MaxAttempts int           // Максимум попыток, включая первую (меньше 2 — без повторов)
+	BaseDelay   time.Duration // Задержка перед первым повтором; удваивается с каждой попыткой
+	MaxDelay    time.Duration // Верхняя граница задержки
+}
+
+// delay возвращает задержку перед повтором после попытки attempt.
+//
+//	Задержка растёт экспоненциально от BaseDelay до MaxDelay; случайная
+//	добавка до четверти задержки разводит одновременно стартующие экземпляры.
+func (p Policy) delay(attempt int) time.Duration {
+	d := p.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if exp := p.BaseDelay << shift; exp > 0 && (d <= 0 || exp < d) {
+			d = exp
+		}
+	}
+	if d <= 4 {
+		return d
+	}
+	return d + rand.N(d/4) //nolint:gosec // Криптостойкость для джиттера не требуется
+}
+
+// Wait выполняет connect, пока подключение не удастся или не закончатся попытки.
+//
+//	Каждая попытка ограничена attemptTimeout; неудачные попытки пишутся в
+//	журнал с задержкой до следующей.
+//	Параметры:
+//	- ctx: контекст выполнения; его отмена прекращает ожидание.
+//	- name: название зависимости для журнала.
+//	- policy: политика повтора.
+//	- connect: попытка подключения.
+//	Возвращает:
+//	- error: ошибку последней попытки, если зависимость так и не стала доступна.
+func Wait(ctx context.Context, name string, policy Policy, connect func(ctx context.Context) error) error {
+	logger := util.GetLogger().With(zap.String("dependency", name))
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, attemptTimeout)
+		err := connect(attemptCtx)
+		cancel()
+		if err == nil {
+			if attempt > 1 {
+				logger.Info("Dependency is available", zap.Int("attempts", attempt))
+			}
+			return nil
+		}
+		if attempt >= policy.MaxAttempts {
+			return fmt.Errorf("%s is unavailable after %d attempts: %w", name, attempt, err)
+		}
+
+		delay := policy.delay(attempt)
+		logger.Warn("Dependency is unavailable, retrying",
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", policy.MaxAttempts),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("waiting for %s: %w", name, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package startup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"l0_wb/internal/util"
+)
+
+// TestWait проверяет повтор до успешной попытки и отказ после исчерпания попыток.
+func TestWait(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	policy := Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	errDown := errors.New("connection refused")
+
+	calls := 0
+	err := Wait(context.Background(), "db", policy, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errDown
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("Wait = %v after %d calls, want success after 3", err, calls)
+	}
+
+	calls = 0
+	err = Wait(context.Background(), "db", policy, func(context.Context) error {
+		calls++
+		return errDown
+	})
+	if !errors.Is(err, errDown) || calls != 3 {
+		t.Fatalf("Wait = %v after %d calls, want errDown after 3", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = Wait(ctx, "db", Policy{MaxAttempts: 10, BaseDelay: time.Hour}, func(context.Context) error {
+		return errDown
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait with canceled context = %v, want context.Canceled", err)
+	}
+}
+
+// TestPolicyDelay проверяет рост задержки и её верхнюю границу.
+func TestPolicyDelay(t *testing.T) {
+	p := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, base := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 10: time.Second} {
+		if d := p.delay(attempt); d < base || d > base+base/4 {
+			t.Errorf("delay(%d) = %v, want within [%v, %v]", attempt, d, base, base+base/4)
+		}
+	}
+}
@@ -3,52 +3,175 @@ package main
 
 import (
 	"encoding/json"
-	"flag"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/spf13/cobra"
 	vegeta "github.com/tsenart/vegeta/v12/lib"
 )
 
-// main запускает стресс-тест с использованием Vegeta.
+// main запускает стресс-тест с использованием Vegeta или выводит отчёт по его результатам.
+//
+//	Команды:
+//	- attack: нагружает URL и сохраняет метрики в --output;
+//	- report: выводит отчёт по сохранённым метрикам.
 //
 //	Пример запуска:
-//	go run internal/tools/ht/stress_tester.go -url=http://localhost:8081/order/test-0 -rate=100 -duration=30 -output=stress_test_results.json
+//	go run internal/tools/ht/stress_tester.go attack --url=http://localhost:8081/api/v1/orders/test-0 --rate=100 --duration=30 --output=stress_test_results.json
+//	go run internal/tools/ht/stress_tester.go report --input=stress_test_results.json --format=text
 func main() {
-	// Параметры командной строки
-	url := flag.String("url", "http://localhost:8081/order/test-0", "Target URL for stress testing")
-	rate := flag.Int("rate", 1000, "Requests per second")
-	duration := flag.Int("duration", 30, "Test duration in seconds")
-	output := flag.String("output", "stress_test_results.json", "Output file for test results")
-	flag.Parse()
+	if err := newRootCmd(os.Stdout).Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// attackOptions содержит параметры команды attack.
+type attackOptions struct {
+	url      string
+	rate     int
+	duration int // Длительность теста в секундах
+	output   string
+	format   string
+}
+
+// reportOptions содержит параметры команды report.
+type reportOptions struct {
+	input  string
+	format string
+}
+
+// newRootCmd создаёт корневую команду с подкомандами attack и report.
+//
+//	Параметры:
+//	- out: поток для вывода отчётов.
+//	Возвращает:
+//	- *cobra.Command: корневая команда.
+func newRootCmd(out io.Writer) *cobra.Command {
+	root := &cobra.Command{
+		Use:          "stress_tester",
+		Short:        "HTTP stress testing with Vegeta",
+		SilenceUsage: true,
+	}
+	root.SetOut(out)
+	root.AddCommand(newAttackCmd(), newReportCmd())
+	return root
+}
+
+// newAttackCmd создаёт команду attack.
+func newAttackCmd() *cobra.Command {
+	var opts attackOptions
+	cmd := &cobra.Command{
+		Use:   "attack",
+		Short: "Load the target URL and save metrics to the output file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runAttack(opts, cmd.OutOrStdout())
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.url, "url", "http://localhost:8081/api/v1/orders/test-0", "Target URL for stress testing")
+	flags.IntVar(&opts.rate, "rate", 1000, "Requests per second")
+	flags.IntVar(&opts.duration, "duration", 30, "Test duration in seconds")
+	flags.StringVar(&opts.output, "output", "stress_test_results.json", "Output file for test results")
+	flags.StringVar(&opts.format, "format", "text", "Report format printed after the test: text or json")
+	return cmd
+}
 
+// newReportCmd создаёт команду report.
+func newReportCmd() *cobra.Command {
+	var opts reportOptions
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Print a report for metrics saved by attack",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runReport(opts, cmd.OutOrStdout())
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.input, "input", "stress_test_results.json", "File with results saved by attack")
+	flags.StringVar(&opts.format, "format", "text", "Report format: text or json")
+	return cmd
+}
+
+// runAttack проверяет параметры, запускает стресс-тест и сохраняет результаты.
+//
+//	Параметры:
+//	- opts: параметры команды.
+//	- out: поток для вывода отчёта.
+//	Возвращает:
+//	- error: ошибка параметров, теста или сохранения результатов.
+func runAttack(opts attackOptions, out io.Writer) error {
 	// Проверка параметров
-	if *url == "" {
-		log.Fatal("Target URL is required")
+	if opts.url == "" {
+		return errors.New("target URL is required")
+	}
+	if opts.rate < 1 || opts.duration < 1 {
+		return errors.New("rate and duration must be positive")
+	}
+	report, err := newReporter(opts.format)
+	if err != nil {
+		return err
+	}
+	// Путь проверяется до теста, чтобы не терять результаты
+	outPath, err := outputPath(opts.output)
+	if err != nil {
+		return err
 	}
 
-	log.Printf("Starting stress test: %d RPS for %d seconds on %s", *rate, *duration, *url)
+	log.Printf("Starting stress test: %d RPS for %d seconds on %s", opts.rate, opts.duration, opts.url)
+	metrics := RunStressTest(opts.url, opts.rate, opts.duration)
 
-	// Запуск стресс-теста
-	if err := RunStressTest(*url, *rate, *duration, *output); err != nil {
-		log.Fatalf("Stress test failed: %v", err)
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics to JSON: %w", err)
+	}
+	if err := os.WriteFile(outPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write data to file: %w", err)
 	}
+	log.Printf("Stress test results saved to %s", outPath)
 
-	log.Println("Stress test completed successfully")
+	return report(metrics, out)
 }
 
-// RunStressTest запускает стресс-тест и сохраняет результаты в файл.
+// runReport выводит отчёт по сохранённым метрикам.
+//
+//	Параметры:
+//	- opts: параметры команды.
+//	- out: поток для вывода отчёта.
+//	Возвращает:
+//	- error: ошибка параметров или чтения результатов.
+func runReport(opts reportOptions, out io.Writer) error {
+	report, err := newReporter(opts.format)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(opts.input)
+	if err != nil {
+		return fmt.Errorf("failed to read results: %w", err)
+	}
+	var metrics vegeta.Metrics
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		return fmt.Errorf("failed to decode results: %w", err)
+	}
+	return report(&metrics, out)
+}
+
+// RunStressTest запускает стресс-тест и возвращает собранные метрики.
 //
 //	Параметры:
 //	- url: Целевой URL для тестирования.
 //	- rate: Частота запросов в секунду.
 //	- duration: Длительность теста в секундах.
-//	- output: Файл для сохранения результатов.
-func RunStressTest(url string, rate, duration int, output string) error {
+//	Возвращает:
+//	- *vegeta.Metrics: метрики теста.
+func RunStressTest(url string, rate, duration int) *vegeta.Metrics {
 	// Настройка Vegeta
 	rateLimiter := vegeta.Rate{Freq: rate, Per: time.Second}
 	durationTime := time.Duration(duration) * time.Second
@@ -64,38 +187,50 @@ func RunStressTest(url string, rate, duration int, output string) error {
 		metrics.Add(res)
 	}
 	metrics.Close()
+	return &metrics
+}
 
+// newReporter возвращает функцию вывода отчёта в заданном формате.
+//
+//	Параметры:
+//	- format: формат отчёта, text или json.
+//	Возвращает:
+//	- func: вывод отчёта по метрикам в w.
+//	- error: ошибка для неизвестного формата.
+func newReporter(format string) (func(m *vegeta.Metrics, w io.Writer) error, error) {
+	switch format {
+	case "text":
+		return func(m *vegeta.Metrics, w io.Writer) error { return vegeta.NewTextReporter(m).Report(w) }, nil
+	case "json":
+		return func(m *vegeta.Metrics, w io.Writer) error { return vegeta.NewJSONReporter(m).Report(w) }, nil
+	}
+	return nil, fmt.Errorf("unknown report format %q", format)
+}
+
+// outputPath проверяет, что файл результатов находится внутри текущего каталога.
+//
+//	Параметры:
+//	- output: путь к файлу результатов.
+//	Возвращает:
+//	- string: абсолютный путь к файлу.
+//	- error: ошибка, если путь выходит за пределы текущего каталога.
+func outputPath(output string) (string, error) {
 	// Разрешенная директория
 	allowedDir, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to get current working directory: %w", err)
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
 	}
 
 	// Очистка пути
-	cleanPath := filepath.Clean(output)
-	absPath, err := filepath.Abs(cleanPath)
+	absPath, err := filepath.Abs(filepath.Clean(output))
 	if err != nil {
-		return fmt.Errorf("invalid output path: %w", err)
+		return "", fmt.Errorf("invalid output path: %w", err)
 	}
 
 	// Проверяем, что путь находится внутри allowedDir
 	relPath, err := filepath.Rel(allowedDir, absPath)
 	if err != nil || strings.HasPrefix(relPath, "..") {
-		return fmt.Errorf("attempt to write outside allowed directory: %s", absPath)
-	}
-
-	// Кодируем метрики в JSON
-	data, err := json.Marshal(metrics)
-	if err != nil {
-		return fmt.Errorf("failed to encode metrics to JSON: %w", err)
+		return "", fmt.Errorf("attempt to write outside allowed directory: %s", absPath)
 	}
-
-	// Безопасная запись в файл
-	err = os.WriteFile(absPath, data, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to write data to file: %w", err)
-	}
-
-	log.Printf("Stress test results saved to %s", absPath)
-	return nil
+	return absPath, nil
 }
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	vegeta "github.com/tsenart/vegeta/v12/lib"
+)
+
+// chdir переходит во временный каталог на время теста: attack пишет результаты только внутри текущего каталога.
+func chdir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Chdir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	return dir
+}
+
+// execute запускает корневую команду с аргументами и возвращает её вывод.
+func execute(args ...string) (string, error) {
+	var out bytes.Buffer
+	cmd := newRootCmd(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+// TestAttackArguments проверяет отклонение некорректных аргументов attack до начала теста.
+func TestAttackArguments(t *testing.T) {
+	chdir(t)
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"empty url", []string{"--url="}, "target URL is required"},
+		{"zero rate", []string{"--rate=0"}, "rate and duration must be positive"},
+		{"negative duration", []string{"--duration=-1"}, "rate and duration must be positive"},
+		{"invalid rate", []string{"--rate=fast"}, "invalid argument"},
+		{"unknown format", []string{"--format=xml"}, `unknown report format "xml"`},
+		{"output outside", []string{"--output=../results.json"}, "attempt to write outside allowed directory"},
+		{"unknown flag", []string{"--workers=10"}, "unknown flag: --workers"},
+		{"single dash", []string{"-url=http://localhost"}, "unknown shorthand flag"},
+		{"positional", []string{"http://localhost"}, "unknown command"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := execute(append([]string{"attack"}, tt.args...)...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := execute("flood"); err == nil {
+		t.Error("expected error for unknown command")
+	}
+}
+
+// TestAttackSavesResults проверяет, что attack нагружает URL, сохраняет метрики и выводит отчёт.
+func TestAttackSavesResults(t *testing.T) {
+	dir := chdir(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	out, err := execute("attack", "--url="+srv.URL, "--rate=20", "--duration=1", "--output=results.json", "--format=json")
+	if err != nil {
+		t.Fatalf("attack: %v", err)
+	}
+	var printed vegeta.Metrics
+	if err := json.Unmarshal([]byte(out), &printed); err != nil {
+		t.Fatalf("expected JSON report, got %q: %v", out, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "results.json"))
+	if err != nil {
+		t.Fatalf("results not saved: %v", err)
+	}
+	var saved vegeta.Metrics
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("failed to decode results: %v", err)
+	}
+	if saved.Requests == 0 || saved.Requests != printed.Requests {
+		t.Errorf("expected saved and printed requests to match, got %d and %d", saved.Requests, printed.Requests)
+	}
+	if saved.StatusCodes["200"] != int(saved.Requests) {
+		t.Errorf("expected all requests to succeed, got %v", saved.StatusCodes)
+	}
+}
+
+// TestReport проверяет вывод отчёта по сохранённым метрикам и ошибки чтения.
+func TestReport(t *testing.T) {
+	dir := t.TempDir()
+	metrics := vegeta.Metrics{Requests: 42, Success: 1, StatusCodes: map[string]int{"200": 42}}
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	input := filepath.Join(dir, "results.json")
+	if err := os.WriteFile(input, data, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	broken := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(broken, []byte("{"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	out, err := execute("report", "--input="+input, "--format=json")
+	if err != nil {
+		t.Fatalf("report json: %v", err)
+	}
+	var got vegeta.Metrics
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("expected JSON report, got %q: %v", out, err)
+	}
+	if got.Requests != 42 || got.StatusCodes["200"] != 42 {
+		t.Errorf("unexpected report %+v", got)
+	}
+
+	// Формат text используется по умолчанию
+	out, err = execute("report", "--input", input)
+	if err != nil {
+		t.Fatalf("report text: %v", err)
+	}
+	if !strings.Contains(out, "Requests") || !strings.Contains(out, "42") {
+		t.Errorf("unexpected text report %q", out)
+	}
+
+	errTests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"missing file", []string{"--input=" + filepath.Join(dir, "missing.json")}, "failed to read results"},
+		{"broken file", []string{"--input=" + broken}, "failed to decode results"},
+		{"unknown format", []string{"--input=" + input, "--format=xml"}, `unknown report format "xml"`},
+		{"positional", []string{input}, "unknown command"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := execute(append([]string{"report"}, tt.args...)...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -2,21 +2,49 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
-	"go.uber.org/zap"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
+	"unicode"
 
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 	"l0_wb/internal/config"
+	"l0_wb/internal/dto"
+	kafkaclient "l0_wb/internal/kafka"
 	"l0_wb/internal/model"
 	"l0_wb/internal/util"
 )
 
-// main скрипт для генерации и отправки тестового сообщения в kafka.
+// main скрипт для генерации и отправки тестовых сообщений в kafka.
+//
+//	Без флагов отправляет один заказ. -count и -duration включают режим
+//	нагрузки: заказы отправляются -concurrency параллельными писателями с
+//	частотой не выше -rate сообщений в секунду, пока не отправлено -count
+//	сообщений или не истекло -duration (что наступит раньше; 0 — без
+//	ограничения). Каждые 5 секунд и по завершении выводятся число
+//	отправленных сообщений, ошибок и пропускная способность.
+//
+//	С -file вместо случайных заказов отправляются документы из файла (JSON-
+//	массив или по документу в строке) без изменений, ключ сообщения —
+//	order_uid документа. Отправляются все документы файла, если -count не
+//	задан явно.
 //
 //	go run internal/tools/kafka/producer.go
+//	go run internal/tools/kafka/producer.go -count=0 -duration=5m -rate=500 -concurrency=8
+//	go run internal/tools/kafka/producer.go -file=samples.ndjson -rate=50
 func main() {
 	// Инициализируем логгер, если он еще не был инициализирован
 	if err := util.InitLogger(); err != nil {
@@ -25,6 +53,24 @@ func main() {
 	logger := util.GetLogger()
 	defer util.SyncLogger()
 
+	opts, err := parseOptions(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		logger.Fatal("Invalid flags", zap.Error(err))
+	}
+
+	var src source = generatorSource{}
+	if opts.file != "" {
+		f, err := os.Open(opts.file)
+		if err != nil {
+			logger.Fatal("Failed to open file", zap.Error(err))
+		}
+		defer f.Close()
+		src = newFileSource(f)
+	}
+
 	logger.Info("Starting Kafka producer")
 
 	// Загружаем конфигурацию
@@ -34,11 +80,9 @@ func main() {
 	}
 
 	// Создаем Kafka writer
-	writer := &kafka.Writer{
-		Addr:     kafka.TCP(cfg.KafkaBrokers...),
-		Topic:    cfg.KafkaTopic,
-		Balancer: &kafka.LeastBytes{},
-	}
+	writer := kafkaclient.NewWriter(cfg, cfg.KafkaTopic)
+	// Сообщения пишутся по одному: без короткого ожидания пакета каждая запись ждала бы секунду
+	writer.BatchTimeout = 10 * time.Millisecond
 	defer func() {
 		if err := writer.Close(); err != nil {
 			logger.Warn("Failed to close Kafka writer", zap.Error(err))
@@ -50,26 +94,214 @@ func main() {
 	// Инициализация gofakeit
 	gofakeit.Seed(0)
 
-	// Генерируем и публикуем сообщение
-	order := generateOrder()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if opts.duration > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, opts.duration)
+		defer cancelTimeout()
+	}
 
-	// Преобразуем сообщение в JSON
-	data, err := json.Marshal(order)
-	if err != nil {
-		logger.Fatal("Failed to marshal order", zap.Error(err))
+	limit := rate.Inf
+	if opts.rate > 0 {
+		limit = rate.Limit(opts.rate)
+	}
+	limiter := rate.NewLimiter(limit, 1)
+
+	var remaining, published, failed atomic.Int64
+	remaining.Store(int64(opts.count))
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for range opts.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				// Номер очередного сообщения резервируется до ожидания лимита
+				if opts.count > 0 && remaining.Add(-1) < 0 {
+					return
+				}
+				if err := limiter.Wait(ctx); err != nil {
+					return
+				}
+				msg, err := src.next()
+				if errors.Is(err, io.EOF) {
+					return
+				}
+				if err != nil {
+					// После ошибки разбора продолжить чтение файла нельзя
+					failed.Add(1)
+					logger.Error("Failed to read order document", zap.Error(err))
+					cancel()
+					return
+				}
+				if err := writer.WriteMessages(ctx, msg); err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					failed.Add(1)
+					logger.Error("Failed to write message to Kafka", zap.Error(err))
+					continue
+				}
+				published.Add(1)
+				if opts.count == 1 {
+					logger.Info("Message published successfully", zap.String("order_uid", string(msg.Key)))
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			logStats(logger, "Publishing", published.Load(), failed.Load(), time.Since(start))
+		case <-done:
+			logStats(logger, "Publishing finished", published.Load(), failed.Load(), time.Since(start))
+			return
+		}
 	}
+}
 
-	// Публикуем сообщение в Kafka
-	err = writer.WriteMessages(context.Background(), kafka.Message{
-		Key:   []byte(order.OrderUID),
-		Value: data,
-	})
+// options содержит параметры запуска продюсера.
+type options struct {
+	count       int
+	rate        int
+	concurrency int
+	duration    time.Duration
+	file        string
+}
+
+// parseOptions разбирает и проверяет флаги командной строки.
+//
+//	Параметры:
+//	- args: аргументы без имени программы.
+//	Возвращает:
+//	- options: параметры запуска.
+//	- error: ошибка разбора или недопустимое сочетание значений (flag.ErrHelp для -h).
+func parseOptions(args []string) (options, error) {
+	var opts options
+	fs := flag.NewFlagSet("producer", flag.ContinueOnError)
+	fs.IntVar(&opts.count, "count", 1, "Number of messages to publish (0 - until -duration expires)")
+	fs.IntVar(&opts.rate, "rate", 0, "Maximum messages per second (0 - unlimited)")
+	fs.IntVar(&opts.concurrency, "concurrency", 1, "Number of parallel publishers")
+	fs.DurationVar(&opts.duration, "duration", 0, "Maximum run time (0 - until -count messages are published)")
+	fs.StringVar(&opts.file, "file", "", "Publish order documents from a JSON array or NDJSON file instead of random orders")
+	if err := fs.Parse(args); err != nil {
+		return options{}, err
+	}
+	if fs.NArg() > 0 {
+		return options{}, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	if opts.count < 0 || opts.rate < 0 || opts.concurrency < 1 || opts.duration < 0 {
+		return options{}, errors.New("count, rate and duration must not be negative, concurrency must be positive")
+	}
+	countSet := false
+	fs.Visit(func(f *flag.Flag) { countSet = countSet || f.Name == "count" })
+	if opts.file != "" && !countSet {
+		// Без явного -count отправляются все документы файла
+		opts.count = 0
+	}
+	if opts.count == 0 && opts.duration == 0 && opts.file == "" {
+		return options{}, errors.New("either count or duration must be set")
+	}
+	return opts, nil
+}
+
+// source поставляет сообщения для отправки; io.EOF означает, что сообщения закончились.
+type source interface {
+	next() (kafka.Message, error)
+}
+
+// generatorSource создаёт сообщения из случайных заказов.
+type generatorSource struct{}
+
+// next сериализует в JSON новый случайный заказ.
+func (generatorSource) next() (kafka.Message, error) {
+	order := generateOrder()
+	data, err := json.Marshal(dto.NewKafkaOrder(order))
 	if err != nil {
-		logger.Error("Failed to write message to Kafka", zap.Error(err))
-	} else {
-		logger.Info("Message published successfully", zap.String("orderUID", order.OrderUID))
+		return kafka.Message{}, err
 	}
+	return kafka.Message{Key: []byte(order.OrderUID), Value: data}, nil
+}
 
+// fileSource читает документы заказов из JSON-массива или NDJSON.
+type fileSource struct {
+	mu      sync.Mutex
+	dec     *json.Decoder
+	inArray bool // Файл — JSON-массив, открывающая скобка ещё не прочитана
+}
+
+// newFileSource создаёт fileSource для чтения из r.
+//
+//	Массив определяется по первому непробельному символу.
+func newFileSource(r io.Reader) *fileSource {
+	br := bufio.NewReader(r)
+	s := &fileSource{}
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			break
+		}
+		if !unicode.IsSpace(rune(c)) {
+			_ = br.UnreadByte()
+			s.inArray = c == '['
+			break
+		}
+	}
+	s.dec = json.NewDecoder(br)
+	return s
+}
+
+// next возвращает следующий документ файла без изменений.
+func (s *fileSource) next() (kafka.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.inArray {
+		if _, err := s.dec.Token(); err != nil {
+			return kafka.Message{}, err
+		}
+		s.inArray = false
+	}
+	if !s.dec.More() {
+		// Закрывающая скобка массива или конец NDJSON
+		return kafka.Message{}, io.EOF
+	}
+
+	var raw json.RawMessage
+	if err := s.dec.Decode(&raw); err != nil {
+		return kafka.Message{}, fmt.Errorf("document at offset %d: %w", s.dec.InputOffset(), err)
+	}
+	var doc struct {
+		OrderUID string `json:"order_uid"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return kafka.Message{}, fmt.Errorf("document at offset %d is not an object: %w", s.dec.InputOffset(), err)
+	}
+	return kafka.Message{Key: []byte(doc.OrderUID), Value: raw}, nil
+}
+
+// logStats выводит число отправленных сообщений, ошибок и пропускную способность.
+func logStats(logger *zap.Logger, msg string, published, failed int64, elapsed time.Duration) {
+	logger.Info(msg,
+		zap.Int64("published", published),
+		zap.Int64("errors", failed),
+		zap.Duration("elapsed", elapsed.Round(time.Millisecond)),
+		zap.Float64("msgs_per_sec", float64(published)/elapsed.Seconds()),
+	)
 }
 
 // generateOrder генерирует случайный заказ со всеми связанными данными.
@@ -100,36 +332,40 @@ func generateOrder() *model.Order {
 		Email:   gofakeit.Email(),
 	}
 
-	// Генерация данных для payments
-	order.Payment = model.Payment{
-		Transaction:  gofakeit.UUID(),
-		RequestID:    gofakeit.UUID(),
-		Currency:     gofakeit.CurrencyShort(),
-		Provider:     gofakeit.Company(),
-		Amount:       gofakeit.Number(100, 10000),
-		PaymentDt:    time.Now().Unix(),
-		Bank:         gofakeit.Company(),
-		DeliveryCost: gofakeit.Number(10, 500),
-		GoodsTotal:   gofakeit.Number(50, 5000),
-		CustomFee:    gofakeit.Number(0, 100),
-	}
-
 	// Генерация данных для items
 	for i := 0; i < gofakeit.Number(1, 5); i++ { // Случайное количество товаров в заказе
+		price, sale := gofakeit.Number(100, 1000), gofakeit.Number(0, 50)
 		order.Items = append(order.Items, model.Item{
 			ChrtID:      gofakeit.Number(1000, 9999),
 			TrackNumber: gofakeit.Word(),
-			Price:       gofakeit.Number(100, 1000),
+			Price:       price,
 			Rid:         gofakeit.UUID(),
 			Name:        gofakeit.Word(),
-			Sale:        gofakeit.Number(0, 50),
+			Sale:        sale,
 			Size:        gofakeit.Letter(),
-			TotalPrice:  gofakeit.Number(100, 2000),
+			TotalPrice:  price * (100 - sale) / 100,
 			NmID:        gofakeit.Number(100000, 999999),
 			Brand:       gofakeit.Company(),
 			Status:      gofakeit.Number(1, 3),
 		})
 	}
 
+	// Генерация данных для payments
+	order.Payment = model.Payment{
+		Transaction:  gofakeit.UUID(),
+		RequestID:    gofakeit.UUID(),
+		Currency:     gofakeit.CurrencyShort(),
+		Provider:     gofakeit.Company(),
+		PaymentDt:    time.Now().Unix(),
+		Bank:         gofakeit.Company(),
+		DeliveryCost: gofakeit.Number(10, 500),
+		CustomFee:    gofakeit.Number(0, 100),
+	}
+	// Суммы оплаты согласованы с товарами, чтобы заказ проходил валидацию
+	for _, item := range order.Items {
+		order.Payment.GoodsTotal += item.TotalPrice
+	}
+	order.Payment.Amount = order.Payment.GoodsTotal + order.Payment.DeliveryCost
+
 	return order
 }
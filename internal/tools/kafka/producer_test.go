@@ -0,0 +1,151 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestParseOptions проверяет разбор флагов частоты, длительности и числа сообщений.
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    options
+		wantErr string
+	}{
+		{name: "defaults", want: options{count: 1, concurrency: 1}},
+		{name: "load mode", args: []string{"-count=0", "-duration=5m", "-rate=500", "-concurrency=8"},
+			want: options{rate: 500, concurrency: 8, duration: 5 * time.Minute}},
+		{name: "count and duration", args: []string{"-count", "1000", "-duration", "1m30s"},
+			want: options{count: 1000, concurrency: 1, duration: 90 * time.Second}},
+		{name: "file sends all documents", args: []string{"-file=samples.ndjson", "-rate=50"},
+			want: options{rate: 50, concurrency: 1, file: "samples.ndjson"}},
+		{name: "file with explicit count", args: []string{"-file=samples.ndjson", "-count=1"},
+			want: options{count: 1, concurrency: 1, file: "samples.ndjson"}},
+		{name: "duration without unit", args: []string{"-duration=30"}, wantErr: "invalid value"},
+		{name: "non-numeric rate", args: []string{"-rate=fast"}, wantErr: "invalid value"},
+		{name: "negative rate", args: []string{"-rate=-1"}, wantErr: "must not be negative"},
+		{name: "negative duration", args: []string{"-duration=-1s"}, wantErr: "must not be negative"},
+		{name: "negative count", args: []string{"-count=-5"}, wantErr: "must not be negative"},
+		{name: "zero concurrency", args: []string{"-concurrency=0"}, wantErr: "concurrency must be positive"},
+		{name: "no limit", args: []string{"-count=0"}, wantErr: "either count or duration must be set"},
+		{name: "unknown flag", args: []string{"-speed=10"}, wantErr: "flag provided but not defined"},
+		{name: "positional argument", args: []string{"orders"}, wantErr: "unexpected arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOptions(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := parseOptions([]string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("expected flag.ErrHelp for -h, got %v", err)
+	}
+}
+
+// TestFileSource проверяет чтение документов из JSON-массива и NDJSON без изменений.
+func TestFileSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantKeys []string
+		wantDocs []string
+		wantErr  string // Ошибка после прочитанных документов (пусто — io.EOF)
+	}{
+		{name: "ndjson", input: `{"order_uid":"a","track_number":"T1"}` + "\n" + `{"order_uid":"b"}` + "\n",
+			wantKeys: []string{"a", "b"}, wantDocs: []string{`{"order_uid":"a","track_number":"T1"}`, `{"order_uid":"b"}`}},
+		{name: "array", input: "\n  [ {\"order_uid\":\"a\"},\n {\"order_uid\":\"b\", \"items\": [1, 2]} ]\n",
+			wantKeys: []string{"a", "b"}, wantDocs: []string{`{"order_uid":"a"}`, `{"order_uid":"b", "items": [1, 2]}`}},
+		{name: "empty array", input: "[]"},
+		{name: "empty file", input: ""},
+		{name: "document without order_uid", input: `{"track_number":"T1"}`,
+			wantKeys: []string{""}, wantDocs: []string{`{"track_number":"T1"}`}},
+		{name: "broken document", input: `{"order_uid":"a"}` + "\n" + `{"order_uid":`,
+			wantKeys: []string{"a"}, wantDocs: []string{`{"order_uid":"a"}`}, wantErr: "document at offset"},
+		{name: "not an object", input: `{"order_uid":"a"} 42`,
+			wantKeys: []string{"a"}, wantDocs: []string{`{"order_uid":"a"}`}, wantErr: "is not an object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newFileSource(strings.NewReader(tt.input))
+			var keys, docs []string
+			var err error
+			for {
+				msg, nextErr := src.next()
+				if nextErr != nil {
+					err = nextErr
+					break
+				}
+				keys = append(keys, string(msg.Key))
+				docs = append(docs, string(msg.Value))
+			}
+			if !slices.Equal(keys, tt.wantKeys) || !slices.Equal(docs, tt.wantDocs) {
+				t.Errorf("expected keys %q and docs %q, got %q and %q", tt.wantKeys, tt.wantDocs, keys, docs)
+			}
+			if tt.wantErr == "" {
+				if !errors.Is(err, io.EOF) {
+					t.Errorf("expected io.EOF, got %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestFileSourceConcurrent проверяет, что параллельные писатели получают каждый документ ровно один раз.
+func TestFileSourceConcurrent(t *testing.T) {
+	var input strings.Builder
+	for i := range 200 {
+		fmt.Fprintf(&input, "{\"order_uid\":\"order-%d\"}\n", i)
+	}
+	src := newFileSource(strings.NewReader(input.String()))
+
+	var mu sync.Mutex
+	seen := map[string]int{}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				msg, err := src.next()
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				seen[string(msg.Key)]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != 200 {
+		t.Fatalf("expected 200 distinct documents, got %d", len(seen))
+	}
+	for key, n := range seen {
+		if n != 1 {
+			t.Errorf("document %s read %d times", key, n)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// main provides PII re-encryption cli util.
+package main
+
+import (
+	"context"
+	"flag"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+	"l0_wb/internal/db"
+	"l0_wb/internal/fieldcrypt"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/util"
+)
+
+// main шифрует основным ключом PII_ENCRYPTION_KEY персональные данные
+// получателя, сохранённые открытым текстом или прежним ключом.
+//
+//	Обрабатываются телефон, email и адрес в таблице deliveries и те же поля в
+//	журнале order_events. Значения под прежними ключами расшифровываются
+//	ключами из PII_ENCRYPTION_PREVIOUS_KEYS. Инструмент можно запускать на
+//	работающем сервисе и повторять: уже обработанные строки не меняются.
+//
+//	go run internal/tools/piicrypt/main.go -batch=500
+func main() {
+	batch := flag.Int("batch", 500, "Rows per page")
+	flag.Parse()
+
+	if err := util.InitLogger(); err != nil {
+		panic("Failed to initialize logger: " + err.Error())
+	}
+	logger := util.GetLogger()
+	defer util.SyncLogger()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Fatal("Failed to load config", zap.Error(err))
+	}
+	if cfg.PIIEncryptionKey == "" {
+		logger.Fatal("PII_ENCRYPTION_KEY is required")
+	}
+	fieldCipher, err := fieldcrypt.NewFromBase64(cfg.PIIEncryptionKey, cfg.PIIEncryptionPreviousKeys)
+	if err != nil {
+		logger.Fatal("Invalid PII encryption key", zap.Error(err))
+	}
+	repository.SetFieldCipher(fieldCipher)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	database, err := db.InitDB(ctx, cfg)
+	if err != nil {
+		logger.Fatal("Failed to initialize database", zap.Error(err))
+	}
+	defer database.Close()
+
+	total := 0
+	for after := ""; ; {
+		next, updated, err := repository.ReencryptDeliveries(ctx, database, after, *batch)
+		if err != nil {
+			logger.Fatal("Failed to re-encrypt deliveries", zap.String("after", after), zap.Error(err))
+		}
+		total += updated
+		if next == "" {
+			break
+		}
+		after = next
+	}
+	logger.Info("Deliveries re-encrypted", zap.Int("updated", total))
+
+	total = 0
+	for after := int64(0); ; {
+		next, updated, err := repository.ReencryptOrderEvents(ctx, database, after, *batch)
+		if err != nil {
+			logger.Fatal("Failed to re-encrypt order events", zap.Int64("after", after), zap.Error(err))
+		}
+		total += updated
+		if next == 0 {
+			break
+		}
+		after = next
+	}
+	logger.Info("Order events re-encrypted", zap.Int("updated", total))
+}
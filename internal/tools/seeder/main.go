@@ -0,0 +1,299 @@
+// main provides database seeder cli util.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"go.uber.org/zap"
+	"l0_wb/internal/config"
+	"l0_wb/internal/db"
+	"l0_wb/internal/fieldcrypt"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/service"
+	"l0_wb/internal/util"
+)
+
+// main заполняет БД случайными заказами.
+//
+//	Заказы сохраняются через OrderService пакетами по -batch-size, то есть
+//	теми же параметризованными запросами, что и заказы из Kafka: значения не
+//	подставляются в текст SQL, а журнал order_events и шифрование
+//	персональных данных ведутся как обычно. Миграции применяются перед
+//	заполнением (при DB_AUTO_MIGRATE), сами заказы в миграции не попадают.
+//
+//	С ненулевым -seed и заданными -from и -to повторный запуск на пустой БД
+//	создаёт тот же набор заказов. -items задаёт диапазон числа товаров в
+//	заказе, -locales — доли локалей (например, ru=70,en=25,kz=5).
+//
+//	go run internal/tools/seeder/main.go -count=10000 -batch-size=500 -seed=42 -from=2024-01-01 -to=2024-07-01
+func main() {
+	count := flag.Int("count", 1000, "Number of orders to insert")
+	batchSize := flag.Int("batch-size", 100, "Orders per transaction")
+	seed := flag.Int64("seed", 0, "Random seed for a reproducible dataset (0 - random, logged at start)")
+	items := flag.String("items", "1-5", "Range of items per order, MIN-MAX")
+	locales := flag.String("locales", "", "Locale weights, e.g. ru=70,en=30 (empty - random locales)")
+	from := flag.String("from", "", "Earliest date_created, YYYY-MM-DD or RFC 3339 (default 30 days before -to)")
+	to := flag.String("to", "", "Latest date_created, YYYY-MM-DD or RFC 3339 (default now)")
+	flag.Parse()
+
+	if err := util.InitLogger(); err != nil {
+		panic("Failed to initialize logger: " + err.Error())
+	}
+	logger := util.GetLogger()
+	defer util.SyncLogger()
+
+	if *count < 1 || *batchSize < 1 {
+		logger.Fatal("count and batch-size must be positive", zap.Int("count", *count), zap.Int("batch_size", *batchSize))
+	}
+	if *seed == 0 {
+		// Случайный seed попадает в журнал, чтобы набор можно было повторить
+		*seed = time.Now().UnixNano()
+	}
+	gen, err := newGenerator(*seed, *items, *locales, *from, *to)
+	if err != nil {
+		logger.Fatal("Invalid generation options", zap.Error(err))
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Fatal("Failed to load config", zap.Error(err))
+	}
+	// Заказы шифруются тем же ключом, что и у сервиса, иначе он не прочитает их
+	fieldCipher, err := fieldcrypt.NewFromBase64(cfg.PIIEncryptionKey, cfg.PIIEncryptionPreviousKeys)
+	if err != nil {
+		logger.Fatal("Invalid PII encryption key", zap.Error(err))
+	}
+	repository.SetFieldCipher(fieldCipher)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	database, err := db.InitDB(ctx, cfg)
+	if err != nil {
+		logger.Fatal("Failed to initialize database", zap.Error(err))
+	}
+	defer database.Close()
+
+	orderService := service.NewOrderService(repository.NewTxManager(database), repository.NewRepositories(database), nil,
+		service.WithAmountTolerance(cfg.OrderAmountTolerance))
+
+	logger.Info("Seeding orders",
+		zap.Int("count", *count),
+		zap.Int64("seed", *seed),
+		zap.Time("from", gen.from),
+		zap.Time("to", gen.to),
+	)
+	start := time.Now()
+	saved, failed := 0, 0
+	for done := 0; done < *count; {
+		orders := make([]*model.Order, min(*batchSize, *count-done))
+		for i := range orders {
+			orders[i] = gen.order()
+		}
+		results, err := orderService.SaveBatch(ctx, orders)
+		if err != nil {
+			logger.Fatal("Failed to save batch", zap.Int("saved", saved), zap.Error(err))
+		}
+		for _, r := range results {
+			if r.Stored() {
+				saved++
+				continue
+			}
+			failed++
+			logger.Warn("Order not saved", zap.String("order_uid", r.OrderUID), zap.Error(r.Err))
+		}
+		done += len(orders)
+		logger.Info("Batch saved", zap.Int("done", done), zap.Int("count", *count))
+	}
+
+	logger.Info("Seeding finished",
+		zap.Int("saved", saved),
+		zap.Int("failed", failed),
+		zap.Duration("elapsed", time.Since(start)),
+	)
+}
+
+// generator создаёт случайные заказы с заданной формой набора данных.
+//
+//	Все значения, включая order_uid и даты, берутся из одного источника
+//	случайных чисел, поэтому набор определяется seed и параметрами.
+type generator struct {
+	faker    *gofakeit.Faker
+	minItems int
+	maxItems int
+	locales  []string // Локали в порядке накопленных весов
+	weights  []int    // Накопленные веса локалей
+	from     time.Time
+	to       time.Time
+}
+
+// newGenerator создаёт generator по значениям флагов.
+//
+//	Параметры:
+//	- seed: начальное значение генератора.
+//	- items: диапазон числа товаров MIN-MAX.
+//	- locales: веса локалей вида ru=70,en=30 (пусто — случайные локали).
+//	- from, to: границы date_created (пусто — 30 дней до to и текущее время).
+//	Возвращает:
+//	- *generator: настроенный генератор.
+//	- error: ошибка разбора параметров.
+func newGenerator(seed int64, items, locales, from, to string) (*generator, error) {
+	g := &generator{faker: gofakeit.New(seed)}
+
+	var err error
+	if g.minItems, g.maxItems, err = parseRange(items); err != nil {
+		return nil, fmt.Errorf("invalid items %q: %w", items, err)
+	}
+	if g.minItems < 1 {
+		return nil, fmt.Errorf("invalid items %q: order needs at least one item", items)
+	}
+
+	if locales != "" {
+		total := 0
+		for _, part := range strings.Split(locales, ",") {
+			locale, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+			w, err := strconv.Atoi(weight)
+			if !ok || locale == "" || err != nil || w < 1 {
+				return nil, fmt.Errorf("invalid locale weight %q", part)
+			}
+			total += w
+			g.locales = append(g.locales, locale)
+			g.weights = append(g.weights, total)
+		}
+	}
+
+	g.to = time.Now().UTC()
+	if to != "" {
+		if g.to, err = parseDate(to); err != nil {
+			return nil, fmt.Errorf("invalid to %q: %w", to, err)
+		}
+	}
+	g.from = g.to.AddDate(0, 0, -30)
+	if from != "" {
+		if g.from, err = parseDate(from); err != nil {
+			return nil, fmt.Errorf("invalid from %q: %w", from, err)
+		}
+	}
+	if !g.from.Before(g.to) {
+		return nil, errors.New("from must be before to")
+	}
+	return g, nil
+}
+
+// parseRange разбирает диапазон вида MIN-MAX или одно число.
+func parseRange(s string) (int, int, error) {
+	lo, hi, found := strings.Cut(s, "-")
+	if !found {
+		hi = lo
+	}
+	minValue, err := strconv.Atoi(strings.TrimSpace(lo))
+	if err != nil {
+		return 0, 0, err
+	}
+	maxValue, err := strconv.Atoi(strings.TrimSpace(hi))
+	if err != nil {
+		return 0, 0, err
+	}
+	if minValue > maxValue {
+		return 0, 0, errors.New("min is greater than max")
+	}
+	return minValue, maxValue, nil
+}
+
+// parseDate разбирает дату в формате YYYY-MM-DD или RFC 3339.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// locale возвращает локаль заказа с учётом весов.
+func (g *generator) locale() string {
+	if len(g.locales) == 0 {
+		return g.faker.LanguageAbbreviation()
+	}
+	n := g.faker.Number(1, g.weights[len(g.weights)-1])
+	i, _ := slices.BinarySearch(g.weights, n)
+	return g.locales[i]
+}
+
+// order генерирует случайный заказ со всеми связанными данными.
+func (g *generator) order() *model.Order {
+	f := g.faker
+	created := f.DateRange(g.from, g.to)
+
+	// Генерация данных для orders
+	order := &model.Order{
+		OrderUID:          f.UUID(),
+		TrackNumber:       f.Word(),
+		Entry:             f.Word(),
+		Locale:            g.locale(),
+		InternalSignature: f.UUID(),
+		CustomerID:        f.UUID(),
+		DeliveryService:   f.Company(),
+		Shardkey:          f.Word(),
+		SmID:              f.Number(1, 100),
+		DateCreated:       created,
+		OofShard:          f.Word(),
+	}
+
+	// Генерация данных для deliveries
+	order.Delivery = model.Delivery{
+		Name:    f.Name(),
+		Phone:   f.Phone(),
+		Zip:     f.Zip(),
+		City:    f.City(),
+		Address: f.Street(),
+		Region:  f.State(),
+		Email:   f.Email(),
+	}
+
+	// Генерация данных для items
+	for range f.Number(g.minItems, g.maxItems) {
+		price, sale := f.Number(100, 1000), f.Number(0, 50)
+		order.Items = append(order.Items, model.Item{
+			ChrtID:      f.Number(1000, 9999),
+			TrackNumber: f.Word(),
+			Price:       price,
+			Rid:         f.UUID(),
+			Name:        f.Word(),
+			Sale:        sale,
+			Size:        f.Letter(),
+			TotalPrice:  price * (100 - sale) / 100,
+			NmID:        f.Number(100000, 999999),
+			Brand:       f.Company(),
+			Status:      f.Number(1, 3),
+		})
+	}
+
+	// Генерация данных для payments; оплата — в течение часа после создания заказа
+	order.Payment = model.Payment{
+		Transaction:  f.UUID(),
+		RequestID:    f.UUID(),
+		Currency:     f.CurrencyShort(),
+		Provider:     f.Company(),
+		PaymentDt:    created.Add(time.Duration(f.Number(0, 3600)) * time.Second).Unix(),
+		Bank:         f.Company(),
+		DeliveryCost: f.Number(10, 500),
+		CustomFee:    f.Number(0, 100),
+	}
+	// Суммы оплаты согласованы с товарами, чтобы заказ проходил валидацию
+	for _, item := range order.Items {
+		order.Payment.GoodsTotal += item.TotalPrice
+	}
+	order.Payment.Amount = order.Payment.GoodsTotal + order.Payment.DeliveryCost
+
+	return order
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGeneratorSeed проверяет, что одинаковые seed и параметры дают одинаковый набор заказов.
+func TestGeneratorSeed(t *testing.T) {
+	generate := func(seed int64) []string {
+		t.Helper()
+		g, err := newGenerator(seed, "1-5", "ru=70,en=30", "2024-01-01", "2024-07-01")
+		if err != nil {
+			t.Fatalf("newGenerator: %v", err)
+		}
+		var keys []string
+		for range 20 {
+			o := g.order()
+			keys = append(keys, o.OrderUID+o.Locale+o.DateCreated.String()+o.Delivery.Email)
+		}
+		return keys
+	}
+
+	first, second := generate(42), generate(42)
+	if !reflect.DeepEqual(first, second) {
+		t.Error("expected the same orders for the same seed")
+	}
+	if reflect.DeepEqual(first, generate(43)) {
+		t.Error("expected different orders for a different seed")
+	}
+
+	g1, _ := newGenerator(7, "2", "", "2024-01-01", "2024-01-02")
+	g2, _ := newGenerator(7, "2", "", "2024-01-01", "2024-01-02")
+	if a, b := g1.order(), g2.order(); !reflect.DeepEqual(a, b) {
+		t.Errorf("expected identical orders, got %+v and %+v", a, b)
+	}
+}
+
+// TestGeneratorLocales проверяет распределение локалей по весам.
+func TestGeneratorLocales(t *testing.T) {
+	g, err := newGenerator(1, "1", "ru=70, en=25, kz=5", "2024-01-01", "2024-02-01")
+	if err != nil {
+		t.Fatalf("newGenerator: %v", err)
+	}
+	const n = 20000
+	counts := map[string]int{}
+	for range n {
+		counts[g.locale()]++
+	}
+	for locale, weight := range map[string]float64{"ru": 0.70, "en": 0.25, "kz": 0.05} {
+		if got := float64(counts[locale]) / n; got < weight-0.02 || got > weight+0.02 {
+			t.Errorf("locale %s: expected share %.2f, got %.3f", locale, weight, got)
+		}
+	}
+	if len(counts) != 3 {
+		t.Errorf("expected only configured locales, got %v", counts)
+	}
+
+	// Единственная локаль выбирается всегда
+	g, _ = newGenerator(1, "1", "ru=1", "2024-01-01", "2024-02-01")
+	for range 100 {
+		if l := g.order().Locale; l != "ru" {
+			t.Fatalf("expected ru, got %q", l)
+		}
+	}
+}
+
+// TestGeneratorItems проверяет, что число товаров в заказе не выходит за диапазон -items и принимает крайние значения.
+func TestGeneratorItems(t *testing.T) {
+	g, err := newGenerator(3, "2-4", "", "2024-01-01", "2024-02-01")
+	if err != nil {
+		t.Fatalf("newGenerator: %v", err)
+	}
+	seen := map[int]bool{}
+	for range 500 {
+		o := g.order()
+		n := len(o.Items)
+		if n < 2 || n > 4 {
+			t.Fatalf("expected 2-4 items, got %d", n)
+		}
+		seen[n] = true
+
+		// Суммы оплаты согласованы с товарами
+		goods := 0
+		for _, item := range o.Items {
+			goods += item.TotalPrice
+		}
+		if o.Payment.GoodsTotal != goods || o.Payment.Amount != goods+o.Payment.DeliveryCost {
+			t.Fatalf("payment totals do not match items: %+v", o.Payment)
+		}
+	}
+	if !seen[2] || !seen[4] {
+		t.Errorf("expected both bounds to be generated, got %v", seen)
+	}
+
+	g, _ = newGenerator(3, "3", "", "2024-01-01", "2024-02-01")
+	if n := len(g.order().Items); n != 3 {
+		t.Errorf("expected exactly 3 items, got %d", n)
+	}
+}
+
+// TestGeneratorDates проверяет, что даты заказов лежат в диапазоне -from/-to.
+func TestGeneratorDates(t *testing.T) {
+	g, err := newGenerator(5, "1", "", "2024-03-01", "2024-03-01T12:00:00Z")
+	if err != nil {
+		t.Fatalf("newGenerator: %v", err)
+	}
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(12 * time.Hour)
+	for range 200 {
+		o := g.order()
+		if o.DateCreated.Before(from) || o.DateCreated.After(to) {
+			t.Fatalf("date_created %s is outside %s - %s", o.DateCreated, from, to)
+		}
+		if paid := time.Unix(o.Payment.PaymentDt, 0); paid.Before(o.DateCreated.Truncate(time.Second)) {
+			t.Fatalf("payment at %s precedes order creation at %s", paid, o.DateCreated)
+		}
+	}
+
+	// Без -from берутся 30 дней до -to
+	g, _ = newGenerator(5, "1", "", "", "2024-03-31")
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC); !g.from.Equal(want) {
+		t.Errorf("expected default from %s, got %s", want, g.from)
+	}
+}
+
+// TestNewGeneratorInvalidOptions проверяет отказ на некорректных значениях флагов.
+func TestNewGeneratorInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name                     string
+		items, locales, from, to string
+		wantErr                  string
+	}{
+		{name: "from after to", items: "1-5", from: "2024-07-01", to: "2024-01-01", wantErr: "from must be before to"},
+		{name: "from equals to", items: "1-5", from: "2024-01-01", to: "2024-01-01", wantErr: "from must be before to"},
+		{name: "bad from", items: "1-5", from: "01.01.2024", to: "2024-07-01", wantErr: `invalid from "01.01.2024"`},
+		{name: "bad to", items: "1-5", to: "tomorrow", wantErr: `invalid to "tomorrow"`},
+		{name: "reversed items", items: "5-2", wantErr: "min is greater than max"},
+		{name: "zero items", items: "0-3", wantErr: "order needs at least one item"},
+		{name: "non-numeric items", items: "a-b", wantErr: `invalid items "a-b"`},
+		{name: "locale without weight", items: "1", locales: "ru", wantErr: `invalid locale weight "ru"`},
+		{name: "zero weight", items: "1", locales: "ru=70,en=0", wantErr: `invalid locale weight "en=0"`},
+		{name: "empty locale", items: "1", locales: "=5", wantErr: `invalid locale weight "=5"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newGenerator(1, tt.items, tt.locales, tt.from, tt.to)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
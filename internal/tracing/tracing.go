@@ -0,0 +1,125 @@
+// Package tracing configures OpenTelemetry distributed tracing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"l0_wb/internal/buildinfo"
+)
+
+// instrumentationName — имя трассировщика приложения.
+const instrumentationName = "l0_wb"
+
+// defaultServiceName — имя сервиса, если OTEL_SERVICE_NAME не задан.
+const defaultServiceName = "l0_wb"
+
+// Enabled сообщает, настроен ли экспорт трассировок переменными OTEL_*.
+//
+//	Экспорт включается, если задан OTEL_TRACES_EXPORTER=otlp или адрес
+//	коллектора (OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_TRACES_ENDPOINT),
+//	и выключается OTEL_SDK_DISABLED=true или OTEL_TRACES_EXPORTER=none.
+func Enabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	switch strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER")) {
+	case "otlp":
+		return true
+	case "":
+		return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+	default:
+		return false
+	}
+}
+
+// Init настраивает глобальный TracerProvider с экспортом по OTLP.
+//
+//	Адрес коллектора, протокол (http/protobuf по умолчанию или grpc), заголовки,
+//	сэмплирование и параметры батчей берутся из стандартных переменных OTEL_*,
+//	имя сервиса и атрибуты ресурса — из OTEL_SERVICE_NAME и OTEL_RESOURCE_ATTRIBUTES.
+//	Контекст трассировки передаётся в формате W3C Trace Context. Если экспорт
+//	не настроен (см. Enabled), спаны не записываются.
+//
+//	Параметры:
+//	- ctx: контекст инициализации.
+//	Возвращает:
+//	- func(context.Context) error: отправка накопленных спанов и остановка экспорта.
+//	- error: ошибку создания экспортёра или ресурса.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(defaultServiceName), semconv.ServiceVersion(buildinfo.Get().Version)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// newExporter создаёт экспортёр OTLP по OTEL_EXPORTER_OTLP_TRACES_PROTOCOL или OTEL_EXPORTER_OTLP_PROTOCOL.
+func newExporter(ctx context.Context) (*otlptrace.Exporter, error) {
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	switch protocol {
+	case "", "http/protobuf":
+		return otlptracehttp.New(ctx)
+	case "grpc":
+		return otlptracegrpc.New(ctx)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", protocol)
+	}
+}
+
+// Start начинает спан трассировщика приложения.
+//
+//	Параметры:
+//	- ctx: контекст с родительским спаном.
+//	- name: имя спана.
+//	- opts: вид спана, атрибуты, ссылки.
+//	Возвращает:
+//	- context.Context: контекст с новым спаном.
+//	- trace.Span: спан; завершается вызовом End.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End завершает спан, отмечая его ошибкой, если err не nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
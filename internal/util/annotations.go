@@ -0,0 +1,89 @@
+package util
+
+import (
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// annotations — поля, добавляемые ко всем записям глобального логгера.
+var annotations struct {
+	sync.RWMutex
+	fields map[string]zap.Field
+}
+
+// SetLogAnnotation добавляет поле ко всем последующим записям глобального логгера.
+//
+//	Используется для пометки записей, сделанных во время инцидента, чтобы их
+//	можно было сопоставить с окном недоступности зависимости.
+//
+//	Параметры:
+//	- key: имя поля.
+//	- value: значение поля.
+func SetLogAnnotation(key string, value any) {
+	annotations.Lock()
+	defer annotations.Unlock()
+	if annotations.fields == nil {
+		annotations.fields = make(map[string]zap.Field)
+	}
+	annotations.fields[key] = zap.Any(key, value)
+}
+
+// ClearLogAnnotation удаляет поле, добавленное SetLogAnnotation.
+func ClearLogAnnotation(key string) {
+	annotations.Lock()
+	defer annotations.Unlock()
+	delete(annotations.fields, key)
+}
+
+// currentAnnotations возвращает текущие поля аннотаций в стабильном порядке.
+func currentAnnotations() []zap.Field {
+	annotations.RLock()
+	defer annotations.RUnlock()
+	if len(annotations.fields) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(annotations.fields))
+	for key := range annotations.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields := make([]zap.Field, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, annotations.fields[key])
+	}
+	return fields
+}
+
+// annotatingCore добавляет текущие аннотации к каждой записи.
+type annotatingCore struct {
+	zapcore.Core
+}
+
+// newAnnotatingCore оборачивает core для использования в zap.WrapCore.
+func newAnnotatingCore(core zapcore.Core) zapcore.Core {
+	return &annotatingCore{Core: core}
+}
+
+// With возвращает core с дополнительными полями, сохраняя аннотирование.
+func (c *annotatingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &annotatingCore{Core: c.Core.With(fields)}
+}
+
+// Check делегирует решение о записи (уровень, сэмплирование) вложенному core.
+func (c *annotatingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Check(ent, nil) == nil {
+		return ce
+	}
+	return ce.AddCore(ent, c)
+}
+
+// Write добавляет аннотации к полям записи.
+func (c *annotatingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if extra := currentAnnotations(); len(extra) > 0 {
+		fields = append(fields[:len(fields):len(fields)], extra...)
+	}
+	return c.Core.Write(ent, fields)
+}
@@ -2,24 +2,148 @@
 package util
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 var logger *zap.Logger
 
-// InitLogger инициализирует глобальный логгер.
+// logFile — файл журнала с ротацией; nil, если журнал пишется в stdout/stderr.
+var logFile *lumberjack.Logger
+
+// logLevel — уровень глобального логгера; меняется без пересоздания логгера (см. SetLogLevel).
+var logLevel = zap.NewAtomicLevelAt(zap.InfoLevel)
+
+// loggerKey — ключ контекста для логгера запроса.
+type loggerKey struct{}
+
+// LoggerOptions — параметры вывода глобального логгера.
+type LoggerOptions struct {
+	Format     string // json (по умолчанию) или console
+	Output     string // stderr (по умолчанию), stdout или путь к файлу
+	MaxSizeMB  int    // Размер файла, после которого он ротируется, МБ (0 — 100 МБ)
+	MaxBackups int    // Сколько ротированных файлов хранить (0 — все)
+	MaxAgeDays int    // Сколько дней хранить ротированные файлы (0 — не удалять по возрасту)
+	Compress   bool   // Сжимать ротированные файлы gzip
+}
+
+// InitLogger инициализирует глобальный логгер с выводом JSON в stderr.
 //
 //	Возвращает:
 //	- error: если не удалось создать логгер.
 func InitLogger() error {
-	var err error
-	logger, err = zap.NewProduction()
+	return ConfigureLogger(LoggerOptions{})
+}
+
+// ConfigureLogger пересоздаёт глобальный логгер с заданным форматом и выводом.
+//
+//	Уровень журналирования сохраняется и меняется отдельно (см. SetLogLevel).
+//	Логгеры, полученные через GetLogger до вызова, продолжают писать в прежний вывод,
+//	поэтому ConfigureLogger вызывается при старте до создания компонентов.
+//	Параметры:
+//	- opts: формат и вывод журнала; файл ротируется по размеру.
+//	Возвращает:
+//	- error: ошибку, если формат неизвестен.
+func ConfigureLogger(opts LoggerOptions) error {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	var encoder zapcore.Encoder
+	switch opts.Format {
+	case "", "json":
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case "console":
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return fmt.Errorf("unknown log format %q", opts.Format)
+	}
+
+	var output zapcore.WriteSyncer
+	var file *lumberjack.Logger
+	switch opts.Output {
+	case "", "stderr":
+		output = zapcore.Lock(os.Stderr)
+	case "stdout":
+		output = zapcore.Lock(os.Stdout)
+	default:
+		file = &lumberjack.Logger{
+			Filename:   opts.Output,
+			MaxSize:    opts.MaxSizeMB,
+			MaxBackups: opts.MaxBackups,
+			MaxAge:     opts.MaxAgeDays,
+			Compress:   opts.Compress,
+		}
+		output = zapcore.AddSync(file)
+	}
+
+	// Выборка и трассировка стека — как у zap.NewProduction
+	core := zapcore.NewSamplerWithOptions(zapcore.NewCore(encoder, output, logLevel), time.Second, 100, 100)
+	next := zap.New(newMaskingCore(newAnnotatingCore(core)),
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+		zap.AddCaller(),
+		zap.AddStacktrace(zapcore.ErrorLevel),
+	)
+
+	if logger != nil {
+		_ = logger.Sync()
+	}
+	if logFile != nil {
+		_ = logFile.Close()
+	}
+	logger, logFile = next, file
+	return nil
+}
+
+// LogLevel возвращает текущий уровень глобального логгера.
+func LogLevel() string {
+	return logLevel.Level().String()
+}
+
+// SetLogLevel меняет уровень глобального логгера и всех производных от него логгеров.
+//
+//	Параметры:
+//	- level: имя уровня (debug, info, warn, error, dpanic, panic, fatal).
+//	Возвращает:
+//	- error: ошибку, если имя уровня неизвестно.
+func SetLogLevel(level string) error {
+	l, err := zapcore.ParseLevel(level)
 	if err != nil {
 		return err
 	}
+	logLevel.SetLevel(l)
 	return nil
 }
 
+// NewAccessLogger создаёт отдельный логгер для журнала доступа HTTP.
+//
+//	Журнал доступа пишется в собственный поток вывода, чтобы его можно было
+//	собирать и хранить отдельно от логов приложения.
+//
+//	Параметры:
+//	- output: путь к файлу журнала или "stdout"/"stderr".
+//	Возвращает:
+//	- *zap.Logger: логгер журнала доступа.
+//	- error: если не удалось открыть поток вывода.
+func NewAccessLogger(output string) (*zap.Logger, error) {
+	cfg := zap.NewProductionConfig()
+	// Выборку записей выполняет middleware журнала доступа, встроенная выборка zap не нужна
+	cfg.Sampling = nil
+	cfg.DisableCaller = true
+	cfg.DisableStacktrace = true
+	cfg.OutputPaths = []string{output}
+	l, err := cfg.Build()
+	if err != nil {
+		return nil, err
+	}
+	return l.Named("access"), nil
+}
+
 // GetLogger возвращает глобальный логгер.
 //
 // Возвращает:
@@ -38,4 +162,56 @@ func SyncLogger() {
 	if logger != nil {
 		_ = logger.Sync()
 	}
+	if logFile != nil {
+		_ = logFile.Close()
+	}
+}
+
+// ContextWithLogger возвращает контекст с логгером, привязанным к запросу.
+//
+//	Параметры:
+//	- ctx: родительский контекст.
+//	- l: логгер с полями запроса (например, request_id).
+//	Возвращает:
+//	- context.Context: контекст с логгером.
+func ContextWithLogger(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// ContextWithFields возвращает контекст с логгером, дополненным полями корреляции.
+//
+//	Поля добавляются к логгеру из контекста (или к глобальному, если его нет),
+//	поэтому все записи, сделанные через ContextLogger ниже по стеку вызовов,
+//	содержат request_id, order_uid, partition/offset сообщения Kafka и т. п.
+//	Параметры:
+//	- ctx: родительский контекст.
+//	- fields: поля корреляции.
+//	Возвращает:
+//	- context.Context: контекст с дополненным логгером.
+func ContextWithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	return ContextWithLogger(ctx, ContextLogger(ctx).With(fields...))
+}
+
+// ContextLogger возвращает логгер из контекста или глобальный логгер.
+//
+//	Параметры:
+//	- ctx: контекст запроса или обработки сообщения.
+//	Возвращает:
+//	- *zap.Logger: логгер с полями корреляции контекста.
+func ContextLogger(ctx context.Context) *zap.Logger {
+	return LoggerFromContext(ctx, GetLogger())
+}
+
+// LoggerFromContext возвращает логгер из контекста.
+//
+//	Параметры:
+//	- ctx: контекст запроса.
+//	- fallback: логгер, возвращаемый, если в контексте логгера нет.
+//	Возвращает:
+//	- *zap.Logger: логгер запроса или fallback.
+func LoggerFromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return l
+	}
+	return fallback
 }
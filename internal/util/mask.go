@@ -0,0 +1,150 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// sensitiveKeys — имена полей с персональными данными, значения которых маскируются в журнале.
+var sensitiveKeys = map[string]bool{
+	"name":        true,
+	"phone":       true,
+	"email":       true,
+	"address":     true,
+	"customer_id": true,
+}
+
+// rawPayloads разрешает писать в журнал содержимое сообщений и запросов без маскирования.
+var rawPayloads atomic.Bool
+
+// SetLogRawPayloads разрешает или запрещает запись в журнал содержимого сообщений как есть.
+//
+//	Параметры:
+//	- allow: true — содержимое пишется без маскирования (только для отладки).
+func SetLogRawPayloads(allow bool) {
+	rawPayloads.Store(allow)
+}
+
+// Payload возвращает поле журнала с содержимым сообщения или запроса.
+//
+//	Если запись содержимого как есть не разрешена (см. SetLogRawPayloads),
+//	в JSON маскируются значения персональных полей (телефон, email, адрес,
+//	имя, customer_id) на любой глубине, а содержимое, которое не удалось
+//	разобрать как JSON, заменяется его размером.
+//	Параметры:
+//	- key: имя поля журнала.
+//	- payload: содержимое.
+//	Возвращает:
+//	- zap.Field: поле журнала.
+func Payload(key string, payload []byte) zap.Field {
+	if rawPayloads.Load() {
+		return zap.String(key, string(payload))
+	}
+	var doc any
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return zap.String(key, fmt.Sprintf("<%d bytes redacted>", len(payload)))
+	}
+	masked, err := json.Marshal(maskJSON(doc))
+	if err != nil {
+		return zap.String(key, fmt.Sprintf("<%d bytes redacted>", len(payload)))
+	}
+	return zap.String(key, string(masked))
+}
+
+// maskJSON маскирует значения персональных полей разобранного JSON.
+func maskJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if s, ok := value.(string); ok && sensitiveKeys[strings.ToLower(key)] {
+				v[key] = MaskPII(key, s)
+				continue
+			}
+			v[key] = maskJSON(value)
+		}
+	case []any:
+		for i := range v {
+			v[i] = maskJSON(v[i])
+		}
+	}
+	return v
+}
+
+// MaskPII маскирует значение персонального поля.
+//
+//	У email сохраняются первый символ имени и домен (j***@example.com),
+//	у остальных значений — последние два символа (*********55).
+//	Параметры:
+//	- key: имя поля (email маскируется особым образом).
+//	- value: значение.
+//	Возвращает:
+//	- string: маскированное значение.
+func MaskPII(key, value string) string {
+	if value == "" {
+		return ""
+	}
+	if strings.EqualFold(key, "email") {
+		if local, domain, ok := strings.Cut(value, "@"); ok && local != "" {
+			return local[:1] + "***@" + domain
+		}
+	}
+	runes := []rune(value)
+	if len(runes) <= 4 {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", len(runes)-2) + string(runes[len(runes)-2:])
+}
+
+// maskingCore маскирует строковые поля журнала с персональными данными (phone, email и т. п.).
+//
+//	Защищает от случайной записи персональных данных в журнал: значение поля
+//	zap.String("email", ...) попадает в журнал уже маскированным.
+type maskingCore struct {
+	zapcore.Core
+}
+
+// newMaskingCore оборачивает core маскированием персональных полей.
+func newMaskingCore(core zapcore.Core) zapcore.Core {
+	return &maskingCore{Core: core}
+}
+
+// With возвращает core с дополнительными полями, маскируя персональные данные.
+func (c *maskingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &maskingCore{Core: c.Core.With(maskFields(fields))}
+}
+
+// Check делегирует решение о записи (уровень, сэмплирование) вложенному core.
+func (c *maskingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Check(ent, nil) == nil {
+		return ce
+	}
+	return ce.AddCore(ent, c)
+}
+
+// Write записывает запись с маскированными персональными полями.
+func (c *maskingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, maskFields(fields))
+}
+
+// maskFields возвращает поля с маскированными значениями персональных данных.
+func maskFields(fields []zapcore.Field) []zapcore.Field {
+	var masked []zapcore.Field
+	for i, f := range fields {
+		if f.Type != zapcore.StringType || !sensitiveKeys[strings.ToLower(f.Key)] {
+			continue
+		}
+		if masked == nil {
+			masked = append([]zapcore.Field(nil), fields...)
+		}
+		masked[i] = zap.String(f.Key, MaskPII(f.Key, f.String))
+	}
+	if masked == nil {
+		return fields
+	}
+	return masked
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestPayloadMasking проверяет маскирование персональных данных в содержимом сообщения.
+func TestPayloadMasking(t *testing.T) {
+	payload := []byte(`{"order_uid":"b563feb7b2b84b6test","delivery":{"phone":"+9720000000","email":"test@gmail.com"},"customer_id":"test"}`)
+
+	got := Payload("message", payload).String
+	if strings.Contains(got, "+9720000000") || strings.Contains(got, "test@gmail.com") || strings.Contains(got, `"customer_id":"test"`) {
+		t.Errorf("payload exposes personal data: %s", got)
+	}
+	if !strings.Contains(got, `"t***@gmail.com"`) || !strings.Contains(got, `"*********00"`) || !strings.Contains(got, "b563feb7b2b84b6test") {
+		t.Errorf("unexpected masked payload: %s", got)
+	}
+	if got := Payload("message", []byte("+9720000000 not json")).String; got != "<20 bytes redacted>" {
+		t.Errorf("expected redacted invalid payload, got %q", got)
+	}
+
+	SetLogRawPayloads(true)
+	defer SetLogRawPayloads(false)
+	if got := Payload("message", payload).String; got != string(payload) {
+		t.Errorf("expected raw payload, got %s", got)
+	}
+}
+
+// TestMaskingCore проверяет маскирование персональных полей журнала.
+func TestMaskingCore(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(newMaskingCore(core)).With(zap.String("customer_id", "customer-42"))
+	logger.Info("Order saved", zap.String("email", "test@gmail.com"), zap.String("order_uid", "b563feb7b2b84b6test"))
+
+	fields := logs.AllUntimed()[0].ContextMap()
+	if fields["customer_id"] != "*********42" || fields["email"] != "t***@gmail.com" || fields["order_uid"] != "b563feb7b2b84b6test" {
+		t.Errorf("unexpected fields %v", fields)
+	}
+}
@@ -0,0 +1,186 @@
+// Package watchdog tracks dependency availability and records incident windows.
+package watchdog
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"l0_wb/internal/metrics"
+	"l0_wb/internal/model"
+	"l0_wb/internal/repository"
+	"l0_wb/internal/util"
+)
+
+// probeTimeout ограничивает время одной проверки зависимости.
+const probeTimeout = 5 * time.Second
+
+// Probe проверяет доступность зависимости.
+type Probe func(ctx context.Context) error
+
+// Dependency описывает наблюдаемую зависимость.
+type Dependency struct {
+	Name  string
+	Probe Probe
+}
+
+// Settings содержит пороги открытия и закрытия инцидентов.
+type Settings struct {
+	Interval          time.Duration // Интервал проверки
+	FailureThreshold  int           // Неудачных проверок подряд для открытия инцидента
+	RecoveryThreshold int           // Успешных проверок подряд для закрытия инцидента
+}
+
+// state хранит счётчики проверок и открытый инцидент зависимости.
+type state struct {
+	failures  int
+	successes int
+	lastError string
+	incident  *model.Incident
+}
+
+// Watchdog периодически проверяет зависимости и ведёт окна инцидентов.
+//
+//	Инцидент открывается после FailureThreshold неудачных проверок подряд и
+//	закрывается после RecoveryThreshold успешных. На время инцидента все записи
+//	глобального логгера помечаются полем incident_<dependency>, метрика
+//	dependency_incident_open выставляется в 1, а окно сохраняется в таблицу incidents.
+type Watchdog struct {
+	deps     []Dependency
+	repo     repository.IncidentsRepository
+	settings Settings
+	states   map[string]*state
+	now      func() time.Time
+	logger   *zap.Logger
+}
+
+// New создаёт Watchdog.
+//
+//	Параметры:
+//	- repo: репозиторий для сохранения инцидентов.
+//	- settings: интервал проверки и пороги.
+//	- deps: наблюдаемые зависимости.
+//	Возвращает:
+//	- *Watchdog: экземпляр Watchdog.
+func New(repo repository.IncidentsRepository, settings Settings, deps ...Dependency) *Watchdog {
+	states := make(map[string]*state, len(deps))
+	for _, dep := range deps {
+		states[dep.Name] = &state{}
+		metrics.DependencyIncidentOpen.WithLabelValues(dep.Name).Set(0)
+	}
+	return &Watchdog{
+		deps:     deps,
+		repo:     repo,
+		settings: settings,
+		states:   states,
+		now:      time.Now,
+		logger:   util.GetLogger(),
+	}
+}
+
+// Run проверяет зависимости до отмены контекста.
+//
+//	Параметры:
+//	- ctx: контекст выполнения для остановки наблюдения.
+func (w *Watchdog) Run(ctx context.Context) {
+	w.logger.Info("Dependency watchdog started", zap.Duration("interval", w.settings.Interval))
+	ticker := time.NewTicker(w.settings.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.checkAll(ctx)
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Dependency watchdog stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAll выполняет по одной проверке каждой зависимости.
+func (w *Watchdog) checkAll(ctx context.Context) {
+	for _, dep := range w.deps {
+		probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		err := dep.Probe(probeCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		w.observe(ctx, dep.Name, err)
+	}
+}
+
+// observe учитывает результат проверки и при достижении порога открывает или закрывает инцидент.
+func (w *Watchdog) observe(ctx context.Context, name string, probeErr error) {
+	st := w.states[name]
+	if probeErr != nil {
+		st.failures++
+		st.successes = 0
+		st.lastError = probeErr.Error()
+		if st.incident == nil && st.failures >= w.settings.FailureThreshold {
+			w.open(ctx, name, st)
+		}
+		return
+	}
+
+	st.successes++
+	st.failures = 0
+	if st.incident != nil && st.successes >= w.settings.RecoveryThreshold {
+		w.close(ctx, name, st)
+	}
+}
+
+// open открывает инцидент по зависимости.
+//
+//	Если сохранить инцидент не удалось (например, недоступна сама БД), он
+//	остаётся в памяти и будет записан целиком при закрытии.
+func (w *Watchdog) open(ctx context.Context, name string, st *state) {
+	st.incident = &model.Incident{
+		Dependency: name,
+		StartedAt:  w.now().UTC(),
+		LastError:  st.lastError,
+	}
+	id, err := w.repo.Insert(ctx, st.incident)
+	if err != nil {
+		w.logger.Warn("Failed to record incident start", zap.String("dependency", name), zap.Error(err))
+	} else {
+		st.incident.ID = id
+	}
+
+	metrics.SetIncidentOpen(name, true)
+	util.SetLogAnnotation("incident_"+name, st.incident.StartedAt.Format(time.RFC3339))
+	w.logger.Error("Dependency incident opened",
+		zap.String("dependency", name),
+		zap.Int64("incident_id", st.incident.ID),
+		zap.Int("failures", st.failures),
+		zap.String("last_error", st.lastError),
+	)
+}
+
+// close закрывает инцидент по зависимости.
+func (w *Watchdog) close(ctx context.Context, name string, st *state) {
+	incident := st.incident
+	endedAt := w.now().UTC()
+	incident.EndedAt = &endedAt
+	incident.LastError = st.lastError
+
+	var err error
+	if incident.ID == 0 {
+		incident.ID, err = w.repo.Insert(ctx, incident)
+	} else {
+		err = w.repo.Close(ctx, incident.ID, endedAt, incident.LastError)
+	}
+	if err != nil {
+		w.logger.Warn("Failed to record incident end", zap.String("dependency", name), zap.Error(err))
+	}
+
+	st.incident = nil
+	metrics.SetIncidentOpen(name, false)
+	util.ClearLogAnnotation("incident_" + name)
+	w.logger.Info("Dependency incident closed",
+		zap.String("dependency", name),
+		zap.Int64("incident_id", incident.ID),
+		zap.Duration("duration", endedAt.Sub(incident.StartedAt)),
+	)
+}
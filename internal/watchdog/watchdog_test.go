@@ -0,0 +1,82 @@
+package watchdog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"l0_wb/internal/model"
+	"l0_wb/internal/util"
+)
+
+// memoryIncidents — хранилище инцидентов в памяти для тестов.
+type memoryIncidents struct {
+	incidents map[int64]model.Incident
+	fail      bool
+}
+
+func (m *memoryIncidents) Insert(_ context.Context, incident *model.Incident) (int64, error) {
+	if m.fail {
+		return 0, errors.New("database is unavailable")
+	}
+	id := int64(len(m.incidents) + 1)
+	m.incidents[id] = *incident
+	return id, nil
+}
+
+func (m *memoryIncidents) Close(_ context.Context, id int64, endedAt time.Time, lastError string) error {
+	incident := m.incidents[id]
+	incident.EndedAt = &endedAt
+	incident.LastError = lastError
+	m.incidents[id] = incident
+	return nil
+}
+
+// TestWatchdogIncidentWindow проверяет открытие инцидента после серии сбоев и закрытие после восстановления.
+func TestWatchdogIncidentWindow(t *testing.T) {
+	if err := util.InitLogger(); err != nil {
+		t.Fatalf("failed to initialize logger: %v", err)
+	}
+	repo := &memoryIncidents{incidents: make(map[int64]model.Incident)}
+	w := New(repo, Settings{Interval: time.Second, FailureThreshold: 3, RecoveryThreshold: 2},
+		Dependency{Name: "db"})
+	ctx := context.Background()
+	probeErr := errors.New("connection refused")
+
+	// Сбои ниже порога и прерванная серия не открывают инцидент.
+	w.observe(ctx, "db", probeErr)
+	w.observe(ctx, "db", probeErr)
+	w.observe(ctx, "db", nil)
+	w.observe(ctx, "db", probeErr)
+	if len(repo.incidents) != 0 {
+		t.Fatalf("expected no incidents, got %d", len(repo.incidents))
+	}
+
+	// Пока сама БД недоступна, инцидент хранится в памяти.
+	repo.fail = true
+	w.observe(ctx, "db", probeErr)
+	w.observe(ctx, "db", probeErr)
+	if w.states["db"].incident == nil {
+		t.Fatal("expected incident to be opened")
+	}
+
+	// Одной успешной проверки недостаточно для закрытия.
+	repo.fail = false
+	w.observe(ctx, "db", nil)
+	if w.states["db"].incident == nil {
+		t.Fatal("expected incident to stay open after single success")
+	}
+	w.observe(ctx, "db", nil)
+	if w.states["db"].incident != nil {
+		t.Fatal("expected incident to be closed")
+	}
+
+	if len(repo.incidents) != 1 {
+		t.Fatalf("expected one recorded incident, got %d", len(repo.incidents))
+	}
+	incident := repo.incidents[1]
+	if incident.Dependency != "db" || incident.EndedAt == nil || incident.LastError != probeErr.Error() {
+		t.Errorf("unexpected incident: %+v", incident)
+	}
+}
@@ -0,0 +1,314 @@
+// Package client provides a typed Go client for the orders HTTP API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"l0_wb/internal/model"
+)
+
+// Order — заказ в формате API.
+type Order = model.Order
+
+// Значения по умолчанию для повторных попыток.
+const (
+	defaultMaxAttempts = 3
+	defaultBackoff     = 200 * time.Millisecond
+	maxBackoff         = 5 * time.Second
+)
+
+// requestIDHeader — заголовок, по которому сервис связывает запрос с логами.
+const requestIDHeader = "X-Request-ID"
+
+// Client вызывает HTTP API сервиса заказов.
+//
+//	Запросы повторяются при сетевых ошибках и ответах 429, 502, 503, 504 с
+//	экспоненциальной задержкой (или по заголовку Retry-After). Все методы API
+//	идемпотентны: сохранение заказа выполняет upsert по order_uid.
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	apiKey      string
+	userAgent   string
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// Option настраивает Client.
+type Option func(*Client)
+
+// WithAPIKey задаёт ключ, передаваемый в заголовке X-API-Key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithHTTPClient задаёт HTTP-клиент, например с транспортом трассировки.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithRetry задаёт число попыток (1 — без повторов) и начальную задержку между ними.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxAttempts = max(maxAttempts, 1)
+		c.backoff = backoff
+	}
+}
+
+// WithUserAgent задаёт заголовок User-Agent.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// New создаёт клиент API.
+//
+//	Параметры:
+//	- baseURL: адрес сервиса, например "http://localhost:8081".
+//	- opts: параметры клиента.
+//	Возвращает:
+//	- *Client: экземпляр клиента.
+//	- error: ошибку разбора адреса.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("base url must be absolute: %q", baseURL)
+	}
+	c := &Client{
+		baseURL:     u,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		userAgent:   "l0_wb-client",
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// APIError — ошибка, возвращённая API.
+type APIError struct {
+	StatusCode int             `json:"-"`
+	Code       string          `json:"code"`
+	Message    string          `json:"message"`
+	Details    json.RawMessage `json:"details,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+}
+
+// Error реализует интерфейс error.
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("orders api: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("orders api: status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// IsNotFound сообщает, что ресурс не найден.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// requestIDKey — ключ контекста для идентификатора запроса.
+type requestIDKey struct{}
+
+// ContextWithRequestID возвращает контекст, идентификатор из которого передаётся в X-Request-ID.
+//
+//	Позволяет сквозным образом связать логи вызывающего сервиса и сервиса заказов.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// GetOrder возвращает заказ по order_uid.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- orderUID: идентификатор заказа.
+//	Возвращает:
+//	- *Order: заказ.
+//	- error: *APIError (для отсутствующего заказа IsNotFound возвращает true) или ошибку транспорта.
+func (c *Client) GetOrder(ctx context.Context, orderUID string) (*Order, error) {
+	var order Order
+	if err := c.do(ctx, http.MethodGet, "/api/v1/orders/"+url.PathEscape(orderUID), nil, nil, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// ListOptions задаёт параметры постраничной выдачи заказов.
+type ListOptions struct {
+	Limit  int    // Размер страницы (0 — по умолчанию сервиса)
+	Cursor string // Курсор из OrderPage.NextCursor предыдущей страницы
+}
+
+// OrderPage — страница заказов.
+type OrderPage struct {
+	Orders     []*Order `json:"orders"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// ListOrders возвращает страницу заказов, упорядоченных от новых к старым.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- opts: параметры страницы.
+//	Возвращает:
+//	- *OrderPage: заказы и курсор следующей страницы (пустой для последней).
+//	- error: *APIError или ошибку транспорта.
+func (c *Client) ListOrders(ctx context.Context, opts ListOptions) (*OrderPage, error) {
+	q := url.Values{}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	q.Set("limit", strconv.Itoa(limit))
+	if opts.Cursor != "" {
+		q.Set("cursor", opts.Cursor)
+	}
+	var page OrderPage
+	if err := c.do(ctx, http.MethodGet, "/api/v1/orders", q, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// bulkResponse — ответ массовой загрузки заказов.
+type bulkResponse struct {
+	Results []struct {
+		OrderUID string `json:"order_uid"`
+		Status   string `json:"status"`
+		Error    string `json:"error"`
+	} `json:"results"`
+}
+
+// CreateOrder сохраняет заказ; существующий заказ с тем же order_uid обновляется.
+//
+//	Параметры:
+//	- ctx: контекст выполнения.
+//	- order: заказ.
+//	Возвращает:
+//	- error: *APIError, ошибку сохранения заказа или ошибку транспорта.
+func (c *Client) CreateOrder(ctx context.Context, order *Order) error {
+	body, err := json.Marshal([]*Order{order})
+	if err != nil {
+		return fmt.Errorf("marshal order: %w", err)
+	}
+	var resp bulkResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/orders/bulk", nil, body, &resp); err != nil {
+		return err
+	}
+	if len(resp.Results) != 1 {
+		return fmt.Errorf("orders api: unexpected bulk response with %d results", len(resp.Results))
+	}
+	// Повторная отправка неизменённого заказа сервис отмечает как skipped_duplicate
+	if r := resp.Results[0]; r.Status != "saved" && r.Status != "skipped_duplicate" {
+		return fmt.Errorf("orders api: order %s was not saved: %s", order.OrderUID, r.Error)
+	}
+	return nil
+}
+
+// do выполняет запрос с повторными попытками и декодирует ответ в out.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body []byte, out any) error {
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := c.attempt(ctx, method, u.String(), body, out)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if retryAfter < 0 || attempt >= c.maxAttempts {
+			return lastErr
+		}
+
+		delay := retryAfter
+		if delay == 0 {
+			delay = min(c.backoff<<(attempt-1), maxBackoff)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(lastErr, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt выполняет одну попытку запроса.
+//
+//	Возвращает:
+//	- time.Duration: задержку перед повтором (0 — по умолчанию, < 0 — повторять нельзя).
+//	- error: ошибку попытки.
+func (c *Client) attempt(ctx context.Context, method, rawURL string, body []byte, out any) (time.Duration, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reader)
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok && id != "" {
+		req.Header.Set(requestIDHeader, id)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, err
+		}
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(apiErr)
+		if apiErr.RequestID == "" {
+			apiErr.RequestID = resp.Header.Get(requestIDHeader)
+		}
+		return retryDelay(resp), apiErr
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return -1, fmt.Errorf("decode response: %w", err)
+	}
+	return 0, nil
+}
+
+// retryDelay определяет, можно ли повторить запрос после ответа с ошибкой.
+func retryDelay(resp *http.Response) time.Duration {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return -1
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		return min(time.Duration(secs)*time.Second, maxBackoff)
+	}
+	return 0
+}
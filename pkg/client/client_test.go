@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestGetOrderRetries проверяет повтор запроса после 503 и передачу ключа и идентификатора запроса.
+func TestGetOrderRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/orders/b563feb7b2b84b6test" || r.Header.Get("X-API-Key") != "key" || r.Header.Get("X-Request-ID") != "req-1" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Header)
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Order{OrderUID: "b563feb7b2b84b6test"})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithAPIKey("key"), WithRetry(3, time.Millisecond))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	order, err := c.GetOrder(ContextWithRequestID(context.Background(), "req-1"), "b563feb7b2b84b6test")
+	if err != nil {
+		t.Fatalf("GetOrder failed: %v", err)
+	}
+	if order.OrderUID != "b563feb7b2b84b6test" || calls.Load() != 2 {
+		t.Errorf("unexpected result %v after %d calls", order, calls.Load())
+	}
+}
+
+// TestGetOrderNotFound проверяет, что 404 не повторяется и возвращается как APIError.
+func TestGetOrderNotFound(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code":"not_found","message":"order not found","request_id":"req-2"}`))
+	}))
+	defer srv.Close()
+
+	c, _ := New(srv.URL, WithRetry(3, time.Millisecond))
+	_, err := c.GetOrder(context.Background(), "missing")
+	if !IsNotFound(err) || calls.Load() != 1 {
+		t.Fatalf("expected single not found error, got %v after %d calls", err, calls.Load())
+	}
+	if apiErr := err.(*APIError); apiErr.Code != "not_found" || apiErr.RequestID != "req-2" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
+
+// TestCreateOrder проверяет отправку заказа через массовую загрузку и разбор результата.
+func TestCreateOrder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var orders []Order
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/orders/bulk" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&orders); err != nil || len(orders) != 1 {
+			t.Errorf("unexpected body: %v", err)
+		}
+		status := "saved"
+		switch orders[0].OrderUID {
+		case "bad":
+			status = "failed"
+		case "dup":
+			status = "skipped_duplicate"
+		}
+		_, _ = w.Write([]byte(`{"results":[{"index":0,"order_uid":"` + orders[0].OrderUID + `","status":"` + status + `","error":"order has no items"}]}`))
+	}))
+	defer srv.Close()
+
+	c, _ := New(srv.URL)
+	if err := c.CreateOrder(context.Background(), &Order{OrderUID: "ok"}); err != nil {
+		t.Errorf("CreateOrder failed: %v", err)
+	}
+	if err := c.CreateOrder(context.Background(), &Order{OrderUID: "dup"}); err != nil {
+		t.Errorf("CreateOrder of unchanged order failed: %v", err)
+	}
+	if err := c.CreateOrder(context.Background(), &Order{OrderUID: "bad"}); err == nil {
+		t.Error("expected error for rejected order")
+	}
+}
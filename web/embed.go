@@ -0,0 +1,9 @@
+// Package web provides the bundled web UI.
+package web
+
+import "embed"
+
+// FS содержит статические файлы веб-интерфейса, встроенные в бинарный файл.
+//
+//go:embed *.html
+var FS embed.FS